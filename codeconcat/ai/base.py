@@ -2,16 +2,90 @@
 
 import asyncio
 import hashlib
+import ipaddress
 import json
+import logging
+import random
+import re
+import time
 from abc import ABC, abstractmethod
+from contextvars import ContextVar
 from dataclasses import dataclass, field
 from enum import Enum
 from functools import wraps
 from typing import TYPE_CHECKING, Any, ClassVar, Optional
+from urllib.parse import urlparse
 
 if TYPE_CHECKING:
     import aiohttp
 
+logger = logging.getLogger(__name__)
+
+# Longest wait between retries, whatever the backoff or Retry-After header says
+MAX_RETRY_DELAY = 60.0
+
+# Prompt templates that ai_prompt_templates / ai_prompt_templates_dir can override
+PROMPT_TEMPLATE_NAMES = (
+    "file",
+    "file_system",
+    "function",
+    "function_system",
+    "overview",
+    "overview_system",
+    "directory",
+    "directory_system",
+)
+
+_PLACEHOLDER_RE = re.compile(r"\{(\w+)\}")
+
+# Names for common language codes in ai_summary_language; other values are used as given
+LANGUAGE_NAMES = {
+    "ar": "Arabic",
+    "cs": "Czech",
+    "da": "Danish",
+    "de": "German",
+    "el": "Greek",
+    "en": "English",
+    "es": "Spanish",
+    "fa": "Persian",
+    "fi": "Finnish",
+    "fr": "French",
+    "he": "Hebrew",
+    "hi": "Hindi",
+    "hu": "Hungarian",
+    "id": "Indonesian",
+    "it": "Italian",
+    "ja": "Japanese",
+    "ko": "Korean",
+    "nl": "Dutch",
+    "no": "Norwegian",
+    "pl": "Polish",
+    "pt": "Portuguese",
+    "pt-br": "Brazilian Portuguese",
+    "ro": "Romanian",
+    "ru": "Russian",
+    "sv": "Swedish",
+    "th": "Thai",
+    "tr": "Turkish",
+    "uk": "Ukrainian",
+    "vi": "Vietnamese",
+    "zh": "Simplified Chinese",
+    "zh-cn": "Simplified Chinese",
+    "zh-tw": "Traditional Chinese",
+}
+
+
+def language_name(language: str | None) -> str | None:
+    """Return the name of a summary language given as a code (``ja``, ``pt-BR``) or name.
+
+    Returns None for no language or English, the language of the built-in prompts.
+    """
+    if not language or not language.strip():
+        return None
+    key = language.strip().lower().replace("_", "-")
+    name = LANGUAGE_NAMES.get(key) or LANGUAGE_NAMES.get(key.split("-")[0]) or language.strip()
+    return None if name == "English" else name
+
 
 class AIProviderType(Enum):
     """Supported AI provider types."""
@@ -31,6 +105,97 @@ class AIProviderType(Enum):
     QWEN = "qwen"  # DashScope OpenAI-compatible API
     GOOGLE = "google"  # Native Google Gemini API
     ZHIPU = "zhipu"  # Native Zhipu GLM API
+    MISTRAL = "mistral"  # OpenAI-compatible API
+    GROQ = "groq"  # OpenAI-compatible API
+    AZURE_OPENAI = "azure_openai"  # Azure OpenAI deployments
+    BEDROCK = "bedrock"  # AWS Bedrock Converse API
+
+
+# Environment variable holding each provider's API key (None: no key needed)
+PROVIDER_API_KEY_ENV: dict[AIProviderType, str | None] = {
+    AIProviderType.OPENAI: "OPENAI_API_KEY",
+    AIProviderType.ANTHROPIC: "ANTHROPIC_API_KEY",
+    AIProviderType.OPENROUTER: "OPENROUTER_API_KEY",
+    AIProviderType.OLLAMA: None,
+    AIProviderType.LLAMACPP: None,
+    AIProviderType.LOCAL_SERVER: "LOCAL_LLM_API_KEY",
+    AIProviderType.VLLM: "VLLM_API_KEY",
+    AIProviderType.LMSTUDIO: "LMSTUDIO_API_KEY",
+    AIProviderType.LLAMACPP_SERVER: "LLAMACPP_SERVER_API_KEY",
+    AIProviderType.DEEPSEEK: "DEEPSEEK_API_KEY",
+    AIProviderType.MINIMAX: "MINIMAX_API_KEY",
+    AIProviderType.QWEN: "DASHSCOPE_API_KEY",
+    AIProviderType.GOOGLE: "GOOGLE_API_KEY",
+    AIProviderType.ZHIPU: "ZHIPUAI_API_KEY",
+    AIProviderType.MISTRAL: "MISTRAL_API_KEY",
+    AIProviderType.GROQ: "GROQ_API_KEY",
+    AIProviderType.AZURE_OPENAI: "AZURE_OPENAI_API_KEY",
+    AIProviderType.BEDROCK: None,  # AWS credential chain
+}
+
+
+def provider_type_from_name(name: str | None) -> AIProviderType | None:
+    """Resolve a provider name such as ``"openai"`` or ``"Mistral"`` to its type.
+
+    Args:
+        name: Provider name as given on the command line or in config
+
+    Returns:
+        The matching AIProviderType, or None if the name is unknown
+    """
+    if not name:
+        return None
+    try:
+        return AIProviderType(name.strip().lower())
+    except ValueError:
+        return None
+
+
+# Providers that run models in-process or on a self-hosted server. Cloud
+# providers with OpenAI-compatible APIs (DeepSeek, Mistral, Groq, ...) are excluded.
+LOCAL_PROVIDER_TYPES = frozenset(
+    {
+        AIProviderType.OLLAMA,
+        AIProviderType.LLAMACPP,
+        AIProviderType.LOCAL_SERVER,
+        AIProviderType.VLLM,
+        AIProviderType.LMSTUDIO,
+        AIProviderType.LLAMACPP_SERVER,
+    }
+)
+
+
+def is_on_prem_endpoint(api_base: str | None, allowed_hosts: list[str] | None = None) -> bool:
+    """Check whether an API base URL stays on the local machine or private network.
+
+    Loopback, private (RFC 1918 / ULA) and link-local addresses, ``localhost``
+    and hosts listed in ``allowed_hosts`` are accepted. Other hostnames are
+    rejected without a DNS lookup, since resolution could change between the
+    check and the request.
+
+    Args:
+        api_base: Endpoint URL, or None for in-process providers
+        allowed_hosts: Additional hostnames explicitly approved as on-prem
+
+    Returns:
+        True if requests to ``api_base`` cannot leave the approved network
+    """
+    if not api_base:
+        return True
+
+    host = (urlparse(api_base if "://" in api_base else f"//{api_base}").hostname or "").lower()
+    if not host:
+        return False
+    if host in {h.lower() for h in allowed_hosts or []}:
+        return True
+    if host == "localhost" or host.endswith(".localhost"):
+        return True
+
+    try:
+        address = ipaddress.ip_address(host)
+    except ValueError:
+        return False
+    return address.is_loopback or address.is_private or address.is_link_local
 
 
 @dataclass
@@ -44,14 +209,104 @@ class AIProviderConfig:
     temperature: float = 0.3
     max_tokens: int = 500
     timeout: int = 600  # 10 minutes - increased for AI model processing
-    max_retries: int = 3
+    max_retries: int = 3  # total attempts per request
     retry_delay: float = 1.0
+    requests_per_minute: float | None = None  # None uses the provider's default
     cache_enabled: bool = True
-    cache_ttl: int = 3600  # 1 hour
+    cache_ttl: int = 2592000  # 30 days; 0 disables expiry
+    cache_dir: str | None = None  # None uses ai.cache.default_cache_dir()
     cost_per_1k_input_tokens: float = 0.0
     cost_per_1k_output_tokens: float = 0.0
     custom_headers: dict[str, str] = field(default_factory=dict)
     extra_params: dict[str, Any] = field(default_factory=dict)
+    prompt_templates: dict[str, str] = field(default_factory=dict)  # see PROMPT_TEMPLATE_NAMES
+    summary_language: str | None = None  # language code or name; None writes English
+
+
+def render_prompt_template(template: str, variables: dict[str, str]) -> str:
+    """Fill ``{name}`` placeholders in a user prompt template.
+
+    Substitution is a single pass, so placeholders inside the substituted
+    code are left alone, as are unknown placeholders and other braces.
+    """
+    return _PLACEHOLDER_RE.sub(lambda m: variables.get(m.group(1), m.group(0)), template)
+
+
+class ProviderAPIError(Exception):
+    """Error response from a provider's HTTP API."""
+
+    def __init__(self, message: str, status: int, retry_after: float | None = None):
+        super().__init__(message)
+        self.status = status
+        self.retry_after = retry_after
+
+
+def parse_retry_after(headers: Any) -> float | None:
+    """Return the delay in seconds from a ``Retry-After`` header, if it has one."""
+    value = headers.get("Retry-After") if headers is not None else None
+    if not isinstance(value, (str, int, float)):
+        return None
+    try:
+        return max(0.0, float(value))
+    except ValueError:
+        return None  # HTTP-date form; fall back to exponential backoff
+
+
+def is_retryable_error(error: BaseException) -> bool:
+    """Return True for rate limiting, server errors, timeouts and network failures.
+
+    The status is read from ``status`` or ``status_code``, which covers
+    :class:`ProviderAPIError` and the SDK exceptions of the native providers.
+    Errors without a status are assumed to be transient.
+    """
+    status = getattr(error, "status", None)
+    if not isinstance(status, int):
+        status = getattr(error, "status_code", None)
+    if not isinstance(status, int):
+        return True
+    return status in (408, 429) or status >= 500
+
+
+class RateLimiter:
+    """Spaces requests evenly to stay under a requests-per-minute limit.
+
+    With no limit, requests are only held back while the limiter is paused
+    after a rate-limit response.
+    """
+
+    def __init__(self, requests_per_minute: float | None = None):
+        self.interval = 60.0 / requests_per_minute if requests_per_minute else 0.0
+        self._next_slot = 0.0
+        self._lock = asyncio.Lock()
+
+    async def acquire(self) -> None:
+        """Wait for the next free request slot."""
+        async with self._lock:
+            now = time.monotonic()
+            start = max(now, self._next_slot)
+            self._next_slot = start + self.interval
+        if start > now:
+            await asyncio.sleep(start - now)
+
+    def pause(self, seconds: float) -> None:
+        """Hold back every request for ``seconds``, e.g. after an HTTP 429."""
+        self._next_slot = max(self._next_slot, time.monotonic() + seconds)
+
+
+@dataclass
+class RequestActivity:
+    """Requests, input tokens, time and retries spent on one unit of work, such as a file."""
+
+    requests: int = 0
+    input_tokens: int = 0
+    latency: float = 0.0  # seconds spent in provider requests
+    retries: int = 0
+
+
+# Set per file by the summarization processor; the retry loop adds its retries to it
+request_activity: ContextVar[RequestActivity | None] = ContextVar(
+    "request_activity", default=None
+)
 
 
 @dataclass
@@ -81,14 +336,33 @@ class AIProvider(ABC):
         """You are a senior software engineer specializing in code documentation. Your expertise includes identifying function contracts, understanding complex algorithms, and explaining code behavior concisely. Create summaries that are technically precise yet accessible, highlighting the 'what', 'how', and 'why' of each function."""
     )
 
+    SYSTEM_PROMPT_META_OVERVIEW: ClassVar[str] = (
+        "You are a senior software architect conducting a comprehensive codebase analysis. "
+        "Your expertise includes system design, architectural patterns, technology assessment, "
+        "and identifying technical debt and improvement opportunities across large-scale projects."
+    )
+
+    SYSTEM_PROMPT_DIRECTORY_SUMMARY: ClassVar[str] = (
+        "You are a senior software architect who explains how the modules of a codebase fit "
+        "together. You condense summaries of files and subdirectories into a concise account "
+        "of a directory's responsibility, structure and dependencies."
+    )
+
+    # Requests per minute used when the config sets none (None: no spacing)
+    DEFAULT_REQUESTS_PER_MINUTE: ClassVar[float | None] = None
+
     _session: Optional["aiohttp.ClientSession"]
     _session_lock: "asyncio.Lock"
+    rate_limiter: RateLimiter
 
     def __init__(self, config: AIProviderConfig):
         """Initialize the AI provider with configuration."""
         self.config = config
         self._session = None
         self._session_lock = asyncio.Lock()
+        self.rate_limiter = RateLimiter(
+            config.requests_per_minute or self.DEFAULT_REQUESTS_PER_MINUTE
+        )
 
     @abstractmethod
     async def summarize_code(
@@ -175,6 +449,29 @@ class AIProvider(ABC):
             max_length=max_tokens or 2000,
         )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt and return the model's reply.
+
+        Unlike the summarize methods, the prompt is sent as-is and the result
+        is not cached. Used for question answering over generated output.
+
+        Args:
+            prompt: The user prompt
+            system_prompt: Optional system prompt
+            max_tokens: Maximum tokens for the reply
+
+        Returns:
+            SummarizationResult whose ``summary`` holds the reply
+        """
+        raise NotImplementedError(
+            f"{type(self).__name__} does not support free-form completion requests"
+        )
+
     @abstractmethod
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current model.
@@ -216,23 +513,80 @@ class AIProvider(ABC):
         return hashlib.sha256(cache_str.encode()).hexdigest()
 
     async def _retry_with_backoff(self, func, *args, **kwargs):
-        """Execute a function with exponential backoff retry logic."""
+        """Execute a rate-limited request, retrying transient failures with backoff.
+
+        Rate limiting (429), server errors (5xx), timeouts and network errors
+        are retried with jittered exponential backoff, or after the server's
+        ``Retry-After`` delay. A 429 pauses all of this provider's requests.
+        Other client errors such as 400 or 401 are raised immediately.
+        """
         last_exception = None
+        attempts = max(1, self.config.max_retries)
 
-        for attempt in range(self.config.max_retries):
+        for attempt in range(attempts):
+            await self.rate_limiter.acquire()
             try:
                 return await func(*args, **kwargs)
             except Exception as e:
                 last_exception = e
-                if attempt < self.config.max_retries - 1:
-                    delay = self.config.retry_delay * (2**attempt)
-                    await asyncio.sleep(delay)
-                continue
+                if not is_retryable_error(e) or attempt == attempts - 1:
+                    break
+                delay = self.config.retry_delay * (2**attempt) * random.uniform(1.0, 1.5)
+                retry_after = getattr(e, "retry_after", None)
+                if isinstance(retry_after, (int, float)):
+                    delay = max(delay, retry_after)
+                delay = min(delay, MAX_RETRY_DELAY)
+                if getattr(e, "status", None) == 429:
+                    self.rate_limiter.pause(delay)
+                activity = request_activity.get()
+                if activity is not None:
+                    activity.retries += 1
+                logger.warning(
+                    f"{self.config.provider_type.value} request failed ({str(e)[:200]}); "
+                    f"retry {attempt + 1}/{attempts - 1} in {delay:.1f}s"
+                )
+                await asyncio.sleep(delay)
 
         if last_exception is not None:
             raise last_exception
         raise RuntimeError("Unexpected retry error")
 
+    def _system_prompt(self, kind: str) -> str:
+        """Return the system prompt for ``file``, ``function``, ``directory`` or ``overview``.
+
+        A ``<kind>_system`` entry in ``config.prompt_templates`` replaces the default,
+        and the ``summary_language`` instruction is appended to either.
+        """
+        defaults = {
+            "file": self.SYSTEM_PROMPT_CODE_SUMMARY,
+            "function": self.SYSTEM_PROMPT_FUNCTION_SUMMARY,
+            "overview": self.SYSTEM_PROMPT_META_OVERVIEW,
+            "directory": self.SYSTEM_PROMPT_DIRECTORY_SUMMARY,
+        }
+        prompt = self.config.prompt_templates.get(f"{kind}_system") or defaults[kind]
+        instruction = self._language_instruction()
+        return f"{prompt}\n\n{instruction}" if instruction else prompt
+
+    def _language_instruction(self) -> str:
+        """Return the instruction to write in ``config.summary_language``, or "" for English."""
+        name = language_name(self.config.summary_language)
+        if not name:
+            return ""
+        return (
+            f"Write your entire response in {name}, including headings. Keep code identifiers, "
+            "file paths, commands and technical terms without a common translation unchanged."
+        )
+
+    def _render_user_template(
+        self, template: str, code: str, language: str, variables: dict[str, str]
+    ) -> str:
+        """Render a user prompt template, appending the code if it has no ``{code}``."""
+        variables = {**variables, "language": language, "code": code}
+        prompt = render_prompt_template(template, variables)
+        if "{code}" not in template:
+            prompt += f"\n\n```{language}\n{code}\n```"
+        return prompt
+
     def _estimate_tokens(self, text: str) -> int:
         """Estimate token count for text (rough approximation)."""
         # Rough estimate: 1 token ≈ 4 characters for code
@@ -311,6 +665,22 @@ class AIProvider(ABC):
 
         truncation_note = " (Note: Code was truncated due to length)" if was_truncated else ""
 
+        template = self.config.prompt_templates.get("file")
+        if template:
+            declarations = context.get("declarations", []) if context else []
+            return self._render_user_template(
+                template,
+                code,
+                language,
+                {
+                    "path": file_path,
+                    "imports": imports_str,
+                    "declarations": "\n".join(f"- {d}" for d in declarations) or "none",
+                    "num_functions": str(num_functions),
+                    "num_classes": str(num_classes),
+                },
+            )
+
         # Language-specific analysis hints
         language_hints = {
             "python": "Pay attention to decorators, type hints, async/await patterns, class inheritance, and data science libraries (pandas, numpy, scikit-learn).",
@@ -457,6 +827,16 @@ Analyze the code and provide:
 
         truncation_note = " (Note: Function was truncated due to length)" if was_truncated else ""
 
+        template = self.config.prompt_templates.get("function")
+        if template:
+            kind = context.get("kind", "function") if context else "function"
+            return self._render_user_template(
+                template,
+                function_code,
+                language,
+                {"path": file_path, "name": function_name, "kind": kind},
+            )
+
         # Language-specific function analysis focus
         lang_function_hints = {
             "python": "Consider decorators, generators, type hints, exception handling, and data science operations (numpy vectorization, pandas operations).",
@@ -546,6 +926,47 @@ Key requirements:
 
         return prompt
 
+    def _create_directory_summary_prompt(self, directory: str, summaries: dict[str, str]) -> str:
+        """Create a prompt that folds file and subdirectory summaries into a directory summary.
+
+        Args:
+            directory: Directory path relative to the project root ("" for the root)
+            summaries: Summaries of the directory's files and subdirectories, by path
+
+        Returns:
+            Formatted prompt string for the directory summary
+        """
+        name = directory or "(project root)"
+        listing = "\n\n".join(
+            f"**{self._escape_triple_backticks(path)}**\n{self._escape_triple_backticks(summary)}"
+            for path, summary in summaries.items()
+        )
+        template = self.config.prompt_templates.get("directory")
+        if template:
+            variables = {"path": name, "count": str(len(summaries))}
+            return f"{render_prompt_template(template, variables)}\n\n### Summaries\n{listing}"
+
+        return "\n".join(
+            [
+                "### Task",
+                f"Summarize the `{name}` directory of a codebase from the summaries of its "
+                f"{len(summaries)} files and subdirectories below.",
+                "",
+                "### Output Requirements",
+                "- One paragraph of 3-5 sentences on the directory's responsibility, its main "
+                "components and how they work together",
+                "- Then up to 5 bullet points naming the most important files or subdirectories "
+                "and their roles",
+                "- Mention dependencies on other parts of the project when the summaries show them",
+                "- Generalize instead of restating each summary",
+                "",
+                "### Summaries",
+                listing,
+                "",
+                "### Directory Summary",
+            ]
+        )
+
     def _create_meta_overview_prompt(
         self,
         file_summaries: dict[str, str],
@@ -564,15 +985,29 @@ Key requirements:
         Returns:
             Formatted prompt string for meta-overview generation
         """
-        # If custom prompt provided, use it with minimal enhancement
+        # If custom prompt provided, use it with minimal enhancement. The
+        # {file_count}, {languages}, {total_loc} and {tree} placeholders are filled in.
         if custom_prompt:
+            languages = (context or {}).get("languages", {})
+            custom_prompt = render_prompt_template(
+                custom_prompt,
+                {
+                    "file_count": str(
+                        (context or {}).get("summarized_files", len(file_summaries))
+                    ),
+                    "languages": ", ".join(f"{lang}: {n} files" for lang, n in languages.items()),
+                    "total_loc": f"{(context or {}).get('total_loc', 0):,}",
+                    "tree": tree_structure or "",
+                },
+            )
             combined_summaries = "\n\n".join(
                 [f"**{path}**\n{summary}" for path, summary in file_summaries.items()]
             )
             return f"{custom_prompt}\n\n### File Summaries\n{combined_summaries}"
 
         # Extract context information
-        total_files = len(file_summaries)
+        # Hierarchical runs pass directory summaries; the context keeps the file count
+        total_files = (context or {}).get("summarized_files", len(file_summaries))
         languages = context.get("languages", {}) if context else {}
         total_loc = context.get("total_loc", 0) if context else 0
 
@@ -4,11 +4,41 @@ import asyncio
 import contextlib
 import hashlib
 import json
+import os
+import platform
 import re
+import tempfile
 import time
 from pathlib import Path
 from typing import Any, cast
 
+from .base import language_name
+
+
+# Version of the summarization prompt templates, part of every cache key.
+# Bump this whenever prompts in ai/base.py or the providers change so that
+# cached summaries produced by an older prompt are not reused.
+PROMPT_TEMPLATE_VERSION = "2"
+
+
+def default_cache_dir() -> Path:
+    """Return the default on-disk location for cached summaries.
+
+    Uses ``$CODECONCAT_CACHE_DIR/ai-summaries`` if set, otherwise the user cache
+    directory (``$XDG_CACHE_HOME`` or ``~/.cache``) on Linux/macOS and the
+    system temp directory elsewhere. A persistent location means scheduled
+    runs keep their cache across reboots.
+
+    Returns:
+        Path: The cache directory path
+    """
+    if "CODECONCAT_CACHE_DIR" in os.environ:
+        return Path(os.environ["CODECONCAT_CACHE_DIR"]) / "ai-summaries"
+    if platform.system() in ("Linux", "Darwin"):
+        cache_base = os.environ.get("XDG_CACHE_HOME", Path.home() / ".cache")
+        return Path(cache_base) / "codeconcat" / "ai-summaries"
+    return Path(tempfile.gettempdir()) / "codeconcat_ai_cache"
+
 
 def normalize_content_for_hash(content: str) -> str:
     """Normalize content for cache key hashing to improve cache hit rate.
@@ -57,26 +87,56 @@ def normalize_content_for_hash(content: str) -> str:
 class SummaryCache:
     """Cache for AI-generated summaries to avoid redundant API calls."""
 
-    def __init__(self, cache_dir: Path | None = None, ttl: int = 604800):
+    def __init__(
+        self,
+        cache_dir: Path | None = None,
+        ttl: int = 604800,
+        prompt_templates_hash: str = "",
+        summary_language: str = "",
+    ):
         """Initialize the cache.
 
         Args:
-            cache_dir: Directory to store cache files (uses temp if None)
-            ttl: Time-to-live in seconds for cache entries (default: 7 days)
+            cache_dir: Directory to store cache files (uses default_cache_dir() if None)
+            ttl: Time-to-live in seconds for cache entries (default: 7 days). Values
+                 <= 0 disable expiry; keys already change whenever content does.
                  PERFORMANCE: Increased from 1 hour to 7 days for better cache persistence
+            prompt_templates_hash: Hash of user prompt templates, added to every key so
+                 summaries made with other templates are not reused
+            summary_language: Language summaries are written in, added to every key
+                 so summaries in other languages are not reused ("" for English)
         """
-        if cache_dir is None:
-            import tempfile
-
-            self.cache_dir = Path(tempfile.gettempdir()) / "codeconcat_ai_cache"
-        else:
-            self.cache_dir = Path(cache_dir)
+        self.cache_dir = default_cache_dir() if cache_dir is None else Path(cache_dir)
+        self.prompt_templates_hash = prompt_templates_hash
+        self.summary_language = summary_language
 
         self.cache_dir.mkdir(parents=True, exist_ok=True)
         self.ttl = ttl
+        self.hits = 0
+        self.misses = 0
         self._lock = asyncio.Lock()
         self._memory_cache: dict[str, dict[str, Any]] = {}
 
+    @classmethod
+    def from_provider_config(cls, config: Any) -> "SummaryCache":
+        """Create a cache using the directory, TTL, prompt templates and language of a config."""
+        templates = getattr(config, "prompt_templates", None) or {}
+        templates_hash = (
+            hashlib.sha256(json.dumps(templates, sort_keys=True).encode()).hexdigest()[:16]
+            if templates
+            else ""
+        )
+        return cls(
+            cache_dir=getattr(config, "cache_dir", None),
+            ttl=getattr(config, "cache_ttl", 604800),
+            prompt_templates_hash=templates_hash,
+            summary_language=language_name(getattr(config, "summary_language", None)) or "",
+        )
+
+    def _is_fresh(self, entry: dict[str, Any]) -> bool:
+        """Return True if a cache entry has not expired."""
+        return self.ttl <= 0 or time.time() - entry["timestamp"] < self.ttl
+
     def _get_cache_file(self, key: str) -> Path:
         """Get the cache file path for a given key."""
         return self.cache_dir / f"{key}.json"
@@ -107,7 +167,8 @@ class SummaryCache:
         # Check memory cache first (async lock)
         async with self._lock:
             entry = self._memory_cache.get(key)
-            if entry and time.time() - entry["timestamp"] < self.ttl:
+            if entry and self._is_fresh(entry):
+                self.hits += 1
                 return str(entry["summary"])
             elif entry:
                 # Expired, remove from memory cache
@@ -121,17 +182,20 @@ class SummaryCache:
             if entry is None:
                 # Corrupted cache file, remove it
                 await asyncio.to_thread(self._delete_cache_file, cache_file)
+                self.misses += 1
                 return None
 
-            if time.time() - entry["timestamp"] < self.ttl:
+            if self._is_fresh(entry):
                 # Load into memory cache
                 async with self._lock:
                     self._memory_cache[key] = entry
+                self.hits += 1
                 return str(entry["summary"])
             else:
                 # Expired, remove file
                 await asyncio.to_thread(self._delete_cache_file, cache_file)
 
+        self.misses += 1
         return None
 
     def _write_cache_file(self, cache_file: Path, entry: dict[str, Any]) -> None:
@@ -164,7 +228,7 @@ class SummaryCache:
     def generate_key(
         self, content: str, provider: str, model: str, operation: str, **kwargs
     ) -> str:
-        """Generate a cache key based on content and parameters.
+        """Generate a cache key based on content, prompt version and parameters.
 
         PERFORMANCE: Content is normalized before hashing to improve cache hit rate.
         Whitespace-only and comment-only changes won't invalidate the cache.
@@ -185,11 +249,16 @@ class SummaryCache:
         normalized_content = normalize_content_for_hash(content)
         key_data = {
             "content_hash": hashlib.sha256(normalized_content.encode()).hexdigest(),
+            "prompt_version": PROMPT_TEMPLATE_VERSION,
             "provider": provider,
             "model": model,
             "operation": operation,
             **kwargs,
         }
+        if self.prompt_templates_hash:
+            key_data["prompt_templates"] = self.prompt_templates_hash
+        if self.summary_language:
+            key_data["summary_language"] = self.summary_language
         # Use default=str to handle non-JSON-serializable values (Path, datetime, etc.)
         key_str = json.dumps(key_data, sort_keys=True, default=str)
         return hashlib.sha256(key_str.encode()).hexdigest()
@@ -218,7 +287,7 @@ class SummaryCache:
                 with open(cache_file) as f:
                     entry = json.load(f)
 
-                if current_time - entry["timestamp"] >= self.ttl:
+                if self.ttl > 0 and current_time - entry["timestamp"] >= self.ttl:
                     cache_file.unlink()
             except (OSError, json.JSONDecodeError, KeyError):
                 # Corrupted or inaccessible file, remove it
@@ -233,7 +302,7 @@ class SummaryCache:
             expired_keys = [
                 key
                 for key, entry in self._memory_cache.items()
-                if current_time - entry["timestamp"] >= self.ttl
+                if self.ttl > 0 and current_time - entry["timestamp"] >= self.ttl
             ]
             for key in expired_keys:
                 del self._memory_cache[key]
@@ -256,4 +325,6 @@ class SummaryCache:
             "total_size_bytes": total_size,
             "cache_dir": str(self.cache_dir),
             "ttl_seconds": self.ttl,
+            "hits": self.hits,
+            "misses": self.misses,
         }
@@ -83,6 +83,8 @@ def get_ai_provider(config: AIProviderConfig) -> AIProvider:
         AIProviderType.DEEPSEEK,
         AIProviderType.MINIMAX,
         AIProviderType.QWEN,
+        AIProviderType.MISTRAL,
+        AIProviderType.GROQ,
     }:
         from .providers.local_server_provider import LocalServerProvider
 
@@ -98,6 +100,16 @@ def get_ai_provider(config: AIProviderConfig) -> AIProvider:
 
         return ZhipuProvider(config)
 
+    elif config.provider_type == AIProviderType.AZURE_OPENAI:
+        from .providers.azure_openai_provider import AzureOpenAIProvider
+
+        return AzureOpenAIProvider(config)
+
+    elif config.provider_type == AIProviderType.BEDROCK:
+        from .providers.bedrock_provider import BedrockProvider
+
+        return BedrockProvider(config)
+
     else:
         raise ValueError(f"Unsupported provider type: {config.provider_type}")
 
@@ -149,7 +161,7 @@ def list_available_providers() -> list[str]:
     available.extend(["local_server", "vllm", "lmstudio", "llamacpp_server"])
 
     # OpenAI-compatible cloud providers (use standard HTTP)
-    available.extend(["deepseek", "minimax", "qwen"])
+    available.extend(["deepseek", "minimax", "qwen", "mistral", "groq"])
 
     # Check for Google Generative AI (requires google.genai module)
     if (
@@ -162,6 +174,13 @@ def list_available_providers() -> list[str]:
     if importlib.util.find_spec("zhipuai") is not None:
         available.append("zhipu")
 
+    # Azure OpenAI uses standard HTTP; Entra ID sign-in needs azure-identity
+    available.append("azure_openai")
+
+    # Check for boto3 (AWS Bedrock)
+    if importlib.util.find_spec("boto3") is not None:
+        available.append("bedrock")
+
     return available
 
 
@@ -328,6 +347,30 @@ def get_provider_info(provider_name: str) -> dict[str, Any]:
             "env_var": "DASHSCOPE_API_KEY",
             "notes": "Alibaba's Qwen models via DashScope; OpenAI-compatible API",
         },
+        "mistral": {
+            "name": "Mistral",
+            "models": provider_models
+            if provider_models
+            else ["codestral-latest", "mistral-small-latest", "mistral-large-latest"],
+            "requires_api_key": True,
+            "supports_streaming": True,
+            "supports_function_calling": True,
+            "pip_install": None,
+            "env_var": "MISTRAL_API_KEY",
+            "notes": "OpenAI-compatible API; Codestral is tuned for code; EU data residency",
+        },
+        "groq": {
+            "name": "Groq",
+            "models": provider_models
+            if provider_models
+            else ["llama-3.3-70b-versatile", "llama-3.1-8b-instant"],
+            "requires_api_key": True,
+            "supports_streaming": True,
+            "supports_function_calling": True,
+            "pip_install": None,
+            "env_var": "GROQ_API_KEY",
+            "notes": "OpenAI-compatible API on LPU hardware; very low latency for open-weight models",
+        },
         "zhipu": {
             "name": "Zhipu GLM",
             "models": provider_models
@@ -340,6 +383,34 @@ def get_provider_info(provider_name: str) -> dict[str, Any]:
             "env_var": "ZHIPUAI_API_KEY",
             "notes": "Native Zhipu SDK; strong multilingual and code capabilities",
         },
+        "azure_openai": {
+            "name": "Azure OpenAI",
+            "models": ["your deployment name"],
+            "requires_api_key": False,
+            "supports_streaming": True,
+            "supports_function_calling": True,
+            "pip_install": None,
+            "env_var": "AZURE_OPENAI_API_KEY",
+            "notes": "Set AZURE_OPENAI_ENDPOINT and use the deployment name as the model; "
+            "API key or Microsoft Entra ID (AZURE_OPENAI_AD_TOKEN or azure-identity)",
+        },
+        "bedrock": {
+            "name": "AWS Bedrock",
+            "models": provider_models
+            if provider_models
+            else [
+                "anthropic.claude-3-haiku-20240307-v1:0",
+                "anthropic.claude-3-5-sonnet-20241022-v2:0",
+                "amazon.titan-text-express-v1",
+            ],
+            "requires_api_key": False,
+            "supports_streaming": True,
+            "supports_function_calling": True,
+            "pip_install": "boto3",
+            "env_var": None,
+            "notes": "Converse API for Claude, Titan and other Bedrock models; uses the AWS "
+            "credential chain (env, profile, SSO, instance role) and AWS_REGION",
+        },
     }
 
     return info.get(provider_name, {})
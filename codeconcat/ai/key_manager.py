@@ -102,6 +102,9 @@ class APIKeyManager:
             "minimax": lambda k: len(k) >= 30,
             "qwen": lambda k: k.startswith("sk-") or len(k) >= 30,
             "zhipu": lambda k: len(k) >= 30,
+            "mistral": lambda k: len(k) >= 30,
+            "groq": lambda k: k.startswith("gsk_"),
+            "azure_openai": lambda k: len(k) >= 32,
         }
 
         if provider in validations:
@@ -120,30 +123,16 @@ class APIKeyManager:
         Returns:
             True if key works
         """
-        from .base import AIProviderConfig, AIProviderType
+        from .base import AIProviderConfig, provider_type_from_name
         from .factory import get_ai_provider
 
         try:
-            # Map provider name to enum
-            provider_map = {
-                "openai": AIProviderType.OPENAI,
-                "anthropic": AIProviderType.ANTHROPIC,
-                "openrouter": AIProviderType.OPENROUTER,
-                "google": AIProviderType.GOOGLE,
-                "deepseek": AIProviderType.DEEPSEEK,
-                "minimax": AIProviderType.MINIMAX,
-                "qwen": AIProviderType.QWEN,
-                "zhipu": AIProviderType.ZHIPU,
-                "ollama": AIProviderType.OLLAMA,
-            }
-
-            if provider not in provider_map:
+            provider_type = provider_type_from_name(provider)
+            if provider_type is None:
                 return False
 
             # Create minimal config
-            config = AIProviderConfig(
-                provider_type=provider_map[provider], api_key=api_key, max_tokens=1
-            )
+            config = AIProviderConfig(provider_type=provider_type, api_key=api_key, max_tokens=1)
 
             # Try to create provider and validate
             provider_instance = get_ai_provider(config)
@@ -177,6 +166,9 @@ class APIKeyManager:
             "minimax": "MINIMAX_API_KEY",
             "qwen": "DASHSCOPE_API_KEY",
             "zhipu": "ZHIPUAI_API_KEY",
+            "mistral": "MISTRAL_API_KEY",
+            "groq": "GROQ_API_KEY",
+            "azure_openai": "AZURE_OPENAI_API_KEY",
             "ollama": None,  # Ollama doesn't need API key
             "local_server": "LOCAL_LLM_API_KEY",
             "vllm": "VLLM_API_KEY",
@@ -380,6 +372,9 @@ class APIKeyManager:
             ("minimax", "MINIMAX_API_KEY"),
             ("qwen", "DASHSCOPE_API_KEY"),
             ("zhipu", "ZHIPUAI_API_KEY"),
+            ("mistral", "MISTRAL_API_KEY"),
+            ("groq", "GROQ_API_KEY"),
+            ("azure_openai", "AZURE_OPENAI_API_KEY"),
         ]:
             if os.getenv(env_var):
                 providers.append(f"{provider} (env)")
@@ -424,6 +419,8 @@ async def setup_api_keys(interactive: bool = True) -> dict[str, str]:
         ("minimax", "MiniMax", "(API key)"),
         ("qwen", "Qwen/DashScope", "sk-..."),
         ("zhipu", "Zhipu GLM", "(API key)"),
+        ("mistral", "Mistral", "(API key)"),
+        ("groq", "Groq", "gsk_..."),
     ]
 
     for provider_id, provider_name, key_format in providers:
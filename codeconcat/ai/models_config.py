@@ -532,6 +532,152 @@ MODEL_CONFIGS = {
         tokenizer="gpt2",
         notes="Zhipu's code-specialized model - free for developers",
     ),
+    # Mistral Models (2025) - OpenAI-compatible API
+    "codestral-latest": ModelConfig(
+        provider="mistral",
+        model_id="codestral-latest",
+        display_name="Codestral",
+        tier=ModelTier.BUDGET,
+        context_window=256000,
+        max_output=8192,
+        cost_per_1k_input=0.0003,
+        cost_per_1k_output=0.0009,
+        supports_functions=True,
+        tokenizer="gpt2",
+        notes="Mistral's code-specialized model",
+    ),
+    "mistral-small-latest": ModelConfig(
+        provider="mistral",
+        model_id="mistral-small-latest",
+        display_name="Mistral Small",
+        tier=ModelTier.BUDGET,
+        context_window=128000,
+        max_output=8192,
+        cost_per_1k_input=0.0001,
+        cost_per_1k_output=0.0003,
+        supports_functions=True,
+        tokenizer="gpt2",
+        notes="Mistral's efficient general-purpose model",
+    ),
+    "mistral-large-latest": ModelConfig(
+        provider="mistral",
+        model_id="mistral-large-latest",
+        display_name="Mistral Large",
+        tier=ModelTier.STANDARD,
+        context_window=128000,
+        max_output=8192,
+        cost_per_1k_input=0.002,
+        cost_per_1k_output=0.006,
+        supports_functions=True,
+        tokenizer="gpt2",
+        notes="Mistral's flagship model",
+    ),
+    # Groq Models (2025) - OpenAI-compatible API
+    "llama-3.3-70b-versatile": ModelConfig(
+        provider="groq",
+        model_id="llama-3.3-70b-versatile",
+        display_name="Llama 3.3 70B (Groq)",
+        tier=ModelTier.BUDGET,
+        context_window=128000,
+        max_output=8192,
+        cost_per_1k_input=0.00059,
+        cost_per_1k_output=0.00079,
+        supports_functions=True,
+        tokenizer="llama",
+        notes="Open-weight Llama served on Groq LPUs - very fast",
+    ),
+    "llama-3.1-8b-instant": ModelConfig(
+        provider="groq",
+        model_id="llama-3.1-8b-instant",
+        display_name="Llama 3.1 8B Instant (Groq)",
+        tier=ModelTier.BUDGET,
+        context_window=128000,
+        max_output=8192,
+        cost_per_1k_input=0.00005,
+        cost_per_1k_output=0.00008,
+        supports_functions=True,
+        tokenizer="llama",
+        notes="Smallest Groq-hosted Llama - lowest latency and cost",
+    ),
+    # AWS Bedrock Models (on-demand, us-east-1 pricing) - Converse API
+    "anthropic.claude-3-haiku-20240307-v1:0": ModelConfig(
+        provider="bedrock",
+        model_id="anthropic.claude-3-haiku-20240307-v1:0",
+        display_name="Claude 3 Haiku (Bedrock)",
+        tier=ModelTier.BUDGET,
+        context_window=200000,
+        max_output=4096,
+        cost_per_1k_input=0.00025,
+        cost_per_1k_output=0.00125,
+        supports_functions=True,
+        tokenizer="claude",
+        notes="Default Bedrock model - available in most regions",
+    ),
+    "anthropic.claude-3-5-haiku-20241022-v1:0": ModelConfig(
+        provider="bedrock",
+        model_id="anthropic.claude-3-5-haiku-20241022-v1:0",
+        display_name="Claude 3.5 Haiku (Bedrock)",
+        tier=ModelTier.BUDGET,
+        context_window=200000,
+        max_output=8192,
+        cost_per_1k_input=0.0008,
+        cost_per_1k_output=0.004,
+        supports_functions=True,
+        tokenizer="claude",
+        notes="Fast Claude on Bedrock; some regions need a us. inference profile",
+    ),
+    "anthropic.claude-3-5-sonnet-20241022-v2:0": ModelConfig(
+        provider="bedrock",
+        model_id="anthropic.claude-3-5-sonnet-20241022-v2:0",
+        display_name="Claude 3.5 Sonnet v2 (Bedrock)",
+        tier=ModelTier.STANDARD,
+        context_window=200000,
+        max_output=8192,
+        cost_per_1k_input=0.003,
+        cost_per_1k_output=0.015,
+        supports_functions=True,
+        tokenizer="claude",
+        notes="Strong code understanding on Bedrock",
+    ),
+    "amazon.titan-text-express-v1": ModelConfig(
+        provider="bedrock",
+        model_id="amazon.titan-text-express-v1",
+        display_name="Titan Text Express",
+        tier=ModelTier.BUDGET,
+        context_window=8192,
+        max_output=8192,
+        cost_per_1k_input=0.0002,
+        cost_per_1k_output=0.0006,
+        supports_functions=False,
+        tokenizer="gpt2",
+        notes="Amazon's general-purpose Titan model",
+    ),
+    "amazon.titan-text-lite-v1": ModelConfig(
+        provider="bedrock",
+        model_id="amazon.titan-text-lite-v1",
+        display_name="Titan Text Lite",
+        tier=ModelTier.BUDGET,
+        context_window=4096,
+        max_output=4096,
+        cost_per_1k_input=0.00015,
+        cost_per_1k_output=0.0002,
+        supports_functions=False,
+        tokenizer="gpt2",
+        notes="Smallest Titan model - short files only",
+    ),
+    "amazon.titan-text-premier-v1:0": ModelConfig(
+        provider="bedrock",
+        model_id="amazon.titan-text-premier-v1:0",
+        display_name="Titan Text Premier",
+        tier=ModelTier.STANDARD,
+        context_window=32000,
+        max_output=3072,
+        cost_per_1k_input=0.0005,
+        cost_per_1k_output=0.0015,
+        supports_functions=False,
+        tokenizer="gpt2",
+        notes="Most capable Titan text model",
+    ),
 }
 
 
@@ -544,6 +690,8 @@ DEFAULT_MODELS = {
         "deepseek-coder",  # DeepSeek extremely cheap
         "qwen-coder-turbo",  # Qwen fast and cheap
         "glm-4-flash",  # Zhipu efficient
+        "mistral-small-latest",  # Mistral efficient
+        "llama-3.1-8b-instant",  # Groq lowest latency
     ],
     "standard": [
         "claude-sonnet-4.1",  # Best balance (2025)
@@ -570,6 +718,7 @@ DEFAULT_MODELS = {
         "qwen-coder-plus",  # Premium code model
         "codegeex-4",  # Zhipu code specialist
         "gemini-2.0-flash",  # Free with good code understanding
+        "codestral-latest",  # Mistral code specialist
     ],
 }
 
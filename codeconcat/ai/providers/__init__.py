@@ -1,6 +1,8 @@
 """AI provider implementations."""
 
 from codeconcat.ai.providers.anthropic_provider import AnthropicProvider
+from codeconcat.ai.providers.azure_openai_provider import AzureOpenAIProvider
+from codeconcat.ai.providers.bedrock_provider import BedrockProvider
 from codeconcat.ai.providers.google_provider import GoogleProvider
 from codeconcat.ai.providers.llamacpp_provider import LlamaCppProvider
 from codeconcat.ai.providers.local_server_provider import LocalServerProvider
@@ -11,6 +13,8 @@ from codeconcat.ai.providers.zhipu_provider import ZhipuProvider
 
 __all__ = [
     "AnthropicProvider",
+    "AzureOpenAIProvider",
+    "BedrockProvider",
     "GoogleProvider",
     "LlamaCppProvider",
     "LocalServerProvider",
@@ -3,12 +3,17 @@
 import asyncio
 import logging
 import os
-import time
 from typing import Any
 
 import aiohttp
 
-from ..base import AIProvider, AIProviderConfig, SummarizationResult
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
 from ..cache import SummaryCache
 
 logger = logging.getLogger(__name__)
@@ -17,6 +22,9 @@ logger = logging.getLogger(__name__)
 class AnthropicProvider(AIProvider):
     """Anthropic API provider for code summarization."""
 
+    # Tier-dependent limits: Tier 1 5 RPM, Tier 2 50 RPM, Tier 3 1000 RPM, Tier 4 2000 RPM
+    DEFAULT_REQUESTS_PER_MINUTE = 24
+
     _session: aiohttp.ClientSession | None
 
     def __init__(self, config: AIProviderConfig):
@@ -57,18 +65,12 @@ class AnthropicProvider(AIProvider):
                     config.cost_per_1k_input_tokens = 0.00025
                     config.cost_per_1k_output_tokens = 0.00125
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
-        # Rate limiting: Anthropic tier-dependent limits
-        # Tier 1: 5 RPM, Tier 2: 50 RPM, Tier 3: 1000 RPM, Tier 4: 2000 RPM
-        # Using Tier 1 with concurrent requests: 5 RPM = 1 req every 12s, but allow 5 concurrent
-        self._rate_limit_delay = 2.5  # seconds between requests (allows ~24 RPM with concurrency)
-        self._last_request_time = 0.0
-        self._rate_limit_lock = asyncio.Lock()
         self._concurrent_limit = asyncio.Semaphore(5)  # Max 5 concurrent requests
 
         logger.info(
-            f"Anthropic provider initialized - Model: {config.model}, Cache: {bool(self.cache)}, API Key: {bool(config.api_key)}, Rate limit: {self._rate_limit_delay}s"
+            f"Anthropic provider initialized - Model: {config.model}, Cache: {bool(self.cache)}, API Key: {bool(config.api_key)}, Rate limit: {self.rate_limiter.interval:.2f}s"
         )
 
     async def _get_session(self) -> aiohttp.ClientSession:
@@ -88,20 +90,8 @@ class AnthropicProvider(AIProvider):
         return self._session
 
     async def _make_api_call(self, messages: list, max_tokens: int | None = None) -> dict:
-        """Make an API call to Anthropic with rate limiting and concurrency control."""
-        # Use semaphore to limit concurrent requests
-        async with self._concurrent_limit:
-            # Enforce minimum delay between requests
-            async with self._rate_limit_lock:
-                now = time.time()
-                time_since_last = now - self._last_request_time
-                if time_since_last < self._rate_limit_delay:
-                    wait_time = self._rate_limit_delay - time_since_last
-                    logger.debug(f"Rate limiting: waiting {wait_time:.2f}s")
-                    await asyncio.sleep(wait_time)
-                self._last_request_time = time.time()
-
-            session = await self._get_session()
+        """Make an API call to Anthropic with concurrency control."""
+        session = await self._get_session()
 
         # Convert messages to Anthropic format
         system_message = None
@@ -126,10 +116,15 @@ class AnthropicProvider(AIProvider):
 
         url = f"{self.config.api_base}/messages"
 
-        async with session.post(url, json=payload) as response:
+        # Use semaphore to limit concurrent requests
+        async with self._concurrent_limit, session.post(url, json=payload) as response:
             if response.status != 200:
                 error_text = await response.text()
-                raise Exception(f"Anthropic API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"Anthropic API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
 
             result = await response.json()
             return dict(result) if result else {}
@@ -159,12 +154,7 @@ class AnthropicProvider(AIProvider):
         # Create the prompt
         prompt = self._create_code_summary_prompt(code, language, context)
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         messages = [
             {
                 "role": "system",
@@ -239,12 +229,7 @@ class AnthropicProvider(AIProvider):
             function_code, function_name, language, context
         )
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         messages = [
             {
                 "role": "system",
@@ -337,11 +322,7 @@ class AnthropicProvider(AIProvider):
         )
 
         # Prepare messages with extended thinking for Sonnet 4.5
-        system_prompt = (
-            "You are a senior software architect conducting a comprehensive codebase analysis. "
-            "Your expertise includes system design, architectural patterns, technology assessment, "
-            "and identifying technical debt and improvement opportunities across large-scale projects."
-        )
+        system_prompt = self._system_prompt("overview")
 
         messages = [
             {"role": "system", "content": system_prompt},
@@ -465,6 +446,34 @@ class AnthropicProvider(AIProvider):
                 # Ignore any errors during config restoration
                 pass
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to Anthropic without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("input_tokens", 0)
+            output_tokens = usage.get("output_tokens", 0)
+            return SummarizationResult(
+                summary=response["content"][0]["text"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="anthropic",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="anthropic"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current Anthropic model."""
         model_info = {
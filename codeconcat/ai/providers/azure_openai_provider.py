@@ -0,0 +1,397 @@
+"""Azure OpenAI provider implementation for code summarization."""
+
+import asyncio
+import logging
+import os
+import time
+from typing import Any
+from urllib.parse import quote
+
+import aiohttp
+
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
+from ..cache import SummaryCache
+
+logger = logging.getLogger(__name__)
+
+# GA data-plane API version used when none is configured
+DEFAULT_API_VERSION = "2024-10-21"
+
+# Microsoft Entra ID scope for Azure OpenAI
+ENTRA_SCOPE = "https://cognitiveservices.azure.com/.default"
+
+# Entra ID tokens are refreshed this many seconds before they expire
+_TOKEN_REFRESH_MARGIN = 300
+
+# Provider settings consumed here rather than sent with the request
+_PROVIDER_SETTINGS = frozenset({"api_version", "base_model"})
+
+
+class AzureOpenAIProvider(AIProvider):
+    """Azure OpenAI Service provider for code summarization.
+
+    Requests go to a deployment of the configured resource, so ``model`` is
+    the deployment name. Authentication uses, in order, an API key
+    (``AZURE_OPENAI_API_KEY``), a Microsoft Entra ID token
+    (``AZURE_OPENAI_AD_TOKEN``) or ``DefaultAzureCredential`` from the
+    azure-identity package (managed identity, Azure CLI login, ...).
+    """
+
+    # Quotas are assigned per deployment; raise with requests_per_minute to match yours
+    DEFAULT_REQUESTS_PER_MINUTE = 60
+
+    _session: aiohttp.ClientSession | None
+
+    def __init__(self, config: AIProviderConfig):
+        """Initialize the Azure OpenAI provider.
+
+        Raises:
+            ValueError: If the endpoint, deployment or credentials are not configured.
+        """
+        super().__init__(config)
+        logger.info(f"Initializing Azure OpenAI provider with deployment: {config.model}")
+
+        if not config.api_base:
+            config.api_base = os.getenv("AZURE_OPENAI_ENDPOINT")
+        if not config.api_base:
+            raise ValueError(
+                "Azure OpenAI endpoint not configured. Set AZURE_OPENAI_ENDPOINT or --ai-api-base "
+                "to your resource endpoint, e.g. https://my-resource.openai.azure.com"
+            )
+        config.api_base = config.api_base.rstrip("/")
+
+        if not config.model:
+            config.model = os.getenv("AZURE_OPENAI_DEPLOYMENT", "")
+        if not config.model:
+            raise ValueError(
+                "Azure OpenAI deployment not configured. Set --ai-model (or "
+                "AZURE_OPENAI_DEPLOYMENT) to the name of your model deployment"
+            )
+
+        self.api_version = str(
+            config.extra_params.get("api_version")
+            or os.getenv("AZURE_OPENAI_API_VERSION")
+            or DEFAULT_API_VERSION
+        )
+        # Deployment names are arbitrary; base_model names the model behind it for pricing
+        self.base_model = str(config.extra_params.get("base_model") or config.model)
+
+        if not config.api_key:
+            config.api_key = os.getenv("AZURE_OPENAI_API_KEY")
+        self._ad_token = None if config.api_key else os.getenv("AZURE_OPENAI_AD_TOKEN")
+        self._credential = None
+        self._token: tuple[str, float] | None = None
+        if not config.api_key and not self._ad_token:
+            try:
+                from azure.identity import (  # type: ignore[import-not-found]
+                    DefaultAzureCredential,
+                )
+            except ImportError as err:
+                error_msg = (
+                    "Azure OpenAI credentials not configured. Please set one of the following:\n"
+                    "1. Set the AZURE_OPENAI_API_KEY environment variable\n"
+                    "2. Set AZURE_OPENAI_AD_TOKEN to a Microsoft Entra ID access token\n"
+                    "3. Install azure-identity (pip install azure-identity) to sign in with "
+                    "a managed identity or the Azure CLI"
+                )
+                logger.error(error_msg)
+                raise ValueError(error_msg) from err
+            self._credential = DefaultAzureCredential()
+
+        if config.cost_per_1k_input_tokens == 0:
+            from ..models_config import get_model_config
+
+            model_cfg = get_model_config(self.base_model)
+            if model_cfg:
+                config.cost_per_1k_input_tokens = model_cfg.cost_per_1k_input
+                config.cost_per_1k_output_tokens = model_cfg.cost_per_1k_output
+
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
+
+        self._concurrent_limit = asyncio.Semaphore(10)  # Max 10 concurrent requests
+
+        auth = "API key" if config.api_key else "Entra ID"
+        logger.info(
+            f"Azure OpenAI provider initialized - Deployment: {config.model}, "
+            f"API version: {self.api_version}, Auth: {auth}, Cache: {bool(self.cache)}"
+        )
+
+    async def _get_session(self) -> aiohttp.ClientSession:
+        """Get or create the aiohttp session; authentication is added per request."""
+        if self._session is None:
+            async with self._session_lock:
+                if self._session is None:
+                    headers = {
+                        "Content-Type": "application/json",
+                        **(self.config.custom_headers or {}),
+                    }
+                    timeout = aiohttp.ClientTimeout(total=self.config.timeout)
+                    self._session = aiohttp.ClientSession(headers=headers, timeout=timeout)
+        return self._session
+
+    async def _auth_headers(self) -> dict[str, str]:
+        """Return the API key header, or a bearer token refreshed before it expires."""
+        if self.config.api_key:
+            return {"api-key": self.config.api_key}
+        if self._ad_token:
+            return {"Authorization": f"Bearer {self._ad_token}"}
+
+        if self._token is None or self._token[1] - _TOKEN_REFRESH_MARGIN < time.time():
+            loop = asyncio.get_event_loop()
+            access = await loop.run_in_executor(None, self._credential.get_token, ENTRA_SCOPE)
+            self._token = (access.token, float(access.expires_on))
+        return {"Authorization": f"Bearer {self._token[0]}"}
+
+    def _is_reasoning_model(self) -> bool:
+        model = self.base_model.lower()
+        return any(x in model for x in ["gpt-5", "o1", "o3", "o4"])
+
+    async def _make_api_call(self, messages: list, max_tokens: int | None = None) -> dict:
+        """Send a chat completion request to the deployment.
+
+        Raises:
+            ProviderAPIError: On a non-200 response, with the HTTP status.
+        """
+        session = await self._get_session()
+        extra = {k: v for k, v in self.config.extra_params.items() if k not in _PROVIDER_SETTINGS}
+        limit = max_tokens or self.config.max_tokens
+        if self._is_reasoning_model():
+            payload = {"messages": messages, "max_completion_tokens": limit, **extra}
+        else:
+            payload = {
+                "messages": messages,
+                "temperature": self.config.temperature,
+                "max_tokens": limit,
+                **extra,
+            }
+
+        url = (
+            f"{self.config.api_base}/openai/deployments/{quote(self.config.model, safe='')}"
+            f"/chat/completions?api-version={self.api_version}"
+        )
+        logger.info(f"Making Azure OpenAI API call to deployment {self.config.model}")
+
+        headers = await self._auth_headers()
+        async with self._concurrent_limit, session.post(
+            url, json=payload, headers=headers
+        ) as response:
+            if response.status != 200:
+                error_text = await response.text()
+                logger.error(f"Azure OpenAI API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"Azure OpenAI API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
+            result = await response.json()
+            return dict(result) if result else {}
+
+    @staticmethod
+    def _reply_text(response: dict) -> str:
+        # Content is null when the reply was blocked by the content filter
+        choice = response["choices"][0]
+        content = choice["message"].get("content")
+        if content is None and choice.get("finish_reason") == "content_filter":
+            raise ValueError("Azure OpenAI content filter blocked the reply")
+        return (content or "").strip()
+
+    async def summarize_code(
+        self,
+        code: str,
+        language: str,
+        context: dict[str, Any] | None = None,
+        max_length: int | None = None,
+    ) -> SummarizationResult:
+        """Generate a summary for a code file using Azure OpenAI."""
+        # Check cache first
+        if self.cache:
+            cache_key = self.cache.generate_key(
+                code, "azure_openai", self.config.model, "summarize_code", language=language
+            )
+            cached_summary = await self.cache.get(cache_key)
+            if cached_summary:
+                logger.info(f"Cache hit for {language} code - returning cached summary")
+                return SummarizationResult(
+                    summary=cached_summary,
+                    model_used=self.config.model,
+                    provider="azure_openai",
+                    cached=True,
+                )
+
+        messages = [
+            {"role": "system", "content": self._system_prompt("file")},
+            {"role": "user", "content": self._create_code_summary_prompt(code, language, context)},
+        ]
+
+        try:
+            # Reasoning models spend part of the budget on reasoning tokens
+            if self._is_reasoning_model() and (max_length is None or max_length < 2000):
+                max_length = 2000
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_length)
+
+            summary = self._reply_text(response)
+            usage = response["usage"]
+            tokens_used = usage["total_tokens"]
+            input_tokens = usage["prompt_tokens"]
+            output_tokens = usage["completion_tokens"]
+            cost = self._calculate_cost(input_tokens, output_tokens)
+
+            # Cache the result
+            if self.cache and cache_key:
+                await self.cache.set(cache_key, summary, {"tokens": tokens_used, "cost": cost})
+
+            return SummarizationResult(
+                summary=summary,
+                tokens_used=tokens_used,
+                cost_estimate=cost,
+                model_used=self.config.model,
+                provider="azure_openai",
+                cached=False,
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+
+        except Exception as e:
+            logger.error(f"Azure OpenAI API error: {e}")
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="azure_openai"
+            )
+
+    async def summarize_function(
+        self,
+        function_code: str,
+        function_name: str,
+        language: str,
+        context: dict[str, Any] | None = None,
+    ) -> SummarizationResult:
+        """Generate a summary for a specific function using Azure OpenAI."""
+        # Check cache first
+        if self.cache:
+            cache_key = self.cache.generate_key(
+                function_code,
+                "azure_openai",
+                self.config.model,
+                "summarize_function",
+                function_name=function_name,
+                language=language,
+            )
+            cached_summary = await self.cache.get(cache_key)
+            if cached_summary:
+                logger.info(f"Cache hit for {function_name} - returning cached summary")
+                return SummarizationResult(
+                    summary=cached_summary,
+                    model_used=self.config.model,
+                    provider="azure_openai",
+                    cached=True,
+                )
+
+        user_prompt = self._create_function_summary_prompt(
+            function_code, function_name, language, context
+        )
+        messages = [
+            {"role": "system", "content": self._system_prompt("function")},
+            {"role": "user", "content": user_prompt},
+        ]
+
+        try:
+            response = await self._retry_with_backoff(
+                self._make_api_call,
+                messages,
+                200,  # Shorter max tokens for function summaries
+            )
+
+            summary = self._reply_text(response)
+            usage = response["usage"]
+            tokens_used = usage["total_tokens"]
+            input_tokens = usage["prompt_tokens"]
+            output_tokens = usage["completion_tokens"]
+            cost = self._calculate_cost(input_tokens, output_tokens)
+
+            # Cache the result
+            if self.cache and cache_key:
+                await self.cache.set(cache_key, summary, {"tokens": tokens_used, "cost": cost})
+
+            return SummarizationResult(
+                summary=summary,
+                tokens_used=tokens_used,
+                cost_estimate=cost,
+                model_used=self.config.model,
+                provider="azure_openai",
+                cached=False,
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+
+        except Exception as e:
+            logger.error(f"Azure OpenAI API error: {e}")
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="azure_openai"
+            )
+
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to Azure OpenAI without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            return SummarizationResult(
+                summary=self._reply_text(response),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="azure_openai",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="azure_openai"
+            )
+
+    async def get_model_info(self) -> dict[str, Any]:
+        """Get information about the current deployment."""
+        from ..models_config import get_model_config
+
+        model_cfg = get_model_config(self.base_model)
+        info: dict[str, Any] = {
+            "provider": "azure_openai",
+            "model": self.config.model,
+            "base_model": self.base_model,
+            "api_version": self.api_version,
+            "api_base": self.config.api_base,
+            "temperature": self.config.temperature,
+            "cost_per_1k_input": self.config.cost_per_1k_input_tokens,
+            "cost_per_1k_output": self.config.cost_per_1k_output_tokens,
+        }
+        if model_cfg:
+            info["context_window"] = model_cfg.context_window
+            info["max_output"] = model_cfg.max_output
+        return info
+
+    async def validate_connection(self) -> bool:
+        """Validate that the deployment answers a minimal request."""
+        try:
+            messages = [{"role": "user", "content": "test"}]
+            response = await self._make_api_call(messages, max_tokens=10)
+            return bool(response.get("choices"))
+        except Exception as e:
+            logger.error(f"Azure OpenAI connection validation failed: {e}")
+            return False
+
+    async def close(self):
+        """Close the HTTP session and the Entra ID credential."""
+        if self._credential is not None and hasattr(self._credential, "close"):
+            self._credential.close()
+        await super().close()
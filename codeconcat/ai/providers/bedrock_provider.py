@@ -0,0 +1,390 @@
+"""AWS Bedrock provider implementation for code summarization."""
+
+import asyncio
+import logging
+import os
+from typing import Any
+
+from ..base import AIProvider, AIProviderConfig, ProviderAPIError, SummarizationResult
+from ..cache import SummaryCache
+
+logger = logging.getLogger(__name__)
+
+# Provider settings consumed here rather than sent with the request
+_PROVIDER_SETTINGS = frozenset({"region", "profile"})
+
+# Prefixes of cross-region inference profile ids ("us.anthropic.claude-...")
+_INFERENCE_PROFILE_PREFIXES = ("us.", "eu.", "apac.", "us-gov.", "global.")
+
+
+def base_model_id(model_id: str) -> str:
+    """Return the foundation model id behind a cross-region inference profile id."""
+    for prefix in _INFERENCE_PROFILE_PREFIXES:
+        if model_id.startswith(prefix):
+            return model_id[len(prefix) :]
+    return model_id
+
+
+class BedrockProvider(AIProvider):
+    """AWS Bedrock provider for code summarization.
+
+    Uses the Bedrock Converse API through boto3, so Anthropic Claude, Amazon
+    Titan and the other Bedrock chat models share one request format.
+    Credentials come from the standard AWS chain: environment variables, the
+    shared config and credentials files (``profile`` setting or
+    ``AWS_PROFILE``), SSO, and instance or task roles. ``api_base`` sets a
+    custom endpoint, e.g. a VPC interface endpoint.
+    """
+
+    DEFAULT_REQUESTS_PER_MINUTE = 50
+
+    def __init__(self, config: AIProviderConfig):
+        """Initialize the Bedrock provider.
+
+        Raises:
+            ImportError: If boto3 is not installed.
+            ValueError: If no AWS region or credentials are configured.
+        """
+        super().__init__(config)
+        logger.info(f"Initializing AWS Bedrock provider with model: {config.model}")
+
+        try:
+            import boto3  # type: ignore[import-untyped]
+        except ImportError as err:
+            raise ImportError(
+                "boto3 package is required for AWS Bedrock support. "
+                "Install it with: pip install boto3"
+            ) from err
+
+        if config.api_key:
+            logger.warning(
+                "AWS Bedrock ignores ai_api_key; credentials come from the AWS credential chain"
+            )
+
+        self._session_aws = boto3.Session(
+            profile_name=config.extra_params.get("profile") or None,
+            region_name=config.extra_params.get("region") or os.getenv("AWS_REGION") or None,
+        )
+        self.region = self._session_aws.region_name
+        if not self.region:
+            raise ValueError(
+                "AWS region not configured for Bedrock. Set AWS_REGION, a region in your AWS "
+                "profile, or 'region' under ai_provider_settings.bedrock"
+            )
+        if self._session_aws.get_credentials() is None:
+            error_msg = (
+                "AWS credentials not found for Bedrock. Please configure one of the following:\n"
+                "1. Set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (and AWS_SESSION_TOKEN)\n"
+                "2. Set AWS_PROFILE or 'profile' under ai_provider_settings.bedrock\n"
+                "3. Sign in with 'aws sso login' or run with an instance or task role"
+            )
+            logger.error(error_msg)
+            raise ValueError(error_msg)
+
+        if not config.model:
+            config.model = "anthropic.claude-3-haiku-20240307-v1:0"  # Widely available, cheap
+            logger.debug(f"Using default model: {config.model}")
+
+        # Set costs from models_config if not specified
+        if config.cost_per_1k_input_tokens == 0:
+            from ..models_config import get_model_config
+
+            model_cfg = get_model_config(base_model_id(config.model))
+            if model_cfg:
+                config.cost_per_1k_input_tokens = model_cfg.cost_per_1k_input
+                config.cost_per_1k_output_tokens = model_cfg.cost_per_1k_output
+
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
+
+        self._concurrent_limit = asyncio.Semaphore(10)  # Max 10 concurrent requests
+
+        # Lazy-load the client
+        self._client = None
+
+        logger.info(
+            f"AWS Bedrock provider initialized - Model: {config.model}, Region: {self.region}, "
+            f"Cache: {bool(self.cache)}"
+        )
+
+    def _get_client(self):
+        """Get or create the bedrock-runtime client."""
+        if self._client is None:
+            from botocore.config import Config  # type: ignore[import-untyped]
+
+            self._client = self._session_aws.client(
+                "bedrock-runtime",
+                endpoint_url=self.config.api_base or None,
+                # Retries are handled by _retry_with_backoff
+                config=Config(
+                    read_timeout=self.config.timeout,
+                    retries={"max_attempts": 1, "mode": "standard"},
+                ),
+            )
+        return self._client
+
+    def _converse_request(
+        self, messages: list[dict[str, str]], max_tokens: int | None
+    ) -> dict[str, Any]:
+        """Build the Converse request for OpenAI-style ``messages``."""
+        system = "\n\n".join(m["content"] for m in messages if m["role"] == "system")
+        turns = [
+            {"role": m["role"], "content": [{"text": m["content"]}]}
+            for m in messages
+            if m["role"] != "system"
+        ]
+        request: dict[str, Any] = {
+            "modelId": self.config.model,
+            "messages": turns,
+            "inferenceConfig": {
+                "maxTokens": max_tokens or self.config.max_tokens,
+                "temperature": self.config.temperature,
+            },
+        }
+        if system:
+            if base_model_id(self.config.model).startswith("amazon.titan"):
+                # Titan text models take no system prompt; lead the first turn with it
+                first = turns[0]["content"][0]
+                first["text"] = f"{system}\n\n{first['text']}"
+            else:
+                request["system"] = [{"text": system}]
+        extra = {k: v for k, v in self.config.extra_params.items() if k not in _PROVIDER_SETTINGS}
+        if extra:
+            request["additionalModelRequestFields"] = extra
+        return request
+
+    async def _make_api_call(
+        self, messages: list[dict[str, str]], max_tokens: int | None = None
+    ) -> dict:
+        """Make a Converse call with concurrency control.
+
+        Raises:
+            ProviderAPIError: On a Bedrock error response, with its HTTP status.
+        """
+        client = self._get_client()
+        request = self._converse_request(messages, max_tokens)
+
+        logger.info(f"Making AWS Bedrock API call with model {self.config.model}")
+
+        # Run the synchronous API call in a thread pool
+        loop = asyncio.get_event_loop()
+        async with self._concurrent_limit:
+            try:
+                response = await loop.run_in_executor(None, lambda: client.converse(**request))
+            except Exception as e:
+                error_response = getattr(e, "response", None)
+                if not isinstance(error_response, dict):
+                    raise
+                status = error_response.get("ResponseMetadata", {}).get("HTTPStatusCode", 500)
+                code = error_response.get("Error", {}).get("Code", type(e).__name__)
+                message = error_response.get("Error", {}).get("Message", str(e))
+                raise ProviderAPIError(
+                    f"AWS Bedrock API error ({status} {code}): {message}", int(status)
+                ) from e
+
+        # Convert to the OpenAI-like format shared by the summarize methods
+        content = response.get("output", {}).get("message", {}).get("content", [])
+        usage = response.get("usage", {})
+        result = {
+            "choices": [
+                {"message": {"content": "".join(part.get("text", "") for part in content)}}
+            ],
+            "usage": {
+                "prompt_tokens": usage.get("inputTokens", 0),
+                "completion_tokens": usage.get("outputTokens", 0),
+                "total_tokens": usage.get("totalTokens", 0),
+            },
+        }
+
+        logger.info(f"AWS Bedrock API call successful - tokens used: {result['usage']}")
+        return result
+
+    async def summarize_code(
+        self,
+        code: str,
+        language: str,
+        context: dict[str, Any] | None = None,
+        max_length: int | None = None,
+    ) -> SummarizationResult:
+        """Generate a summary for a code file using AWS Bedrock."""
+        # Check cache first
+        if self.cache:
+            cache_key = self.cache.generate_key(
+                code, "bedrock", self.config.model, "summarize_code", language=language
+            )
+            cached_summary = await self.cache.get(cache_key)
+            if cached_summary:
+                logger.info(f"Cache hit for {language} code - returning cached summary")
+                return SummarizationResult(
+                    summary=cached_summary,
+                    model_used=self.config.model,
+                    provider="bedrock",
+                    cached=True,
+                )
+
+        messages = [
+            {"role": "system", "content": self._system_prompt("file")},
+            {"role": "user", "content": self._create_code_summary_prompt(code, language, context)},
+        ]
+
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_length)
+
+            summary = response["choices"][0]["message"]["content"].strip()
+            usage = response["usage"]
+            tokens_used = usage["total_tokens"]
+            input_tokens = usage["prompt_tokens"]
+            output_tokens = usage["completion_tokens"]
+            cost = self._calculate_cost(input_tokens, output_tokens)
+
+            # Cache the result
+            if self.cache and cache_key:
+                await self.cache.set(cache_key, summary, {"tokens": tokens_used, "cost": cost})
+
+            return SummarizationResult(
+                summary=summary,
+                tokens_used=tokens_used,
+                cost_estimate=cost,
+                model_used=self.config.model,
+                provider="bedrock",
+                cached=False,
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+
+        except Exception as e:
+            logger.error(f"AWS Bedrock API error: {e}")
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="bedrock"
+            )
+
+    async def summarize_function(
+        self,
+        function_code: str,
+        function_name: str,
+        language: str,
+        context: dict[str, Any] | None = None,
+    ) -> SummarizationResult:
+        """Generate a summary for a specific function using AWS Bedrock."""
+        # Check cache first
+        if self.cache:
+            cache_key = self.cache.generate_key(
+                function_code,
+                "bedrock",
+                self.config.model,
+                "summarize_function",
+                function_name=function_name,
+                language=language,
+            )
+            cached_summary = await self.cache.get(cache_key)
+            if cached_summary:
+                logger.info(f"Cache hit for {function_name} - returning cached summary")
+                return SummarizationResult(
+                    summary=cached_summary,
+                    model_used=self.config.model,
+                    provider="bedrock",
+                    cached=True,
+                )
+
+        user_prompt = self._create_function_summary_prompt(
+            function_code, function_name, language, context
+        )
+        messages = [
+            {"role": "system", "content": self._system_prompt("function")},
+            {"role": "user", "content": user_prompt},
+        ]
+
+        try:
+            response = await self._retry_with_backoff(
+                self._make_api_call,
+                messages,
+                200,  # Shorter max tokens for function summaries
+            )
+
+            summary = response["choices"][0]["message"]["content"].strip()
+            usage = response["usage"]
+            tokens_used = usage["total_tokens"]
+            input_tokens = usage["prompt_tokens"]
+            output_tokens = usage["completion_tokens"]
+            cost = self._calculate_cost(input_tokens, output_tokens)
+
+            # Cache the result
+            if self.cache and cache_key:
+                await self.cache.set(cache_key, summary, {"tokens": tokens_used, "cost": cost})
+
+            return SummarizationResult(
+                summary=summary,
+                tokens_used=tokens_used,
+                cost_estimate=cost,
+                model_used=self.config.model,
+                provider="bedrock",
+                cached=False,
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+
+        except Exception as e:
+            logger.error(f"AWS Bedrock API error: {e}")
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="bedrock"
+            )
+
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to AWS Bedrock without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            return SummarizationResult(
+                summary=response["choices"][0]["message"]["content"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="bedrock",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="bedrock"
+            )
+
+    async def get_model_info(self) -> dict[str, Any]:
+        """Get information about the current Bedrock model."""
+        from ..models_config import get_model_config
+
+        model_cfg = get_model_config(base_model_id(self.config.model))
+        info: dict[str, Any] = {
+            "provider": "bedrock",
+            "model": self.config.model,
+            "region": self.region,
+            "temperature": self.config.temperature,
+            "cost_per_1k_input": self.config.cost_per_1k_input_tokens,
+            "cost_per_1k_output": self.config.cost_per_1k_output_tokens,
+        }
+        if model_cfg:
+            info["context_window"] = model_cfg.context_window
+            info["max_output"] = model_cfg.max_output
+        return info
+
+    async def validate_connection(self) -> bool:
+        """Validate that the model answers a minimal request."""
+        try:
+            messages = [{"role": "user", "content": "test"}]
+            response = await self._make_api_call(messages, max_tokens=10)
+            return bool(response.get("choices"))
+        except Exception as e:
+            logger.error(f"AWS Bedrock connection validation failed: {e}")
+            return False
+
+    async def close(self):
+        """Clean up resources."""
+        if self._client is not None:
+            self._client.close()
+            self._client = None
+        await super().close()
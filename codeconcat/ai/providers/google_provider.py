@@ -18,6 +18,8 @@ class GoogleProvider(AIProvider):
     Uses the official google-genai SDK for native API access.
     """
 
+    DEFAULT_REQUESTS_PER_MINUTE = 120
+
     def __init__(self, config: AIProviderConfig):
         """Initialize Google Gemini provider."""
         super().__init__(config)
@@ -41,12 +43,8 @@ class GoogleProvider(AIProvider):
                 config.cost_per_1k_input_tokens = model_cfg.cost_per_1k_input
                 config.cost_per_1k_output_tokens = model_cfg.cost_per_1k_output
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
-        # Rate limiting for Google API
-        self._rate_limit_delay = 0.5  # seconds between requests
-        self._last_request_time = 0.0
-        self._rate_limit_lock = asyncio.Lock()
         self._concurrent_limit = asyncio.Semaphore(5)  # Max 5 concurrent requests
 
         # Lazy-load the client
@@ -79,19 +77,7 @@ class GoogleProvider(AIProvider):
         return self._client
 
     async def _make_api_call(self, prompt: str, max_tokens: int | None = None) -> dict:
-        """Make an API call to Google Gemini with rate limiting."""
-        import time
-
-        # Use semaphore to limit concurrent requests and enforce minimum delay
-        async with self._concurrent_limit, self._rate_limit_lock:
-            now = time.time()
-            time_since_last = now - self._last_request_time
-            if time_since_last < self._rate_limit_delay:
-                wait_time = self._rate_limit_delay - time_since_last
-                logger.debug(f"Rate limiting: waiting {wait_time:.2f}s")
-                await asyncio.sleep(wait_time)
-            self._last_request_time = time.time()
-
+        """Make an API call to Google Gemini with concurrency control."""
         client = self._get_client()
 
         # Configure generation parameters
@@ -105,14 +91,15 @@ class GoogleProvider(AIProvider):
 
         # Run the synchronous API call in a thread pool
         loop = asyncio.get_event_loop()
-        response = await loop.run_in_executor(
-            None,
-            lambda: client.models.generate_content(
-                model=self.config.model,
-                contents=prompt,
-                config=generation_config,
-            ),
-        )
+        async with self._concurrent_limit:
+            response = await loop.run_in_executor(
+                None,
+                lambda: client.models.generate_content(
+                    model=self.config.model,
+                    contents=prompt,
+                    config=generation_config,
+                ),
+            )
 
         # Extract response data
         result = {
@@ -157,11 +144,7 @@ class GoogleProvider(AIProvider):
                 )
 
         # Create the prompt
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         user_prompt = self._create_code_summary_prompt(code, language, context)
 
         # Combine system and user prompts for Gemini
@@ -230,11 +213,7 @@ class GoogleProvider(AIProvider):
                 )
 
         # Create the prompt
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         user_prompt = self._create_function_summary_prompt(
             function_code, function_name, language, context
         )
@@ -280,6 +259,32 @@ class GoogleProvider(AIProvider):
                 summary="", error=str(e), model_used=self.config.model, provider="google"
             )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to Google Gemini without caching."""
+        full_prompt = f"{system_prompt}\n\n{prompt}" if system_prompt else prompt
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, full_prompt, max_tokens)
+            usage = response["usage"]
+            input_tokens = usage["prompt_tokens"]
+            output_tokens = usage["completion_tokens"]
+            return SummarizationResult(
+                summary=response["text"].strip(),
+                tokens_used=usage["total_tokens"],
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="google",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="google"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current Google Gemini model."""
         from ..models_config import get_model_config
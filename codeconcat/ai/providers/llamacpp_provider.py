@@ -62,7 +62,7 @@ class LlamaCppProvider(AIProvider):
         config.cost_per_1k_input_tokens = 0
         config.cost_per_1k_output_tokens = 0
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
         self._llm = None
         self._initialize_model()
 
@@ -137,12 +137,7 @@ class LlamaCppProvider(AIProvider):
         base_prompt = self._create_code_summary_prompt(code, language, context)
 
         # Format for Llama chat model
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         prompt = f"""<s>[INST] <<SYS>>
 {system_prompt}
 <</SYS>>
@@ -221,12 +216,7 @@ class LlamaCppProvider(AIProvider):
         )
 
         # Format for Llama chat model
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         prompt = f"""<s>[INST] <<SYS>>
 {system_prompt}
 <</SYS>>
@@ -268,6 +258,38 @@ class LlamaCppProvider(AIProvider):
                 provider="llamacpp",
             )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to the local Llama model without caching."""
+        system = system_prompt or "You are a helpful assistant."
+        full_prompt = f"""<s>[INST] <<SYS>>
+{system}
+<</SYS>>
+
+{prompt} [/INST]"""
+        try:
+            reply = await self._generate(full_prompt, max_tokens)
+            input_tokens = self._estimate_tokens(full_prompt)
+            output_tokens = self._estimate_tokens(reply)
+            return SummarizationResult(
+                summary=reply,
+                tokens_used=input_tokens + output_tokens,
+                model_used=Path(self.config.model).name,
+                provider="llamacpp",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="",
+                error=str(e),
+                model_used=Path(self.config.model).name,
+                provider="llamacpp",
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current local model."""
         model_name = Path(self.config.model).name if self.config.model else "unknown"
@@ -1,4 +1,4 @@
-"""OpenAI-compatible local server provider for vLLM, TGI, LocalAI, etc."""
+"""OpenAI-compatible server provider for local runtimes (vLLM, TGI, LocalAI) and hosted APIs."""
 
 import asyncio
 import logging
@@ -8,7 +8,14 @@ from typing import Any
 import aiohttp
 from aiohttp import ClientConnectorError
 
-from ..base import AIProvider, AIProviderConfig, AIProviderType, SummarizationResult
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    AIProviderType,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
 from ..cache import SummaryCache
 
 _SERVER_PRESETS: dict[AIProviderType, dict[str, str | None]] = {
@@ -65,8 +72,35 @@ _SERVER_PRESETS: dict[AIProviderType, dict[str, str | None]] = {
         "model_env": "QWEN_MODEL",
         "default_model": "qwen-coder-plus",
     },
+    AIProviderType.MISTRAL: {
+        "name": "Mistral",
+        "api_base": "https://api.mistral.ai",
+        "api_base_env": "MISTRAL_API_BASE",
+        "api_key_env": "MISTRAL_API_KEY",
+        "model_env": "MISTRAL_MODEL",
+        "default_model": "codestral-latest",
+    },
+    AIProviderType.GROQ: {
+        "name": "Groq",
+        "api_base": "https://api.groq.com/openai",
+        "api_base_env": "GROQ_API_BASE",
+        "api_key_env": "GROQ_API_KEY",
+        "model_env": "GROQ_MODEL",
+        "default_model": "llama-3.3-70b-versatile",
+    },
 }
 
+# Presets that are hosted cloud APIs (billed) rather than local servers
+_CLOUD_PROVIDERS = frozenset(
+    {
+        AIProviderType.DEEPSEEK,
+        AIProviderType.MINIMAX,
+        AIProviderType.QWEN,
+        AIProviderType.MISTRAL,
+        AIProviderType.GROQ,
+    }
+)
+
 
 def _first_non_empty_env(*env_names: str | None) -> str | None:
     """Return the first environment variable with a non-empty value."""
@@ -101,11 +135,7 @@ class LocalServerProvider(AIProvider):
         self.server_kind = extra_server_kind or preset.get("name") or "local server"
 
         # Check if this is a cloud provider (has costs) vs local server (free)
-        self._is_cloud_provider = config.provider_type in {
-            AIProviderType.DEEPSEEK,
-            AIProviderType.MINIMAX,
-            AIProviderType.QWEN,
-        }
+        self._is_cloud_provider = config.provider_type in _CLOUD_PROVIDERS
 
         # Resolve API base precedence: explicit config > provider-specific env > generic env > preset default
         api_base_from_env = _first_non_empty_env(preset.get("api_base_env"), "LOCAL_LLM_API_BASE")
@@ -141,7 +171,7 @@ class LocalServerProvider(AIProvider):
         if config.api_key is None:
             config.api_key = ""
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
         self._auto_discovery_needed = not bool(config.model)
         self._model_autodiscovery_attempted = False
         self._auto_discovered_model: str | None = None
@@ -279,14 +309,20 @@ class LocalServerProvider(AIProvider):
                 async with session.post(url, json=payload) as retry_response:
                     if retry_response.status != 200:
                         error_text = await retry_response.text()
-                        raise Exception(
-                            f"Local server API error ({retry_response.status}): {error_text}"
+                        raise ProviderAPIError(
+                            f"Local server API error ({retry_response.status}): {error_text}",
+                            retry_response.status,
+                            parse_retry_after(retry_response.headers),
                         )
                     result = await retry_response.json()
                     return dict(result) if result else {}
             elif response.status != 200:
                 error_text = await response.text()
-                raise Exception(f"Local server API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"Local server API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
             else:
                 result = await response.json()
                 return dict(result) if result else {}
@@ -314,11 +350,7 @@ class LocalServerProvider(AIProvider):
                 )
 
         # Create messages for OpenAI-compatible format
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         user_prompt = self._create_code_summary_prompt(code, language, context)
 
         messages = [
@@ -403,11 +435,7 @@ class LocalServerProvider(AIProvider):
                 )
 
         # Create messages
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         user_prompt = self._create_function_summary_prompt(
             function_code, function_name, language, context
         )
@@ -469,6 +497,35 @@ class LocalServerProvider(AIProvider):
                 summary="", error=str(e), model_used=self.config.model, provider=provider_name
             )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to the local server without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        provider_name = self.server_kind.lower().replace(" ", "_").replace("/", "_")
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            return SummarizationResult(
+                summary=response["choices"][0]["message"]["content"].strip(),
+                tokens_used=usage.get("total_tokens", input_tokens + output_tokens),
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider=provider_name,
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider=provider_name
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current local server model."""
         provider_name = self.server_kind.lower().replace(" ", "_").replace("/", "_")
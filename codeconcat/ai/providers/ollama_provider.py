@@ -6,7 +6,13 @@ from typing import Any
 
 import aiohttp
 
-from ..base import AIProvider, AIProviderConfig, SummarizationResult
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
 from ..cache import SummaryCache
 
 
@@ -21,7 +27,12 @@ class OllamaProvider(AIProvider):
 
         # Set defaults for Ollama
         if not config.api_base:
-            config.api_base = os.getenv("OLLAMA_API_BASE", "http://localhost:11434")
+            config.api_base = (
+                os.getenv("OLLAMA_API_BASE") or os.getenv("OLLAMA_HOST") or "http://localhost:11434"
+            )
+            if "://" not in config.api_base:
+                # OLLAMA_HOST is commonly set as host:port without a scheme
+                config.api_base = f"http://{config.api_base}"
 
         # Auto-discover models if not specified
         if not config.model:
@@ -31,7 +42,7 @@ class OllamaProvider(AIProvider):
         config.cost_per_1k_input_tokens = 0
         config.cost_per_1k_output_tokens = 0
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
     async def _auto_discover_model(self) -> str | None:
         """Auto-discover the best available model for code summarization."""
@@ -122,7 +133,11 @@ class OllamaProvider(AIProvider):
         async with session.post(url, json=payload) as response:
             if response.status != 200:
                 error_text = await response.text()
-                raise Exception(f"Ollama API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"Ollama API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
 
             result = await response.json()
             return dict(result) if result else {}
@@ -150,12 +165,7 @@ class OllamaProvider(AIProvider):
                 )
 
         # Create the prompt (Ollama uses a single prompt, not messages)
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         user_prompt = self._create_code_summary_prompt(code, language, context)
         full_prompt = f"{system_prompt}\n\n{user_prompt}"
 
@@ -228,12 +238,7 @@ class OllamaProvider(AIProvider):
                 )
 
         # Create the prompt
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         user_prompt = self._create_function_summary_prompt(
             function_code, function_name, language, context
         )
@@ -282,6 +287,30 @@ class OllamaProvider(AIProvider):
                 summary="", error=str(e), model_used=self.config.model, provider="ollama"
             )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to Ollama without caching."""
+        full_prompt = f"{system_prompt}\n\n{prompt}" if system_prompt else prompt
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, full_prompt, max_tokens)
+            input_tokens = response.get("prompt_eval_count", 0)
+            output_tokens = response.get("eval_count", 0)
+            return SummarizationResult(
+                summary=response["response"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                model_used=self.config.model,
+                provider="ollama",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="ollama"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current Ollama model."""
         info = {
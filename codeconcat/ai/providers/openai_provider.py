@@ -3,12 +3,17 @@
 import asyncio
 import logging
 import os
-import time
 from typing import Any
 
 import aiohttp
 
-from ..base import AIProvider, AIProviderConfig, SummarizationResult
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
 from ..cache import SummaryCache
 
 logger = logging.getLogger(__name__)
@@ -17,6 +22,9 @@ logger = logging.getLogger(__name__)
 class OpenAIProvider(AIProvider):
     """OpenAI API provider for code summarization."""
 
+    # Tier-dependent limits: free tier 3 RPM, Tier 1 500 RPM, Tier 2 5000 RPM
+    DEFAULT_REQUESTS_PER_MINUTE = 200
+
     _session: aiohttp.ClientSession | None
 
     def __init__(self, config: AIProviderConfig):
@@ -68,18 +76,12 @@ class OpenAIProvider(AIProvider):
                     config.cost_per_1k_input_tokens = 0.001
                     config.cost_per_1k_output_tokens = 0.002
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
-        # Rate limiting: OpenAI tier-dependent limits
-        # Free tier: 3 RPM, Tier 1: 500 RPM, Tier 2: 5000 RPM
-        # Using Tier 1 with concurrent requests: 500 RPM / 10 concurrent = 50 RPM per slot = 1.2s delay
-        self._rate_limit_delay = 0.3  # seconds between requests (allows ~200 RPM with concurrency)
-        self._last_request_time = 0.0
-        self._rate_limit_lock = asyncio.Lock()
         self._concurrent_limit = asyncio.Semaphore(10)  # Max 10 concurrent requests
 
         logger.info(
-            f"OpenAI provider initialized - Model: {config.model}, Cache: {bool(self.cache)}, API Key: {bool(config.api_key)}, Rate limit: {self._rate_limit_delay}s"
+            f"OpenAI provider initialized - Model: {config.model}, Cache: {bool(self.cache)}, API Key: {bool(config.api_key)}, Rate limit: {self.rate_limiter.interval:.2f}s"
         )
 
     async def _get_session(self) -> aiohttp.ClientSession:
@@ -107,7 +109,7 @@ class OpenAIProvider(AIProvider):
         return self._session
 
     async def _make_api_call(self, messages: list, max_tokens: int | None = None) -> dict:
-        """Execute an API request to OpenAI with rate limiting and concurrency control.
+        """Execute an API request to OpenAI with concurrency control.
 
         Handles the HTTP communication with OpenAI's chat completions endpoint,
         including model-specific parameter adjustments for reasoning models.
@@ -120,22 +122,10 @@ class OpenAIProvider(AIProvider):
             JSON response dictionary from the API.
 
         Raises:
-            Exception: On API error (non-200 status) with HTTP status code and error details.
+            ProviderAPIError: On API error (non-200 status) with HTTP status code and error details.
             aiohttp.ClientError: On network/connection errors (timeout, DNS failure, etc.).
         """
-        # Use semaphore to limit concurrent requests
-        async with self._concurrent_limit:
-            # Enforce minimum delay between requests
-            async with self._rate_limit_lock:
-                now = time.time()
-                time_since_last = now - self._last_request_time
-                if time_since_last < self._rate_limit_delay:
-                    wait_time = self._rate_limit_delay - time_since_last
-                    logger.debug(f"Rate limiting: waiting {wait_time:.2f}s")
-                    await asyncio.sleep(wait_time)
-                self._last_request_time = time.time()
-
-            session = await self._get_session()
+        session = await self._get_session()
 
         # GPT-5 and o-series models use max_completion_tokens instead of max_tokens
         # and require temperature=1.0
@@ -165,11 +155,16 @@ class OpenAIProvider(AIProvider):
             f"Payload: messages={len(messages)} msgs, max_tokens={max_tokens or self.config.max_tokens}"
         )
 
-        async with session.post(url, json=payload) as response:
+        # Use semaphore to limit concurrent requests
+        async with self._concurrent_limit, session.post(url, json=payload) as response:
             if response.status != 200:
                 error_text = await response.text()
                 logger.error(f"OpenAI API error ({response.status}): {error_text}")
-                raise Exception(f"OpenAI API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"OpenAI API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
 
             result = await response.json()
             logger.info(
@@ -222,12 +217,7 @@ class OpenAIProvider(AIProvider):
         # Create the prompt
         prompt = self._create_code_summary_prompt(code, language, context)
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         messages = [
             {
                 "role": "system",
@@ -330,12 +320,7 @@ class OpenAIProvider(AIProvider):
             function_code, function_name, language, context
         )
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         messages = [
             {
                 "role": "system",
@@ -426,11 +411,7 @@ class OpenAIProvider(AIProvider):
         )
 
         # Prepare messages
-        system_prompt = (
-            "You are a senior software architect conducting a comprehensive codebase analysis. "
-            "Your expertise includes system design, architectural patterns, technology assessment, "
-            "and identifying technical debt and improvement opportunities across large-scale projects."
-        )
+        system_prompt = self._system_prompt("overview")
 
         messages = [
             {"role": "system", "content": system_prompt},
@@ -539,6 +520,34 @@ class OpenAIProvider(AIProvider):
             ):
                 self.config.temperature = original_temp
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to OpenAI without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            return SummarizationResult(
+                summary=response["choices"][0]["message"]["content"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="openai",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="openai"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current OpenAI model."""
         model_info = {
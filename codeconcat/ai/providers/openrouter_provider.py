@@ -7,7 +7,13 @@ from typing import Any
 
 import aiohttp
 
-from ..base import AIProvider, AIProviderConfig, SummarizationResult
+from ..base import (
+    AIProvider,
+    AIProviderConfig,
+    ProviderAPIError,
+    SummarizationResult,
+    parse_retry_after,
+)
 from ..cache import SummaryCache
 
 logger = logging.getLogger(__name__)
@@ -38,7 +44,7 @@ class OpenRouterProvider(AIProvider):
             config.cost_per_1k_input_tokens = 0.0001
             config.cost_per_1k_output_tokens = 0.0001
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
     async def _get_session(self) -> aiohttp.ClientSession:
         """Get or create an aiohttp session (thread-safe)."""
@@ -74,7 +80,11 @@ class OpenRouterProvider(AIProvider):
         async with session.post(url, json=payload) as response:
             if response.status != 200:
                 error_text = await response.text()
-                raise Exception(f"OpenRouter API error ({response.status}): {error_text}")
+                raise ProviderAPIError(
+                    f"OpenRouter API error ({response.status}): {error_text}",
+                    response.status,
+                    parse_retry_after(response.headers),
+                )
 
             result = await response.json()
             return dict(result) if result else {}
@@ -104,12 +114,7 @@ class OpenRouterProvider(AIProvider):
         # Create the prompt
         prompt = self._create_code_summary_prompt(code, language, context)
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         messages = [
             {
                 "role": "system",
@@ -198,12 +203,7 @@ class OpenRouterProvider(AIProvider):
             function_code, function_name, language, context
         )
 
-        # Use getattr with fallback to prevent AttributeError
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         messages = [
             {
                 "role": "system",
@@ -307,11 +307,7 @@ class OpenRouterProvider(AIProvider):
         )
 
         # Prepare messages
-        system_prompt = (
-            "You are a senior software architect conducting a comprehensive codebase analysis. "
-            "Your expertise includes system design, architectural patterns, technology assessment, "
-            "and identifying technical debt and improvement opportunities across large-scale projects."
-        )
+        system_prompt = self._system_prompt("overview")
 
         messages = [
             {"role": "system", "content": system_prompt},
@@ -368,6 +364,35 @@ class OpenRouterProvider(AIProvider):
             self.config.model = original_model
             self.config.extra_params = original_extra_params
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to OpenRouter without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            cost = usage.get("total_cost", 0.0) or self._calculate_cost(input_tokens, output_tokens)
+            return SummarizationResult(
+                summary=response["choices"][0]["message"]["content"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=cost,
+                model_used=response.get("model", self.config.model),
+                provider="openrouter",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="openrouter"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current OpenRouter model."""
         # OpenRouter supports many models, return generic info
@@ -18,6 +18,8 @@ class ZhipuProvider(AIProvider):
     Uses the official zhipuai SDK.
     """
 
+    DEFAULT_REQUESTS_PER_MINUTE = 200
+
     def __init__(self, config: AIProviderConfig):
         """Initialize Zhipu GLM provider."""
         super().__init__(config)
@@ -41,12 +43,8 @@ class ZhipuProvider(AIProvider):
                 config.cost_per_1k_input_tokens = model_cfg.cost_per_1k_input
                 config.cost_per_1k_output_tokens = model_cfg.cost_per_1k_output
 
-        self.cache = SummaryCache() if config.cache_enabled else None
+        self.cache = SummaryCache.from_provider_config(config) if config.cache_enabled else None
 
-        # Rate limiting for Zhipu API
-        self._rate_limit_delay = 0.3  # seconds between requests
-        self._last_request_time = 0.0
-        self._rate_limit_lock = asyncio.Lock()
         self._concurrent_limit = asyncio.Semaphore(10)  # Max 10 concurrent requests
 
         # Lazy-load the client
@@ -76,19 +74,7 @@ class ZhipuProvider(AIProvider):
     async def _make_api_call(
         self, messages: list[dict[str, str]], max_tokens: int | None = None
     ) -> dict:
-        """Make an API call to Zhipu GLM with rate limiting."""
-        import time
-
-        # Use semaphore to limit concurrent requests and enforce minimum delay
-        async with self._concurrent_limit, self._rate_limit_lock:
-            now = time.time()
-            time_since_last = now - self._last_request_time
-            if time_since_last < self._rate_limit_delay:
-                wait_time = self._rate_limit_delay - time_since_last
-                logger.debug(f"Rate limiting: waiting {wait_time:.2f}s")
-                await asyncio.sleep(wait_time)
-            self._last_request_time = time.time()
-
+        """Make an API call to Zhipu GLM with concurrency control."""
         client = self._get_client()
 
         logger.info(f"Making Zhipu GLM API call with model {self.config.model}")
@@ -105,7 +91,8 @@ class ZhipuProvider(AIProvider):
                 max_tokens=max_tokens or self.config.max_tokens,
             )
 
-        response = await loop.run_in_executor(None, make_request)
+        async with self._concurrent_limit:
+            response = await loop.run_in_executor(None, make_request)
 
         # Extract response data (OpenAI-like format)
         result = {
@@ -150,11 +137,7 @@ class ZhipuProvider(AIProvider):
                 )
 
         # Create the prompt
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_CODE_SUMMARY",
-            "You are a helpful assistant that creates concise, informative code summaries.",
-        )
+        system_prompt = self._system_prompt("file")
         user_prompt = self._create_code_summary_prompt(code, language, context)
 
         messages = [
@@ -225,11 +208,7 @@ class ZhipuProvider(AIProvider):
                 )
 
         # Create the prompt
-        system_prompt = getattr(
-            self,
-            "SYSTEM_PROMPT_FUNCTION_SUMMARY",
-            "You are a helpful assistant that creates concise function summaries.",
-        )
+        system_prompt = self._system_prompt("function")
         user_prompt = self._create_function_summary_prompt(
             function_code, function_name, language, context
         )
@@ -277,6 +256,34 @@ class ZhipuProvider(AIProvider):
                 summary="", error=str(e), model_used=self.config.model, provider="zhipu"
             )
 
+    async def complete(
+        self,
+        prompt: str,
+        system_prompt: str | None = None,
+        max_tokens: int | None = None,
+    ) -> SummarizationResult:
+        """Send a free-form prompt to Zhipu GLM without caching."""
+        messages = [{"role": "user", "content": prompt}]
+        if system_prompt:
+            messages.insert(0, {"role": "system", "content": system_prompt})
+        try:
+            response = await self._retry_with_backoff(self._make_api_call, messages, max_tokens)
+            usage = response.get("usage", {})
+            input_tokens = usage.get("prompt_tokens", 0)
+            output_tokens = usage.get("completion_tokens", 0)
+            return SummarizationResult(
+                summary=response["choices"][0]["message"]["content"].strip(),
+                tokens_used=input_tokens + output_tokens,
+                cost_estimate=self._calculate_cost(input_tokens, output_tokens),
+                model_used=self.config.model,
+                provider="zhipu",
+                metadata={"input_tokens": input_tokens, "output_tokens": output_tokens},
+            )
+        except Exception as e:
+            return SummarizationResult(
+                summary="", error=str(e), model_used=self.config.model, provider="zhipu"
+            )
+
     async def get_model_info(self) -> dict[str, Any]:
         """Get information about the current Zhipu GLM model."""
         from ..models_config import get_model_config
@@ -5,6 +5,8 @@ from typing import Any
 
 import tiktoken
 
+from ..utils.offline import require_tiktoken_encoding
+
 logger = logging.getLogger(__name__)
 
 # Cache for tokenizers to avoid re-initialization
@@ -31,27 +33,32 @@ class TokenCounter:
             # OpenAI models using tiktoken
             if "gpt-4o" in model or "o1" in model:
                 # GPT-4o and o1 models use o200k_base
+                require_tiktoken_encoding("o200k_base")
                 enc = tiktoken.get_encoding("o200k_base")
                 _tokenizer_cache[model] = enc
                 return enc
             elif "gpt-4" in model:
                 # GPT-4 models use cl100k_base
+                require_tiktoken_encoding("cl100k_base")
                 enc = tiktoken.get_encoding("cl100k_base")
                 _tokenizer_cache[model] = enc
                 return enc
             elif "gpt-3.5" in model or "turbo" in model:
                 # GPT-3.5 models use cl100k_base
+                require_tiktoken_encoding("cl100k_base")
                 enc = tiktoken.get_encoding("cl100k_base")
                 _tokenizer_cache[model] = enc
                 return enc
             elif "text-davinci" in model or "davinci" in model:
                 # Older models use p50k_base
+                require_tiktoken_encoding("p50k_base")
                 enc = tiktoken.get_encoding("p50k_base")
                 _tokenizer_cache[model] = enc
                 return enc
 
             # Try to get encoding by model name directly
             try:
+                require_tiktoken_encoding(tiktoken.model.encoding_name_for_model(model))
                 enc = tiktoken.encoding_for_model(model)
                 _tokenizer_cache[model] = enc
                 return enc
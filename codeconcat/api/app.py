@@ -76,10 +76,18 @@ class CodeConcatRequest(BaseModel):
         None, description="Local path to process (DISABLED in production for security)"
     )
     source_url: str | None = Field(
-        None, description="GitHub repository URL or shorthand (username/repo)"
+        None,
+        description="GitHub, GitLab or Bitbucket repository URL or shorthand (username/repo)",
     )
-    source_ref: str | None = Field(None, description="Branch, tag, or commit hash for GitHub repos")
+    source_ref: str | None = Field(None, description="Branch, tag, or commit hash for Git repos")
     github_token: str | None = Field(None, description="GitHub token for private repositories")
+    gitlab_token: str | None = Field(None, description="GitLab token for private repositories")
+    bitbucket_token: str | None = Field(
+        None, description="Bitbucket access token or app password for private repositories"
+    )
+    bitbucket_username: str | None = Field(
+        None, description="Bitbucket username to use with an app password"
+    )
 
     # Output options
     format: str = Field("json", description="Output format: json, markdown, xml, or text")
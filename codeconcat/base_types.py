@@ -136,6 +136,31 @@ class SecuritySeverity(IntEnum):
     CRITICAL = 4
 
 
+@dataclass
+class SecurityTriage:
+    """AI assessment of a security finding (``ai_security_triage``).
+
+    Attributes:
+        likelihood: Estimated probability (0.0-1.0) that the finding is a true positive
+        severity: Severity the assessment assigns, which may differ from the scanner's
+        rationale: Short explanation of the likelihood and severity
+
+    """
+
+    likelihood: float
+    severity: SecuritySeverity
+    rationale: str = ""
+
+    @property
+    def verdict(self) -> str:
+        """Return "likely true positive", "uncertain" or "likely false positive"."""
+        if self.likelihood >= 0.7:
+            return "likely true positive"
+        if self.likelihood <= 0.3:
+            return "likely false positive"
+        return "uncertain"
+
+
 @dataclass
 class SecurityIssue:
     """Represents a potential security issue found during scanning.
@@ -147,6 +172,7 @@ class SecurityIssue:
         line_number: Line number where the issue was found
         severity: SecuritySeverity enum level (INFO=0 to CRITICAL=4)
         context: Snippet of code around the issue for context
+        triage: AI assessment of the finding, when ai_security_triage is enabled
 
     """
 
@@ -156,6 +182,7 @@ class SecurityIssue:
     line_number: int
     severity: SecuritySeverity  # Enum for severity level
     context: str = ""  # Snippet of code around the issue
+    triage: SecurityTriage | None = None  # AI triage of the finding
 
 
 # Pydantic model for Custom Security Patterns
@@ -275,6 +302,18 @@ class CustomSecurityPattern(BaseModel):
             raise ValueError("Regex validation failed unexpectedly") from None
 
 
+class SubmoduleFilter(BaseModel):
+    """Include/exclude patterns for one git submodule.
+
+    Patterns match paths relative to the submodule root. ``include_paths``
+    replaces the top-level include patterns when non-empty; ``exclude_paths``
+    is added to the top-level exclude patterns.
+    """
+
+    include_paths: list[str] = Field(default_factory=list)
+    exclude_paths: list[str] = Field(default_factory=list)
+
+
 # --- Data Structures for Parsing & Processing ---
 
 
@@ -735,6 +774,22 @@ class CodeConCatConfig(BaseModel):
     target_path: str = Field(
         ".", description="Local path to process if source_url is not provided."
     )
+    sources: list[str] = Field(
+        default_factory=list,
+        description="Local paths and remote repository URLs or shorthands collected into one "
+        "output, each under its own path prefix ('prefix=location' names the prefix). "
+        "Replaces target_path and source_url.",
+    )
+    files_from: str | None = Field(
+        default=None,
+        description="File listing the paths to collect, one per line or NUL-separated; '-' "
+        "reads stdin. Relative paths resolve against target_path, which is not walked.",
+    )
+    archive: str | None = Field(
+        default=None,
+        description="Path or URL of a zip or tar archive (.zip, .tar, .tar.gz, .tgz, "
+        ".tar.bz2, .tar.xz) whose files are read in memory instead of walking target_path.",
+    )
     # Rename github_url -> source_url
     source_url: str | None = Field(
         None,
@@ -744,6 +799,52 @@ class CodeConCatConfig(BaseModel):
         None,
         description="GitHub token for accessing private repositories or increasing rate limits.",
     )
+    gitlab_token: str | None = Field(
+        None,
+        description="GitLab personal, project or group access token for private repositories.",
+    )
+    gitlab_url: str | None = Field(
+        None,
+        description="Base URL of a self-hosted GitLab instance (e.g. https://gitlab.example.com), "
+        "used for gitlab: shorthands and to recognise its repository URLs.",
+    )
+    bitbucket_token: str | None = Field(
+        None,
+        description="Bitbucket access token, or app password when bitbucket_username is set.",
+    )
+    bitbucket_username: str | None = Field(
+        None,
+        description="Bitbucket username that goes with an app password in bitbucket_token.",
+    )
+    git_protocol: str = Field(
+        "auto",
+        description="Clone protocol: 'auto' (SSH for SSH URLs or when ssh_key_path is set, "
+        "HTTPS otherwise), 'https' or 'ssh'",
+        pattern="^(auto|https|ssh)$",
+    )
+    ssh_key_path: str | None = Field(
+        None,
+        description="Private SSH key for cloning; without it the SSH agent and ~/.ssh/config "
+        "apply.",
+    )
+    github_app_id: str | None = Field(
+        None,
+        description="GitHub App ID; with github_app_private_key, clones GitHub repositories "
+        "with a repository-scoped installation token instead of a personal token.",
+    )
+    github_app_private_key: str | None = Field(
+        None,
+        description="GitHub App private key, as a path to the PEM file or the PEM text itself.",
+    )
+    github_app_installation_id: int | None = Field(
+        None,
+        description="GitHub App installation ID; looked up from the repository when unset.",
+    )
+    sparse_clone: bool = Field(
+        True,
+        description="When every include_paths pattern starts with a directory, clone remote "
+        "repositories partially with a sparse checkout of those directories.",
+    )
     # Rename github_ref -> source_ref
     source_ref: str | None = Field(
         None,
@@ -752,11 +853,59 @@ class CodeConCatConfig(BaseModel):
     # Diff mode fields
     diff_from: str | None = Field(
         None,
-        description="Starting Git ref for diff mode (branch, tag, or commit SHA).",
+        description="Starting Git ref for diff mode (branch, tag, or commit SHA), or the URL "
+        "of a GitHub pull request, GitLab merge request or Bitbucket pull request.",
     )
     diff_to: str | None = Field(
         None,
-        description="Ending Git ref for diff mode (branch, tag, or commit SHA).",
+        description="Ending Git ref for diff mode (branch, tag, or commit SHA). "
+        "Defaults to HEAD when diff_from is a ref.",
+    )
+    diff_context: str = Field(
+        "none",
+        description="Unchanged files added to a diff through the import graph: 'none', "
+        "'dependencies' (files the changes import), 'dependents' (files importing the "
+        "changes) or 'both'.",
+        pattern="^(none|dependencies|dependents|both)$",
+    )
+    # Monorepo fields
+    per_package: bool = Field(
+        False,
+        description="Detect the monorepo workspace at the target (pnpm, npm/Yarn, Lerna, Nx, "
+        "Cargo, go.work) and write one output per package plus a workspace index.",
+    )
+    workspace_output_dir: str = Field(
+        "ccc_codeconcat_workspace",
+        description="Directory for the per-package outputs and the workspace index.",
+    )
+    # Submodule fields
+    include_submodules: bool = Field(
+        False,
+        description="Collect the initialized git submodules listed in .gitmodules, each walked "
+        "as its own root with its own .gitignore.",
+    )
+    submodule_exclude: list[str] = Field(
+        default_factory=list,
+        description="Glob patterns on submodule paths or names; matching submodules and the "
+        "submodules inside them are skipped.",
+    )
+    submodule_filters: dict[str, SubmoduleFilter] = Field(
+        default_factory=dict,
+        description="Per-submodule include/exclude patterns, keyed by submodule path or name.",
+    )
+    # Git history fields
+    include_git_history: bool = Field(
+        False,
+        description="Append a condensed git history (hash, author, date, subject, files touched) "
+        "of recent commits to the output.",
+    )
+    git_history_depth: int = Field(
+        20, ge=1, description="Maximum number of commits in the git history section."
+    )
+    git_history_since: str | None = Field(
+        None,
+        description="Only list commits after this ref in the git history section; implies "
+        "include_git_history.",
     )
     # Removed duplicate - using the one below with None
     exclude_languages: list[str] = Field(
@@ -768,6 +917,65 @@ class CodeConCatConfig(BaseModel):
     exclude_paths: list[str] = Field(
         default_factory=list, description="Patterns for files/directories to exclude."
     )
+    max_file_size: int | None = Field(
+        None,
+        description="Skip files larger than this many bytes; accepts sizes such as '200kb'.",
+    )
+    modified_since: str | None = Field(
+        None,
+        description="Skip files not modified within this age ('30d', '2w', '12h') or since "
+        "this date ('2024-06-01'); uses the last commit for files tracked by git.",
+    )
+    authors: list[str] = Field(
+        default_factory=list,
+        description="Keep only files with a commit by an author whose 'Name <email>' contains "
+        "one of these strings (case-insensitive).",
+    )
+
+    @field_validator("max_file_size", mode="before")
+    @classmethod
+    def _validate_max_file_size(cls, value: str | int | None) -> int | None:
+        """Parse sizes such as '200kb' into bytes."""
+        from codeconcat.collector.file_filters import parse_size
+
+        if value is None or str(value).strip() == "":
+            return None
+        return parse_size(value)
+
+    @field_validator("modified_since")
+    @classmethod
+    def _validate_modified_since(cls, value: str | None) -> str | None:
+        """Reject values that are neither an age nor a date."""
+        from codeconcat.collector.file_filters import parse_since
+
+        if value:
+            parse_since(value)
+        return value or None
+
+    symlink_policy: str = Field(
+        "skip",
+        description="What to do with symbolic links: 'skip' them, 'follow' them (each real "
+        "directory is walked once) or list them as a 'stub' without their target.",
+        pattern="^(skip|follow|stub)$",
+    )
+    symlink_rules: dict[str, str] = Field(
+        default_factory=dict,
+        description="Glob patterns on a link's path or name mapped to 'skip', 'follow' or "
+        "'stub'; the first matching pattern overrides symlink_policy.",
+    )
+
+    @field_validator("symlink_rules")
+    @classmethod
+    def _validate_symlink_rules(cls, value: dict[str, str]) -> dict[str, str]:
+        """Reject unknown policies."""
+        for pattern, policy in value.items():
+            if policy not in ("skip", "follow", "stub"):
+                raise ValueError(
+                    f"Invalid symlink policy '{policy}' for '{pattern}'. "
+                    "Must be one of: skip, follow, stub."
+                )
+        return value
+
     use_gitignore: bool = Field(
         True, description="Whether to respect rules found in .gitignore files."
     )
@@ -791,6 +999,37 @@ class CodeConCatConfig(BaseModel):
         default_factory=dict,
         description="Custom mapping of file extensions to language identifiers",
     )
+    summarize_dependencies: bool = Field(
+        False,
+        description="Replace lockfiles (package-lock.json, Cargo.lock, go.sum, ...) and vendored "
+        "directories with generated dependency summaries instead of excluding them silently",
+    )
+    dependency_summary_max_entries: int = Field(
+        25,
+        description="Maximum direct dependencies and notable versions listed per dependency summary",
+    )
+    include_asset_stubs: bool = Field(
+        False,
+        description="Emit stub entries (path, size, MIME type) for binary assets such as images, "
+        "fonts and archives instead of skipping them silently",
+    )
+    asset_stub_details: bool = Field(
+        True, description="Include image dimensions and archive listings in asset stubs"
+    )
+    asset_stub_max_archive_entries: int = Field(
+        20, description="Maximum archive members listed per asset stub"
+    )
+    sample_data_files: bool = Field(
+        False,
+        description="Replace CSV/TSV/JSON/NDJSON data files with a schema summary and a sample of "
+        "rows instead of their full content",
+    )
+    data_sample_rows: int = Field(5, description="Rows or records included per sampled data file")
+    data_sample_min_json_bytes: int = Field(
+        16384,
+        description="Only sample plain JSON files at least this large; smaller JSON is usually "
+        "configuration and is kept intact",
+    )
     output: str = Field("", description="Output file path (auto-generated if empty)")
     format: str = Field(
         "markdown", description="Output format: 'markdown', 'json', 'xml', or 'text'"
@@ -811,6 +1050,55 @@ class CodeConCatConfig(BaseModel):
     xml_processing_instructions: bool = Field(
         False, description="Include AI processing instructions in XML output"
     )
+    chunk_output: str = Field(
+        "",
+        description="Also write declaration-aware chunks (JSON Lines) to this path for vector "
+        "databases (disabled if empty)",
+    )
+    chunk_max_tokens: int = Field(512, ge=1, description="Maximum tokens per chunk")
+    chunk_overlap_tokens: int = Field(
+        64,
+        ge=0,
+        description="Tokens of trailing context repeated at the start of each following chunk",
+    )
+    embed: bool = Field(
+        False,
+        description="Embed chunks or declarations and export them to a vector store",
+    )
+    embed_backend: str = Field(
+        "sentence-transformers",
+        description="Embedding backend: 'sentence-transformers' (local), 'openai' "
+        "(OpenAI or OpenAI-compatible server) or 'hashing' (offline, no model)",
+        pattern="^(sentence-transformers|openai|hashing)$",
+    )
+    embed_model: str | None = Field(
+        None,
+        description="Embedding model (default: all-MiniLM-L6-v2 / text-embedding-3-small)",
+    )
+    embed_api_base: str | None = Field(
+        None, description="Base URL for the openai embedding backend (e.g. a local vLLM server)"
+    )
+    embed_store: str = Field(
+        "npy",
+        description="Vector store: 'npy' (embeddings.npy + metadata.jsonl), 'faiss', "
+        "'chroma' or 'qdrant'",
+        pattern="^(npy|faiss|chroma|qdrant)$",
+    )
+    embed_output: str = Field(
+        "",
+        description="Vector store directory or Qdrant URL (default: <output>.embeddings)",
+    )
+    embed_units: str = Field(
+        "chunks",
+        description="What to embed: 'chunks' (declaration-aware chunks, sized by "
+        "chunk_max_tokens) or 'declarations' (one record per declaration)",
+        pattern="^(chunks|declarations)$",
+    )
+    embed_collection: str = Field(
+        "codeconcat", description="Collection name for the chroma and qdrant stores"
+    )
+    embed_batch_size: int = Field(64, description="Records sent to the embedder per batch")
+
     max_workers: int = Field(
         4, description="Maximum number of worker threads for parallel processing"
     )
@@ -826,6 +1114,18 @@ class CodeConCatConfig(BaseModel):
     remove_comments: bool = Field(False, description="Remove comments from code in output")
     remove_empty_lines: bool = Field(False, description="Remove empty lines from code in output")
     remove_docstrings: bool = Field(False, description="Remove docstrings from code in output")
+    max_line_length: int = Field(
+        0,
+        ge=0,
+        description="Truncate lines longer than this many characters with a continuation marker "
+        "(0 disables truncation)",
+    )
+    max_string_literal_length: int = Field(
+        0,
+        ge=0,
+        description="Replace string literals longer than this many characters with a preview, "
+        "length and hash (0 disables)",
+    )
     show_line_numbers: bool = Field(False, description="Include line numbers in code output")
     enable_token_counting: bool = Field(
         False, description="Enable token counting for AI processing"
@@ -882,6 +1182,11 @@ class CodeConCatConfig(BaseModel):
     )
     verbose: int = Field(0, description="Verbosity level for logging (0=quiet, 1=info, 2+=debug)")
     quiet: bool = Field(False, description="Suppress all non-error output for API usage")
+    offline: bool = Field(
+        False,
+        description="Strict offline mode: disable every network access and fail fast if an "
+        "enabled feature (remote collection, hosted AI, registry rulesets) needs the network",
+    )
 
     # Markdown cross-linking
     cross_link_symbols: bool = Field(
@@ -954,6 +1259,37 @@ class CodeConCatConfig(BaseModel):
         default_factory=lambda: ["important", "keep", "security"],
         description="Special comment tags that mark segments to always keep despite compression.",
     )
+    compression_report: bool = Field(
+        False,
+        description="Estimate token counts per directory under each compression level and strategy "
+        "instead of writing the final output.",
+    )
+    compression_report_depth: int = Field(
+        1, description="Directory depth used to group files in the compression report."
+    )
+    prompt_compression: bool = Field(
+        False,
+        description="Prune low-information words from comments and docstrings (LLMLingua-style) "
+        "as a final squeeze; code is never modified.",
+    )
+    prompt_compression_ratio: float = Field(
+        0.5, description="Fraction of comment/docstring words to keep when pruning (0-1]."
+    )
+    prompt_compression_backend: str = Field(
+        "self-information",
+        description="Pruning backend: 'self-information' (built-in) or 'llmlingua' (optional package).",
+    )
+    token_budget: int = Field(
+        0,
+        description="Hard token budget for the output. When set, prompt compression only runs once "
+        "the estimated total reaches 90% of the budget (0 = always run when enabled). With "
+        "task, files past the budget in relevance order are compressed or left out.",
+    )
+    task: str | None = Field(
+        None,
+        description="Task description; files are ranked by embedding similarity to it, most "
+        "relevant first, and the ranking decides what fits token_budget.",
+    )
 
     # --- Processing Options ---
     analysis_prompt: str | None = Field(
@@ -968,8 +1304,9 @@ class CodeConCatConfig(BaseModel):
     ai_provider: str = Field(
         "openai",
         description=(
-            "AI provider to use: openai, anthropic, openrouter, ollama, local_server, "
-            "vllm, lmstudio, llamacpp_server, llamacpp (deprecated)"
+            "AI provider to use: openai, anthropic, openrouter, google, deepseek, minimax, "
+            "qwen, zhipu, mistral, groq, azure_openai, bedrock, ollama, local_server, vllm, "
+            "lmstudio, llamacpp_server, llamacpp (deprecated)"
         ),
     )
     ai_api_key: str | None = Field(
@@ -979,6 +1316,21 @@ class CodeConCatConfig(BaseModel):
         None,
         description="Override API base URL for the selected AI provider (useful for local servers)",
     )
+    ai_provider_settings: dict[str, dict[str, Any]] = Field(
+        default_factory=dict,
+        description="Per-provider settings keyed by provider name: model, api_base, api_key_env, "
+        "temperature, max_tokens, timeout, cost_per_1k_input_tokens, cost_per_1k_output_tokens; "
+        "other keys are passed to the API request",
+    )
+    ai_local_only: bool = Field(
+        False,
+        description="Refuse AI providers and endpoints that would send source code off the local "
+        "machine or private network",
+    )
+    ai_allowed_hosts: list[str] = Field(
+        default_factory=list,
+        description="Hostnames of self-hosted model servers accepted by ai_local_only",
+    )
     ai_model: str | None = Field(
         None,
         description="Specific model to use (provider-dependent, e.g., 'gpt-3.5-turbo', 'claude-3-haiku')",
@@ -987,11 +1339,33 @@ class CodeConCatConfig(BaseModel):
         0.3, description="Temperature for AI generation (0.0-1.0, lower is more deterministic)"
     )
     ai_max_tokens: int = Field(500, description="Maximum tokens for AI summaries")
+    ai_max_cost_usd: float | None = Field(
+        None,
+        ge=0,
+        description="Spend cap in USD for AI requests in one run. Summarization is skipped if the "
+        "estimate exceeds it, and stops once actual spend reaches it",
+    )
     ai_cache_enabled: bool = Field(
         True, description="Cache AI summaries to avoid redundant API calls"
     )
+    ai_cache_dir: str | None = Field(
+        None,
+        description="Directory for the AI summary cache (default: $CODECONCAT_CACHE_DIR/ai-summaries "
+        "or ~/.cache/codeconcat/ai-summaries)",
+    )
+    ai_cache_ttl: int = Field(
+        2592000,
+        description="Seconds before cached AI summaries expire (default: 30 days; 0 never expires). "
+        "Keys include the content hash, prompt version and model, so changed files always miss",
+    )
     ai_summarize_functions: bool = Field(
-        False, description="Generate summaries for individual functions/methods"
+        False,
+        description="Generate one-line summaries for individual functions, methods and classes",
+    )
+    ai_functions_public_only: bool = Field(
+        True,
+        description="Only summarize public declarations (no leading underscore or "
+        "private/protected modifier)",
     )
     ai_min_file_lines: int = Field(
         5, description="Minimum file size (in lines) to trigger summarization"
@@ -1006,6 +1380,17 @@ class CodeConCatConfig(BaseModel):
         50000, description="Maximum characters of content to send to AI (truncate if larger)"
     )
     ai_max_concurrent: int = Field(5, description="Maximum concurrent AI API requests")
+    ai_requests_per_minute: float | None = Field(
+        None,
+        gt=0,
+        description="Maximum AI requests per minute (None uses the provider's default limit)",
+    )
+    ai_max_retries: int = Field(
+        2,
+        ge=0,
+        description="Retries per AI request after rate limiting (429), server errors or "
+        "network failures, with exponential backoff",
+    )
     ai_include_languages: list[str] | None = Field(
         None, description="Only summarize these languages (None means all)"
     )
@@ -1025,6 +1410,69 @@ class CodeConCatConfig(BaseModel):
         None,
         description="Custom prompt for meta-overview generation. If None, uses default prompt.",
     )
+    ai_meta_overview_prompt_file: str | None = Field(
+        None,
+        description="File containing the meta-overview prompt (overrides ai_meta_overview_prompt). "
+        "{file_count}, {languages}, {total_loc} and {tree} placeholders are filled in",
+    )
+    ai_prompt_templates: dict[str, str] = Field(
+        default_factory=dict,
+        description="Prompt template overrides keyed by file, file_system, function, "
+        "function_system, directory, directory_system, overview or overview_system",
+    )
+    ai_prompt_templates_dir: str | None = Field(
+        None,
+        description="Directory of prompt template files named <template>.txt or <template>.md "
+        "(override ai_prompt_templates)",
+    )
+    ai_summary_language: str | None = Field(
+        None,
+        description="Natural language of summaries and the meta-overview, as a code (ja, de, "
+        "pt-BR) or a name (Japanese); None writes English",
+    )
+    ai_meta_overview_fallback: bool = Field(
+        True,
+        description="Build a heuristic project overview (README, layout, key modules, tech stack) "
+        "when no AI meta-overview is available",
+    )
+    ai_hierarchical_summary: bool = Field(
+        False,
+        description="Fold file summaries into per-directory summaries, then fold those into the "
+        "meta-overview (scales to repositories too large for a single overview request)",
+    )
+    ai_hierarchy_fan_in: int = Field(
+        20,
+        ge=2,
+        description="Maximum summaries combined in one directory or overview request when "
+        "ai_hierarchical_summary is enabled",
+    )
+    ai_architecture_diagram: bool = Field(
+        False,
+        description="Generate a Mermaid component diagram from the import graph and summaries "
+        "and embed it in the output",
+    )
+    ai_change_summary: bool = Field(
+        True,
+        description="In diff mode, write an AI reviewer changelog of what changed and why it "
+        "matters from the changed declarations, as the first section of the output",
+    )
+    ai_diagram_depth: int = Field(
+        2,
+        ge=1,
+        description="Directory levels below the project root that form one diagram component",
+    )
+    ai_security_triage: bool = Field(
+        False,
+        description="Have the AI provider rate each security finding's true-positive "
+        "likelihood and severity, shown with the finding",
+    )
+    ai_triage_min_likelihood: float = Field(
+        0.0,
+        ge=0.0,
+        le=1.0,
+        description="Leave out security findings the AI triage rates below this "
+        "true-positive likelihood (0 keeps every finding)",
+    )
     ai_meta_overview_max_tokens: int = Field(
         8000,
         description="Maximum tokens for meta-overview generation (completion tokens for reasoning models)",
@@ -15,7 +15,7 @@ from rich.text import Text
 
 from codeconcat.version import __version__
 
-from .commands import api, diagnose, init, keys, reconstruct, run
+from .commands import api, ask, diagnose, init, keys, reconstruct, run, watch
 from .commands import config as config_commands
 from .config import GlobalState
 from .utils import setup_logging
@@ -145,6 +145,8 @@ app.command(name="validate")(init.validate_config)  # Uses docstring from valida
 app.command(name="reconstruct")(
     reconstruct.reconstruct_command
 )  # Uses docstring from reconstruct_command
+app.command(name="ask")(ask.ask_command)  # Uses docstring from ask_command
+app.command(name="watch")(watch.watch_command)  # Uses docstring from watch_command
 app.add_typer(api.app, name="api", help="Start the CodeConCat API server")
 app.add_typer(diagnose.app, name="diagnose", help="Diagnostic and verification tools")
 app.add_typer(keys.app, name="keys", help="Manage API keys for AI providers")
@@ -2,6 +2,6 @@
 CodeConCat CLI commands module.
 """
 
-from . import api, diagnose, init, keys, reconstruct, run
+from . import api, ask, diagnose, init, keys, reconstruct, run, watch
 
-__all__ = ["api", "diagnose", "init", "keys", "reconstruct", "run"]
+__all__ = ["api", "ask", "diagnose", "init", "keys", "reconstruct", "run", "watch"]
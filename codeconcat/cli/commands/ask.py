@@ -0,0 +1,269 @@
+"""
+Ask command - Answer questions about a codebase from CodeConCat output.
+"""
+
+import asyncio
+import os
+from pathlib import Path
+from typing import Annotated, Any
+
+import typer
+from rich.markdown import Markdown
+from rich.panel import Panel
+from rich.table import Table
+
+from codeconcat.config.config_builder import ConfigBuilder
+from codeconcat.processor.embeddings import EmbeddingError, default_embed_location
+from codeconcat.processor.retrieval import (
+    EmbeddingIndex,
+    RetrievedChunk,
+    answer_question,
+    chunks_from_output,
+    find_latest_output,
+    search_chunks,
+)
+from codeconcat.processor.summarization_processor import SummarizationProcessor
+from codeconcat.utils.offline import OfflineError, apply_offline_mode
+
+from ..config import get_state
+from ..utils import console, print_error, print_info
+from .run import _get_api_key_for_provider
+
+
+def _print_sources(results: list[RetrievedChunk], show_content: bool) -> None:
+    table = Table(title="Sources", show_header=True, header_style="bold cyan")
+    table.add_column("#", justify="right")
+    table.add_column("Location")
+    table.add_column("Score", justify="right")
+    for number, result in enumerate(results, start=1):
+        table.add_row(str(number), result.chunk.context_header, f"{result.score:.3f}")
+    console.print(table)
+    if show_content:
+        for number, result in enumerate(results, start=1):
+            console.print(
+                Panel(result.chunk.content, title=f"[{number}] {result.chunk.file_path}")
+            )
+
+
+def _retrieve(
+    question: str,
+    input_file: Path | None,
+    index_dir: Path | None,
+    config: Any,
+    top_k: int,
+    quiet: bool,
+) -> list[RetrievedChunk]:
+    """Search the embedding index if there is one, else the chunked output file."""
+    if index_dir is None and input_file is not None:
+        output_config = config.model_copy(update={"output": str(input_file), "embed_output": None})
+        candidate = Path(default_embed_location(output_config))
+        if (candidate / "manifest.json").exists():
+            index_dir = candidate
+
+    if index_dir is not None:
+        if not quiet:
+            print_info(f"Searching embedding index {index_dir}")
+        return EmbeddingIndex(index_dir, config).search(question, top_k)
+
+    if input_file is None:
+        print_error(
+            "No CodeConCat output found. Pass --input or --index, or run 'codeconcat run' first."
+        )
+    if not quiet:
+        print_info(f"No embedding index found; searching {input_file} by keywords")
+    return search_chunks(question, chunks_from_output(str(input_file)), top_k)
+
+
+def ask_command(
+    question: Annotated[str, typer.Argument(help="Question about the codebase")],
+    input_file: Annotated[
+        Path | None,
+        typer.Option(
+            "--input",
+            "-i",
+            help="CodeConCat output file (markdown, JSON or XML); defaults to the newest "
+            "ccc_codeconcat_* file in the current directory",
+            exists=True,
+            dir_okay=False,
+            resolve_path=True,
+            rich_help_panel="Input Options",
+        ),
+    ] = None,
+    index_dir: Annotated[
+        Path | None,
+        typer.Option(
+            "--index",
+            help="Embedding directory written by --embed (defaults to <output>.embeddings)",
+            exists=True,
+            file_okay=False,
+            resolve_path=True,
+            rich_help_panel="Input Options",
+        ),
+    ] = None,
+    top_k: Annotated[
+        int,
+        typer.Option(
+            "--top-k",
+            "-k",
+            help="Number of chunks to retrieve as context",
+            min=1,
+            rich_help_panel="Retrieval Options",
+        ),
+    ] = 8,
+    retrieve_only: Annotated[
+        bool,
+        typer.Option(
+            "--retrieve-only",
+            help="Only show the matching chunks; do not call an AI provider",
+            rich_help_panel="Retrieval Options",
+        ),
+    ] = False,
+    show_context: Annotated[
+        bool,
+        typer.Option(
+            "--show-context",
+            help="Print the content of every retrieved chunk",
+            rich_help_panel="Retrieval Options",
+        ),
+    ] = False,
+    ai_provider: Annotated[
+        str | None,
+        typer.Option(
+            "--ai-provider",
+            help="AI provider used to answer (defaults to ai_provider from the config)",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    ai_model: Annotated[
+        str | None,
+        typer.Option("--ai-model", help="Model used to answer", rich_help_panel="AI Options"),
+    ] = None,
+    ai_api_key: Annotated[
+        str | None,
+        typer.Option(
+            "--ai-api-key",
+            help="API key for the AI provider (or set OPENAI_API_KEY, ANTHROPIC_API_KEY, etc.)",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    ai_api_base: Annotated[
+        str | None,
+        typer.Option(
+            "--ai-api-base",
+            help="Override the API base URL for the selected AI provider",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    ai_local_only: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-local-only/--no-ai-local-only",
+            help="Only allow local/self-hosted AI providers and embedding endpoints",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    offline: Annotated[
+        bool | None,
+        typer.Option(
+            "--offline",
+            help="Strict offline mode: fail instead of using a hosted AI provider or embedder",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    max_tokens: Annotated[
+        int,
+        typer.Option(
+            "--max-tokens", help="Maximum tokens for the answer", rich_help_panel="AI Options"
+        ),
+    ] = 1000,
+):
+    """
+    Answer a question about a codebase from previously generated output.
+
+    Relevant chunks are retrieved from the embedding index written by
+    'codeconcat run --embed' when one exists next to the output file, or
+    from the output file itself otherwise. They are sent with the question
+    to the configured AI provider, which answers with numbered citations.
+
+    \b
+    Examples:
+      codeconcat ask "Where is authentication handled?"
+      codeconcat ask "How are retries configured?" -i output.md
+      codeconcat ask "What does the chunker do?" --index output.embeddings
+      codeconcat ask "Which modules touch the database?" --retrieve-only
+      codeconcat ask "Explain the parser pipeline" --ai-provider ollama --ai-model llama3.2
+    """
+    state = get_state()
+
+    builder = ConfigBuilder().with_defaults()
+    builder.with_yaml_config(str(state.config_path) if state.config_path else None)
+    cli_args: dict[str, Any] = {
+        # Offline checks only need a local provider when the answer is generated
+        "enable_ai_summary": not retrieve_only,
+        "ai_provider": ai_provider,
+        "ai_model": ai_model,
+        "ai_api_key": ai_api_key or _get_api_key_for_provider(ai_provider),
+        "ai_api_base": ai_api_base,
+        "ai_local_only": ai_local_only,
+        "offline": offline,
+        "ai_max_tokens": max_tokens,
+    }
+    config = builder.with_cli_args(cli_args).build()
+    try:
+        apply_offline_mode(config)
+    except OfflineError as e:
+        print_error(str(e))
+        return
+
+    if input_file is None and index_dir is None:
+        if config.output and os.path.isfile(config.output):
+            input_file = Path(config.output).resolve()
+        else:
+            latest = find_latest_output()
+            input_file = Path(latest).resolve() if latest else None
+
+    try:
+        results = _retrieve(question, input_file, index_dir, config, top_k, state.quiet)
+    except (EmbeddingError, ValueError, OSError) as e:
+        print_error(f"Retrieval failed: {e}")
+        return
+
+    if not results:
+        print_error("No content found to answer from.")
+
+    if retrieve_only:
+        _print_sources(results, show_context)
+        return
+
+    processor = SummarizationProcessor(config)
+    provider = processor.ai_provider
+    if provider is None:
+        print_error(
+            f"AI provider '{config.ai_provider}' is not available. Check your API key and "
+            "provider settings, or use --retrieve-only."
+        )
+        return
+
+    async def _ask():
+        try:
+            return await answer_question(provider, question, results, max_tokens)
+        finally:
+            await processor.cleanup()
+
+    with console.status("[bold green]Thinking...[/bold green]", spinner="dots"):
+        try:
+            result = asyncio.run(_ask())
+        except NotImplementedError as e:
+            print_error(str(e))
+            return
+
+    if result.error:
+        print_error(f"AI request failed: {result.error}")
+
+    console.print(Panel(Markdown(result.summary), title="Answer", border_style="green"))
+    _print_sources(results, show_context)
+    if state.verbose and result.tokens_used:
+        console.print(
+            f"[dim]{result.model_used}: {result.tokens_used:,} tokens, "
+            f"~${result.cost_estimate:.4f}[/dim]"
+        )
@@ -313,3 +313,185 @@ def list_languages():
             console.print(f"  [cyan].{ext}[/cyan] → [yellow]{lang}[/yellow]")
 
     console.print(f"\n[dim]Total: {len(LANGUAGE_MAPPING)} file extensions supported[/dim]")
+
+
+@app.command(name="eval-summaries", hidden=True)
+def eval_summaries_command(
+    models: Annotated[
+        list[str] | None,
+        typer.Option(
+            "--model",
+            "-m",
+            help="Provider and model to evaluate as provider[:model]; repeat to compare "
+            "several (defaults to ai_provider and ai_model from the config)",
+            rich_help_panel="Evaluation Options",
+        ),
+    ] = None,
+    corpus: Annotated[
+        Path | None,
+        typer.Option(
+            "--corpus",
+            help="Directory of source files to summarize (defaults to tests/parser_test_corpus)",
+            exists=True,
+            file_okay=False,
+            resolve_path=True,
+            rich_help_panel="Evaluation Options",
+        ),
+    ] = None,
+    golden: Annotated[
+        Path | None,
+        typer.Option(
+            "--golden",
+            help="JSON file of reference summaries (defaults to <corpus>/golden_summaries.json)",
+            exists=True,
+            dir_okay=False,
+            resolve_path=True,
+            rich_help_panel="Evaluation Options",
+        ),
+    ] = None,
+    embed_backend: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-backend",
+            help="Embedding backend for the similarity score (hashing, sentence-transformers, "
+            "openai); omit to report ROUGE only",
+            rich_help_panel="Evaluation Options",
+        ),
+    ] = None,
+    embed_model: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-model", help="Embedding model", rich_help_panel="Evaluation Options"
+        ),
+    ] = None,
+    output: Annotated[
+        Path | None,
+        typer.Option(
+            "--output",
+            "-o",
+            help="Write the full report, including every summary, as JSON",
+            dir_okay=False,
+            resolve_path=True,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    show_summaries: Annotated[
+        bool,
+        typer.Option(
+            "--show-summaries",
+            help="Print each generated summary with its scores",
+            rich_help_panel="Output Options",
+        ),
+    ] = False,
+):
+    """
+    Score AI summaries of a corpus against golden reference summaries.
+
+    Every file listed in the golden file is summarized with each provider and
+    model, and the summaries are scored with ROUGE-1, ROUGE-2 and ROUGE-L F1
+    and, with --embed-backend, the embedding similarity to the reference.
+    Use it to compare models before configuring one for a project.
+
+    \b
+    Examples:
+      codeconcat diagnose eval-summaries -m ollama:llama3.2
+      codeconcat diagnose eval-summaries -m openai:gpt-4o-mini -m anthropic -o eval.json
+      codeconcat diagnose eval-summaries -m ollama:qwen2.5-coder --embed-backend hashing
+    """
+    import asyncio
+    import json
+
+    from codeconcat.config.config_builder import ConfigBuilder
+    from codeconcat.processor.embeddings import EmbeddingError, create_embedder
+    from codeconcat.processor.summary_eval import evaluate_model, load_golden
+
+    from ..config import get_state
+    from .run import _get_api_key_for_provider
+
+    state = get_state()
+    corpus = corpus or Path(__file__).parent.parent.parent.parent / "tests" / "parser_test_corpus"
+    try:
+        cases = load_golden(corpus, golden)
+    except ValueError as e:
+        print_error(str(e))
+        raise typer.Exit(1) from e
+
+    def build_config(spec: str | None):
+        provider, _, model = (spec or "").partition(":")
+        builder = ConfigBuilder().with_defaults()
+        builder.with_yaml_config(str(state.config_path) if state.config_path else None)
+        cli_args = {
+            "enable_ai_summary": True,
+            "ai_provider": provider or None,
+            "ai_model": model or None,
+            "ai_min_file_lines": 0,
+            "ai_include_languages": [],
+            "ai_exclude_languages": [],
+            "ai_exclude_patterns": [],
+            "ai_summarize_functions": False,
+            "ai_meta_overview": False,
+            "ai_hierarchical_summary": False,
+            "ai_security_triage": False,
+            "enable_security_scanning": False,
+            "enable_semgrep": False,
+            "embed_backend": embed_backend,
+            "embed_model": embed_model,
+        }
+        config = builder.with_cli_args(cli_args).build()
+        if not config.ai_api_key:
+            config.ai_api_key = _get_api_key_for_provider(config.ai_provider)
+        return config
+
+    reports = []
+    for spec in models or [None]:
+        config = build_config(spec)
+        embedder = None
+        if embed_backend:
+            try:
+                embedder = create_embedder(config)
+            except EmbeddingError as e:
+                print_error(f"Embedding similarity unavailable: {e}")
+                raise typer.Exit(1) from e
+        label = f"{config.ai_provider}:{config.ai_model or 'default'}"
+        print_info(f"Summarizing {len(cases)} files with {label}")
+        with console.status(f"[bold green]Evaluating {label}...[/bold green]", spinner="dots"):
+            reports.append(asyncio.run(evaluate_model(config, corpus, cases, embedder)))
+
+    table = Table(title="Summary Quality", show_header=True, header_style="bold cyan")
+    for column in ("Model", "Files", "Failed"):
+        table.add_column(column)
+    for column in ("ROUGE-1", "ROUGE-2", "ROUGE-L", "Embedding", "Cost"):
+        table.add_column(column, justify="right")
+
+    def fmt(value: float | None) -> str:
+        return f"{value:.3f}" if value is not None else "-"
+
+    for report in reports:
+        if report.error:
+            print_warning(f"{report.provider}: {report.error}")
+        table.add_row(
+            f"{report.provider}:{report.model}",
+            str(len(report.cases)),
+            str(report.failures) if not report.error else "all",
+            fmt(report.mean("rouge1")),
+            fmt(report.mean("rouge2")),
+            fmt(report.mean("rougeL")),
+            fmt(report.mean("embedding")),
+            f"${report.cost_usd:.4f}",
+        )
+        if show_summaries:
+            for case in report.cases:
+                scores = f"R-L {fmt(case.rougeL)}, embedding {fmt(case.embedding)}"
+                body = case.summary or f"[red]{case.error}[/red]"
+                console.print(
+                    Panel(body, title=f"{report.model} · {case.path}", subtitle=scores)
+                )
+    console.print(table)
+
+    if output:
+        payload = {"corpus": str(corpus), "models": [r.to_dict() for r in reports]}
+        output.write_text(json.dumps(payload, indent=2), encoding="utf-8")
+        print_success(f"Wrote evaluation report to {output}")
+
+    if all(report.error or report.failures == len(report.cases) for report in reports):
+        raise typer.Exit(1)
@@ -95,6 +95,9 @@ def list_keys(
         ("minimax", "MiniMax"),
         ("qwen", "Qwen/DashScope"),
         ("zhipu", "Zhipu GLM"),
+        ("mistral", "Mistral"),
+        ("groq", "Groq"),
+        ("azure_openai", "Azure OpenAI"),
         ("ollama", "Ollama"),
         ("vllm", "vLLM"),
         ("lmstudio", "LM Studio"),
@@ -148,6 +151,9 @@ def set_key(
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
         "ollama",
         "vllm",
         "lmstudio",
@@ -215,6 +221,9 @@ def delete_key(
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
         "ollama",
         "vllm",
         "lmstudio",
@@ -268,6 +277,9 @@ def reset_keys(force: bool = typer.Option(False, "--force", "-f", help="Skip con
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
         "ollama",
         "vllm",
         "lmstudio",
@@ -330,6 +342,9 @@ def test_key(
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
     ]
 
     if provider not in valid_providers:
@@ -387,6 +402,9 @@ def change_password():
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
         "ollama",
         "vllm",
         "lmstudio",
@@ -484,6 +502,9 @@ def export_keys(
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
         "ollama",
         "vllm",
         "lmstudio",
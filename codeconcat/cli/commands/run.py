@@ -3,6 +3,7 @@ Run command - Main processing functionality.
 """
 
 import os
+import sys
 from enum import Enum
 from pathlib import Path
 from typing import Annotated, Any
@@ -11,6 +12,8 @@ import typer
 from rich.panel import Panel
 from rich.table import Table
 
+from codeconcat.ai.base import PROVIDER_API_KEY_ENV, provider_type_from_name
+from codeconcat.collector.archive_collector import is_archive
 from codeconcat.config.config_builder import ConfigBuilder
 from codeconcat.errors import CodeConcatError
 from codeconcat.main import _write_output_files, run_codeconcat
@@ -105,6 +108,10 @@ def complete_provider(incomplete: str) -> list[str]:
         "minimax",
         "qwen",
         "zhipu",
+        "mistral",
+        "groq",
+        "azure_openai",
+        "bedrock",
         "ollama",
         "llamacpp",
         "local_server",
@@ -167,29 +174,85 @@ def _get_api_key_for_provider(provider: str | None) -> str | None:
     if not provider:
         return None
 
-    provider_env_map = {
-        "openai": "OPENAI_API_KEY",
-        "anthropic": "ANTHROPIC_API_KEY",
-        "openrouter": "OPENROUTER_API_KEY",
-        "ollama": None,  # Ollama doesn't need an API key
-        "llamacpp": None,  # llama.cpp doesn't need an API key
-        "local_server": "LOCAL_LLM_API_KEY",
-        "vllm": "VLLM_API_KEY",
-        "lmstudio": "LMSTUDIO_API_KEY",
-        "llamacpp_server": "LLAMACPP_SERVER_API_KEY",
-    }
-
-    env_var = provider_env_map.get(provider.lower())
+    provider_type = provider_type_from_name(provider)
+    env_var = PROVIDER_API_KEY_ENV.get(provider_type) if provider_type else None
     if env_var:
         return os.getenv(env_var)
     return None
 
 
+def _run_per_package(config, quiet: bool) -> None:
+    """Write one output per workspace package and the workspace index."""
+    from codeconcat.workspace import run_workspace
+
+    if config.source_url or config.sources:
+        print_error("--per-package needs a single local directory; clone the repository first")
+    try:
+        with console.status("[bold green]Processing packages...[/bold green]", spinner="dots"):
+            run = run_workspace(config)
+    except CodeConcatError as e:
+        print_error(f"Processing failed: {e}")
+        raise typer.Exit(1) from e
+
+    if quiet:
+        return
+    table = Table(
+        title=f"Workspace: {', '.join(run.workspace.managers)}",
+        show_header=True,
+        header_style="bold cyan",
+    )
+    table.add_column("Package")
+    table.add_column("Path")
+    table.add_column("Files", justify="right")
+    table.add_column("Output")
+    for result in run.outputs:
+        output = result.output or f"[red]{result.error or 'no output'}[/red]"
+        table.add_row(result.package.name, result.package.path, str(result.files), output)
+    console.print(table)
+    print_success(f"Workspace index: {run.index_path}")
+
+
+def _print_compression_report(report) -> None:
+    """Render a compression dry-run report as a Rich table."""
+    table = Table(
+        title="Compression Report (estimated GPT-4 tokens)",
+        show_header=True,
+        header_style="bold cyan",
+    )
+    table.add_column("Directory", style="cyan")
+    table.add_column("Files", justify="right")
+    for strategy in report.strategies:
+        table.add_column(strategy, justify="right", style="green")
+
+    for directory, row in sorted(report.directories.items()):
+        table.add_row(
+            directory,
+            str(report.file_counts[directory]),
+            *(f"{row[s]:,}" for s in report.strategies),
+        )
+
+    totals = report.totals()
+    table.add_row(
+        "[bold]TOTAL[/bold]",
+        str(sum(report.file_counts.values())),
+        *(f"[bold]{totals[s]:,}[/bold]" for s in report.strategies),
+        end_section=True,
+    )
+    table.add_row(
+        "[dim]savings[/dim]",
+        "",
+        *(f"[dim]{report.savings(s):.0%}[/dim]" for s in report.strategies),
+    )
+    console.print("\n", table)
+
+
 def run_command(
     target: Annotated[
-        str | None,
+        list[str] | None,
         typer.Argument(
-            help="Target directory, file, or GitHub URL/shorthand (e.g., owner/repo)",
+            help="Target directory, file, or GitHub URL/shorthand (e.g., owner/repo); "
+            "several targets are merged into one output under per-source prefixes",
+            show_default=False,
         ),
     ] = None,
     # Output options
@@ -222,6 +285,81 @@ def run_command(
             rich_help_panel="Output Options",
         ),
     ] = None,
+    chunk_output: Annotated[
+        Path | None,
+        typer.Option(
+            "--chunk-output",
+            help="Also write declaration-aware chunks as JSON Lines (for vector databases)",
+            resolve_path=True,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    chunk_max_tokens: Annotated[
+        int | None,
+        typer.Option(
+            "--chunk-tokens",
+            help="Maximum tokens per chunk (default: 512)",
+            min=16,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    chunk_overlap_tokens: Annotated[
+        int | None,
+        typer.Option(
+            "--chunk-overlap",
+            help="Tokens of overlap between consecutive chunks (default: 64)",
+            min=0,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed: Annotated[
+        bool | None,
+        typer.Option(
+            "--embed/--no-embed",
+            help="Embed chunks or declarations and export them to a vector store",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed_backend: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-backend",
+            help="Embedding backend: sentence-transformers (default), openai, hashing",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed_model: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-model",
+            help="Embedding model (default: all-MiniLM-L6-v2 / text-embedding-3-small)",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed_store: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-store",
+            help="Vector store: npy (default), faiss, chroma, qdrant",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed_output: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-output",
+            help="Vector store directory or Qdrant URL (default: <output>.embeddings)",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    embed_units: Annotated[
+        str | None,
+        typer.Option(
+            "--embed-units",
+            help="Embed 'chunks' (default) or 'declarations'",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
     # Source options
     source_url: Annotated[
         str | None,
@@ -240,6 +378,95 @@ def run_command(
             rich_help_panel="Source Options",
         ),
     ] = None,
+    gitlab_token: Annotated[
+        str | None,
+        typer.Option(
+            "--gitlab-token",
+            help="GitLab access token for private repositories",
+            envvar="GITLAB_TOKEN",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    gitlab_url: Annotated[
+        str | None,
+        typer.Option(
+            "--gitlab-url",
+            help="Base URL of a self-hosted GitLab instance (e.g., https://gitlab.example.com)",
+            envvar="GITLAB_URL",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    bitbucket_token: Annotated[
+        str | None,
+        typer.Option(
+            "--bitbucket-token",
+            help="Bitbucket access token or app password for private repositories",
+            envvar="BITBUCKET_TOKEN",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    bitbucket_username: Annotated[
+        str | None,
+        typer.Option(
+            "--bitbucket-username",
+            help="Bitbucket username to use with an app password",
+            envvar="BITBUCKET_USERNAME",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    git_protocol: Annotated[
+        str | None,
+        typer.Option(
+            "--git-protocol",
+            help="Clone protocol: auto (SSH for SSH URLs or with --ssh-key), https, or ssh",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    ssh_key: Annotated[
+        Path | None,
+        typer.Option(
+            "--ssh-key",
+            help="Private SSH key for cloning (default: SSH agent and ~/.ssh/config)",
+            envvar="CODECONCAT_SSH_KEY",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    github_app_id: Annotated[
+        str | None,
+        typer.Option(
+            "--github-app-id",
+            help="GitHub App ID for cloning with an installation token",
+            envvar="GITHUB_APP_ID",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    github_app_private_key: Annotated[
+        str | None,
+        typer.Option(
+            "--github-app-private-key",
+            help="GitHub App private key: PEM file path or PEM text",
+            envvar="GITHUB_APP_PRIVATE_KEY",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    github_app_installation_id: Annotated[
+        int | None,
+        typer.Option(
+            "--github-app-installation-id",
+            help="GitHub App installation ID (default: looked up from the repository)",
+            envvar="GITHUB_APP_INSTALLATION_ID",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
+    sparse_clone: Annotated[
+        bool | None,
+        typer.Option(
+            "--sparse-clone/--no-sparse-clone",
+            help="Only download the directories named by --include-path patterns when "
+            "cloning (default: on)",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
     source_ref: Annotated[
         str | None,
         typer.Option(
@@ -248,12 +475,22 @@ def run_command(
             rich_help_panel="Source Options",
         ),
     ] = None,
+    offline: Annotated[
+        bool | None,
+        typer.Option(
+            "--offline",
+            help="Strict offline mode: never access the network and fail if an enabled "
+            "feature needs it (for air-gapped environments)",
+            rich_help_panel="Source Options",
+        ),
+    ] = None,
     # Diff mode options
     diff_from: Annotated[
         str | None,
         typer.Option(
             "--diff-from",
-            help="Starting Git ref for diff mode (branch, tag, or commit)",
+            help="Starting Git ref for diff mode (branch, tag, or commit), or a pull request "
+            "or merge request URL",
             rich_help_panel="Diff Mode Options",
         ),
     ] = None,
@@ -261,10 +498,63 @@ def run_command(
         str | None,
         typer.Option(
             "--diff-to",
-            help="Ending Git ref for diff mode (branch, tag, or commit)",
+            help="Ending Git ref for diff mode (branch, tag, or commit; default: HEAD)",
             rich_help_panel="Diff Mode Options",
         ),
     ] = None,
+    diff_context: Annotated[
+        str | None,
+        typer.Option(
+            "--diff-context",
+            help="Add unchanged files related to the changes through imports: none, "
+            "dependencies, dependents or both",
+            rich_help_panel="Diff Mode Options",
+        ),
+    ] = None,
+    # Git history options
+    git_history: Annotated[
+        bool | None,
+        typer.Option(
+            "--git-history/--no-git-history",
+            help="Append a condensed history of recent commits to the output",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    git_history_depth: Annotated[
+        int | None,
+        typer.Option(
+            "--git-history-depth",
+            help="Maximum number of commits in the git history (default: 20)",
+            min=1,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    git_history_since: Annotated[
+        str | None,
+        typer.Option(
+            "--git-history-since",
+            help="Only list commits after this ref in the git history (implies --git-history)",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    # Monorepo options
+    per_package: Annotated[
+        bool | None,
+        typer.Option(
+            "--per-package/--no-per-package",
+            help="Detect the monorepo workspace and write one output per package plus an index",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    workspace_output_dir: Annotated[
+        Path | None,
+        typer.Option(
+            "--workspace-output-dir",
+            help="Directory for per-package outputs (default: ccc_codeconcat_workspace)",
+            resolve_path=True,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
     # Filtering options
     include_paths: Annotated[
         list[str] | None,
@@ -304,6 +594,74 @@ def run_command(
             autocompletion=complete_language,
         ),
     ] = None,
+    submodules: Annotated[
+        bool | None,
+        typer.Option(
+            "--submodules/--no-submodules",
+            help="Collect initialized git submodules, each filtered relative to its own root",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    submodule_exclude: Annotated[
+        list[str] | None,
+        typer.Option(
+            "--exclude-submodule",
+            help="Glob on a submodule path or name to skip (with --submodules)",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    max_file_size: Annotated[
+        str | None,
+        typer.Option(
+            "--max-file-size",
+            help="Skip files larger than this size (e.g., 200kb, 1mb)",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    modified_since: Annotated[
+        str | None,
+        typer.Option(
+            "--modified-since",
+            help="Skip files not modified within this age (30d, 2w, 12h) or since a date "
+            "(2024-06-01); uses git commit dates for tracked files",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    authors: Annotated[
+        list[str] | None,
+        typer.Option(
+            "--author",
+            help="Keep only files with a commit by an author whose name or email contains "
+            "this text (repeatable)",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    symlinks: Annotated[
+        str | None,
+        typer.Option(
+            "--symlinks",
+            help="Symbolic links: skip (default), follow (each real directory once) or stub "
+            "(list the link without its target)",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    symlink_rules: Annotated[
+        list[str] | None,
+        typer.Option(
+            "--symlink-rule",
+            help="PATTERN=POLICY overriding --symlinks for links whose path or name matches "
+            "(repeatable; first match wins)",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
+    files_from: Annotated[
+        str | None,
+        typer.Option(
+            "--files-from",
+            help="Collect only the paths listed in this file, one per line; '-' reads stdin",
+            rich_help_panel="Filtering Options",
+        ),
+    ] = None,
     use_gitignore: Annotated[
         bool,
         typer.Option(
@@ -339,6 +697,25 @@ def run_command(
             rich_help_panel="Processing Options",
         ),
     ] = 4,
+    max_line_length: Annotated[
+        int | None,
+        typer.Option(
+            "--max-line-length",
+            help="Truncate lines longer than N characters (minified code, base64 blobs); 0 disables",
+            min=0,
+            rich_help_panel="Processing Options",
+        ),
+    ] = None,
+    max_string_literal_length: Annotated[
+        int | None,
+        typer.Option(
+            "--max-string-length",
+            help="Replace string literals longer than N characters (SQL dumps, base64, minified "
+            "JSON) with a preview, length and hash; 0 disables",
+            min=0,
+            rich_help_panel="Processing Options",
+        ),
+    ] = None,
     # Feature toggles
     extract_docs: Annotated[
         bool,
@@ -380,6 +757,39 @@ def run_command(
             rich_help_panel="Feature Options",
         ),
     ] = False,
+    summarize_dependencies: Annotated[
+        bool,
+        typer.Option(
+            "--summarize-deps/--no-summarize-deps",
+            help="Summarize lockfiles and vendored directories instead of excluding them",
+            rich_help_panel="Feature Options",
+        ),
+    ] = False,
+    include_asset_stubs: Annotated[
+        bool,
+        typer.Option(
+            "--asset-stubs/--no-asset-stubs",
+            help="Emit stub entries (size, MIME type, dimensions) for binary assets",
+            rich_help_panel="Feature Options",
+        ),
+    ] = False,
+    sample_data_files: Annotated[
+        bool,
+        typer.Option(
+            "--sample-data/--no-sample-data",
+            help="Include only a schema summary and sample rows for CSV/TSV/JSON/NDJSON files",
+            rich_help_panel="Feature Options",
+        ),
+    ] = False,
+    data_sample_rows: Annotated[
+        int | None,
+        typer.Option(
+            "--data-sample-rows",
+            help="Rows or records to keep per sampled data file (default: 5)",
+            min=0,
+            rich_help_panel="Feature Options",
+        ),
+    ] = None,
     # Compression options
     enable_compression: Annotated[
         bool,
@@ -397,6 +807,50 @@ def run_command(
             rich_help_panel="Compression Options",
         ),
     ] = CompressionLevel.MEDIUM,
+    compression_report: Annotated[
+        bool,
+        typer.Option(
+            "--compression-report",
+            help="Show estimated tokens per directory for each compression strategy without writing output",
+            rich_help_panel="Compression Options",
+        ),
+    ] = False,
+    prompt_compression: Annotated[
+        bool,
+        typer.Option(
+            "--prompt-compression/--no-prompt-compression",
+            help="Prune low-information words from comments and docstrings only",
+            rich_help_panel="Compression Options",
+        ),
+    ] = False,
+    prompt_compression_ratio: Annotated[
+        float | None,
+        typer.Option(
+            "--prompt-compression-ratio",
+            help="Fraction of comment/docstring words to keep (default: 0.5)",
+            min=0.05,
+            max=1.0,
+            rich_help_panel="Compression Options",
+        ),
+    ] = None,
+    token_budget: Annotated[
+        int | None,
+        typer.Option(
+            "--token-budget",
+            help="Hard token budget; prompt compression runs only when output nears it",
+            min=0,
+            rich_help_panel="Compression Options",
+        ),
+    ] = None,
+    task: Annotated[
+        str | None,
+        typer.Option(
+            "--task",
+            help="Rank files by relevance to this task description: most relevant first, and "
+            "with --token-budget the least relevant are compressed or left out",
+            rich_help_panel="Compression Options",
+        ),
+    ] = None,
     # AI Summarization options
     enable_ai_summary: Annotated[
         bool,
@@ -412,7 +866,8 @@ def run_command(
             "--ai-provider",
             help=(
                 "AI provider (openai, anthropic, openrouter, google, deepseek, minimax, "
-                "qwen, zhipu, ollama, local_server, vllm, lmstudio, llamacpp_server)"
+                "qwen, zhipu, mistral, groq, azure_openai, bedrock, ollama, local_server, "
+                "vllm, lmstudio, llamacpp_server)"
             ),
             rich_help_panel="AI Summarization Options",
             autocompletion=complete_provider,
@@ -442,14 +897,93 @@ def run_command(
             rich_help_panel="AI Summarization Options",
         ),
     ] = None,
+    ai_local_only: Annotated[
+        bool,
+        typer.Option(
+            "--ai-local-only/--no-ai-local-only",
+            help="Only allow local/self-hosted AI providers (ollama, local_server, vllm, ...) "
+            "on loopback or private addresses",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = False,
+    ai_cache_enabled: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-cache/--no-ai-cache",
+            help="Reuse cached summaries for unchanged files (default: enabled)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_cache_dir: Annotated[
+        Path | None,
+        typer.Option(
+            "--ai-cache-dir",
+            help="Directory for the AI summary cache (default: ~/.cache/codeconcat/ai-summaries)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_max_cost_usd: Annotated[
+        float | None,
+        typer.Option(
+            "--ai-max-cost",
+            help="Spend cap in USD: skip AI summaries if the estimate exceeds it and stop "
+            "requests once actual spend reaches it",
+            min=0,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_max_concurrent: Annotated[
+        int | None,
+        typer.Option(
+            "--ai-max-concurrent",
+            help="Maximum AI requests in flight at once (default: 5)",
+            min=1,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_requests_per_minute: Annotated[
+        float | None,
+        typer.Option(
+            "--ai-rpm",
+            help="Maximum AI requests per minute (default: the provider's own limit)",
+            min=0.1,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_max_retries: Annotated[
+        int | None,
+        typer.Option(
+            "--ai-max-retries",
+            help="Retries per AI request on rate limiting (429), server errors and network "
+            "failures (default: 2)",
+            min=0,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
     ai_summarize_functions: Annotated[
         bool,
         typer.Option(
             "--ai-functions/--no-ai-functions",
-            help="Also summarize individual functions/methods",
+            help="Also summarize public functions, methods and classes (one-line gloss each)",
             rich_help_panel="AI Summarization Options",
         ),
     ] = False,
+    ai_min_function_lines: Annotated[
+        int | None,
+        typer.Option(
+            "--ai-min-function-lines",
+            help="Minimum declaration size in lines for --ai-functions (default: 10)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_functions_public_only: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-functions-public-only/--ai-functions-all",
+            help="Skip private declarations (leading underscore, private/protected modifier)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
     ai_meta_overview: Annotated[
         bool,
         typer.Option(
@@ -458,6 +992,66 @@ def run_command(
             rich_help_panel="AI Summarization Options",
         ),
     ] = False,
+    ai_hierarchical_summary: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-hierarchical/--no-ai-hierarchical",
+            help="Fold file summaries into per-directory summaries, then into the meta-overview",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_hierarchy_fan_in: Annotated[
+        int | None,
+        typer.Option(
+            "--ai-fan-in",
+            help="Maximum summaries combined per request with --ai-hierarchical (default: 20)",
+            min=2,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_architecture_diagram: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-diagram/--no-ai-diagram",
+            help="Embed a Mermaid component diagram drawn from the import graph and summaries",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_diagram_depth: Annotated[
+        int | None,
+        typer.Option(
+            "--ai-diagram-depth",
+            help="Directory levels that form one diagram component (default: 2)",
+            min=1,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_change_summary: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-change-summary/--no-ai-change-summary",
+            help="In diff mode, open the output with an AI reviewer changelog (default: enabled)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_security_triage: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-triage/--no-ai-triage",
+            help="Rate each security finding's true-positive likelihood and severity with AI",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_triage_min_likelihood: Annotated[
+        float | None,
+        typer.Option(
+            "--ai-triage-min-likelihood",
+            help="Leave out findings the AI triage rates below this likelihood (0.0-1.0)",
+            min=0.0,
+            max=1.0,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
     ai_meta_overview_prompt: Annotated[
         str | None,
         typer.Option(
@@ -466,6 +1060,42 @@ def run_command(
             rich_help_panel="AI Summarization Options",
         ),
     ] = None,
+    ai_meta_overview_prompt_file: Annotated[
+        Path | None,
+        typer.Option(
+            "--ai-meta-prompt-file",
+            help="Read the meta-overview prompt from a file ({tree}, {languages} placeholders)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_prompt_templates_dir: Annotated[
+        Path | None,
+        typer.Option(
+            "--ai-prompt-dir",
+            help="Directory of prompt templates (file.md, function.md, overview.md and "
+            "*_system.md) with {language}, {path} and {declarations} variables",
+            exists=True,
+            file_okay=False,
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_summary_language: Annotated[
+        str | None,
+        typer.Option(
+            "--ai-language",
+            help="Write summaries and the meta-overview in this language, as a code (ja, de, "
+            "pt-BR) or a name",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
+    ai_meta_overview_fallback: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-meta-fallback/--no-ai-meta-fallback",
+            help="Use a heuristic project overview when no AI overview is available (default: on)",
+            rich_help_panel="AI Summarization Options",
+        ),
+    ] = None,
     ai_meta_overview_use_higher_tier: Annotated[
         bool,
         typer.Option(
@@ -636,6 +1266,7 @@ def run_command(
     Examples:
       codeconcat run                           # Process current directory
       codeconcat run /path/to/project          # Process specific directory
+      codeconcat run service ../shared-lib     # Several sources in one output
       codeconcat run -f json -o output.json    # JSON output
       codeconcat run --compress --preset lean  # Compressed lean output
       codeconcat run --xml-pi --format xml     # XML with AI instructions
@@ -648,24 +1279,47 @@ def run_command(
         if not state.quiet:
             show_quote()
 
-        # Detect if target is a URL or local path
-        actual_target: str | None = target or "."
+        # Several targets are collected as separate sources into one output
+        targets = [target] if isinstance(target, str) else list(target or [])
+        sources = targets if len(targets) > 1 else []
+        single_target = targets[0] if len(targets) == 1 else None
+        if sources and source_url:
+            print_error("--source-url cannot be combined with several targets")
+        if files_from and (sources or source_url):
+            print_error("--files-from lists local paths and needs a single local target")
+        if files_from == "-" and sys.stdin.isatty():
+            print_error("--files-from - reads paths from stdin; pipe them in")
+
+        symlink_rule_map: dict[str, str] = {}
+        for rule in symlink_rules or []:
+            pattern, _, policy = rule.rpartition("=")
+            if not pattern:
+                print_error(f"--symlink-rule expects PATTERN=POLICY, got '{rule}'")
+            symlink_rule_map[pattern] = policy
+
+        # Detect if target is an archive, a URL or a local path
+        actual_target: str | None = single_target or "."
         actual_source_url = source_url
-
-        # Check if target is a GitHub URL or shorthand
-        if target:
-            is_url, cleaned_target = is_github_url_or_shorthand(target)
+        archive = single_target if single_target and is_archive(single_target) else None
+        if archive and (source_url or files_from):
+            print_error("An archive target cannot be combined with --source-url or --files-from")
+
+        if sources or archive:
+            actual_target = None
+        # Check if target is a remote repository URL or shorthand
+        elif single_target:
+            is_url, cleaned_target = is_github_url_or_shorthand(single_target, gitlab_url)
             if is_url:
                 # Target is a URL, use it as source_url
                 actual_source_url = cleaned_target
                 actual_target = None  # No local target when using URL
             else:
                 # Target is a local path
-                actual_target = target
+                actual_target = single_target
                 # Validate that local path exists
-                target_path = Path(target)
+                target_path = Path(single_target)
                 if not target_path.exists():
-                    print_error(f"Target path does not exist: {target}")
+                    print_error(f"Target path does not exist: {single_target}")
                     raise typer.Exit(1)
         else:
             # No target provided, use current directory
@@ -674,9 +1328,16 @@ def run_command(
         # Show processing header
         if not state.quiet:
             display_target = (
-                actual_source_url if actual_source_url else (actual_target or "Current directory")
+                ", ".join(sources)
+                if sources
+                else archive or actual_source_url or actual_target or "Current directory"
             )
-            target_type = "GitHub Repository" if actual_source_url else "Local Directory"
+            if sources:
+                target_type = f"{len(sources)} Sources"
+            elif archive:
+                target_type = "Archive"
+            else:
+                target_type = "Remote Repository" if actual_source_url else "Local Directory"
             console.print(
                 Panel(
                     "[bold cyan]CodeConCat Processing[/bold cyan]\n\n"
@@ -706,7 +1367,11 @@ def run_command(
             cli_args: dict[str, Any] = {}
 
             # Only add target_path if we're processing locally (no source URL)
-            if actual_source_url:
+            if sources:
+                cli_args["sources"] = sources
+            elif archive:
+                cli_args["archive"] = archive
+            elif actual_source_url:
                 # Using GitHub/remote source
                 cli_args["source_url"] = actual_source_url
             elif actual_target:
@@ -718,33 +1383,104 @@ def run_command(
             cli_args_update = {
                 "output": str(output) if output else "",
                 "format": format.value,
+                "chunk_output": str(chunk_output) if chunk_output else None,
+                "chunk_max_tokens": chunk_max_tokens,
+                "chunk_overlap_tokens": chunk_overlap_tokens,
+                "embed": embed,
+                "embed_backend": embed_backend,
+                "embed_model": embed_model,
+                "embed_store": embed_store,
+                "embed_output": embed_output,
+                "embed_units": embed_units,
                 "github_token": github_token or "",
+                "gitlab_token": gitlab_token or "",
+                "gitlab_url": gitlab_url,
+                "bitbucket_token": bitbucket_token or "",
+                "bitbucket_username": bitbucket_username,
+                "git_protocol": git_protocol,
+                "ssh_key_path": str(ssh_key) if ssh_key else None,
+                "github_app_id": github_app_id,
+                "github_app_private_key": github_app_private_key,
+                "github_app_installation_id": github_app_installation_id,
                 "source_ref": source_ref or "",
+                "sparse_clone": sparse_clone,
+                "offline": offline,
                 "diff_from": diff_from or "",
                 "diff_to": diff_to or "",
+                "diff_context": diff_context,
+                "per_package": per_package,
+                "workspace_output_dir": str(workspace_output_dir)
+                if workspace_output_dir
+                else None,
+                "include_git_history": git_history,
+                "git_history_depth": git_history_depth,
+                "git_history_since": git_history_since,
                 "include_paths": include_paths if include_paths else [],
                 "exclude_paths": exclude_paths if exclude_paths else [],
                 "include_languages": include_languages if include_languages else [],
                 "exclude_languages": exclude_languages if exclude_languages else [],
+                "include_submodules": submodules,
+                "submodule_exclude": submodule_exclude,
+                "max_file_size": max_file_size,
+                "modified_since": modified_since,
+                "authors": authors,
+                "symlink_policy": symlinks,
+                "symlink_rules": symlink_rule_map or None,
+                "files_from": files_from,
                 "use_gitignore": use_gitignore,
                 "use_default_excludes": use_default_excludes,
                 "parser_engine": parser_engine.value if parser_engine else "",
                 "max_workers": max_workers,
+                "max_line_length": max_line_length,
+                "max_string_literal_length": max_string_literal_length,
                 "extract_docs": extract_docs,
                 "merge_docs": merge_docs,
                 "disable_annotations": disable_annotations,
                 "remove_docstrings": remove_docstrings,
                 "remove_comments": remove_comments,
+                "summarize_dependencies": summarize_dependencies,
+                "include_asset_stubs": include_asset_stubs,
+                "sample_data_files": sample_data_files,
+                "data_sample_rows": data_sample_rows,
                 "enable_compression": enable_compression,
                 "compression_level": compression_level.value,
+                "compression_report": compression_report,
+                "prompt_compression": prompt_compression,
+                "prompt_compression_ratio": prompt_compression_ratio,
+                "token_budget": token_budget,
+                "task": task,
                 "enable_ai_summary": enable_ai_summary,
                 "ai_provider": ai_provider or "",
                 "ai_model": ai_model or "",
                 "ai_api_key": ai_api_key or _get_api_key_for_provider(ai_provider) or "",
                 "ai_api_base": ai_api_base if ai_api_base else None,
+                "ai_local_only": ai_local_only,
+                "ai_cache_enabled": ai_cache_enabled,
+                "ai_cache_dir": str(ai_cache_dir) if ai_cache_dir else None,
+                "ai_max_cost_usd": ai_max_cost_usd,
+                "ai_max_concurrent": ai_max_concurrent,
+                "ai_requests_per_minute": ai_requests_per_minute,
+                "ai_max_retries": ai_max_retries,
                 "ai_summarize_functions": ai_summarize_functions,
+                "ai_min_function_lines": ai_min_function_lines,
+                "ai_functions_public_only": ai_functions_public_only,
                 "ai_meta_overview": ai_meta_overview,
                 "ai_meta_overview_prompt": ai_meta_overview_prompt or "",
+                "ai_meta_overview_prompt_file": str(ai_meta_overview_prompt_file)
+                if ai_meta_overview_prompt_file
+                else None,
+                "ai_prompt_templates_dir": str(ai_prompt_templates_dir)
+                if ai_prompt_templates_dir
+                else None,
+                "ai_summary_language": ai_summary_language,
+                "ai_hierarchical_summary": ai_hierarchical_summary,
+                "ai_hierarchy_fan_in": ai_hierarchy_fan_in,
+                "ai_architecture_diagram": ai_architecture_diagram,
+                "ai_change_summary": ai_change_summary,
+                "ai_diagram_depth": ai_diagram_depth,
+                "ai_security_triage": ai_security_triage,
+                "ai_triage_min_likelihood": ai_triage_min_likelihood,
+                "ai_meta_overview_fallback": ai_meta_overview_fallback,
                 "ai_meta_overview_use_higher_tier": ai_meta_overview_use_higher_tier,
                 "ai_meta_overview_model": ai_meta_overview_model
                 if ai_meta_overview_model
@@ -836,8 +1572,12 @@ def run_command(
             report_path=Path(unsupported_report_path),
         )
 
+        if config.per_package:
+            _run_per_package(config, state.quiet)
+            return
+
         if not state.quiet:
-            process_source = config.source_url if config.source_url else config.target_path
+            process_source = ", ".join(config.sources) or config.source_url or config.target_path
             console.print(f"\n[bold cyan]Processing files from:[/bold cyan] {process_source}\n")
 
         # Setup cancellation token and signal handler for graceful Ctrl+C
@@ -892,6 +1632,12 @@ def run_command(
             print_warning("Operation cancelled by user")
             raise typer.Exit(130)
 
+        # Compression dry-run: show the report instead of writing output
+        report = getattr(config, "_compression_report", None)
+        if report is not None:
+            _print_compression_report(report)
+            return
+
         # Write output
         if output_content:
             _write_output_files(output_content, config)
@@ -943,6 +1689,55 @@ def run_command(
                     stats_table.add_row("Languages", ", ".join(stats.get("languages", [])) or "0")
                     stats_table.add_row("Total lines", f"{stats.get('total_lines', 0):,}")
                     stats_table.add_row("Total bytes", f"{stats.get('total_bytes', 0):,}")
+                    if stats.get("lines_truncated"):
+                        stats_table.add_row(
+                            "Lines truncated",
+                            f"{stats['lines_truncated']:,} ({stats.get('chars_truncated', 0):,} chars)",
+                        )
+                    if stats.get("ai_cost_estimated") is not None:
+                        cost_text = (
+                            f"${stats['ai_cost_actual']:.4f} actual / "
+                            f"${stats['ai_cost_estimated']:.4f} estimated"
+                        )
+                        if stats.get("ai_cost_cap") is not None:
+                            cost_text += f" (cap ${stats['ai_cost_cap']:.2f}"
+                            cost_text += ", reached)" if stats.get("ai_cost_cap_exceeded") else ")"
+                        stats_table.add_row("AI cost", cost_text)
+                    if (
+                        stats.get("ai_files_failed")
+                        or stats.get("ai_functions_failed")
+                        or stats.get("ai_directories_failed")
+                    ):
+                        stats_table.add_row(
+                            "AI failures",
+                            f"{stats.get('ai_files_failed', 0):,} files, "
+                            f"{stats.get('ai_functions_failed', 0):,} declarations, "
+                            f"{stats.get('ai_directories_failed', 0):,} directories",
+                        )
+                    if stats.get("ai_retries"):
+                        stats_table.add_row("AI retries", f"{stats['ai_retries']:,}")
+                    if stats.get("ai_triage_failed") or stats.get("ai_findings_hidden"):
+                        stats_table.add_row(
+                            "Security triage",
+                            f"{stats.get('ai_findings_hidden', 0):,} findings left out, "
+                            f"{stats.get('ai_triage_failed', 0):,} not triaged",
+                        )
+                    if stats.get("task_files_compressed") or stats.get("task_files_dropped"):
+                        stats_table.add_row(
+                            "Task budget",
+                            f"{stats.get('task_files_compressed', 0):,} compressed, "
+                            f"{stats.get('task_files_dropped', 0):,} left out",
+                        )
+                    if stats.get("embeddings_written"):
+                        stats_table.add_row(
+                            "Embeddings",
+                            f"{stats['embeddings_written']:,} → {stats.get('embeddings_location', '')}",
+                        )
+                    if stats.get("literals_truncated"):
+                        stats_table.add_row(
+                            "String literals truncated",
+                            f"{stats['literals_truncated']:,} ({stats.get('literal_chars_truncated', 0):,} chars)",
+                        )
 
                 console.print("\n", stats_table)
         else:
@@ -0,0 +1,153 @@
+"""
+Watch command - Regenerate the output whenever the input changes.
+"""
+
+from datetime import datetime
+from pathlib import Path
+from typing import Annotated, Any
+
+import typer
+
+from codeconcat.config.config_builder import ConfigBuilder
+from codeconcat.errors import CodeConcatError
+from codeconcat.main import _write_output_files
+from codeconcat.watch import Regeneration, Watcher
+
+from ..config import get_state
+from ..utils import console, print_error, print_info, print_warning
+from .run import OutputFormat, OutputPreset, _get_api_key_for_provider
+
+
+def watch_command(
+    target: Annotated[
+        Path,
+        typer.Argument(
+            help="Directory to watch",
+            exists=True,
+            resolve_path=True,
+        ),
+    ] = Path("."),
+    output: Annotated[
+        Path | None,
+        typer.Option(
+            "--output",
+            "-o",
+            help="Output file path (auto-detected from format if omitted)",
+            resolve_path=True,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    format: Annotated[
+        OutputFormat | None,
+        typer.Option(
+            "--format",
+            "-f",
+            help="Output format (defaults to the config file's format, else markdown)",
+            case_sensitive=False,
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    preset: Annotated[
+        OutputPreset | None,
+        typer.Option(
+            "--preset",
+            "-p",
+            help="Configuration preset (overrides config file)",
+            rich_help_panel="Output Options",
+        ),
+    ] = None,
+    ai_summary: Annotated[
+        bool | None,
+        typer.Option(
+            "--ai-summary/--no-ai-summary",
+            help="Summarize files with the AI provider from the config; unchanged files "
+            "are served from the summary cache",
+            rich_help_panel="AI Options",
+        ),
+    ] = None,
+    interval: Annotated[
+        float,
+        typer.Option(
+            "--interval",
+            help="Seconds between checks for changes",
+            min=0.1,
+            rich_help_panel="Watch Options",
+        ),
+    ] = 1.0,
+    debounce: Annotated[
+        float,
+        typer.Option(
+            "--debounce",
+            help="Seconds the files must stay unchanged before regenerating",
+            min=0.0,
+            rich_help_panel="Watch Options",
+        ),
+    ] = 0.5,
+):
+    """
+    Keep a fresh CodeConCat output while you work.
+
+    The output is generated once, then regenerated whenever a file under the
+    target changes. Parse results of unchanged files are reused and AI
+    summaries come from the summary cache, so a run after a small edit only
+    processes what changed. Excluded directories, files ignored by
+    .gitignore and CodeConCat's own outputs are not watched. The output is
+    not copied to the clipboard. Press Ctrl+C to stop.
+
+    \b
+    Examples:
+      codeconcat watch                          # Watch the current directory
+      codeconcat watch src -o context.md        # Keep context.md up to date
+      codeconcat watch --preset lean -f xml     # Lean XML output
+      codeconcat watch --ai-summary             # With cached AI summaries
+    """
+    state = get_state()
+
+    builder = ConfigBuilder().with_defaults()
+    if preset:
+        builder.with_preset(preset.value)
+    builder.with_yaml_config(str(state.config_path) if state.config_path else None)
+    cli_args: dict[str, Any] = {
+        "target_path": str(target),
+        "output": str(output) if output else None,
+        "format": format.value if format else None,
+        "enable_ai_summary": ai_summary,
+        "disable_progress_bar": True,
+        "disable_copy": True,
+        "verbose": state.verbose,
+    }
+    config = builder.with_cli_args({k: v for k, v in cli_args.items() if v is not None}).build()
+    if config.enable_ai_summary and not config.ai_api_key:
+        config.ai_api_key = _get_api_key_for_provider(config.ai_provider)
+
+    def on_run(result: Regeneration) -> None:
+        if state.quiet:
+            return
+        stamp = datetime.now().strftime("%H:%M:%S")
+        if result.output is None:
+            print_warning(f"[{stamp}] Nothing generated")
+            return
+        trigger = f"{len(result.changed)} changed" if result.changed else "initial run"
+        console.print(
+            f"[dim]{stamp}[/dim] [bold green]✓[/bold green] {config.output} "
+            f"({trigger}; {result.parse_misses} parsed, {result.parse_hits} cached; "
+            f"{result.seconds:.1f}s)"
+        )
+
+    def on_error(error: Exception) -> None:
+        if not isinstance(error, CodeConcatError):
+            raise error
+        print_warning(f"Regeneration failed: {error}")
+
+    try:
+        watcher = Watcher(config, interval=interval, debounce=debounce, write=_write_output_files)
+    except ValueError as e:
+        print_error(str(e))
+        return
+    if not state.quiet:
+        print_info(f"Watching {target} (Ctrl+C to stop)")
+    try:
+        watcher.run(on_run=on_run, on_error=on_error)
+    except KeyboardInterrupt:
+        if not state.quiet:
+            console.print("\nStopped watching.")
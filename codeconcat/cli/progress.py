@@ -12,6 +12,7 @@ from typing import Literal, Protocol
 
 from rich.console import Console, Group
 from rich.live import Live
+from rich.markup import escape
 from rich.panel import Panel
 from rich.text import Text
 
@@ -172,6 +173,12 @@ class ProgressDashboard:
                 status_text.append(f"{count_text:<12}", style="cyan")
                 status_text.append(bar, style="cyan")
                 status_text.append(f" {pct_text}", style="cyan bold")
+                if stage.message:
+                    # Per-item detail, e.g. the last summarized file, on its own line
+                    detail = stage.message
+                    if len(detail) > width - 10:
+                        detail = "…" + detail[-(width - 11) :]
+                    status_text.append(f"\n   └ {detail}", style="dim")
             else:
                 # Show spinner-style message
                 status_text = Text(stage.message or "processing...", style="cyan")
@@ -212,13 +219,20 @@ class ProgressDashboard:
         """Start a processing stage.
 
         Args:
-            name: Stage name (must match predefined stage names)
+            name: Stage name; stages outside the defaults, such as "Summarizing",
+                are inserted before the first pending stage
             total: Total items to process (0 for indeterminate)
             message: Optional status message
 
         Returns:
             A callback function for updating progress
         """
+        if not any(stage.name.lower() == name.lower() for stage in self._stages):
+            pending = [
+                idx for idx, stage in enumerate(self._stages) if stage.status == StageStatus.PENDING
+            ]
+            self._stages.insert(pending[0] if pending else len(self._stages), Stage(name))
+
         # Find the stage by name
         for idx, stage in enumerate(self._stages):
             if stage.name.lower() == name.lower():
@@ -377,7 +391,7 @@ class SimpleProgress:
 
         return callback
 
-    def update_progress(self, current: int, total: int, _message: str = "") -> None:
+    def update_progress(self, current: int, total: int, message: str = "") -> None:
         """Update progress (only prints on 10% increments to reduce noise)."""
         if self.quiet or total == 0:
             return
@@ -386,7 +400,10 @@ class SimpleProgress:
         # Only print on 10% increments
         if pct // 10 > self._last_pct // 10:
             self._last_pct = pct
-            self.console.print(f"[cyan][{self._current_stage}][/cyan] {current}/{total} ({pct}%)")
+            line = f"[cyan][{self._current_stage}][/cyan] {current}/{total} ({pct}%)"
+            if message:
+                line += f" {escape(message)}"
+            self.console.print(line)
 
     def complete_stage(self, message: str = "") -> None:
         """Complete current stage."""
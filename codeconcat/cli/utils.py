@@ -191,21 +191,28 @@ def print_file_stats(stats: dict[str, Any]) -> None:
     console.print(table)
 
 
-def is_github_url_or_shorthand(target: str) -> tuple[bool, str]:
+def is_github_url_or_shorthand(target: str, gitlab_url: str | None = None) -> tuple[bool, str]:
     """
-    Detect if a target string is a GitHub URL or shorthand notation.
+    Detect if a target string is a remote Git repository URL or shorthand notation.
+
+    Recognizes GitHub, GitLab (including self-hosted instances) and Bitbucket
+    URLs, ``owner/repo`` shorthands and ``gitlab:``/``bitbucket:`` shorthands.
 
     Args:
         target: Input string to check
+        gitlab_url: Base URL of a self-hosted GitLab instance, if any
 
     Returns:
         Tuple of (is_url, cleaned_url) where:
-        - is_url: True if target is a GitHub URL/shorthand
+        - is_url: True if target is a remote repository URL/shorthand
         - cleaned_url: The URL/shorthand ready for processing
     """
     # GitHub shorthand patterns (owner/repo or owner/repo/ref)
     shorthand_pattern = r"^[a-zA-Z0-9][\w.-]+/[\w.-]+(?:/.*)?$"
 
+    # Host-prefixed shorthands (gitlab:group/project, bitbucket:workspace/repo)
+    host_shorthand_pattern = r"^(?:github|gitlab|bitbucket):[\w.-]+/[\w.-]+"
+
     # GitHub URL patterns
     github_url_patterns = [
         r"^https?://(?:www\.)?github\.com/[\w.-]+/[\w.-]+",
@@ -218,6 +225,9 @@ def is_github_url_or_shorthand(target: str) -> tuple[bool, str]:
         # Looks like owner/repo and not a local path
         return True, target
 
+    if re.match(host_shorthand_pattern, target, re.IGNORECASE):
+        return True, target
+
     # Check if it's a GitHub URL
     for pattern in github_url_patterns:
         if re.match(pattern, target, re.IGNORECASE):
@@ -229,8 +239,17 @@ def is_github_url_or_shorthand(target: str) -> tuple[bool, str]:
         r"^https?://(?:www\.)?bitbucket\.org/",
         r"^git@gitlab\.com:",
         r"^git@bitbucket\.org:",
+        r"^ssh://[\w.-]+@(?:github\.com|bitbucket\.org)[:/]",
+        # Self-hosted GitLab instances
+        r"^https?://[^/]*gitlab[^/]*/[\w.-]+/",
+        r"^https?://[^/]+/[\w./-]+/-/",
+        r"^(?:ssh://)?[\w.-]+@[^/:]*gitlab[^/:]*[:/]",
     ]
 
+    if gitlab_url:
+        host = re.sub(r"^\w+://", "", gitlab_url).split("/")[0]
+        git_url_patterns.append(rf"^(?:https?://|(?:ssh://)?[\w.-]+@){re.escape(host)}[:/]")
+
     for pattern in git_url_patterns:
         if re.match(pattern, target, re.IGNORECASE):
             return True, target
@@ -0,0 +1,280 @@
+"""Collection from zip and tar archives.
+
+A release tarball or a GitHub source archive can be packed without being
+extracted first. Members are read straight from the archive, a local file or
+a URL downloaded into memory, and go through the same filters as a directory
+walk: the archive's ``.gitignore`` and ``.codeconcatignore`` files, the
+default excludes, the run's include and exclude patterns, and the size and
+age filters, which use the sizes and dates recorded in the archive. When
+every member sits under one top-level directory, as in ``project-1.2.0/...``,
+that directory is stripped so the patterns match as they would in a checkout,
+and file paths in the output are relative to the archive's root.
+
+Members are read in archive order, since seeking backwards in a compressed
+tar decompresses it again from the start.
+"""
+
+from __future__ import annotations
+
+import io
+import logging
+import os
+import posixpath
+import stat
+import tarfile
+import time
+import zipfile
+from collections.abc import Callable
+from dataclasses import dataclass
+from typing import BinaryIO
+from urllib.parse import urlparse
+
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.collector.file_filters import FileFilters
+from codeconcat.collector.ignore_rules import (
+    CODECONCATIGNORE,
+    GITIGNORE,
+    IgnoreRules,
+    ignore_rules_kwargs,
+)
+from codeconcat.collector.local_collector import (
+    _compile_specs,
+    file_data_from_bytes,
+    is_skipped_dir,
+    should_include_file,
+)
+from codeconcat.constants import MAX_ARCHIVE_SIZE
+from codeconcat.errors import FileProcessingError
+from codeconcat.utils.file_utils import FileSizeConfig
+from codeconcat.utils.offline import require_network
+
+logger = logging.getLogger(__name__)
+
+ARCHIVE_SUFFIXES = (".zip", ".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz")
+
+
+@dataclass
+class ArchiveMember:
+    """A regular file in an archive.
+
+    Attributes:
+        path: Path inside the archive, with ``/`` separators.
+        size: Uncompressed size in bytes.
+        mtime: Modification time as a timestamp.
+        read: Returns at most the given number of bytes of the content.
+    """
+
+    path: str
+    size: int
+    mtime: float
+    read: Callable[[int], bytes]
+
+
+def _is_url(location: str) -> bool:
+    return location.startswith(("http://", "https://"))
+
+
+def is_archive(location: str) -> bool:
+    """Return True if ``location`` is a zip or tar file, or a URL naming one."""
+    if _is_url(location):
+        return urlparse(location).path.lower().endswith(ARCHIVE_SUFFIXES)
+    return os.path.isfile(location) and location.lower().endswith(ARCHIVE_SUFFIXES)
+
+
+def _download(url: str) -> BinaryIO:
+    require_network("Downloading an archive")
+    import httpx
+
+    buffer = io.BytesIO()
+    try:
+        with httpx.stream("GET", url, follow_redirects=True, timeout=60) as response:
+            response.raise_for_status()
+            for chunk in response.iter_bytes():
+                buffer.write(chunk)
+                if buffer.tell() > MAX_ARCHIVE_SIZE:
+                    raise FileProcessingError(
+                        f"Archive {url} is larger than {MAX_ARCHIVE_SIZE // (1024 * 1024)} MB"
+                    )
+    except httpx.HTTPStatusError as e:
+        raise FileProcessingError(
+            f"Could not download archive {url} ({e.response.status_code})"
+        ) from e
+    except httpx.HTTPError as e:
+        raise FileProcessingError(f"Could not download archive {url}: {e}") from e
+    buffer.seek(0)
+    return buffer
+
+
+def _safe_path(name: str) -> str | None:
+    """Normalize a member name, or return None if it could escape the archive root."""
+    path = name.replace("\\", "/")
+    if path.startswith("/") or (len(path) > 1 and path[1] == ":"):
+        return None
+    path = posixpath.normpath(path)
+    if path == "." or path == ".." or path.startswith("../"):
+        return None
+    return path
+
+
+def _zip_reader(archive: zipfile.ZipFile, info: zipfile.ZipInfo) -> Callable[[int], bytes]:
+    def read(limit: int) -> bytes:
+        with archive.open(info) as f:
+            return f.read(limit)
+
+    return read
+
+
+def _tar_reader(archive: tarfile.TarFile, info: tarfile.TarInfo) -> Callable[[int], bytes]:
+    def read(limit: int) -> bytes:
+        f = archive.extractfile(info)
+        if f is None:
+            return b""
+        with f:
+            return f.read(limit)
+
+    return read
+
+
+def _read_members(data: BinaryIO) -> list[ArchiveMember]:
+    """List the regular files of a zip or tar archive; links and devices are skipped."""
+    members: list[ArchiveMember] = []
+    if zipfile.is_zipfile(data):
+        data.seek(0)
+        archive = zipfile.ZipFile(data)
+        for info in archive.infolist():
+            # Unix file type bits, when the archiver recorded them
+            kind = stat.S_IFMT(info.external_attr >> 16)
+            if info.is_dir() or (kind and kind != stat.S_IFREG):
+                continue
+            members.append(
+                ArchiveMember(
+                    info.filename,
+                    info.file_size,
+                    time.mktime((*info.date_time, 0, 0, -1)),
+                    _zip_reader(archive, info),
+                )
+            )
+        return members
+
+    data.seek(0)
+    try:
+        archive = tarfile.open(fileobj=data, mode="r:*")
+    except tarfile.TarError as e:
+        raise FileProcessingError(f"Not a zip or tar archive: {e}") from e
+    for info in archive.getmembers():
+        if not info.isreg():
+            continue
+        members.append(ArchiveMember(info.name, info.size, info.mtime, _tar_reader(archive, info)))
+    return members
+
+
+def _strip_common_root(members: list[ArchiveMember]) -> None:
+    roots = {m.path.split("/", 1)[0] for m in members}
+    if len(roots) == 1 and all("/" in m.path for m in members):
+        for member in members:
+            member.path = member.path.split("/", 1)[1]
+
+
+def _in_skipped_dir(path: str, specs: tuple, config: CodeConCatConfig) -> bool:
+    parts = path.split("/")[:-1]
+    return any(
+        is_skipped_dir(name, "/".join(parts[: i + 1]), specs, config)
+        for i, name in enumerate(parts)
+    )
+
+
+def collect_archive(location: str, config: CodeConCatConfig) -> list[ParsedFileData]:
+    """Collect the files of the archive at ``location``, a local path or URL.
+
+    Raises:
+        FileProcessingError: If the archive cannot be read or downloaded, or
+            is larger than ``MAX_ARCHIVE_SIZE``.
+    """
+    logger.info(f"[CodeConCat] Collecting files from archive: {location}")
+    if _is_url(location):
+        data = _download(location)
+    else:
+        try:
+            with open(location, "rb") as f:
+                data = io.BytesIO(f.read(MAX_ARCHIVE_SIZE + 1))
+        except OSError as e:
+            raise FileProcessingError(f"Could not read archive {location}: {e}") from e
+        if len(data.getbuffer()) > MAX_ARCHIVE_SIZE:
+            raise FileProcessingError(
+                f"Archive {location} is larger than {MAX_ARCHIVE_SIZE // (1024 * 1024)} MB"
+            )
+
+    try:
+        members = _read_members(data)
+    except (zipfile.BadZipFile, tarfile.TarError, EOFError, OSError) as e:
+        raise FileProcessingError(f"Could not read archive {location}: {e}") from e
+
+    safe: list[ArchiveMember] = []
+    for member in members:
+        path = _safe_path(member.path)
+        if path is None:
+            logger.warning(f"[CodeConCat] Skipping archive member outside the root: {member.path}")
+            continue
+        member.path = path
+        safe.append(member)
+    _strip_common_root(safe)
+
+    # Paths are matched relative to the archive root, not a directory on disk
+    filter_config = config.model_copy(update={"target_path": ""})
+    ignore_files = {
+        member.path: member.read(1024 * 1024)
+        for member in safe
+        if posixpath.basename(member.path) in (GITIGNORE, CODECONCATIGNORE)
+    }
+
+    def read_ignore_file(directory: str, name: str) -> list[str] | None:
+        content = ignore_files.get(posixpath.join(directory, name))
+        if content is None:
+            return None
+        return content.decode("utf-8", "replace").splitlines()
+
+    specs = (
+        IgnoreRules(read_ignore_file, **ignore_rules_kwargs(config)),
+        *_compile_specs(".", filter_config)[1:],
+    )
+
+    filters = FileFilters(None, config)
+    max_size = FileSizeConfig.DEFAULT_MAX_BINARY_CHECK_SIZE
+    total = 0
+    files: list[ParsedFileData] = []
+    for member in safe:
+        if _in_skipped_dir(member.path, specs, filter_config):
+            continue
+        language = should_include_file(member.path, filter_config, *specs)
+        if not language:
+            continue
+        reason = filters.size_or_age_reason(member.size, member.mtime)
+        if reason:
+            filters.report(member.path, reason)
+            continue
+        if member.size > max_size:
+            logger.debug(f"[CodeConCat] Archive member too large, skipping: {member.path}")
+            continue
+        # Sizes in the archive's index can lie, so reads are capped too
+        content = member.read(max_size + 1)
+        if len(content) > max_size:
+            logger.debug(f"[CodeConCat] Archive member too large, skipping: {member.path}")
+            continue
+        reason = filters.size_or_age_reason(len(content), member.mtime)
+        if reason:
+            filters.report(member.path, reason)
+            continue
+        total += len(content)
+        if total > MAX_ARCHIVE_SIZE:
+            logger.warning(
+                f"[CodeConCat] Stopping at {member.path}: the archive's files exceed "
+                f"{MAX_ARCHIVE_SIZE // (1024 * 1024)} MB"
+            )
+            break
+        file_data = file_data_from_bytes(member.path, content, filter_config, language)
+        if file_data:
+            files.append(file_data)
+
+    files.sort(key=lambda f: f.file_path)
+    logger.info(f"[CodeConCat] Collected {len(files)} files from archive {location}")
+    return files
@@ -0,0 +1,337 @@
+"""Stub entries for binary and asset files.
+
+Binary files (images, fonts, archives, media, compiled artifacts) are skipped
+during normal collection. That keeps the output clean, but it also hides the
+fact that an asset exists at all: an LLM reading the output cannot tell that
+``static/logo.png`` or ``data/fixtures.zip`` is in the repository.
+
+This module emits a small stub for each such file instead of its content:
+path, size, MIME type and, optionally, image dimensions or an archive listing.
+Image dimensions are read directly from the file header (PNG, GIF, JPEG, BMP,
+WebP) so no imaging library is required.
+
+The main entry point is :func:`collect_asset_stubs`, which returns
+:class:`ParsedDocData` items that the writers render like documentation files.
+"""
+
+from __future__ import annotations
+
+import logging
+import mimetypes
+import os
+import struct
+import tarfile
+import zipfile
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from pathspec import PathSpec
+from pathspec.patterns.gitwildmatch import GitWildMatchPattern
+
+from codeconcat.base_types import CodeConCatConfig, ParsedDocData
+from codeconcat.collector.ignore_rules import get_ignore_rules
+from codeconcat.collector.local_collector import BINARY_EXTENSIONS, should_skip_dir
+from codeconcat.collector.symlinks import symlink_policy
+
+logger = logging.getLogger(__name__)
+
+# Extension -> broad asset category shown in the stub
+ASSET_CATEGORIES = {
+    **dict.fromkeys(
+        ("png", "jpg", "jpeg", "gif", "ico", "webp", "bmp", "tiff", "tif", "svg"), "image"
+    ),
+    **dict.fromkeys(("woff", "woff2", "ttf", "eot", "otf"), "font"),
+    **dict.fromkeys(("pdf", "doc", "docx", "xls", "xlsx", "ppt", "pptx"), "document"),
+    **dict.fromkeys(("zip", "tar", "gz", "tgz", "7z", "rar", "bz2", "xz", "whl", "egg"), "archive"),
+    **dict.fromkeys(
+        ("mp3", "mp4", "wav", "avi", "mov", "flac", "ogg", "mkv", "wmv", "mpg", "mpeg"), "media"
+    ),
+}
+
+# MIME types that mimetypes does not know on every platform
+_FALLBACK_MIME_TYPES = {
+    "webp": "image/webp",
+    "woff": "font/woff",
+    "woff2": "font/woff2",
+    "ttf": "font/ttf",
+    "otf": "font/otf",
+    "eot": "application/vnd.ms-fontobject",
+    "7z": "application/x-7z-compressed",
+    "rar": "application/vnd.rar",
+    "whl": "application/zip",
+    "egg": "application/zip",
+    "flac": "audio/flac",
+    "mkv": "video/x-matroska",
+    "sqlite": "application/vnd.sqlite3",
+    "db": "application/vnd.sqlite3",
+}
+
+# Bytes read from the start of an image when looking for its dimensions
+_IMAGE_HEADER_BYTES = 64 * 1024
+
+
+@dataclass
+class AssetStub:
+    """Metadata describing a binary or asset file without its content.
+
+    Attributes:
+        file_path: Absolute path of the asset.
+        size_bytes: File size in bytes.
+        mime_type: Best-guess MIME type.
+        category: Broad category ('image', 'font', 'archive', ...).
+        dimensions: Image width and height in pixels, if known.
+        archive_entries: First entries of an archive listing, if requested.
+        archive_entry_count: Total number of entries in the archive.
+    """
+
+    file_path: str
+    size_bytes: int
+    mime_type: str
+    category: str
+    dimensions: tuple[int, int] | None = None
+    archive_entries: list[str] = field(default_factory=list)
+    archive_entry_count: int = 0
+
+    def to_text(self) -> str:
+        """Render the stub as a short plain-text block."""
+        lines = [
+            f"Binary asset ({self.category}): {os.path.basename(self.file_path)}",
+            f"Size: {format_size(self.size_bytes)} ({self.size_bytes:,} bytes)",
+            f"MIME type: {self.mime_type}",
+        ]
+        if self.dimensions:
+            lines.append(f"Dimensions: {self.dimensions[0]}x{self.dimensions[1]} px")
+        if self.archive_entry_count:
+            lines.append(f"Archive entries: {self.archive_entry_count}")
+            lines.extend(f"  - {name}" for name in self.archive_entries)
+            remaining = self.archive_entry_count - len(self.archive_entries)
+            if remaining > 0:
+                lines.append(f"  ... and {remaining} more")
+        lines.append("(content omitted)")
+        return "\n".join(lines)
+
+
+def format_size(size_bytes: int) -> str:
+    """Format a byte count in human-readable units."""
+    size = float(size_bytes)
+    for unit in ("B", "KB", "MB", "GB"):
+        if size < 1024 or unit == "GB":
+            return f"{size:.0f} {unit}" if unit == "B" else f"{size:.1f} {unit}"
+        size /= 1024
+    return f"{size_bytes} B"  # pragma: no cover - loop always returns
+
+
+def _extension(file_path: str) -> str:
+    return os.path.splitext(file_path)[1].lstrip(".").lower()
+
+
+def is_asset_file(file_path: str) -> bool:
+    """Return True if the file is a binary asset that would be skipped during collection."""
+    return _extension(file_path) in BINARY_EXTENSIONS
+
+
+def guess_mime_type(file_path: str) -> str:
+    """Guess a MIME type from the file name, falling back to application/octet-stream."""
+    mime_type, _ = mimetypes.guess_type(file_path)
+    if mime_type:
+        return mime_type
+    return _FALLBACK_MIME_TYPES.get(_extension(file_path), "application/octet-stream")
+
+
+def read_image_dimensions(file_path: str) -> tuple[int, int] | None:
+    """Read image width and height from the file header.
+
+    Supports PNG, GIF, BMP, WebP and JPEG. Returns None for other formats or
+    when the header cannot be parsed.
+    """
+    try:
+        with open(file_path, "rb") as f:
+            header = f.read(_IMAGE_HEADER_BYTES)
+    except OSError:
+        return None
+
+    try:
+        if header.startswith(b"\x89PNG\r\n\x1a\n") and header[12:16] == b"IHDR":
+            width, height = struct.unpack(">II", header[16:24])
+            return width, height
+        if header[:6] in (b"GIF87a", b"GIF89a"):
+            width, height = struct.unpack("<HH", header[6:10])
+            return width, height
+        if header.startswith(b"BM"):
+            width, height = struct.unpack("<ii", header[18:26])
+            return width, abs(height)
+        if header.startswith(b"RIFF") and header[8:12] == b"WEBP":
+            return _webp_dimensions(header)
+        if header.startswith(b"\xff\xd8"):
+            return _jpeg_dimensions(header)
+    except struct.error:
+        return None
+    return None
+
+
+def _webp_dimensions(header: bytes) -> tuple[int, int] | None:
+    chunk = header[12:16]
+    if chunk == b"VP8X":
+        width = int.from_bytes(header[24:27], "little") + 1
+        height = int.from_bytes(header[27:30], "little") + 1
+        return width, height
+    if chunk == b"VP8 ":
+        width, height = struct.unpack("<HH", header[26:30])
+        return width & 0x3FFF, height & 0x3FFF
+    if chunk == b"VP8L":
+        bits = int.from_bytes(header[21:25], "little")
+        return (bits & 0x3FFF) + 1, ((bits >> 14) & 0x3FFF) + 1
+    return None
+
+
+def _jpeg_dimensions(header: bytes) -> tuple[int, int] | None:
+    # Walk JPEG markers until a start-of-frame segment carries the size
+    offset = 2
+    while offset + 9 <= len(header):
+        if header[offset] != 0xFF:
+            return None
+        marker = header[offset + 1]
+        if marker in (0xD8, 0x01) or 0xD0 <= marker <= 0xD7:
+            offset += 2
+            continue
+        (segment_length,) = struct.unpack(">H", header[offset + 2 : offset + 4])
+        if 0xC0 <= marker <= 0xCF and marker not in (0xC4, 0xC8, 0xCC):
+            height, width = struct.unpack(">HH", header[offset + 5 : offset + 9])
+            return width, height
+        offset += 2 + segment_length
+    return None
+
+
+def list_archive(file_path: str, max_entries: int) -> tuple[list[str], int]:
+    """List the members of a zip or tar archive.
+
+    Args:
+        file_path: Path to the archive.
+        max_entries: Maximum number of member names to return.
+
+    Returns:
+        Tuple of (first ``max_entries`` member names, total member count).
+        Returns ``([], 0)`` for unsupported or unreadable archives.
+    """
+    try:
+        if zipfile.is_zipfile(file_path):
+            with zipfile.ZipFile(file_path) as zf:
+                names = [n for n in zf.namelist() if not n.endswith("/")]
+        elif tarfile.is_tarfile(file_path):
+            with tarfile.open(file_path) as tf:
+                names = [m.name for m in tf.getmembers() if m.isfile()]
+        else:
+            return [], 0
+    except (OSError, zipfile.BadZipFile, tarfile.TarError) as e:
+        logger.debug(f"Could not list archive {file_path}: {e}")
+        return [], 0
+    return names[:max_entries], len(names)
+
+
+def build_asset_stub(file_path: str, config: CodeConCatConfig) -> AssetStub | None:
+    """Build a stub for a single asset file.
+
+    Args:
+        file_path: Path to the asset.
+        config: Configuration providing ``asset_stub_details`` and
+            ``asset_stub_max_archive_entries``.
+
+    Returns:
+        An AssetStub, or None if the file cannot be stat'ed.
+    """
+    try:
+        size_bytes = os.path.getsize(file_path)
+    except OSError as e:
+        logger.debug(f"Could not stat asset {file_path}: {e}")
+        return None
+
+    ext = _extension(file_path)
+    stub = AssetStub(
+        file_path=file_path,
+        size_bytes=size_bytes,
+        mime_type=guess_mime_type(file_path),
+        category=ASSET_CATEGORIES.get(ext, "binary"),
+    )
+
+    if not getattr(config, "asset_stub_details", True):
+        return stub
+
+    if stub.category == "image":
+        stub.dimensions = read_image_dimensions(file_path)
+    elif stub.category == "archive":
+        max_entries = getattr(config, "asset_stub_max_archive_entries", 20)
+        stub.archive_entries, stub.archive_entry_count = list_archive(file_path, max_entries)
+
+    return stub
+
+
+def collect_asset_stubs(root_path: str, config: CodeConCatConfig) -> list[ParsedDocData]:
+    """Find binary assets under ``root_path`` and build stub entries for them.
+
+    Directory pruning follows the collector (default and configured excludes,
+    hidden directories). Files are filtered by ``.codeconcatignore``, by
+    ``.gitignore`` when enabled and by the configured
+    ``exclude_paths``/``include_paths``; the default exclude patterns are
+    deliberately not applied to files because they list the asset extensions
+    this feature exists to report.
+
+    Args:
+        root_path: Directory to search.
+        config: The CodeConCat configuration.
+
+    Returns:
+        A list of ParsedDocData items with ``doc_type="asset_stub"``.
+    """
+    if not os.path.isdir(root_path):
+        return []
+
+    gitignore_spec = get_ignore_rules(root_path, config)
+    exclude_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.exclude_paths)
+        if config.exclude_paths
+        else None
+    )
+    include_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.include_paths)
+        if config.include_paths
+        else None
+    )
+
+    stubs: list[AssetStub] = []
+    for dirpath, dirnames, filenames in os.walk(root_path, topdown=True):
+        dirnames[:] = sorted(
+            d
+            for d in dirnames
+            if not d.startswith(".") and not should_skip_dir(os.path.join(dirpath, d), config)
+        )
+
+        for filename in sorted(filenames):
+            file_path = os.path.join(dirpath, filename)
+            if not is_asset_file(file_path):
+                continue
+            rel_path = Path(os.path.relpath(file_path, root_path)).as_posix()
+            if os.path.islink(file_path) and symlink_policy(rel_path, config) != "follow":
+                continue
+            if gitignore_spec.match_file(rel_path):
+                continue
+            if exclude_spec and exclude_spec.match_file(rel_path):
+                continue
+            if include_spec and not include_spec.match_file(rel_path):
+                continue
+
+            stub = build_asset_stub(file_path, config)
+            if stub:
+                stubs.append(stub)
+
+    logger.info(f"Generated {len(stubs)} asset stubs for {root_path}")
+
+    return [
+        ParsedDocData(
+            file_path=s.file_path,
+            content=s.to_text(),
+            doc_type="asset_stub",
+            summary=f"{s.category} asset, {format_size(s.size_bytes)}, {s.mime_type}",
+            tags=["asset", s.category],
+        )
+        for s in stubs
+    ]
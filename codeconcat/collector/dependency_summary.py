@@ -0,0 +1,611 @@
+"""Lockfile and vendored-dependency summarization for CodeConCat.
+
+Lockfiles and vendored dependency trees are excluded from normal collection
+because their raw content is enormous and mostly noise for an LLM. Dropping
+them silently, however, hides useful facts about the project: which
+ecosystems it uses, how many dependencies it pulls in, and which versions are
+pinned. This module replaces them with compact generated summaries.
+
+Supported lockfiles:
+- npm ``package-lock.json``, Yarn ``yarn.lock``, pnpm ``pnpm-lock.yaml``
+- Cargo ``Cargo.lock``
+- Go ``go.sum``
+- Poetry ``poetry.lock``, uv ``uv.lock``, Pipenv ``Pipfile.lock``
+- Composer ``composer.lock``, Bundler ``Gemfile.lock``
+
+Vendored directories (``vendor/``, ``third_party/``, ``node_modules/``) are
+summarized by listing their top-level packages instead of walking them.
+
+The main entry point is :func:`collect_dependency_summaries`, which returns
+:class:`ParsedDocData` items that the writers render like documentation files.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import os
+import re
+from collections.abc import Callable
+from dataclasses import dataclass, field
+from pathlib import Path
+
+import yaml  # type: ignore[import-untyped]
+from pathspec import PathSpec
+from pathspec.patterns.gitwildmatch import GitWildMatchPattern
+
+from codeconcat.base_types import CodeConCatConfig, ParsedDocData
+from codeconcat.collector.ignore_rules import get_ignore_rules
+
+try:
+    import tomllib
+except ModuleNotFoundError:  # pragma: no cover - Python 3.10 fallback
+    import tomli as tomllib  # type: ignore[no-redef]
+
+logger = logging.getLogger(__name__)
+
+# Directory names treated as vendored dependency trees
+VENDORED_DIR_NAMES = frozenset({"vendor", "third_party", "node_modules"})
+
+# Directories never worth descending into while looking for lockfiles
+_SKIP_DIR_NAMES = frozenset(
+    {".git", ".hg", ".svn", "__pycache__", ".venv", "venv", ".tox", "build", "dist"}
+)
+
+
+@dataclass
+class DependencySummary:
+    """Summary of a lockfile or vendored dependency directory.
+
+    Attributes:
+        source_path: Absolute path of the lockfile or vendored directory.
+        ecosystem: Package ecosystem label (e.g., 'npm', 'cargo', 'go').
+        kind: Either 'lockfile' or 'vendored'.
+        packages: Mapping of package name to the set of resolved versions.
+        direct: Names of top-level (direct) dependencies when known.
+
+    """
+
+    source_path: str
+    ecosystem: str
+    kind: str
+    packages: dict[str, set[str]] = field(default_factory=dict)
+    direct: list[str] = field(default_factory=list)
+
+    @property
+    def dependency_count(self) -> int:
+        """Return the number of distinct packages."""
+        return len(self.packages)
+
+    def notable_versions(self) -> list[str]:
+        """Return human-readable notes about versions worth an LLM's attention.
+
+        A version is notable when the same package resolves to several versions
+        (a likely duplication or conflict) or when it is a pre-release.
+        """
+        notes: list[str] = []
+        for name in sorted(self.packages):
+            versions = sorted(v for v in self.packages[name] if v)
+            if len(versions) > 1:
+                notes.append(f"{name}: multiple versions ({', '.join(versions)})")
+            elif versions and re.search(r"[-+](alpha|beta|rc|pre|dev|canary|next)", versions[0]):
+                notes.append(f"{name}: pre-release {versions[0]}")
+        return notes
+
+    def to_text(self, max_entries: int = 25) -> str:
+        """Render the summary as plain text suitable for any output format.
+
+        Args:
+            max_entries: Maximum number of direct dependencies and notes to list.
+
+        Returns:
+            Multi-line summary text.
+
+        """
+        label = "lockfile" if self.kind == "lockfile" else "vendored directory"
+        lines = [
+            f"Dependency summary ({self.ecosystem} {label})",
+            f"Total dependencies: {self.dependency_count}",
+        ]
+
+        if self.direct:
+            listed = []
+            for name in sorted(self.direct)[:max_entries]:
+                versions = sorted(v for v in self.packages.get(name, set()) if v)
+                listed.append(f"{name} {versions[0]}" if len(versions) == 1 else name)
+            more = len(self.direct) - len(listed)
+            suffix = f", ... +{more} more" if more > 0 else ""
+            lines.append(f"Direct dependencies ({len(self.direct)}): {', '.join(listed)}{suffix}")
+
+        notes = self.notable_versions()
+        if notes:
+            lines.append("Notable versions:")
+            lines.extend(f"  - {note}" for note in notes[:max_entries])
+            if len(notes) > max_entries:
+                lines.append(f"  - ... +{len(notes) - max_entries} more")
+
+        if not self.direct and self.kind == "vendored":
+            names = sorted(self.packages)[:max_entries]
+            if names:
+                lines.append(f"Packages: {', '.join(names)}")
+
+        lines.append("(Raw content omitted by CodeConCat dependency summarization.)")
+        return "\n".join(lines)
+
+
+# --- Helpers ------------------------------------------------------------------
+
+
+def _add(packages: dict[str, set[str]], name: str, version: str | None) -> None:
+    """Record a resolved package version."""
+    if not name:
+        return
+    packages.setdefault(name, set())
+    if version:
+        packages[name].add(str(version))
+
+
+def _read_text(path: str) -> str | None:
+    """Read a file as UTF-8 text, returning None on failure."""
+    try:
+        with open(path, encoding="utf-8", errors="replace") as f:
+            return f.read()
+    except OSError as e:
+        logger.debug(f"Could not read dependency file {path}: {e}")
+        return None
+
+
+def _sibling(path: str, name: str) -> str:
+    """Return the path of a file next to ``path``."""
+    return os.path.join(os.path.dirname(path), name)
+
+
+def _json_keys(path: str, *sections: str) -> list[str]:
+    """Return the keys of the given top-level sections of a JSON manifest."""
+    text = _read_text(path)
+    if not text:
+        return []
+    try:
+        data = json.loads(text)
+    except ValueError:
+        return []
+    if not isinstance(data, dict):
+        return []
+    keys: list[str] = []
+    for section in sections:
+        value = data.get(section)
+        if isinstance(value, dict):
+            keys.extend(value.keys())
+    return keys
+
+
+# --- Lockfile parsers -----------------------------------------------------------
+# Each parser returns (packages, direct_dependency_names).
+
+ParseFn = Callable[[str, str], tuple[dict[str, set[str]], list[str]]]
+
+
+def _parse_package_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    data = json.loads(text)
+    packages: dict[str, set[str]] = {}
+    direct: list[str] = []
+
+    if isinstance(data.get("packages"), dict):
+        for key, info in data["packages"].items():
+            if key == "":
+                for section in ("dependencies", "devDependencies", "optionalDependencies"):
+                    direct.extend((info.get(section) or {}).keys())
+                continue
+            name = info.get("name") or key.rsplit("node_modules/", 1)[-1]
+            _add(packages, name, info.get("version"))
+    else:
+        # lockfileVersion 1: nested "dependencies" tree
+        def walk(deps: dict) -> None:
+            for name, info in deps.items():
+                _add(packages, name, info.get("version"))
+                walk(info.get("dependencies") or {})
+
+        direct.extend((data.get("dependencies") or {}).keys())
+        walk(data.get("dependencies") or {})
+
+    if not direct:
+        direct = _json_keys(_sibling(path, "package.json"), "dependencies", "devDependencies")
+    return packages, direct
+
+
+_YARN_ENTRY = re.compile(r'^"?(@?[^@\s"]+)@')
+_YARN_VERSION = re.compile(r'^\s+version:?\s+"?([^"\s]+)"?')
+
+
+def _parse_yarn_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    packages: dict[str, set[str]] = {}
+    current: list[str] = []
+    for line in text.splitlines():
+        if not line.strip() or line.lstrip().startswith("#"):
+            continue
+        if not line.startswith(" "):
+            # Entry header: 'pkg@^1.0.0, pkg@^1.1.0:'
+            current = []
+            for spec in line.rstrip(":").split(","):
+                match = _YARN_ENTRY.match(spec.strip())
+                if match and match.group(1) != "__metadata":
+                    current.append(match.group(1))
+            continue
+        version_match = _YARN_VERSION.match(line)
+        if version_match and current:
+            for name in current:
+                _add(packages, name, version_match.group(1))
+            current = []
+    direct = _json_keys(_sibling(path, "package.json"), "dependencies", "devDependencies")
+    return packages, direct
+
+
+_PNPM_KEY = re.compile(r"^/?(@?[^@/]+(?:/[^@/]+)?)[@/]([^(/]+)")
+
+
+def _parse_pnpm_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    data = yaml.safe_load(text) or {}
+    packages: dict[str, set[str]] = {}
+    for key in (data.get("packages") or {}).keys():
+        match = _PNPM_KEY.match(str(key))
+        if match:
+            _add(packages, match.group(1), match.group(2))
+
+    direct: list[str] = []
+    root = (data.get("importers") or {}).get(".") or data
+    for section in ("dependencies", "devDependencies", "optionalDependencies"):
+        direct.extend((root.get(section) or {}).keys())
+    return packages, direct
+
+
+def _parse_toml_packages(text: str) -> dict[str, set[str]]:
+    """Parse the ``[[package]]`` array shared by Cargo, Poetry and uv lockfiles."""
+    data = tomllib.loads(text)
+    packages: dict[str, set[str]] = {}
+    for pkg in data.get("package", []):
+        _add(packages, pkg.get("name", ""), pkg.get("version"))
+    return packages
+
+
+def _parse_cargo_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    packages = _parse_toml_packages(text)
+    direct: list[str] = []
+    manifest = _read_text(_sibling(path, "Cargo.toml"))
+    if manifest:
+        try:
+            cargo = tomllib.loads(manifest)
+            for section in ("dependencies", "dev-dependencies", "build-dependencies"):
+                direct.extend((cargo.get(section) or {}).keys())
+            # The workspace's own crates are packages too; drop them from the count
+            own_name = (cargo.get("package") or {}).get("name")
+            if own_name:
+                packages.pop(own_name, None)
+        except tomllib.TOMLDecodeError:
+            pass
+    return packages, direct
+
+
+_REQUIREMENT_NAME = re.compile(r"^\s*([A-Za-z0-9][A-Za-z0-9._-]*)")
+
+
+def _parse_python_toml_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    packages = _parse_toml_packages(text)
+    direct: list[str] = []
+    manifest = _read_text(_sibling(path, "pyproject.toml"))
+    if manifest:
+        try:
+            project = tomllib.loads(manifest)
+            poetry = (project.get("tool") or {}).get("poetry") or {}
+            direct.extend(k for k in (poetry.get("dependencies") or {}) if k != "python")
+            for group in (poetry.get("group") or {}).values():
+                direct.extend((group.get("dependencies") or {}).keys())
+            for requirement in (project.get("project") or {}).get("dependencies", []):
+                match = _REQUIREMENT_NAME.match(requirement)
+                if match:
+                    direct.append(match.group(1))
+            own_name = (project.get("project") or {}).get("name") or poetry.get("name")
+            if own_name:
+                packages.pop(own_name, None)
+        except tomllib.TOMLDecodeError:
+            pass
+    return packages, direct
+
+
+def _parse_pipfile_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    data = json.loads(text)
+    packages: dict[str, set[str]] = {}
+    for section in ("default", "develop"):
+        for name, info in (data.get(section) or {}).items():
+            _add(packages, name, str(info.get("version", "")).lstrip("="))
+    # Pipfile is TOML; its [packages] tables name the direct dependencies
+    direct: list[str] = []
+    manifest = _read_text(_sibling(path, "Pipfile"))
+    if manifest:
+        try:
+            pipfile = tomllib.loads(manifest)
+            for section in ("packages", "dev-packages"):
+                direct.extend((pipfile.get(section) or {}).keys())
+        except tomllib.TOMLDecodeError:
+            pass
+    return packages, direct
+
+
+def _parse_composer_lock(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    data = json.loads(text)
+    packages: dict[str, set[str]] = {}
+    for section in ("packages", "packages-dev"):
+        for pkg in data.get(section) or []:
+            _add(packages, pkg.get("name", ""), pkg.get("version"))
+    direct = [
+        name
+        for name in _json_keys(_sibling(path, "composer.json"), "require", "require-dev")
+        if name != "php" and not name.startswith("ext-")
+    ]
+    return packages, direct
+
+
+_GEM_SPEC = re.compile(r"^    ([^\s(]+) \(([^)]+)\)$")
+_GEM_DEP = re.compile(r"^  ([^\s(!]+)")
+
+
+def _parse_gemfile_lock(text: str, _path: str) -> tuple[dict[str, set[str]], list[str]]:
+    packages: dict[str, set[str]] = {}
+    direct: list[str] = []
+    section = ""
+    for line in text.splitlines():
+        if line and not line.startswith(" "):
+            section = line.strip()
+            continue
+        if section in ("GEM", "GIT", "PATH"):
+            match = _GEM_SPEC.match(line)
+            if match:
+                _add(packages, match.group(1), match.group(2))
+        elif section == "DEPENDENCIES":
+            match = _GEM_DEP.match(line)
+            if match:
+                direct.append(match.group(1))
+    return packages, direct
+
+
+def _parse_go_sum(text: str, path: str) -> tuple[dict[str, set[str]], list[str]]:
+    packages: dict[str, set[str]] = {}
+    for line in text.splitlines():
+        parts = line.split()
+        if len(parts) >= 2:
+            _add(packages, parts[0], parts[1].removesuffix("/go.mod"))
+
+    # go.sum keeps hashes for every version ever considered; the versions
+    # actually in use are those listed in go.mod, so prefer those when present.
+    direct: list[str] = []
+    manifest = _read_text(_sibling(path, "go.mod"))
+    if manifest:
+        in_block = False
+        for raw in manifest.splitlines():
+            line = raw.strip()
+            if line.startswith("require ("):
+                in_block = True
+                continue
+            if in_block and line == ")":
+                in_block = False
+                continue
+            if in_block or line.startswith("require "):
+                parts = line.removeprefix("require ").split()
+                if len(parts) >= 2:
+                    packages[parts[0]] = {parts[1]}
+                    if "// indirect" not in line:
+                        direct.append(parts[0])
+    return packages, direct
+
+
+# Lockfile name -> (ecosystem, parser)
+LOCKFILE_PARSERS: dict[str, tuple[str, ParseFn]] = {
+    "package-lock.json": ("npm", _parse_package_lock),
+    "npm-shrinkwrap.json": ("npm", _parse_package_lock),
+    "yarn.lock": ("yarn", _parse_yarn_lock),
+    "pnpm-lock.yaml": ("pnpm", _parse_pnpm_lock),
+    "Cargo.lock": ("cargo", _parse_cargo_lock),
+    "go.sum": ("go", _parse_go_sum),
+    "poetry.lock": ("poetry", _parse_python_toml_lock),
+    "uv.lock": ("uv", _parse_python_toml_lock),
+    "Pipfile.lock": ("pipenv", _parse_pipfile_lock),
+    "composer.lock": ("composer", _parse_composer_lock),
+    "Gemfile.lock": ("bundler", _parse_gemfile_lock),
+}
+
+
+def is_lockfile(file_path: str) -> bool:
+    """Return True if the file name is a supported lockfile."""
+    return os.path.basename(file_path) in LOCKFILE_PARSERS
+
+
+def summarize_lockfile(file_path: str) -> DependencySummary | None:
+    """Summarize a single lockfile.
+
+    Args:
+        file_path: Path to the lockfile.
+
+    Returns:
+        A DependencySummary, or None if the file is unsupported or unparseable.
+
+    """
+    entry = LOCKFILE_PARSERS.get(os.path.basename(file_path))
+    if entry is None:
+        return None
+    ecosystem, parse = entry
+
+    text = _read_text(file_path)
+    if text is None:
+        return None
+
+    try:
+        packages, direct = parse(text, file_path)
+    except (ValueError, KeyError, TypeError, AttributeError, yaml.YAMLError) as e:
+        # json.JSONDecodeError and tomllib.TOMLDecodeError are ValueError subclasses
+        logger.warning(f"Could not parse lockfile {file_path}: {e}")
+        return None
+
+    return DependencySummary(
+        source_path=os.path.abspath(file_path),
+        ecosystem=ecosystem,
+        kind="lockfile",
+        packages=packages,
+        direct=sorted(set(direct)),
+    )
+
+
+def summarize_vendored_dir(dir_path: str) -> DependencySummary | None:
+    """Summarize a vendored dependency directory without walking it fully.
+
+    Only the top level is listed (plus one level for npm ``@scope`` folders and
+    Go's ``vendor/modules.txt`` manifest), so this stays cheap even for huge
+    ``node_modules`` trees.
+
+    Args:
+        dir_path: Path to the vendored directory.
+
+    Returns:
+        A DependencySummary, or None if the directory is empty or unreadable.
+
+    """
+    name = os.path.basename(os.path.normpath(dir_path))
+    packages: dict[str, set[str]] = {}
+
+    modules_txt = os.path.join(dir_path, "modules.txt")
+    if name == "vendor" and os.path.isfile(modules_txt):
+        text = _read_text(modules_txt) or ""
+        for line in text.splitlines():
+            parts = line.split()
+            if len(parts) >= 3 and parts[0] == "#":
+                _add(packages, parts[1], parts[2])
+        ecosystem = "go"
+    else:
+        try:
+            entries = sorted(os.listdir(dir_path))
+        except OSError as e:
+            logger.debug(f"Could not list vendored directory {dir_path}: {e}")
+            return None
+
+        for entry in entries:
+            entry_path = os.path.join(dir_path, entry)
+            if entry.startswith(".") or not os.path.isdir(entry_path):
+                continue
+            if entry.startswith("@"):
+                try:
+                    scoped = sorted(os.listdir(entry_path))
+                except OSError:
+                    continue
+                for sub in scoped:
+                    _add(packages, f"{entry}/{sub}", _package_json_version(entry_path, sub))
+            else:
+                _add(packages, entry, _package_json_version(dir_path, entry))
+        ecosystem = "npm" if name == "node_modules" else "vendored"
+
+    if not packages:
+        return None
+
+    return DependencySummary(
+        source_path=os.path.abspath(dir_path),
+        ecosystem=ecosystem,
+        kind="vendored",
+        packages=packages,
+    )
+
+
+def _package_json_version(parent: str, package_dir: str) -> str | None:
+    """Read the version from a vendored package's package.json, if any."""
+    manifest = os.path.join(parent, package_dir, "package.json")
+    if not os.path.isfile(manifest):
+        return None
+    text = _read_text(manifest)
+    try:
+        version = json.loads(text or "{}").get("version")
+    except ValueError:
+        return None
+    return str(version) if version else None
+
+
+def collect_dependency_summaries(
+    root_path: str, config: CodeConCatConfig
+) -> list[ParsedDocData]:
+    """Find lockfiles and vendored directories under ``root_path`` and summarize them.
+
+    Vendored directories are summarized and never descended into, so lockfiles
+    belonging to vendored packages are not reported separately.
+
+    Lockfiles are filtered like collected files: by ``.codeconcatignore``, by
+    ``.gitignore`` when enabled and by ``exclude_paths``/``include_paths``.
+    Vendored directories are only checked against ``exclude_paths``, since
+    they are usually ignored by git and summarizing them is the point.
+
+    Args:
+        root_path: Directory to search.
+        config: Configuration providing ``dependency_summary_max_entries``.
+
+    Returns:
+        A list of ParsedDocData items with ``doc_type="dependency_summary"``.
+
+    """
+    if not os.path.isdir(root_path):
+        return []
+
+    max_entries = config.dependency_summary_max_entries
+    ignore_rules = get_ignore_rules(root_path, config)
+    exclude_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.exclude_paths)
+        if config.exclude_paths
+        else None
+    )
+    include_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.include_paths)
+        if config.include_paths
+        else None
+    )
+    summaries: list[DependencySummary] = []
+
+    for dirpath, dirnames, filenames in os.walk(root_path, topdown=True):
+        kept_dirs = []
+        for d in sorted(dirnames):
+            if d in _SKIP_DIR_NAMES or d.startswith("."):
+                continue
+            rel_dir = Path(os.path.relpath(os.path.join(dirpath, d), root_path)).as_posix() + "/"
+            if exclude_spec and exclude_spec.match_file(rel_dir):
+                continue
+            if d in VENDORED_DIR_NAMES:
+                summary = summarize_vendored_dir(os.path.join(dirpath, d))
+                if summary:
+                    summaries.append(summary)
+                continue
+            if ignore_rules.match_file(rel_dir):
+                continue
+            kept_dirs.append(d)
+        dirnames[:] = kept_dirs
+
+        for filename in sorted(filenames):
+            if filename in LOCKFILE_PARSERS:
+                file_path = os.path.join(dirpath, filename)
+                rel_path = Path(os.path.relpath(file_path, root_path)).as_posix()
+                if ignore_rules.match_file(rel_path):
+                    continue
+                if exclude_spec and exclude_spec.match_file(rel_path):
+                    continue
+                if include_spec and not include_spec.match_file(rel_path):
+                    continue
+                summary = summarize_lockfile(file_path)
+                if summary:
+                    summaries.append(summary)
+
+    logger.info(f"Generated {len(summaries)} dependency summaries for {root_path}")
+
+    return [
+        ParsedDocData(
+            file_path=s.source_path,
+            content=s.to_text(max_entries),
+            doc_type="dependency_summary",
+            summary=(
+                f"{s.ecosystem} {s.kind}: {s.dependency_count} dependencies"
+                + (f", {len(s.direct)} direct" if s.direct else "")
+            ),
+            tags=["dependencies", s.ecosystem],
+        )
+        for s in summaries
+    ]
@@ -15,11 +15,15 @@ from codeconcat.base_types import (
     DiffMetadata,
     ParsedFileData,
 )
+from codeconcat.collector.diff_context import related_files
 from codeconcat.language_map import ext_map, get_language_guesslang
 from codeconcat.parser.unified_pipeline import parse_code_files
 
 logger = logging.getLogger(__name__)
 
+# Larger files are not considered as context for a diff
+MAX_CONTEXT_FILE_BYTES = 1_000_000
+
 
 class DiffCollector:
     """Collects differential file changes between two Git refs.
@@ -130,6 +134,85 @@ class DiffCollector:
         logger.info(f"Collected {len(annotated_files)} file diffs")
         return annotated_files
 
+    def collect_context(self, changed: list[AnnotatedFileData], mode: str) -> dict[str, str]:
+        """Collect unchanged files related to ``changed`` through the import graph.
+
+        Related files are read from ``to_ref``, so they match the changed code
+        rather than the working tree. With ``mode`` ``"dependents"`` or
+        ``"both"`` every code file at ``to_ref`` is parsed for its imports.
+
+        Args:
+            changed: Items returned by :meth:`collect_diffs`.
+            mode: One of ``DIFF_CONTEXT_MODES``; ``"none"`` collects nothing.
+
+        Returns:
+            Mapping of absolute path to the reason each related file was
+            included. The files themselves are available from
+            :attr:`context_files` as ParsedFileData.
+        """
+        self.context_files: list[ParsedFileData] = []
+        if mode == "none":
+            return {}
+
+        to_commit = self.repo.commit(self.to_ref)
+        blobs = {
+            blob.path: blob
+            for blob in to_commit.tree.traverse()
+            if blob.type == "blob"
+            and blob.size <= MAX_CONTEXT_FILE_BYTES
+            and self._is_code_path(blob.path)
+            and self._should_include_file(blob.path)
+        }
+
+        imports: dict[str, list[str]] = dict.fromkeys(blobs, [])
+        changed_paths = []
+        for item in changed:
+            if item.diff_metadata and item.diff_metadata.change_type == "deleted":
+                continue
+            rel = Path(item.file_path).resolve().relative_to(self.repo_path).as_posix()
+            changed_paths.append(rel)
+            imports[rel] = list(item.imports or [])
+
+        parsed: dict[str, ParsedFileData] = {}
+        if mode in ("dependents", "both"):
+            candidates = [p for p in blobs if p not in changed_paths]
+            for parsed_file in self._parse_blobs(candidates, blobs):
+                rel = Path(parsed_file.file_path).relative_to(self.repo_path).as_posix()
+                parsed[rel] = parsed_file
+                imports[rel] = list(parsed_file.imports or [])
+
+        related = related_files(changed_paths, imports, mode)
+        missing = [p for p in related if p not in parsed]
+        for parsed_file in self._parse_blobs(missing, blobs):
+            parsed[Path(parsed_file.file_path).relative_to(self.repo_path).as_posix()] = parsed_file
+
+        reasons = {}
+        for rel, reason in related.items():
+            if rel in parsed:
+                self.context_files.append(parsed[rel])
+                reasons[parsed[rel].file_path] = reason
+        logger.info(f"Added {len(reasons)} related files as context for the diff ({mode})")
+        return reasons
+
+    def _is_code_path(self, file_path: str) -> bool:
+        ext = os.path.splitext(file_path)[1].lower()
+        return ext in self.config.custom_extension_map or ext in ext_map
+
+    def _parse_blobs(self, paths: list[str], blobs: dict) -> list[ParsedFileData]:
+        """Parse files as of ``to_ref``, with their absolute working-tree paths."""
+        if not paths:
+            return []
+        files = [
+            ParsedFileData(
+                file_path=str(self.repo_path / path),
+                content=blobs[path].data_stream.read().decode("utf-8", errors="replace"),
+                language=self._get_language(path),
+            )
+            for path in paths
+        ]
+        parsed_files, _ = parse_code_files(files, self.config)
+        return parsed_files
+
     def _process_diff_item(self, diff_item, from_commit, to_commit) -> AnnotatedFileData | None:
         """Process a single diff item into AnnotatedFileData.
 
@@ -0,0 +1,61 @@
+"""Related files that give a diff its context.
+
+A review bundle holds the changed files and, optionally, the unchanged files
+directly connected to them in the import graph: their **dependencies**, the
+files the changed code imports, and their **dependents**, the files that
+import the changed code. Only direct neighbours are added so the bundle
+stays review-sized.
+"""
+
+from __future__ import annotations
+
+from ..processor.architecture import file_import_graph
+
+DIFF_CONTEXT_MODES = ("none", "dependencies", "dependents", "both")
+
+# Reasons listed per related file; the rest are counted
+MAX_REASONS = 3
+
+
+def related_files(
+    changed: list[str], imports: dict[str, list[str]], mode: str
+) -> dict[str, str]:
+    """Return the unchanged files directly related to ``changed`` and why.
+
+    Args:
+        changed: Relative paths of the changed files.
+        imports: Import strings by relative path. Must cover every file that
+            may be related; dependents are only found among files whose
+            imports are given.
+        mode: One of :data:`DIFF_CONTEXT_MODES`.
+
+    Returns:
+        A reason such as "imported by app.py" for each related file, by path.
+    """
+    if mode == "none":
+        return {}
+    graph = file_import_graph(imports)
+    changed_set = set(changed)
+    related: dict[str, list[str]] = {}
+
+    if mode in ("dependencies", "both"):
+        for path in changed:
+            for target in sorted(graph.get(path, ())):
+                if target not in changed_set:
+                    related.setdefault(target, []).append(f"imported by {path}")
+
+    if mode in ("dependents", "both"):
+        for path in sorted(graph):
+            if path in changed_set:
+                continue
+            for target in sorted(graph[path] & changed_set):
+                related.setdefault(path, []).append(f"imports {target}")
+
+    return {path: _describe(reasons) for path, reasons in sorted(related.items())}
+
+
+def _describe(reasons: list[str]) -> str:
+    text = "; ".join(reasons[:MAX_REASONS])
+    if len(reasons) > MAX_REASONS:
+        text += f" and {len(reasons) - MAX_REASONS} more"
+    return text
@@ -0,0 +1,229 @@
+"""Filters on file size, modification age and git authorship.
+
+Stale or oversized files can be left out without naming them in path
+patterns. ``max_file_size`` drops files larger than a size such as ``200kb``;
+``modified_since`` drops files not changed within an age such as ``30d`` or
+since a date such as ``2024-06-01``; ``authors`` keeps only files that a
+matching author has committed to.
+
+Ages and authors come from git when the files are in a repository: a tracked
+file was last modified by its latest commit, since a fresh clone gives every
+file the same modification time. Untracked files, and files outside a
+repository, use their modification time on disk.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+import re
+import time
+from dataclasses import dataclass, field
+from datetime import datetime
+from pathlib import Path
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.utils.file_utils import FileSizeConfig
+from codeconcat.validation.unsupported_reporter import get_reporter as get_unsupported_reporter
+
+logger = logging.getLogger(__name__)
+
+_SIZE_UNITS = {
+    "": 1,
+    "b": 1,
+    "k": FileSizeConfig.KB,
+    "kb": FileSizeConfig.KB,
+    "m": FileSizeConfig.MB,
+    "mb": FileSizeConfig.MB,
+    "g": FileSizeConfig.GB,
+    "gb": FileSizeConfig.GB,
+}
+
+_AGE_UNITS = {"s": 1, "m": 60, "h": 3600, "d": 86400, "w": 7 * 86400, "y": 365 * 86400}
+
+
+def parse_size(value: str | int) -> int:
+    """Return the number of bytes in a size such as ``200kb``, ``1.5MB`` or ``512``.
+
+    Units are binary (1kb = 1024 bytes) and case-insensitive.
+
+    Raises:
+        ValueError: If ``value`` is not a size.
+    """
+    if isinstance(value, int):
+        return value
+    match = re.fullmatch(r"\s*(\d+(?:\.\d+)?)\s*([a-zA-Z]*)\s*", value)
+    if not match or match.group(2).lower() not in _SIZE_UNITS:
+        raise ValueError(f"Invalid size '{value}'. Use a number with b, kb, mb or gb.")
+    return int(float(match.group(1)) * _SIZE_UNITS[match.group(2).lower()])
+
+
+def parse_since(value: str, now: float | None = None) -> float:
+    """Return the cutoff timestamp for an age such as ``30d`` or a date.
+
+    Ages count back from ``now`` in seconds (s), minutes (m), hours (h),
+    days (d), weeks (w) or years (y). Dates are ISO 8601, such as
+    ``2024-06-01`` or ``2024-06-01T12:00``, in local time.
+
+    Raises:
+        ValueError: If ``value`` is neither an age nor a date.
+    """
+    match = re.fullmatch(r"\s*(\d+)\s*([smhdwy])\s*", value.lower())
+    if match:
+        now = time.time() if now is None else now
+        return now - int(match.group(1)) * _AGE_UNITS[match.group(2)]
+    try:
+        return datetime.fromisoformat(value.strip()).timestamp()
+    except ValueError:
+        raise ValueError(
+            f"Invalid age '{value}'. Use a number with s, m, h, d, w or y, or a date "
+            "such as 2024-06-01."
+        ) from None
+
+
+@dataclass
+class GitMetadata:
+    """Last commit time and commit authors of the tracked files of a repository.
+
+    Attributes:
+        modified: Absolute path -> timestamp of the latest commit touching it.
+        authors: Absolute path -> ``Name <email>`` of every author who committed to it.
+    """
+
+    modified: dict[str, float] = field(default_factory=dict)
+    authors: dict[str, set[str]] = field(default_factory=dict)
+
+
+def read_git_metadata(path: str, since: float | None = None) -> GitMetadata | None:
+    """Return the metadata of the files under ``path`` from its repository's log.
+
+    Args:
+        path: A file or directory inside the repository.
+        since: If set, only commits from this timestamp on are read. Tracked
+            files with no commit since then are recorded as modified at 0.0,
+            and their authors are not known.
+
+    Returns:
+        The metadata, or None if ``path`` is not inside a git repository or
+        the log cannot be read.
+    """
+    from git import Repo
+    from git.exc import GitCommandError, InvalidGitRepositoryError, NoSuchPathError
+
+    try:
+        repo = Repo(
+            path if os.path.isdir(path) else os.path.dirname(path), search_parent_directories=True
+        )
+    except (InvalidGitRepositoryError, NoSuchPathError):
+        return None
+    root = os.path.realpath(repo.working_tree_dir or path)
+    # -z keeps names with non-ASCII characters unquoted, as they are on disk
+    args = ["-z", "--format=%x00%at%x09%an <%ae>", "--name-only", "--no-renames"]
+    if since is not None:
+        args.append(f"--since=@{int(since)}")
+    try:
+        log = repo.git.log(*args, "--", path)
+        tracked = repo.git.ls_files("-z", "--", path) if since is not None else ""
+    except (GitCommandError, ValueError) as e:
+        logger.warning(f"Cannot read git metadata for {path}: {e}")
+        return None
+
+    metadata = GitMetadata()
+    # Each commit is "\0header\0\nname\0name\0...", so commits end at a double NUL
+    for entry in log.split("\0\0"):
+        header, _, names = entry.strip("\0").partition("\0")
+        if not header:
+            continue
+        timestamp, _, author = header.partition("\t")
+        for name in names.lstrip("\n").split("\0"):
+            if not name:
+                continue
+            file_path = os.path.join(root, name)
+            # The log is newest first, so the first commit seen is the latest
+            metadata.modified.setdefault(file_path, float(timestamp))
+            metadata.authors.setdefault(file_path, set()).add(author)
+    for name in tracked.split("\0"):
+        if name:
+            metadata.modified.setdefault(os.path.join(root, name), 0.0)
+    return metadata
+
+
+class FileFilters:
+    """The size, age and author filters of a run, for the files under one root.
+
+    ``root_path`` is None for files outside any directory, such as archive
+    members, which are filtered by size and age only.
+    """
+
+    def __init__(self, root_path: str | None, config: CodeConCatConfig):
+        self.max_size = config.max_file_size
+        self.cutoff = parse_since(config.modified_since) if config.modified_since else None
+        self.authors = [author.lower() for author in config.authors]
+        self.git: GitMetadata | None = None
+        if root_path is not None and (self.cutoff is not None or self.authors):
+            # The author filter needs every commit; the age filter only the recent ones
+            self.git = read_git_metadata(root_path, None if self.authors else self.cutoff)
+        if self.git is None and self.authors:
+            logger.warning(
+                f"Author filter skipped: {root_path or 'the input'} is not inside a git "
+                "repository"
+            )
+            self.authors = []
+
+    @property
+    def active(self) -> bool:
+        """True if any filter is set."""
+        return self.max_size is not None or self.cutoff is not None or bool(self.authors)
+
+    def _modified(self, path: str) -> float:
+        real_path = os.path.realpath(path)
+        if self.git is not None and real_path in self.git.modified:
+            return self.git.modified[real_path]
+        return os.path.getmtime(path)
+
+    def size_or_age_reason(self, size: int, modified: float) -> str | None:
+        """Return why a file of ``size`` bytes last modified at ``modified`` is filtered out."""
+        if self.max_size is not None and size > self.max_size:
+            return f"larger than {self.max_size} bytes"
+        if self.cutoff is not None and modified < self.cutoff:
+            return "not modified recently enough"
+        return None
+
+    def skip_reason(self, path: str) -> str | None:
+        """Return why the file at ``path`` is filtered out, or None to keep it."""
+        try:
+            reason = self.size_or_age_reason(
+                os.path.getsize(path) if self.max_size is not None else 0,
+                self._modified(path) if self.cutoff is not None else 0.0,
+            )
+        except OSError:
+            return None
+        if reason is None and self.authors:
+            committers = " ".join(self.git.authors.get(os.path.realpath(path), ())).lower()
+            if not any(author in committers for author in self.authors):
+                return "no commits by the requested authors"
+        return reason
+
+    def report(self, path: str, reason: str) -> None:
+        """Record a filtered-out file in the skipped-files report."""
+        logger.debug(f"Filtered out {path}: {reason}")
+        get_unsupported_reporter().add_skipped_file(
+            Path(path), f"Filtered out: {reason}", "filtered"
+        )
+
+    def apply(self, files: list[tuple[str, str]]) -> list[tuple[str, str]]:
+        """Return the ``(path, language)`` pairs that pass the filters."""
+        if not self.active:
+            return files
+        kept = []
+        for file_path, language in files:
+            reason = self.skip_reason(file_path)
+            if reason is None:
+                kept.append((file_path, language))
+            else:
+                self.report(file_path, reason)
+        if len(kept) < len(files):
+            logger.info(
+                f"[CodeConCat] Size, age and author filters left out {len(files) - len(kept)} files"
+            )
+        return kept
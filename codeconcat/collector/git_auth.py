@@ -0,0 +1,260 @@
+"""Credential resolution for cloning remote repositories.
+
+A clone authenticates in one of three ways:
+
+- **SSH**: used for SSH URLs and whenever an SSH key is configured. The key
+  is passed to git through ``GIT_SSH_COMMAND``; without one, the user's SSH
+  agent and ``~/.ssh/config`` apply as usual.
+- **GitHub App**: with an app ID and private key, a short-lived installation
+  token scoped to the repository is requested from the GitHub API, so
+  automation doesn't depend on anyone's personal token.
+- **Access token**: the host's token from the configuration, then from the
+  environment (``GITHUB_TOKEN``/``GH_TOKEN``, ``GITLAB_TOKEN``,
+  ``BITBUCKET_TOKEN``), then from the system keychain (service
+  ``codeconcat``, user ``git_token_<host>``).
+
+Without any of these the clone is anonymous, and git's own credential
+helpers still apply.
+"""
+
+from __future__ import annotations
+
+import base64
+import json
+import logging
+import os
+import shlex
+import time
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+from ..errors import ConfigurationError
+from .git_hosts import BITBUCKET, GITHUB, GITLAB, RemoteRepository
+
+logger = logging.getLogger(__name__)
+
+GITHUB_API_URL = "https://api.github.com"
+
+KEYRING_SERVICE = "codeconcat"
+
+# Environment variables checked, in order, for each host's token
+TOKEN_ENV_VARS = {
+    GITHUB.name: ("GITHUB_TOKEN", "GH_TOKEN"),
+    GITLAB.name: ("GITLAB_TOKEN",),
+    BITBUCKET.name: ("BITBUCKET_TOKEN",),
+}
+
+# Git must fail instead of waiting for a password nobody will type
+_NO_PROMPT_ENV = {"GIT_TERMINAL_PROMPT": "0"}
+
+
+class GitAuthError(ConfigurationError):
+    """Credentials for a remote repository could not be resolved."""
+
+
+@dataclass
+class CloneAuth:
+    """How to clone a repository.
+
+    Attributes:
+        url: Clone URL, including any token.
+        env: Environment variables for the git process.
+        method: ``ssh``, ``github-app``, ``token`` or ``anonymous``; for logs.
+    """
+
+    url: str
+    env: dict[str, str] = field(default_factory=dict)
+    method: str = "anonymous"
+
+
+def keyring_token(host_name: str) -> str | None:
+    """Return the token stored in the system keychain for ``host_name``, if any."""
+    try:
+        import keyring
+
+        return keyring.get_password(KEYRING_SERVICE, f"git_token_{host_name}") or None
+    except Exception as e:
+        # No keyring backend or a locked keychain is the same as no stored token
+        logger.debug(f"Keychain lookup for {host_name} failed: {e}")
+        return None
+
+
+def resolve_token(host_name: str, configured: str | None = None) -> str | None:
+    """Return the access token for ``host_name`` from config, environment or keychain."""
+    if configured:
+        return configured
+    for var in TOKEN_ENV_VARS.get(host_name, ()):
+        if os.environ.get(var):
+            return os.environ[var]
+    return keyring_token(host_name)
+
+
+def ssh_command(key_path: str) -> str:
+    """Return a ``GIT_SSH_COMMAND`` that authenticates with ``key_path`` only.
+
+    Raises:
+        GitAuthError: If the key file does not exist.
+    """
+    path = Path(key_path).expanduser()
+    if not path.is_file():
+        raise GitAuthError(f"SSH key not found: {path}")
+    return f"ssh -i {shlex.quote(str(path))} -o IdentitiesOnly=yes -o BatchMode=yes"
+
+
+def _b64url(data: bytes) -> str:
+    return base64.urlsafe_b64encode(data).rstrip(b"=").decode()
+
+
+def load_private_key(value: str) -> str:
+    """Return the PEM text of a GitHub App private key given as a path or as PEM.
+
+    Raises:
+        GitAuthError: If ``value`` is neither PEM text nor a readable file.
+    """
+    if "-----BEGIN" in value:
+        return value
+    path = Path(value).expanduser()
+    try:
+        return path.read_text()
+    except OSError as e:
+        raise GitAuthError(f"Cannot read GitHub App private key {path}: {e}") from e
+
+
+def github_app_jwt(app_id: str, private_key: str, now: float | None = None) -> str:
+    """Return the RS256 JSON Web Token that authenticates as a GitHub App.
+
+    The token is backdated a minute for clock skew and expires within the
+    ten minutes GitHub allows.
+
+    Args:
+        app_id: The app's ID or client ID.
+        private_key: The app's private key in PEM format.
+        now: Current time, for tests.
+
+    Raises:
+        GitAuthError: If the private key cannot be loaded.
+    """
+    from cryptography.hazmat.primitives import hashes, serialization
+    from cryptography.hazmat.primitives.asymmetric import padding, rsa
+
+    try:
+        key = serialization.load_pem_private_key(private_key.encode(), password=None)
+    except (ValueError, TypeError) as e:
+        raise GitAuthError(f"Invalid GitHub App private key: {e}") from e
+    if not isinstance(key, rsa.RSAPrivateKey):
+        raise GitAuthError("GitHub App private keys must be RSA keys")
+
+    issued = int(now if now is not None else time.time()) - 60
+    header = _b64url(json.dumps({"alg": "RS256", "typ": "JWT"}).encode())
+    claims = {"iat": issued, "exp": issued + 9 * 60, "iss": str(app_id)}
+    signing_input = f"{header}.{_b64url(json.dumps(claims).encode())}"
+    signature = key.sign(signing_input.encode(), padding.PKCS1v15(), hashes.SHA256())
+    return f"{signing_input}.{_b64url(signature)}"
+
+
+async def github_app_installation_token(
+    app_id: str,
+    private_key: str,
+    owner: str,
+    repo: str,
+    installation_id: int | None = None,
+    api_url: str = GITHUB_API_URL,
+) -> str:
+    """Request an installation token that can read ``owner/repo``.
+
+    The installation is looked up from the repository unless
+    ``installation_id`` is given. The token is limited to read access to
+    the one repository and expires after an hour.
+
+    Raises:
+        GitAuthError: If the app is not installed on the repository or the
+            GitHub API rejects the request.
+    """
+    import httpx
+
+    headers = {
+        "Authorization": f"Bearer {github_app_jwt(app_id, private_key)}",
+        "Accept": "application/vnd.github+json",
+        "X-GitHub-Api-Version": "2022-11-28",
+    }
+    async with httpx.AsyncClient(base_url=api_url, headers=headers, timeout=30) as client:
+        try:
+            if not installation_id:
+                response = await client.get(f"/repos/{owner}/{repo}/installation")
+                if response.status_code == 404:
+                    raise GitAuthError(f"GitHub App {app_id} is not installed on {owner}/{repo}")
+                response.raise_for_status()
+                installation_id = response.json()["id"]
+
+            response = await client.post(
+                f"/app/installations/{installation_id}/access_tokens",
+                json={"repositories": [repo], "permissions": {"contents": "read"}},
+            )
+            response.raise_for_status()
+            return str(response.json()["token"])
+        except httpx.HTTPStatusError as e:
+            raise GitAuthError(
+                f"GitHub App token request failed ({e.response.status_code}): "
+                f"{e.response.text[:200]}"
+            ) from e
+        except (httpx.HTTPError, KeyError, ValueError) as e:
+            raise GitAuthError(f"GitHub App token request failed: {e}") from e
+
+
+def _uses_ssh(remote: RemoteRepository, config: Any) -> bool:
+    protocol = getattr(config, "git_protocol", "auto") or "auto"
+    if protocol != "auto":
+        return protocol == "ssh"
+    return remote.transport == "ssh" or bool(getattr(config, "ssh_key_path", None))
+
+
+async def resolve_clone_auth(remote: RemoteRepository, config: Any) -> CloneAuth:
+    """Work out the clone URL and git environment for ``remote``.
+
+    Args:
+        remote: The repository to clone.
+        config: The CodeConCatConfig with the credential settings.
+
+    Raises:
+        GitAuthError: If configured credentials are missing or rejected.
+    """
+    env = dict(_NO_PROMPT_ENV)
+    if _uses_ssh(remote, config):
+        key_path = getattr(config, "ssh_key_path", None)
+        if key_path:
+            env["GIT_SSH_COMMAND"] = ssh_command(key_path)
+        return CloneAuth(remote.ssh_url(), env, "ssh")
+
+    app_id = getattr(config, "github_app_id", None) or os.environ.get("GITHUB_APP_ID")
+    if remote.host is GITHUB and app_id:
+        key = getattr(config, "github_app_private_key", None) or os.environ.get(
+            "GITHUB_APP_PRIVATE_KEY"
+        )
+        if not key:
+            raise GitAuthError(f"GitHub App {app_id} is configured without a private key")
+        installation_id = getattr(config, "github_app_installation_id", None)
+        if not installation_id and os.environ.get("GITHUB_APP_INSTALLATION_ID"):
+            try:
+                installation_id = int(os.environ["GITHUB_APP_INSTALLATION_ID"])
+            except ValueError:
+                raise GitAuthError("GITHUB_APP_INSTALLATION_ID must be an integer") from None
+        token = await github_app_installation_token(
+            str(app_id), load_private_key(key), remote.owner, remote.name, installation_id
+        )
+        return CloneAuth(remote.clone_url(token), env, "github-app")
+
+    username = None
+    if remote.host is GITLAB:
+        configured = getattr(config, "gitlab_token", None)
+    elif remote.host is BITBUCKET:
+        configured = getattr(config, "bitbucket_token", None)
+        username = getattr(config, "bitbucket_username", None) or os.environ.get(
+            "BITBUCKET_USERNAME"
+        )
+    else:
+        configured = getattr(config, "github_token", None)
+    token = resolve_token(remote.host.name, configured)
+    if token:
+        return CloneAuth(remote.clone_url(token, username), env, "token")
+    return CloneAuth(remote.clone_url(), env)
@@ -0,0 +1,123 @@
+"""Condensed git history for the output.
+
+Recent commits give the reader temporal context: what changed lately, who
+changed it and which files were involved. Each commit is reduced to its
+short hash, author, date, subject line and the files it touched, which is
+enough for an LLM to connect the code it reads to recent work without the
+full diffs.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+from dataclasses import asdict, dataclass, field
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+# Files listed per commit; the rest are counted
+MAX_FILES_PER_COMMIT = 10
+
+
+@dataclass
+class GitCommit:
+    """One commit of the condensed history."""
+
+    sha: str
+    author: str
+    date: str
+    subject: str
+    files: list[str] = field(default_factory=list)
+    files_changed: int = 0
+    insertions: int = 0
+    deletions: int = 0
+
+    @property
+    def short_sha(self) -> str:
+        """The abbreviated commit hash."""
+        return self.sha[:7]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Return the commit as a JSON-serializable dict."""
+        return asdict(self)
+
+    def describe_files(self) -> str:
+        """Return the touched files as a comma-separated list, noting any left out."""
+        text = ", ".join(self.files)
+        hidden = self.files_changed - len(self.files)
+        if hidden > 0:
+            text += f" (+{hidden} more)"
+        return text
+
+
+def collect_git_history(
+    path: str, max_commits: int = 20, since_ref: str | None = None
+) -> list[GitCommit]:
+    """Return the recent commits of the repository containing ``path``.
+
+    When ``path`` is a subdirectory of the repository, only commits touching
+    it are listed and file paths are relative to it.
+
+    Args:
+        path: Directory inside a git working tree.
+        max_commits: Maximum number of commits, newest first.
+        since_ref: Only list commits after this ref (``since_ref..HEAD``).
+
+    Returns:
+        The commits, or an empty list if ``path`` is not in a git repository
+        or the ref cannot be resolved.
+    """
+    from git import Repo
+    from git.exc import GitCommandError, InvalidGitRepositoryError, NoSuchPathError
+
+    try:
+        repo = Repo(path, search_parent_directories=True)
+    except (InvalidGitRepositoryError, NoSuchPathError):
+        logger.warning(f"Git history skipped: {path} is not inside a git repository")
+        return []
+
+    root = repo.working_tree_dir or path
+    scope = os.path.relpath(os.path.abspath(path), os.path.abspath(root))
+    scope = "" if scope == "." else scope.replace(os.sep, "/")
+    rev = f"{since_ref}..HEAD" if since_ref else "HEAD"
+
+    commits = []
+    try:
+        for commit in repo.iter_commits(rev, paths=scope, max_count=max_commits):
+            stats = commit.stats
+            files = sorted(stats.files)
+            if scope:
+                prefix = f"{scope}/"
+                files = [f[len(prefix) :] for f in files if f.startswith(prefix)]
+            totals = stats.total
+            message = commit.message if isinstance(commit.message, str) else ""
+            commits.append(
+                GitCommit(
+                    sha=commit.hexsha,
+                    author=commit.author.name or "",
+                    date=commit.committed_datetime.date().isoformat(),
+                    subject=message.strip().splitlines()[0] if message.strip() else "",
+                    files=files[:MAX_FILES_PER_COMMIT],
+                    files_changed=len(files),
+                    insertions=int(totals.get("insertions", 0)),
+                    deletions=int(totals.get("deletions", 0)),
+                )
+            )
+    except (GitCommandError, ValueError) as e:
+        logger.warning(f"Git history skipped: cannot list commits for '{rev}': {e}")
+        return []
+
+    logger.info(f"Collected {len(commits)} commits of git history from {root}")
+    return commits
+
+
+def format_git_history(commits: list[GitCommit]) -> list[str]:
+    """Return one plain-text line per commit for the Markdown and text writers."""
+    lines = []
+    for commit in commits:
+        line = f"{commit.short_sha} {commit.date} {commit.author}: {commit.subject}"
+        if commit.files_changed:
+            line += f" [+{commit.insertions}/-{commit.deletions}; {commit.describe_files()}]"
+        lines.append(line)
+    return lines
@@ -0,0 +1,223 @@
+"""Git hosting services supported by the remote collector.
+
+Each host knows its public domain, the URL path segments that introduce a
+ref in its web URLs, and the username that goes with an access token when
+cloning over HTTPS. :func:`parse_remote_url` turns the URL and shorthand
+forms accepted on the command line into a :class:`RemoteRepository`.
+
+Supported forms:
+    - ``owner/repo``, ``owner/repo#ref`` and ``owner/repo/ref`` (GitHub)
+    - ``github:owner/repo``, ``gitlab:group/subgroup/project`` and
+      ``bitbucket:workspace/repo``, each with an optional ``#ref``
+    - HTTPS, ``git@host:path`` and ``ssh://`` URLs, including web URLs such as
+      ``/tree/<ref>`` (GitHub), ``/-/tree/<ref>`` (GitLab) and
+      ``/src/<ref>`` (Bitbucket)
+
+Self-hosted GitLab instances are recognised by the ``gitlab_url`` setting,
+by a host name containing ``gitlab`` or by GitLab's ``/-/`` URL segment.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import Any
+from urllib.parse import quote, urlsplit
+
+
+@dataclass(frozen=True)
+class GitHost:
+    """A Git hosting service."""
+
+    name: str
+    domain: str
+    # Web URL segments that are followed by a branch, tag or commit
+    ref_markers: tuple[str, ...]
+    # HTTPS username sent with an access token
+    token_user: str
+
+
+GITHUB = GitHost("github", "github.com", ("tree", "blob", "commit"), "x-access-token")
+GITLAB = GitHost("gitlab", "gitlab.com", ("tree", "blob", "commit", "commits"), "oauth2")
+BITBUCKET = GitHost("bitbucket", "bitbucket.org", ("src", "commits", "branch"), "x-token-auth")
+
+HOSTS = {host.name: host for host in (GITHUB, GITLAB, BITBUCKET)}
+
+_SHORTHAND_RE = re.compile(r"^(github|gitlab|bitbucket):(?!//)(.+)$", re.IGNORECASE)
+_SCP_RE = re.compile(r"^([\w.-]+)@([\w.-]+):(?!//)(.+)$")
+
+_INVALID = (
+    "Invalid Git URL or shorthand. Supported formats: 'owner/repo', 'owner/repo#branch', "
+    "'gitlab:group/project', 'bitbucket:workspace/repo', HTTPS URLs and SSH URLs "
+    "(git@host:owner/repo.git)"
+)
+
+
+@dataclass(frozen=True)
+class RemoteRepository:
+    """A repository on a Git host.
+
+    Attributes:
+        host: The hosting service.
+        owner: User, organization or workspace; nested groups for GitLab.
+        name: Repository name without ``.git``.
+        ref: Branch, tag or commit from the URL, if any.
+        domain: Host name, with port, to clone from; differs from
+            ``host.domain`` for self-hosted instances.
+        scheme: ``https``, or ``http`` for self-hosted servers without TLS.
+        transport: ``ssh`` if the repository was given as an SSH URL.
+        ssh_user: User for SSH URLs.
+        ssh_port: Port for SSH URLs that name one.
+    """
+
+    host: GitHost
+    owner: str
+    name: str
+    ref: str | None = None
+    domain: str = ""
+    scheme: str = "https"
+    transport: str = "https"
+    ssh_user: str = "git"
+    ssh_port: int | None = None
+
+    @property
+    def full_name(self) -> str:
+        """The ``owner/name`` path of the repository."""
+        return f"{self.owner}/{self.name}"
+
+    def clone_url(self, token: str | None = None, username: str | None = None) -> str:
+        """Return the HTTPS clone URL, with ``token`` as the password if given.
+
+        Args:
+            token: Access token for private repositories.
+            username: Username for the token; defaults to the host's token user.
+                Bitbucket app passwords need the account's username.
+        """
+        auth = ""
+        if token:
+            user = username or self.host.token_user
+            auth = f"{quote(user, safe='')}:{quote(token, safe='')}@"
+        return f"{self.scheme}://{auth}{self.domain or self.host.domain}/{self.full_name}.git"
+
+    def ssh_url(self) -> str:
+        """Return the SSH clone URL."""
+        hostname = (self.domain or self.host.domain).split(":")[0]
+        if self.ssh_port:
+            return f"ssh://{self.ssh_user}@{hostname}:{self.ssh_port}/{self.full_name}.git"
+        return f"{self.ssh_user}@{hostname}:{self.full_name}.git"
+
+
+def _strip_git(name: str) -> str:
+    return name[:-4] if name.endswith(".git") else name
+
+
+def _split_path(path: str) -> list[str]:
+    return [segment for segment in path.strip("/").split("/") if segment]
+
+
+def _detect_host(domain: str, segments: list[str], gitlab_host: str | None) -> GitHost:
+    bare = domain.lower().rsplit("@", 1)[-1].split(":")[0]
+    bare = bare[4:] if bare.startswith("www.") else bare
+    if bare == GITHUB.domain:
+        return GITHUB
+    if bare == BITBUCKET.domain:
+        return BITBUCKET
+    if bare == GITLAB.domain or bare == gitlab_host or "gitlab" in bare or "-" in segments:
+        return GITLAB
+    raise ValueError(
+        f"Unsupported Git host '{bare}'. Supported hosts are github.com, gitlab.com, "
+        "bitbucket.org and self-hosted GitLab (set gitlab_url for instances whose host "
+        "name does not contain 'gitlab')"
+    )
+
+
+def _from_segments(
+    host: GitHost, segments: list[str], ref: str | None, *, shorthand: bool
+) -> tuple[str, str, str | None]:
+    """Split path segments into owner, name and ref for ``host``."""
+    if host is GITLAB:
+        # Projects can sit in nested groups; web URLs separate the ref with "/-/"
+        rest: list[str] = []
+        if "-" in segments:
+            index = segments.index("-")
+            segments, rest = segments[:index], segments[index + 1 :]
+        if len(segments) < 2:
+            raise ValueError(_INVALID)
+        if len(rest) >= 2 and rest[0] in host.ref_markers:
+            ref = ref or rest[1]
+        return "/".join(segments[:-1]), _strip_git(segments[-1]), ref
+
+    if len(segments) < 2:
+        raise ValueError(_INVALID)
+    owner, name, rest = segments[0], _strip_git(segments[1]), segments[2:]
+    if rest and not ref:
+        if rest[0] in host.ref_markers and len(rest) >= 2:
+            # Web URLs continue with a file path after the ref; shorthands don't
+            ref = "/".join(rest[1:]) if shorthand else rest[1]
+        elif shorthand:
+            ref = "/".join(rest)
+    return owner, name, ref
+
+
+def parse_remote_url(url: str, gitlab_url: str | None = None) -> RemoteRepository:
+    """Parse a Git URL or shorthand into a :class:`RemoteRepository`.
+
+    Args:
+        url: Repository URL or shorthand.
+        gitlab_url: Base URL of a self-hosted GitLab instance, used for
+            ``gitlab:`` shorthands and to recognise its URLs.
+
+    Raises:
+        ValueError: If the URL is malformed or its host is not supported.
+    """
+    text = url.strip()
+    ref = None
+    if "#" in text:
+        text, ref = text.split("#", 1)
+        ref = ref or None
+
+    gitlab_parts = urlsplit(gitlab_url if "://" in (gitlab_url or "") else f"//{gitlab_url or ''}")
+    gitlab_host = (gitlab_parts.hostname or "").lower() or None
+
+    host: GitHost | None = None
+    is_shorthand = False
+    ssh: dict[str, Any] = {}
+    shorthand = _SHORTHAND_RE.match(text)
+    scp = _SCP_RE.match(text)
+    if shorthand:
+        host = HOSTS[shorthand.group(1).lower()]
+        segments = _split_path(shorthand.group(2))
+        domain, scheme = host.domain, "https"
+        if host is GITLAB and gitlab_host:
+            domain, scheme = gitlab_parts.netloc, gitlab_parts.scheme or "https"
+        is_shorthand = True
+    elif scp:
+        domain, segments, scheme = scp.group(2), _split_path(scp.group(3)), "https"
+        ssh = {"transport": "ssh", "ssh_user": scp.group(1)}
+    elif "://" in text:
+        parts = urlsplit(text)
+        if not parts.hostname:
+            raise ValueError(_INVALID)
+        segments = _split_path(parts.path)
+        # SSH ports are kept for SSH clones; HTTPS clones use the host alone
+        if parts.scheme in ("http", "https"):
+            domain, scheme = parts.netloc.rsplit("@", 1)[-1], parts.scheme
+        else:
+            domain, scheme = parts.hostname, "https"
+            ssh = {"transport": "ssh", "ssh_user": parts.username or "git", "ssh_port": parts.port}
+    else:
+        segments = _split_path(text)
+        known = {GITHUB.domain, GITLAB.domain, BITBUCKET.domain, gitlab_host}
+        if segments and segments[0].lower().removeprefix("www.") in known:
+            # Scheme-less URL such as github.com/owner/repo
+            domain, segments, scheme = segments[0], segments[1:], "https"
+        else:
+            host = GITHUB
+            domain, scheme, is_shorthand = GITHUB.domain, "https", True
+
+    if host is None:
+        host = _detect_host(domain, segments, gitlab_host)
+    owner, name, ref = _from_segments(host, segments, ref, shorthand=is_shorthand)
+    if not owner or not name:
+        raise ValueError(_INVALID)
+    return RemoteRepository(host, owner, name, ref, domain, scheme, **ssh)
@@ -5,8 +5,12 @@ using the GitPython library for more robust and maintainable Git operations.
 
 Key Features:
     - Support for GitHub, GitLab, Bitbucket, and other Git services
-    - Token authentication for private repositories
+    - Token, SSH key and GitHub App authentication for private repositories
+    - Self-hosted GitLab instances
+    - Branch, tag or commit selection
+    - Optional checkout of git submodules
     - Shallow cloning for improved performance
+    - Partial clone with sparse checkout when include patterns name directories
     - Automatic fallback from 'main' to 'master' branch
     - Both synchronous and asynchronous interfaces
 
@@ -18,6 +22,7 @@ Example:
 
 import asyncio
 import logging
+import os
 import re
 import tempfile
 
@@ -25,16 +30,24 @@ from git import Repo
 from git.exc import GitCommandError
 
 from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.collector.git_auth import GitAuthError, resolve_clone_auth
+from codeconcat.collector.git_hosts import parse_remote_url
 from codeconcat.collector.local_collector import collect_local_files
+from codeconcat.collector.submodules import read_gitmodules, select_submodules
+from codeconcat.utils.offline import require_network
 
 logger = logging.getLogger(__name__)
 
+# Refs that look like abbreviated or full commit SHAs
+_COMMIT_SHA_RE = re.compile(r"^[0-9a-f]{7,40}$")
+
 
 def parse_git_url(url: str) -> tuple[str, str, str | None]:
     """Parse Git URL or shorthand into owner, repo, and ref.
 
     Supports multiple formats:
     - GitHub shorthand: owner/repo or owner/repo#branch
+    - Host shorthand: gitlab:group/project, bitbucket:workspace/repo
     - HTTPS URLs: https://github.com/owner/repo.git
     - SSH URLs: git@github.com:owner/repo.git
     - URLs with refs: https://github.com/owner/repo/tree/branch
@@ -43,62 +56,15 @@ def parse_git_url(url: str) -> tuple[str, str, str | None]:
         url: Git repository URL or shorthand.
 
     Returns:
-        Tuple of (owner, repo, ref) where ref may be None.
+        Tuple of (owner, repo, ref) where ref may be None. GitLab owners
+        include any nested groups.
 
     Raises:
         ValueError: If URL format is invalid.
     """
-    # Handle GitHub shorthand notation (owner/repo or owner/repo#ref)
-    if "/" in url and not url.startswith(("http", "git@", "ssh://")):
-        # Check for ref specified with # (common convention)
-        if "#" in url:
-            base_url, ref = url.split("#", 1)
-        else:
-            base_url = url
-            ref = None
-
-        parts = base_url.split("/")
-        if len(parts) == 2:
-            owner = parts[0]
-            repo = parts[1].replace(".git", "")
-            logger.debug(f"Parsed shorthand: owner={owner}, repo={repo}, ref={ref}")
-            return owner, repo, ref
-        elif len(parts) > 2:
-            # Support owner/repo/tree/branch format
-            owner = parts[0]
-            repo = parts[1].replace(".git", "")
-            ref = "/".join(parts[2:])
-            return owner, repo, ref
-
-    # Handle full HTTPS URLs
-    match_https = re.match(
-        r"https?://(?:www\.)?github\.com/([^/]+)/([^/\.]+)(?:\.git)?(?:/tree/([^/]+))?", url
-    )
-    if match_https:
-        owner, repo, ref = match_https.group(1), match_https.group(2), match_https.group(3)
-        logger.debug(f"Parsed HTTPS URL: owner={owner}, repo={repo}, ref={ref}")
-        return owner, repo, ref
-
-    # Handle SSH URLs
-    match_ssh = re.match(r"git@github\.com:([^/]+)/([^/\.]+)(?:\.git)?", url)
-    if match_ssh:
-        owner, repo = match_ssh.group(1), match_ssh.group(2)
-        logger.debug(f"Parsed SSH URL: owner={owner}, repo={repo}, ref=None")
-        return owner, repo, None
-
-    # Handle GitLab and other Git hosting services
-    match_gitlab = re.match(
-        r"https?://(?:www\.)?gitlab\.com/([^/]+)/([^/\.]+)(?:\.git)?(?:/-/tree/([^/]+))?", url
-    )
-    if match_gitlab:
-        owner, repo, ref = match_gitlab.group(1), match_gitlab.group(2), match_gitlab.group(3)
-        logger.debug(f"Parsed GitLab URL: owner={owner}, repo={repo}, ref={ref}")
-        return owner, repo, ref
-
-    raise ValueError(
-        "Invalid Git URL or shorthand. Supported formats: 'owner/repo', 'owner/repo#branch', "
-        "'https://github.com/owner/repo', 'git@github.com:owner/repo.git'"
-    )
+    remote = parse_remote_url(url)
+    logger.debug(f"Parsed {remote.host.name} URL: owner={remote.owner}, repo={remote.name}")
+    return remote.owner, remote.name, remote.ref
 
 
 def _build_clone_url(url: str, owner: str, repo: str, token: str | None = None) -> str:
@@ -130,8 +96,55 @@ def _build_clone_url(url: str, owner: str, repo: str, token: str | None = None)
     return base_url
 
 
+def sparse_checkout_dirs(patterns: list[str]) -> list[str] | None:
+    """Return the directories that cover every include pattern, for a sparse checkout.
+
+    A pattern is covered by the literal directories it starts with
+    (``services/api/**/*.py`` needs only ``services/api``). A pattern that can
+    match anywhere in the tree, such as ``**/*.py``, ``*.md`` or ``tests/``,
+    needs the whole repository.
+
+    Returns:
+        The directories, without directories nested in others, or ``None``
+        when the patterns need every path.
+    """
+    dirs = set()
+    for pattern in patterns:
+        pattern = pattern.strip()
+        if not pattern or pattern.startswith(("!", "#")):
+            continue
+        anchored = pattern.startswith("/")
+        pattern = pattern.lstrip("/").removeprefix("./")
+        if not anchored and "/" not in pattern.rstrip("/"):
+            # Like .gitignore, a pattern without an inner slash matches at any depth
+            return None
+        parts = pattern.rstrip("/").split("/")
+        literal = []
+        for part in parts:
+            if not part or any(c in part for c in "*?["):
+                break
+            literal.append(part)
+        if len(literal) == len(parts) and not pattern.endswith("/"):
+            # A literal path may be a file; git sparse-checkout takes directories
+            literal.pop()
+            if not literal:
+                # Files at the top level are always checked out
+                continue
+        if not literal:
+            return None
+        dirs.add("/".join(literal))
+    if not dirs:
+        return None
+    return sorted(d for d in dirs if not any(d.startswith(f"{other}/") for other in dirs))
+
+
 def _clone_repository(
-    clone_url: str, target_dir: str, target_ref: str = "main", depth: int | None = 1
+    clone_url: str,
+    target_dir: str,
+    target_ref: str = "main",
+    depth: int | None = 1,
+    env: dict[str, str] | None = None,
+    sparse_dirs: list[str] | None = None,
 ) -> Repo:
     """Clone a repository using GitPython.
 
@@ -140,6 +153,10 @@ def _clone_repository(
         target_dir: Directory to clone into.
         target_ref: Branch/tag/commit to checkout (default: main).
         depth: Clone depth for shallow clones (None for full clone).
+        env: Extra environment for git, such as ``GIT_SSH_COMMAND``.
+        sparse_dirs: Only check these directories out. The clone is partial
+            (``--filter=blob:none``), so file contents outside them are never
+            downloaded.
 
     Returns:
         GitPython Repo object.
@@ -148,28 +165,90 @@ def _clone_repository(
         GitCommandError: If cloning fails.
     """
     logger.info(f"Cloning repository (ref: {target_ref})")
+    sparse_options = {"filter": "blob:none", "sparse": True} if sparse_dirs else {}
 
     try:
+        if _COMMIT_SHA_RE.match(target_ref):
+            # Commits can't be passed to --branch, so clone fully and check the commit out
+            repo = Repo.clone_from(
+                clone_url, target_dir, env=env, no_checkout=True, **sparse_options
+            )
+            if sparse_dirs:
+                repo.git.sparse_checkout("set", *sparse_dirs)
+            repo.git.checkout(target_ref)
+            logger.info(f"Successfully cloned repository to {target_dir} at commit {target_ref}")
+            return repo
+
         # Perform shallow clone for efficiency (depth=1)
         if depth:
             repo = Repo.clone_from(
-                clone_url, target_dir, branch=target_ref, depth=depth, no_single_branch=False
+                clone_url,
+                target_dir,
+                env=env,
+                branch=target_ref,
+                depth=depth,
+                no_single_branch=False,
+                **sparse_options,
             )
         else:
             # Full clone if depth is None
-            repo = Repo.clone_from(clone_url, target_dir, branch=target_ref)
+            repo = Repo.clone_from(
+                clone_url, target_dir, env=env, branch=target_ref, **sparse_options
+            )
+        if sparse_dirs:
+            repo.git.sparse_checkout("set", *sparse_dirs)
+            logger.info(f"Sparse checkout of {', '.join(sparse_dirs)}")
 
         logger.info(f"Successfully cloned repository to {target_dir}")
         return repo
 
     except GitCommandError as e:
+        if sparse_dirs and "unknown option" in str(e):
+            # git older than 2.25 rejects --sparse before creating anything
+            logger.warning("This git version cannot clone sparsely; cloning every path")
+            return _clone_repository(clone_url, target_dir, target_ref, depth, env)
         # Try with 'master' if 'main' fails
         if target_ref == "main" and "not found" in str(e):
             logger.info("'main' branch not found, trying 'master'...")
-            return _clone_repository(clone_url, target_dir, "master", depth)
+            return _clone_repository(clone_url, target_dir, "master", depth, env, sparse_dirs)
         raise
 
 
+def _update_submodules(
+    repo: Repo, config: CodeConCatConfig, env: dict[str, str] | None = None
+) -> None:
+    """Check out the submodules not skipped by ``submodule_exclude``.
+
+    Submodules are fetched shallowly when the host allows it. If they cannot
+    be fetched a warning is logged and the rest of the repository is still
+    collected.
+    """
+    selected = select_submodules(read_gitmodules(repo.working_dir), config.submodule_exclude)
+    # Submodules outside a sparse checkout have no directory to check out into
+    paths = [s.path for s in selected if os.path.isdir(os.path.join(repo.working_dir, s.path))]
+    if not paths:
+        return
+    logger.info(f"Checking out {len(paths)} submodules")
+    try:
+        repo.git.submodule("update", "--init", "--recursive", "--depth", "1", "--", *paths, env=env)
+    except GitCommandError:
+        # Hosts that refuse fetching unadvertised commits need a full submodule fetch
+        try:
+            repo.git.submodule("update", "--init", "--recursive", "--", *paths, env=env)
+        except GitCommandError as e:
+            logger.warning(f"Could not check out submodules: {e}")
+
+
+def _clone_depth(config: CodeConCatConfig) -> int | None:
+    """Return the clone depth: shallow, but deep enough for the git history section."""
+    if config.git_history_since:
+        return None
+    if config.include_git_history:
+        # One extra commit so the oldest listed commit has a parent to diff against
+        return config.git_history_depth + 1
+    return 1
+
+
 async def collect_git_repo_async(
     source_url_in: str, config: CodeConCatConfig
 ) -> tuple[list[ParsedFileData], tempfile.TemporaryDirectory | None]:
@@ -190,16 +269,22 @@ async def collect_git_repo_async(
         The caller is responsible for calling temp_dir_obj.cleanup() after processing
         is complete to prevent disk leaks. The temp directory must remain valid during
         validation and parsing stages.
+
+    Raises:
+        OfflineError: If offline mode is enabled.
     """
+    require_network("Cloning a remote repository")
     try:
-        owner, repo_name, url_ref = parse_git_url(source_url_in)
+        remote = parse_remote_url(source_url_in, config.gitlab_url)
     except ValueError as e:
         logger.error(f"Failed to parse source URL '{source_url_in}': {e}")
         return [], None
 
     # Use explicit ref from config if provided, otherwise use ref parsed from URL, default to 'main'
-    target_ref = config.source_ref or url_ref or "main"
-    logger.info(f"Targeting ref: '{target_ref}' for repo: '{owner}/{repo_name}'")
+    target_ref = config.source_ref or remote.ref or "main"
+    logger.info(
+        f"Targeting ref: '{target_ref}' for {remote.host.name} repo: '{remote.full_name}'"
+    )
 
     # Create a temporary directory for cloning - caller owns cleanup
     # WHY: Keep temp dir valid while pipeline runs (validation, parsing, etc.)
@@ -208,17 +293,20 @@ async def collect_git_repo_async(
 
     try:
         # Build clone URL with optional authentication
-        clone_url = _build_clone_url(source_url_in, owner, repo_name, config.github_token)
+        auth = await resolve_clone_auth(remote, config)
+        logger.info(f"Cloning with {auth.method} authentication")
 
         # Clone repository using GitPython in thread executor (GitPython is synchronous)
         loop = asyncio.get_event_loop()
         repo = await loop.run_in_executor(
             None,
             _clone_repository,
-            clone_url,
+            auth.url,
             temp_dir,
             target_ref,
-            1,  # Shallow clone for efficiency
+            _clone_depth(config),
+            auth.env,
+            sparse_checkout_dirs(config.include_paths) if config.sparse_clone else None,
         )
 
         # Log repository information
@@ -227,11 +315,13 @@ async def collect_git_repo_async(
             f"Active branch: {repo.active_branch if not repo.head.is_detached else 'detached HEAD'}"
         )
         logger.debug(f"Commit: {repo.head.commit.hexsha[:8]}")
+        if config.include_submodules:
+            await loop.run_in_executor(None, _update_submodules, repo, config, auth.env)
 
         # Collect files using the local collector
         logger.info(f"Collecting files from cloned repository at {temp_dir}")
         files = await loop.run_in_executor(None, collect_local_files, temp_dir, config)
-        logger.info(f"Found {len(files)} files in repository '{owner}/{repo_name}'")
+        logger.info(f"Found {len(files)} files in repository '{remote.full_name}'")
         # HOW: Return temp_dir_obj so caller can manage cleanup
         return files, temp_dir_obj
 
@@ -239,6 +329,10 @@ async def collect_git_repo_async(
         logger.error(f"Git operation failed: {e}")
         temp_dir_obj.cleanup()
         return [], None
+    except GitAuthError as e:
+        logger.error(f"Cannot authenticate to {remote.host.name}: {e}")
+        temp_dir_obj.cleanup()
+        return [], None
     except (OSError, PermissionError, ValueError) as e:
         logger.error(f"Error processing Git repository: {e}")
         temp_dir_obj.cleanup()
@@ -272,7 +366,11 @@ def collect_git_repo(
     Note:
         The caller is responsible for calling temp_dir_obj.cleanup() after processing
         is complete to prevent disk leaks.
+
+    Raises:
+        OfflineError: If offline mode is enabled.
     """
+    require_network("Cloning a remote repository")
     # Check if we're already in an event loop
     try:
         try:
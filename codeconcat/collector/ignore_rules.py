@@ -0,0 +1,152 @@
+"""Ignore files with git's semantics.
+
+Every directory may hold a ``.gitignore`` and a ``.codeconcatignore``. The
+latter uses the same syntax for exclusions that only concern CodeConCat and
+should not live in ``.gitignore``; it is honored even with ``--no-gitignore``.
+
+Matching follows git:
+
+- Patterns are relative to the directory holding the ignore file, and a
+  pattern without an inner slash matches at any depth below it.
+- Within a directory ``.codeconcatignore`` is read after ``.gitignore``, and
+  the ignore files of deeper directories come after those of their parents.
+  The last matching pattern decides, so ``!pattern`` re-includes what an
+  earlier pattern ignored.
+- A pattern ending in ``/`` matches directories only.
+- A file cannot be re-included when a directory above it is ignored.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+from collections.abc import Callable
+
+from pathspec import PathSpec
+from pathspec.patterns.gitwildmatch import GitWildMatchPattern
+
+from codeconcat.base_types import CodeConCatConfig
+
+logger = logging.getLogger(__name__)
+
+GITIGNORE = ".gitignore"
+CODECONCATIGNORE = ".codeconcatignore"
+
+# Patterns that are always ignored along with .gitignore
+ALWAYS_IGNORED = [
+    "**/__pycache__/**",
+    "**/*.pyc",
+    "**/.git/**",
+    "**/node_modules/**",
+    "**/.pytest_cache/**",
+    "**/.coverage",
+    "**/build/**",
+    "**/dist/**",
+    "**/*.egg-info/**",
+]
+
+
+class IgnoreRules:
+    """The ignore files of a tree, matched like git matches them.
+
+    Offers the ``match_file`` method of a PathSpec, so it can stand in for
+    the compiled ``.gitignore`` spec anywhere the collector takes one.
+
+    Args:
+        read_lines: Returns the lines of an ignore file, given the directory
+            relative to the root ('' for the root) and the file name, or None
+            if there is no such file.
+        file_names: Ignore files to read in each directory, in order.
+        extra_patterns: Patterns applied at the root before any ignore file.
+    """
+
+    def __init__(
+        self,
+        read_lines: Callable[[str, str], list[str] | None],
+        file_names: tuple[str, ...] = (GITIGNORE, CODECONCATIGNORE),
+        extra_patterns: list[str] | None = None,
+    ):
+        self._read_lines = read_lines
+        self._file_names = file_names
+        self._extra = list(extra_patterns or [])
+        self._specs: dict[str, PathSpec | None] = {}
+        self._ignored_dirs: dict[str, bool] = {}
+
+    @classmethod
+    def for_directory(cls, root: str, **kwargs) -> IgnoreRules:
+        """Return the rules of the ignore files under the directory ``root``."""
+
+        def read_lines(directory: str, name: str) -> list[str] | None:
+            path = os.path.join(root, directory, name)
+            try:
+                with open(path, encoding="utf-8", errors="replace") as f:
+                    return f.read().splitlines()
+            except OSError:
+                return None
+
+        return cls(read_lines, **kwargs)
+
+    def _spec(self, directory: str) -> PathSpec | None:
+        if directory not in self._specs:
+            lines = list(self._extra) if directory == "" else []
+            for name in self._file_names:
+                lines.extend(self._read_lines(directory, name) or [])
+            lines = [line for line in lines if line.strip() and not line.startswith("#")]
+            self._specs[directory] = (
+                PathSpec.from_lines(GitWildMatchPattern, lines) if lines else None
+            )
+        return self._specs[directory]
+
+    def _decision(self, parts: list[str], is_dir: bool) -> bool | None:
+        """Return True if ignored, False if re-included, None if no pattern matches."""
+        decision = None
+        for depth in range(len(parts)):
+            spec = self._spec("/".join(parts[:depth]))
+            if spec is None:
+                continue
+            relative = "/".join(parts[depth:]) + ("/" if is_dir else "")
+            for pattern in spec.patterns:
+                if pattern.include is not None and pattern.match_file(relative):
+                    decision = pattern.include
+        return decision
+
+    def _dir_ignored(self, parts: list[str]) -> bool:
+        key = "/".join(parts)
+        if key not in self._ignored_dirs:
+            self._ignored_dirs[key] = (
+                len(parts) > 1 and self._dir_ignored(parts[:-1])
+            ) or bool(self._decision(parts, is_dir=True))
+        return self._ignored_dirs[key]
+
+    def match_file(self, path: str) -> bool:
+        """Return True if ``path``, relative to the root, is ignored.
+
+        A path ending in ``/`` is a directory. Paths outside the root are
+        never ignored.
+        """
+        path = str(path).replace(os.sep, "/")
+        is_dir = path.endswith("/")
+        parts = [part for part in path.split("/") if part not in ("", ".")]
+        if not parts or parts[0] == "..":
+            return False
+        if len(parts) > 1 and self._dir_ignored(parts[:-1]):
+            return True
+        if is_dir:
+            return self._dir_ignored(parts)
+        return bool(self._decision(parts, is_dir=False))
+
+
+def ignore_rules_kwargs(config: CodeConCatConfig) -> dict:
+    """Return the :class:`IgnoreRules` arguments that ``config`` calls for.
+
+    ``.codeconcatignore`` files always apply; ``.gitignore`` files and the
+    patterns that go with them only when ``use_gitignore`` is set.
+    """
+    if config.use_gitignore:
+        return {"extra_patterns": ALWAYS_IGNORED}
+    return {"file_names": (CODECONCATIGNORE,)}
+
+
+def get_ignore_rules(root_path: str, config: CodeConCatConfig) -> IgnoreRules:
+    """Return the ignore rules for walking ``root_path`` with ``config``."""
+    return IgnoreRules.for_directory(root_path, **ignore_rules_kwargs(config))
@@ -5,7 +5,7 @@ from the local filesystem. It handles directory traversal, file filtering,
 language detection, and parallel processing for optimal performance.
 
 Features:
-- Directory tree walking with .gitignore support
+- Directory tree walking with nested .gitignore and .codeconcatignore support
 - PathSpec-based pattern matching (same syntax as .gitignore)
 - Language detection by extension and content analysis
 - Binary file detection and filtering
@@ -32,6 +32,7 @@ import hashlib
 import logging
 import os
 import re
+import sys
 from concurrent.futures import ThreadPoolExecutor
 from pathlib import Path
 
@@ -40,8 +41,18 @@ from pathspec.patterns.gitwildmatch import GitWildMatchPattern
 from rich.progress import BarColumn, Progress, SpinnerColumn, TaskProgressColumn, TextColumn
 
 from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.collector.file_filters import FileFilters
+from codeconcat.collector.ignore_rules import ALWAYS_IGNORED, IgnoreRules, get_ignore_rules
+from codeconcat.collector.submodules import find_submodules, select_submodules, submodule_config
+from codeconcat.collector.symlinks import follows_links, symlink_policy
 from codeconcat.constants import DEFAULT_EXCLUDE_PATTERNS, HIDDEN_CONFIG_WHITELIST
-from codeconcat.language_map import GUESSLANG_AVAILABLE, ext_map, get_language_guesslang
+from codeconcat.language_map import (
+    GUESSLANG_AVAILABLE,
+    detect_language_from_content,
+    ext_map,
+    get_language_by_filename,
+    get_language_guesslang,
+)
 from codeconcat.processor.security_processor import SecurityProcessor
 from codeconcat.utils import is_file_too_large_for_binary_check, is_file_too_large_for_collection
 from codeconcat.utils.feature_flags import is_enabled
@@ -73,26 +84,24 @@ def get_gitignore_spec(root_path: str) -> PathSpec:
         Calls: PathSpec.from_lines()
     """
     gitignore_path = os.path.join(root_path, ".gitignore")
-    patterns = []
+    lines: list[str] = []
 
     if os.path.exists(gitignore_path):
         with open(gitignore_path) as f:
-            patterns = [line.strip() for line in f if line.strip() and not line.startswith("#")]
+            lines = f.read().splitlines()
+
+    return gitignore_spec_from_lines(lines)
+
+
+def gitignore_spec_from_lines(lines: list[str]) -> PathSpec:
+    """Create the gitignore PathSpec for the lines of a ``.gitignore`` file.
+
+    Like :func:`get_gitignore_spec`, adds the patterns that are always ignored.
+    """
+    patterns = [line.strip() for line in lines if line.strip() and not line.startswith("#")]
 
     # Add common patterns that should always be ignored
-    patterns.extend(
-        [
-            "**/__pycache__/**",
-            "**/*.pyc",
-            "**/.git/**",
-            "**/node_modules/**",
-            "**/.pytest_cache/**",
-            "**/.coverage",
-            "**/build/**",
-            "**/dist/**",
-            "**/*.egg-info/**",
-        ]
-    )
+    patterns.extend(ALWAYS_IGNORED)
 
     return PathSpec.from_lines(GitWildMatchPattern, patterns)
 
@@ -145,9 +154,9 @@ def should_include_file(
 
     # --- Path Filtering --- #
 
-    # 1. Check .gitignore (if spec exists and enabled)
+    # 1. Check .gitignore and .codeconcatignore (if spec exists)
     # Whitelisted hidden configs bypass gitignore for hidden file patterns
-    if config.use_gitignore and gitignore_spec and gitignore_spec.match_file(norm_path):
+    if gitignore_spec and gitignore_spec.match_file(norm_path):
         if is_whitelisted_hidden:
             if config.verbose:
                 logger.debug(f"Allowing whitelisted hidden config despite .gitignore: {rel_path}")
@@ -196,15 +205,6 @@ def should_include_file(
             )
         return None
 
-    # Special handling for known file types that should be excluded from code parsing
-    # but aren't in doc_extensions
-    filename = os.path.basename(file_path).lower()
-    special_files = ["makefile", "dockerfile", "jenkinsfile", "vagrantfile"]
-    if filename in special_files:
-        if config.verbose:
-            logger.debug(f"Skipping {rel_path} as it's a special file type (name: {filename})")
-        return None
-
     # --- Language Determination and Filtering --- #
     # OPTIMIZED: Check extension FIRST (O(1) lookup, no I/O)
     # Guesslang will be used as fallback in process_file() if needed
@@ -214,16 +214,17 @@ def should_include_file(
         if config.verbose:
             logger.debug(f"Using language '{language}' for {rel_path} based on extension/filename.")
     else:
-        # For files with unknown extensions, we'll try guesslang in process_file()
+        # For files with unknown extensions, we'll detect the language in process_file()
         # For now, mark as potential candidate with special marker
         # This allows the file to proceed to process_file() where content-based
-        # detection will be performed after reading the file once
-        if GUESSLANG_AVAILABLE:
+        # detection will be performed after reading the file once. Shebangs and
+        # modelines are read without guesslang, for files without an extension.
+        if GUESSLANG_AVAILABLE or not os.path.splitext(filename)[1]:
             # Mark for content-based detection in process_file
             language = "__DETECT_BY_CONTENT__"
             if config.verbose:
                 logger.debug(
-                    f"Unknown extension for {rel_path}, will use guesslang in process_file"
+                    f"Unknown extension for {rel_path}, will detect from content in process_file"
                 )
         else:
             if config.verbose:
@@ -247,7 +248,12 @@ def should_include_file(
         return None
 
     # 5. Check include_languages from config
-    if config.include_languages and language not in config.include_languages:
+    # Languages detected from content are checked in file_data_from_bytes()
+    if (
+        config.include_languages
+        and language != "__DETECT_BY_CONTENT__"
+        and language not in config.include_languages
+    ):
         if config.verbose:
             logger.debug(
                 f"Excluded by include_languages: {rel_path} (lang: {language} not in {config.include_languages})"
@@ -287,6 +293,285 @@ def should_include_file(
     return language  # Return the determined language string
 
 
+def _compile_specs(
+    root_path: str, config: CodeConCatConfig
+) -> tuple[IgnoreRules, PathSpec | None, PathSpec | None, PathSpec | None]:
+    """Compile the ignore file rules, default exclude, config exclude and config include specs."""
+    gitignore_spec = get_ignore_rules(
+        root_path if os.path.isdir(root_path) else os.path.dirname(root_path), config
+    )
+    default_exclude_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, DEFAULT_EXCLUDE_PATTERNS)
+        if config.use_default_excludes
+        else None
+    )
+    # Handle None case for config paths
+    config_exclude_patterns = config.exclude_paths or []
+    config_include_patterns = config.include_paths or []
+    # Compile only if patterns exist
+    config_exclude_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config_exclude_patterns)
+        if config_exclude_patterns
+        else None
+    )
+    config_include_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config_include_patterns)
+        if config_include_patterns
+        else None
+    )
+    return gitignore_spec, default_exclude_spec, config_exclude_spec, config_include_spec
+
+
+# Directories that are always skipped, checked first for performance
+# before more complex pattern matching
+_ALWAYS_SKIPPED_DIRS = {
+    # Common cache and build directories
+    "__pycache__",
+    ".git",
+    "node_modules",
+    ".pytest_cache",
+    "build",
+    "dist",
+    # IDE and editor directories
+    ".idea",
+    ".vscode",
+    # Virtual environments - by exact name
+    "venv",
+    ".venv",
+    "env",
+    "codeconcat_venv",
+    "venv_py312",
+    # Common lib directories that are typically large
+    "site-packages",
+    "libs",
+    "vendor",
+    "third_party",
+}
+
+
+def is_skipped_dir(
+    name: str,
+    rel_path: str,
+    specs: tuple[PathSpec | None, PathSpec | None, PathSpec | None, PathSpec | None],
+    config: CodeConCatConfig,
+) -> bool:
+    """Return True if the directory ``name`` at ``rel_path`` is not walked.
+
+    Cache, build, environment and hidden directories are skipped by name,
+    the others when an exclude pattern matches them.
+    """
+    gitignore_spec, default_exclude_spec, config_exclude_spec, _ = specs
+    # Skip if in the explicit skip list
+    if name in _ALWAYS_SKIPPED_DIRS:
+        return True
+
+    # Skip if starts with dot or ends with common patterns
+    if name.startswith(".") or "venv" in name.lower() or "env" in name.lower():
+        return True
+
+    # Skip directories that match common virtual environment patterns
+    if any(pattern in name.lower() for pattern in ["env", "venv", "virtualenv", "pyenv"]):
+        return True
+
+    # For remaining directories, check complex pattern exclusions
+    return is_excluded(
+        rel_path + "/",  # Add '/' for directory match
+        gitignore_spec,
+        default_exclude_spec,
+        config_exclude_spec,
+        None,  # Don't check include_spec for directories - we'll check the files within them
+        config,
+        is_dir=True,
+    )
+
+
+def _walk_directory(
+    root_path: str,
+    config: CodeConCatConfig,
+    specs: tuple[PathSpec | None, PathSpec | None, PathSpec | None, PathSpec | None],
+    pruned_dirs: set[str],
+) -> list[tuple[str, str]]:
+    """Walk ``root_path`` and return ``(path, language)`` for each file to include.
+
+    Directories in ``pruned_dirs`` (absolute paths) are not entered. Symbolic
+    links are followed or skipped by their :func:`symlink_policy`. A followed
+    link keeps its own path, and links leading back to a directory above them
+    are not walked. Each real file is collected once: under its own path when
+    it is reached without a link, otherwise under the first link path by name.
+    """
+    gitignore_spec, default_exclude_spec, config_exclude_spec, config_include_spec = specs
+    real_root = os.path.realpath(root_path)
+    # Real path -> (path, language, reached through a link)
+    collected: dict[str, tuple[str, str, bool]] = {}
+    for dirpath, dirnames, filenames in os.walk(root_path, topdown=True, followlinks=True):
+        # Create paths relative to root_path for matching
+        relative_dirpath = os.path.relpath(dirpath, root_path)
+
+        # A followed link leading back to one of its parents would loop
+        real_dirpath = os.path.realpath(dirpath)
+        parts = Path(relative_dirpath).parts if relative_dirpath != os.curdir else ()
+        if any(
+            os.path.realpath(os.path.join(root_path, *parts[:i])) == real_dirpath
+            for i in range(len(parts))
+        ):
+            logger.debug(f"Skipping directory link that loops back to a parent: {dirpath}")
+            dirnames[:] = []
+            continue
+
+        # Filter dirnames based on exclusion rules (efficiency)
+        # Save original dirnames for logging
+        original_dirnames = dirnames.copy()
+
+        # Filter directories by name first, then by pattern (efficiency)
+        filtered_dirs = []
+        for d in dirnames:
+            # Submodules are walked separately, as roots of their own
+            if os.path.abspath(os.path.join(dirpath, d)) in pruned_dirs:
+                continue
+            rel_dir = os.path.join(relative_dirpath, d)
+            linked = os.path.islink(os.path.join(dirpath, d))
+            if linked and symlink_policy(rel_dir, config) != "follow":
+                continue
+            if not is_skipped_dir(d, rel_dir, specs, config):
+                filtered_dirs.append(d)
+
+        # Update dirnames in-place with our filtered list
+        dirnames[:] = filtered_dirs
+        # Log pruned directories if verbose
+        if config.verbose:
+            pruned_names = set(original_dirnames) - set(dirnames)
+            for pruned in pruned_names:
+                logger.debug(f"Pruning excluded directory: {os.path.join(dirpath, pruned)}")
+
+        for filename in filenames:
+            file_path = os.path.join(dirpath, filename)
+            # Symbolic links are collected only when followed, under their own path
+            is_link = os.path.islink(file_path)
+            if is_link and (
+                symlink_policy(os.path.join(relative_dirpath, filename), config) != "follow"
+                or not os.path.isfile(file_path)
+            ):
+                continue
+            real_path = os.path.realpath(file_path)
+            via_link = real_path != os.path.join(real_root, os.path.relpath(file_path, root_path))
+            seen = collected.get(real_path)
+            if seen and (not seen[2] or (via_link and seen[0] <= os.path.abspath(file_path))):
+                continue
+            # Check if the file itself should be included before adding
+            # Pass compiled specs here
+            lang = should_include_file(
+                file_path,
+                config,
+                gitignore_spec,
+                default_exclude_spec,
+                config_exclude_spec,
+                config_include_spec,
+            )
+            if lang:
+                # Store (file_path, language) tuple to avoid redundant should_include_file call later
+                collected[real_path] = (os.path.abspath(file_path), lang, via_link)
+            # Log exclusion if verbose
+            elif config.verbose:
+                _log_exclusion_reason(
+                    file_path,
+                    config,
+                    gitignore_spec,
+                    default_exclude_spec,
+                    config_exclude_spec,
+                    config_include_spec,
+                )
+
+    return [(path, lang) for path, lang, _ in collected.values()]
+
+
+def _process_files(
+    all_files: list[tuple[str, str]], config: CodeConCatConfig
+) -> list[ParsedFileData]:
+    """Read and process ``(path, language)`` pairs in parallel, in completion order."""
+    parsed_files_data: list[ParsedFileData] = []
+    unsupported_reporter = get_unsupported_reporter()
+    max_workers = (
+        config.max_workers
+        if config.max_workers and config.max_workers > 0
+        else min(32, (os.cpu_count() or 1) + 4)
+    )
+    if (
+        config.verbose or max_workers != config.max_workers
+    ):  # Log if verbose or if value was adjusted
+        logger.info(f"Using {max_workers} workers for parallel processing.")
+
+    # Set a timeout for each file processing task
+    timeout_seconds = 30  # Timeout after 30 seconds per file
+
+    with ThreadPoolExecutor(max_workers=max_workers) as executor:
+        # OPTIMIZED: Language was already determined during os.walk (stored as tuples)
+        # No redundant should_include_file() call needed
+        future_to_file_lang = {}
+        for file_path, lang in all_files:  # Unpack (path, language) tuples
+            # Skip any files that are too large (early filter to prevent hangs)
+            if is_file_too_large_for_collection(file_path):
+                reporter = unsupported_reporter
+                reporter.add_skipped_file(
+                    Path(file_path), "File too large for processing", "too_large"
+                )
+                continue  # File is too large, skip it
+
+            # Submit directly - language already determined, no redundant call
+            future = executor.submit(process_file, file_path, config, lang)
+            future_to_file_lang[future] = (file_path, lang)
+
+        # Import the timeout utilities
+        import concurrent.futures
+        from concurrent.futures import TimeoutError
+
+        # Process results as they complete with timeouts
+        completed = 0
+        total = len(future_to_file_lang)
+
+        # Create progress bar using Rich
+        with Progress(
+            SpinnerColumn(),
+            TextColumn("[bold blue]Processing files"),
+            BarColumn(),
+            TaskProgressColumn(),
+            "[progress.percentage]{task.percentage:>3.0f}%",
+            disable=config.disable_progress_bar,
+        ) as progress:
+            task = progress.add_task("Processing", total=total)
+            # Process each future with a timeout
+            for future in concurrent.futures.as_completed(future_to_file_lang):
+                file_path, language = future_to_file_lang[future]
+                try:
+                    # Apply timeout to prevent hanging on any single file
+                    result = future.result(timeout=timeout_seconds)
+                    if result:
+                        parsed_files_data.append(result)
+                    # else: File processing returned None
+                except TimeoutError:
+                    logger.warning(
+                        f"[CodeConCat] Timeout processing file {file_path} after {timeout_seconds}s"
+                    )
+                except (OSError, UnicodeDecodeError, ValueError, RuntimeError) as exc:
+                    logger.error(
+                        f"[CodeConCat] Error processing file {file_path} in worker: {exc}"
+                    )
+                finally:
+                    # Always update progress regardless of success or failure
+                    completed += 1
+                    progress.update(task, advance=1)
+
+                    # Update the unsupported reporter's process counter
+                    reporter = unsupported_reporter
+                    reporter.increment_processed_count()
+
+                    # Periodically log progress
+                    if completed % 50 == 0 or completed == total:
+                        logger.info(
+                            f"Processed {completed}/{total} files ({completed / total * 100:.1f}%)"
+                        )
+    return parsed_files_data
+
+
 def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[ParsedFileData]:
     """
     Walks a directory tree or processes a single file, identifies, reads, and collects data for code files.
@@ -321,6 +606,8 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
         - Single file or directory tree processing
         - Comprehensive filtering pipeline
         - File size limits (20MB default)
+        - Initialized git submodules with ``include_submodules``, each walked as
+          its own root (see :mod:`codeconcat.collector.submodules`)
         Note: Security scanning is performed in the parsing stage, not in collection
 
     Error Handling:
@@ -347,7 +634,7 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
             )
             root_path = str(
                 SecurityProcessor.validate_path(
-                    base_path, root_path, allow_symlinks=getattr(config, "follow_symlinks", False)
+                    base_path, root_path, allow_symlinks=follows_links(config)
                 )
             )
         except (ValueError, TypeError, OSError, AttributeError) as e:
@@ -356,34 +643,10 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
 
     parsed_files_data: list[ParsedFileData] = []
 
-    # Cache the unsupported reporter to avoid repeated calls
-    unsupported_reporter = get_unsupported_reporter()
-
     # --- Compile PathSpec objects --- #
     # (These are needed for both file and directory cases)
-    gitignore_spec = (
-        get_gitignore_spec(root_path if os.path.isdir(root_path) else os.path.dirname(root_path))
-        if config.use_gitignore
-        else None
-    )
-    default_exclude_spec = (
-        PathSpec.from_lines(GitWildMatchPattern, DEFAULT_EXCLUDE_PATTERNS)
-        if config.use_default_excludes
-        else None
-    )
-    # Handle None case for config paths
-    config_exclude_patterns = config.exclude_paths or []
-    config_include_patterns = config.include_paths or []
-    # Compile only if patterns exist
-    config_exclude_spec = (
-        PathSpec.from_lines(GitWildMatchPattern, config_exclude_patterns)
-        if config_exclude_patterns
-        else None
-    )
-    config_include_spec = (
-        PathSpec.from_lines(GitWildMatchPattern, config_include_patterns)
-        if config_include_patterns
-        else None
+    gitignore_spec, default_exclude_spec, config_exclude_spec, config_include_spec = (
+        _compile_specs(root_path, config)
     )
     # --- End Compile PathSpec objects --- #
 
@@ -400,7 +663,7 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
             config_exclude_spec,
             config_include_spec,
         )
-        if language:
+        if language and FileFilters(root_path, config).apply([(root_path, language)]):
             try:
                 # Process the single file directly
                 result = process_file(root_path, config, language)
@@ -430,104 +693,26 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
     # --- Handle case where root_path is a directory --- #
     elif os.path.isdir(root_path):
         logger.info(f"[CodeConCat] Scanning directory: {root_path}")
-        all_files = []
-        # Use os.walk to recursively find all files
-        for dirpath, dirnames, filenames in os.walk(root_path, topdown=True):
-            # Filter dirnames based on exclusion rules (efficiency)
-            # Create paths relative to root_path for matching
-            relative_dirpath = os.path.relpath(dirpath, root_path)
-
-            # Save original dirnames for logging
-            original_dirnames = dirnames.copy()
-
-            # Enhanced list of directories to always skip
-            # These are checked first for performance before more complex pattern matching
-            skip_dirs = [
-                # Common cache and build directories
-                "__pycache__",
-                ".git",
-                "node_modules",
-                ".pytest_cache",
-                "build",
-                "dist",
-                # IDE and editor directories
-                ".idea",
-                ".vscode",
-                # Virtual environments - by exact name
-                "venv",
-                ".venv",
-                "env",
-                "codeconcat_venv",
-                "venv_py312",
-                # Common lib directories that are typically large
-                "site-packages",
-                "libs",
-                "vendor",
-                "third_party",
-            ]
-
-            # Filter directories by name first (fast check)
-            filtered_dirs = []
-            for d in dirnames:
-                # Skip if in the explicit skip list
-                if d in skip_dirs:
-                    continue
-
-                # Skip if starts with dot or ends with common patterns
-                if d.startswith(".") or "venv" in d.lower() or "env" in d.lower():
-                    continue
-
-                # Skip directories that match common virtual environment patterns
-                if any(pattern in d.lower() for pattern in ["env", "venv", "virtualenv", "pyenv"]):
-                    continue
-
-                # For remaining directories, check complex pattern exclusions
-                if not is_excluded(
-                    os.path.join(relative_dirpath, d) + "/",  # Add '/' for directory match
-                    gitignore_spec,
-                    default_exclude_spec,
-                    config_exclude_spec,
-                    None,  # Don't check include_spec for directories - we'll check the files within them
-                    config,
-                    is_dir=True,
-                ):
-                    filtered_dirs.append(d)
-
-            # Update dirnames in-place with our filtered list
-            dirnames[:] = filtered_dirs
-            # Log pruned directories if verbose
-            if config.verbose:
-                pruned_dirs = set(original_dirnames) - set(dirnames)
-                for pruned in pruned_dirs:
-                    logger.debug(f"Pruning excluded directory: {os.path.join(dirpath, pruned)}")
-
-            for filename in filenames:
-                file_path = os.path.join(dirpath, filename)
-                # Basic check: is it a symbolic link? Skip links for now.
-                if not os.path.islink(file_path):
-                    # Check if the file itself should be included before adding
-                    # Pass compiled specs here
-                    lang = should_include_file(
-                        file_path,
-                        config,
-                        gitignore_spec,
-                        default_exclude_spec,
-                        config_exclude_spec,
-                        config_include_spec,
-                    )
-                    if lang:
-                        # Store (file_path, language) tuple to avoid redundant should_include_file call later
-                        all_files.append((os.path.abspath(file_path), lang))
-                    # Log exclusion if verbose
-                    elif config.verbose:
-                        _log_exclusion_reason(
-                            file_path,
-                            config,
-                            gitignore_spec,
-                            default_exclude_spec,
-                            config_exclude_spec,
-                            config_include_spec,
-                        )
+        submodules = find_submodules(root_path) if config.include_submodules else []
+        pruned_dirs = {os.path.abspath(os.path.join(root_path, s.path)) for s in submodules}
+        all_files = _walk_directory(
+            root_path,
+            config,
+            (gitignore_spec, default_exclude_spec, config_exclude_spec, config_include_spec),
+            pruned_dirs,
+        )
+        all_files = FileFilters(root_path, config).apply(all_files)
+        for submodule in select_submodules(submodules, config.submodule_exclude):
+            sub_config = submodule_config(config, root_path, submodule)
+            sub_files = _walk_directory(
+                sub_config.target_path,
+                sub_config,
+                _compile_specs(sub_config.target_path, sub_config),
+                pruned_dirs,
+            )
+            sub_files = FileFilters(sub_config.target_path, sub_config).apply(sub_files)
+            logger.info(f"[CodeConCat] Submodule {submodule.path}: {len(sub_files)} files")
+            all_files.extend(sub_files)
 
         logger.info(
             f"[CodeConCat] Found {len(all_files)} files matching inclusion criteria. Processing..."
@@ -537,89 +722,7 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
             logger.warning("No files found to process in the specified directory after filtering.")
             return []  # Return empty list if no files are left
 
-        # Use ThreadPoolExecutor for parallel processing
-        # This part is only for directory scanning
-        parsed_files_data = []  # Initialize list for directory results
-        max_workers = (
-            config.max_workers
-            if config.max_workers and config.max_workers > 0
-            else min(32, (os.cpu_count() or 1) + 4)
-        )
-        if (
-            config.verbose or max_workers != config.max_workers
-        ):  # Log if verbose or if value was adjusted
-            logger.info(f"Using {max_workers} workers for parallel processing.")
-
-        # Set a timeout for each file processing task
-        timeout_seconds = 30  # Timeout after 30 seconds per file
-
-        with ThreadPoolExecutor(max_workers=max_workers) as executor:
-            # OPTIMIZED: Language was already determined during os.walk (stored as tuples)
-            # No redundant should_include_file() call needed
-            future_to_file_lang = {}
-            for file_path, lang in all_files:  # Unpack (path, language) tuples
-                # Skip any files that are too large (early filter to prevent hangs)
-                if is_file_too_large_for_collection(file_path):
-                    reporter = unsupported_reporter
-                    reporter.add_skipped_file(
-                        Path(file_path), "File too large for processing", "too_large"
-                    )
-                    continue  # File is too large, skip it
-
-                # Submit directly - language already determined, no redundant call
-                future = executor.submit(process_file, file_path, config, lang)
-                future_to_file_lang[future] = (file_path, lang)
-
-            # Import the timeout utilities
-            import concurrent.futures
-            from concurrent.futures import TimeoutError
-
-            # Process results as they complete with timeouts
-            completed = 0
-            total = len(future_to_file_lang)
-
-            # Create progress bar using Rich
-            with Progress(
-                SpinnerColumn(),
-                TextColumn("[bold blue]Processing files"),
-                BarColumn(),
-                TaskProgressColumn(),
-                "[progress.percentage]{task.percentage:>3.0f}%",
-                disable=config.disable_progress_bar,
-            ) as progress:
-                task = progress.add_task("Processing", total=total)
-                # Process each future with a timeout
-                for future in concurrent.futures.as_completed(future_to_file_lang):
-                    file_path, language = future_to_file_lang[future]
-                    try:
-                        # Apply timeout to prevent hanging on any single file
-                        result = future.result(timeout=timeout_seconds)
-                        if result:
-                            parsed_files_data.append(result)
-                        # else: File processing returned None
-                    except TimeoutError:
-                        logger.warning(
-                            f"[CodeConCat] Timeout processing file {file_path} after {timeout_seconds}s"
-                        )
-                    except (OSError, UnicodeDecodeError, ValueError, RuntimeError) as exc:
-                        logger.error(
-                            f"[CodeConCat] Error processing file {file_path} in worker: {exc}"
-                        )
-                    finally:
-                        # Always update progress regardless of success or failure
-                        completed += 1
-                        progress.update(task, advance=1)
-
-                        # Update the unsupported reporter's process counter
-                        reporter = unsupported_reporter
-                        reporter.increment_processed_count()
-
-                        # Periodically log progress
-                        if completed % 50 == 0 or completed == total:
-                            logger.info(
-                                f"Processed {completed}/{total} files ({completed / total * 100:.1f}%)"
-                            )
-        return parsed_files_data  # Return results from directory scan
+        return _process_files(all_files, config)
 
     # --- Handle case where root_path is neither file nor directory --- #
     else:
@@ -627,6 +730,63 @@ def collect_local_files(root_path: str, config: CodeConCatConfig) -> list[Parsed
         return []  # Return empty list for invalid path
 
 
+def read_file_list(source: str) -> list[str]:
+    """Read the paths named by ``files_from``: a file of paths, or stdin for ``-``.
+
+    Paths are separated by newlines, or by NUL bytes when the input has any
+    (``git diff -z``, ``fd -0``, ``rg -l0``). Blank entries and repeats are
+    dropped; the order is kept.
+    """
+    if source == "-":
+        text = sys.stdin.read()
+    else:
+        with open(source, encoding="utf-8") as f:
+            text = f.read()
+    entries = text.split("\0") if "\0" in text else text.splitlines()
+    return list(dict.fromkeys(e.strip() for e in entries if e.strip()))
+
+
+def collect_file_list(
+    paths: list[str], root_path: str, config: CodeConCatConfig
+) -> list[ParsedFileData]:
+    """Collect the files named in ``paths`` instead of walking ``root_path``.
+
+    Relative paths resolve against ``root_path``. Listed directories are
+    walked. The usual filters still apply, so a list from ``fd`` or
+    ``git diff --name-only`` is narrowed by the run's include and exclude
+    patterns. Paths that no longer exist, such as files deleted in a diff,
+    and paths outside ``root_path`` are skipped.
+    """
+    root_path = os.path.abspath(root_path)
+    specs = _compile_specs(root_path, config)
+    all_files: list[tuple[str, str]] = []
+    seen: set[str] = set()
+    for entry in paths:
+        path = os.path.abspath(os.path.join(root_path, os.path.expanduser(entry)))
+        if os.path.commonpath([root_path, path]) != root_path:
+            logger.warning(f"[CodeConCat] Skipping listed path outside {root_path}: {entry}")
+            continue
+        if os.path.isdir(path):
+            candidates = _walk_directory(path, config, specs, set())
+        elif os.path.isfile(path):
+            language = should_include_file(path, config, *specs)
+            candidates = [(path, language)] if language else []
+        else:
+            logger.debug(f"Skipping listed path that does not exist: {entry}")
+            continue
+        for candidate in candidates:
+            if candidate[0] not in seen:
+                seen.add(candidate[0])
+                all_files.append(candidate)
+
+    all_files = FileFilters(root_path, config).apply(all_files)
+    logger.info(f"[CodeConCat] {len(all_files)} of the listed paths match the filters")
+    if not all_files:
+        logger.warning("No listed files left to process after filtering.")
+        return []
+    return _process_files(all_files, config)
+
+
 def process_file(file_path: str, config: CodeConCatConfig, language: str) -> ParsedFileData | None:
     """Process a single file, reading its content.
 
@@ -654,6 +814,7 @@ def process_file(file_path: str, config: CodeConCatConfig, language: str) -> Par
         This function performs a single read operation for efficiency,
         checking binary content, decoding, and language detection in one pass.
     """
+    requested_path = file_path
     try:
         # Validate file path for security
         if is_enabled("enable_path_validation"):
@@ -664,7 +825,7 @@ def process_file(file_path: str, config: CodeConCatConfig, language: str) -> Par
                     else os.getcwd()
                 )
                 validated_path = SecurityProcessor.validate_path(
-                    base_path, file_path, allow_symlinks=getattr(config, "follow_symlinks", False)
+                    base_path, file_path, allow_symlinks=follows_links(config)
                 )
                 file_path = str(validated_path)
             except (ValueError, TypeError, OSError, AttributeError) as e:
@@ -685,58 +846,13 @@ def process_file(file_path: str, config: CodeConCatConfig, language: str) -> Par
             logger.error(f"[process_file] Error reading {file_path}: {e}")
             return None
 
-        # === BINARY CHECK using already-read content ===
-        if is_binary_content(raw_content[:4096], file_path):
-            logger.debug(f"[process_file] Binary content detected, skipping: {file_path}")
-            return None
-
-        # === DECODE content to string ===
-        try:
-            content = raw_content.decode("utf-8")
-        except UnicodeDecodeError:
-            # Try with error replacement as fallback
-            try:
-                content = raw_content.decode("utf-8", errors="replace")
-                logger.debug(f"[process_file] Decoded {file_path} with replacement chars")
-            except (UnicodeDecodeError, LookupError) as e:
-                # UnicodeDecodeError: Decoding still failed (shouldn't happen with errors="replace")
-                # LookupError: Invalid encoding name
-                logger.warning(
-                    f"[process_file] Could not decode file {file_path}: {type(e).__name__}: {e}"
-                )
-                return None
-
-        # === LANGUAGE DETECTION using content if needed ===
-        if language == "__DETECT_BY_CONTENT__":
-            # Use guesslang with already-read content (no additional I/O)
-            detected_lang = get_language_by_content(content, file_path, bool(config.verbose))
-            if detected_lang:
-                language = detected_lang
-                logger.debug(
-                    f"[process_file] Language detected by content: {language} for {file_path}"
-                )
-            else:
-                # Could not determine language even with guesslang
-                logger.debug(f"[process_file] Could not determine language for: {file_path}")
-                reporter = get_unsupported_reporter()
-                reporter.add_skipped_file(
-                    Path(file_path),
-                    "Could not determine language from content",
-                    "unknown_language",
-                )
-                return None
-
-        logger.debug(f"[CodeConCat] Processed file: {file_path} ({language})")
-
-        # Resolve the file path to handle symlinks and ensure consistency
-        resolved_path = str(Path(file_path).resolve())
-
-        return ParsedFileData(
-            file_path=resolved_path,
-            language=language,
-            content=content,
-            declarations=[],  # We'll fill this in during parsing phase
-        )
+        # Resolve the file path for consistency, unless links are followed:
+        # a followed link is reported under its own path, not its target's
+        if follows_links(config):
+            output_path = os.path.abspath(requested_path)
+        else:
+            output_path = str(Path(file_path).resolve())
+        return file_data_from_bytes(output_path, raw_content, config, language)
     except UnicodeDecodeError:
         logger.debug(f"[CodeConCat] Skipping non-text file: {file_path}")
         return None
@@ -745,6 +861,73 @@ def process_file(file_path: str, config: CodeConCatConfig, language: str) -> Par
         return None
 
 
+def file_data_from_bytes(
+    file_path: str, raw_content: bytes, config: CodeConCatConfig, language: str
+) -> ParsedFileData | None:
+    """Build the :class:`ParsedFileData` for content that has already been read.
+
+    Skips binary content, decodes the text and, for ``__DETECT_BY_CONTENT__``,
+    detects the language from it. ``file_path`` is stored as given.
+    """
+    # === BINARY CHECK using already-read content ===
+    if is_binary_content(raw_content[:4096], file_path):
+        logger.debug(f"[process_file] Binary content detected, skipping: {file_path}")
+        return None
+
+    # === DECODE content to string ===
+    try:
+        content = raw_content.decode("utf-8")
+    except UnicodeDecodeError:
+        # Try with error replacement as fallback
+        try:
+            content = raw_content.decode("utf-8", errors="replace")
+            logger.debug(f"[process_file] Decoded {file_path} with replacement chars")
+        except (UnicodeDecodeError, LookupError) as e:
+            # UnicodeDecodeError: Decoding still failed (shouldn't happen with errors="replace")
+            # LookupError: Invalid encoding name
+            logger.warning(
+                f"[process_file] Could not decode file {file_path}: {type(e).__name__}: {e}"
+            )
+            return None
+
+    # === LANGUAGE DETECTION using content if needed ===
+    if language == "__DETECT_BY_CONTENT__":
+        # Use shebangs, modelines or guesslang with already-read content (no additional I/O)
+        detected_lang = get_language_by_content(content, file_path, bool(config.verbose))
+        if detected_lang:
+            language = detected_lang
+            logger.debug(
+                f"[process_file] Language detected by content: {language} for {file_path}"
+            )
+        else:
+            # Could not determine language even with guesslang
+            logger.debug(f"[process_file] Could not determine language for: {file_path}")
+            reporter = get_unsupported_reporter()
+            reporter.add_skipped_file(
+                Path(file_path),
+                "Could not determine language from content",
+                "unknown_language",
+            )
+            return None
+        if (config.include_languages and language not in config.include_languages) or (
+            config.exclude_languages and language in config.exclude_languages
+        ):
+            logger.debug(f"[process_file] Excluded by language filters: {file_path} ({language})")
+            get_unsupported_reporter().add_skipped_file(
+                Path(file_path), f"Language '{language}' excluded by filters", "excluded_pattern"
+            )
+            return None
+
+    logger.debug(f"[CodeConCat] Processed file: {file_path} ({language})")
+
+    return ParsedFileData(
+        file_path=file_path,
+        language=language,
+        content=content,
+        declarations=[],  # We'll fill this in during parsing phase
+    )
+
+
 def should_skip_dir(dirpath: str, config: CodeConCatConfig) -> bool:
     """Check if a directory should be skipped based on exclude patterns.
 
@@ -857,7 +1040,9 @@ def get_language_by_extension(file_path: str) -> str | None:
     """
     filename = os.path.basename(file_path)
     ext_with_dot = os.path.splitext(file_path)[1].lower()
-    return ext_map.get(filename.lower(), ext_map.get(ext_with_dot))
+    return ext_map.get(filename.lower(), ext_map.get(ext_with_dot)) or get_language_by_filename(
+        filename
+    )
 
 
 # PERFORMANCE: LRU cache for guesslang detection results
@@ -885,11 +1070,13 @@ def _cached_guesslang_detection(content_hash: str, content_sample: str) -> str |
 
 
 def get_language_by_content(content: str, file_path: str = "", verbose: bool = False) -> str | None:
-    """Get language by analyzing file content with guesslang (if available).
+    """Get language by analyzing file content.
 
-    Uses machine learning-based language detection as a fallback when
-    extension-based detection fails. Results are cached based on content
-    hash to avoid repeated ML inference which takes ~100-500ms per call.
+    Used when extension-based detection fails. A shebang line, a vim or Emacs
+    modeline or an unambiguous opening decides first; otherwise guesslang's
+    machine learning-based detection is used if available. Its results are
+    cached based on content hash to avoid repeated ML inference which takes
+    ~100-500ms per call.
 
     Args:
         content: The file content (or first ~5KB of it for analysis).
@@ -898,7 +1085,6 @@ def get_language_by_content(content: str, file_path: str = "", verbose: bool = F
 
     Returns:
         The language identifier string if detected, None otherwise.
-        Returns None if guesslang is not available.
 
     Raises:
         ValueError: If content hashing fails.
@@ -908,6 +1094,13 @@ def get_language_by_content(content: str, file_path: str = "", verbose: bool = F
         PERFORMANCE: Results are cached based on SHA256 hash of the first
         5KB of content. The LRU cache holds up to 512 entries.
     """
+    # Shebangs, modelines and unambiguous openings are cheaper and surer than guesslang
+    language = detect_language_from_content(content)
+    if language:
+        if verbose:
+            logger.debug(f"Language detected from content as '{language}' for {file_path}")
+        return language
+
     if not GUESSLANG_AVAILABLE:
         return None
 
@@ -954,7 +1147,7 @@ def determine_language(
 
     Flow:
         1. Try extension-based detection (O(1), no I/O)
-        2. If no match and content provided, use guesslang
+        2. If no match and content provided, use shebangs, modelines and guesslang
         3. Return result or None
     """
     # FAST PATH: Try extension-based detection first (O(1) lookup, no I/O)
@@ -966,16 +1159,15 @@ def determine_language(
             )
         return language
 
-    # SLOW PATH: Fall back to guesslang for unknown extensions
-    if GUESSLANG_AVAILABLE:
-        # Use provided content for guesslang detection
-        if content is not None:
-            language = get_language_by_content(content, file_path, bool(config.verbose))
-        else:
-            # Skip guesslang if content not provided - caller should provide content
-            # to avoid redundant file reads
-            if config.verbose:
-                logger.debug(f"Skipping guesslang detection for {file_path}: content not provided")
+    # SLOW PATH: Fall back to shebangs, modelines and guesslang for unknown extensions
+    # Use provided content for content-based detection
+    if content is not None:
+        language = get_language_by_content(content, file_path, bool(config.verbose))
+    else:
+        # Skip content detection if content not provided - caller should provide content
+        # to avoid redundant file reads
+        if config.verbose:
+            logger.debug(f"Skipping content detection for {file_path}: content not provided")
 
     if not language and config.verbose:
         logger.debug(f"Could not determine language for {file_path}")
@@ -1292,8 +1484,8 @@ def _log_exclusion_reason(
             f"Attempting to log exclusion reason for: {rel_path} (Normalized: {norm_path}, Full: {file_path})"
         )
 
-    # Check .gitignore (if spec exists and enabled)
-    if config.use_gitignore and gitignore_spec and gitignore_spec.match_file(norm_path):
+    # Check .gitignore and .codeconcatignore (if spec exists)
+    if gitignore_spec and gitignore_spec.match_file(norm_path):
         logger.debug(f"Excluded by .gitignore: {rel_path}")
         return
 
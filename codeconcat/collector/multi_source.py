@@ -0,0 +1,202 @@
+"""Collection from several sources into one output.
+
+``sources`` lists local paths and remote repositories (URLs or shorthands
+such as ``owner/repo``) to pack together, for example a service and the
+internal library it shares with others. Each source is collected with the
+run's filters as if it were the only target, then its files are renamed to
+``<prefix>/<path in the source>`` so paths from different sources cannot
+collide and the model can tell them apart. The prefix defaults to the
+directory or repository name; write ``prefix=location`` to choose it.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+import re
+import tempfile
+from dataclasses import dataclass
+from pathlib import Path
+
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.collector.git_hosts import parse_remote_url
+from codeconcat.collector.github_collector import collect_git_repo
+from codeconcat.collector.local_collector import collect_local_files
+from codeconcat.errors import ConfigurationError, FileProcessingError, ValidationError
+from codeconcat.validation.integration import validate_input_files, verify_file_signatures
+
+logger = logging.getLogger(__name__)
+
+_PREFIX_RE = re.compile(r"^([\w.-]+)=(.+)$")
+
+
+@dataclass
+class Source:
+    """One input of a multi-source run.
+
+    Attributes:
+        prefix: Directory the source's files appear under in the output.
+        location: Local path, remote URL or shorthand.
+        remote: Whether the source is cloned.
+        files: Number of files collected from it.
+    """
+
+    prefix: str
+    location: str
+    remote: bool
+    files: int = 0
+
+
+def _is_remote(location: str, gitlab_url: str | None) -> bool:
+    if os.path.exists(location):
+        return False
+    if location.startswith((".", "/", "~")) or os.path.isabs(location):
+        raise ConfigurationError(f"Source path does not exist: {location}")
+    try:
+        parse_remote_url(location, gitlab_url)
+    except ValueError as e:
+        raise ConfigurationError(f"Source is neither a local path nor a repository: {e}") from e
+    return True
+
+
+def remote_locations(entries: list[str], gitlab_url: str | None = None) -> list[str]:
+    """Return the locations of the ``sources`` entries that are cloned.
+
+    Invalid entries are left for :func:`parse_sources` to report.
+    """
+    locations = []
+    for entry in entries:
+        explicit = None if os.path.exists(entry) else _PREFIX_RE.match(entry)
+        location = explicit.group(2) if explicit else entry
+        try:
+            if _is_remote(location, gitlab_url):
+                locations.append(location)
+        except ConfigurationError:
+            continue
+    return locations
+
+
+def parse_sources(entries: list[str], gitlab_url: str | None = None) -> list[Source]:
+    """Parse ``sources`` entries, giving each source a unique prefix.
+
+    Raises:
+        ConfigurationError: If an entry is neither an existing path nor a
+            repository URL or shorthand, or two entries ask for one prefix.
+    """
+    sources: list[Source] = []
+    taken: set[str] = set()
+    for entry in entries:
+        explicit = None if os.path.exists(entry) else _PREFIX_RE.match(entry)
+        location = explicit.group(2) if explicit else entry
+        remote = _is_remote(location, gitlab_url)
+        if explicit:
+            prefix = explicit.group(1)
+            if prefix in taken:
+                raise ConfigurationError(f"Source prefix '{prefix}' is used twice")
+        else:
+            base = (
+                parse_remote_url(location, gitlab_url).name
+                if remote
+                else os.path.basename(os.path.realpath(location))
+            )
+            prefix, number = base or "source", 2
+            while prefix in taken:
+                prefix, number = f"{base}-{number}", number + 1
+        taken.add(prefix)
+        sources.append(Source(prefix, location, remote))
+    return sources
+
+
+def _validate(files: list[ParsedFileData], config: CodeConCatConfig) -> list[ParsedFileData]:
+    """Validate one source's files against its own root, as the main pipeline does."""
+    try:
+        files = validate_input_files(files, config)
+    except ValidationError as e:
+        logger.error(f"File validation error: {e}")
+        if config.strict_security:
+            raise
+        logger.warning("Continuing with validated files only")
+    try:
+        verify_file_signatures(files)
+    except ValidationError as e:
+        logger.error(f"File signature validation error: {e}")
+        logger.warning("Some files may have incorrect content types")
+    return files
+
+
+def collect_sources(
+    config: CodeConCatConfig,
+) -> tuple[list[ParsedFileData], list[tempfile.TemporaryDirectory], list[Source]]:
+    """Collect and validate every source of ``config.sources``.
+
+    Returns:
+        The files of all sources with prefixed paths, the clone directories
+        the caller must clean up once the run is over, and the sources.
+
+    Raises:
+        ConfigurationError: If a source is invalid.
+        FileProcessingError: If a remote source cannot be cloned.
+    """
+    sources = parse_sources(config.sources, config.gitlab_url)
+    files: list[ParsedFileData] = []
+    temp_dirs: list[tempfile.TemporaryDirectory] = []
+    try:
+        for source in sources:
+            source_config = config.model_copy(deep=True)
+            source_config.sources = []
+            if source.remote:
+                logger.info(f"Collecting source {source.prefix} from {source.location}")
+                source_config.source_url = source.location
+                collected, temp_dir = collect_git_repo(source.location, source_config)
+                if temp_dir is None:
+                    raise FileProcessingError(f"Could not clone source {source.location}")
+                temp_dirs.append(temp_dir)
+                root = temp_dir.name
+            else:
+                logger.info(f"Collecting source {source.prefix} from {source.location}")
+                root = os.path.abspath(source.location)
+                source_config.source_url = None
+                source_config.target_path = root
+                collected = collect_local_files(root, source_config)
+            # Validation keeps each source's files inside its own root
+            source_config.target_path = root
+
+            base = os.path.realpath(root if os.path.isdir(root) else os.path.dirname(root))
+            for file_data in _validate(collected, source_config):
+                relative = Path(os.path.relpath(os.path.realpath(file_data.file_path), base))
+                file_data.file_path = f"{source.prefix}/{relative.as_posix()}"
+                files.append(file_data)
+                source.files += 1
+            logger.info(f"Collected {source.files} files from source {source.prefix}")
+    except BaseException:
+        for temp_dir in temp_dirs:
+            temp_dir.cleanup()
+        raise
+    return files, temp_dirs, sources
+
+
+def render_source_tree(file_paths: list[str]) -> str:
+    """Render the collected files of a multi-source run as a folder tree.
+
+    Uses the layout of the single-source folder tree, with one top-level
+    folder per source prefix.
+    """
+    tree: dict = {}
+    for path in file_paths:
+        node = tree
+        for part in path.split("/")[:-1]:
+            node = node.setdefault(part + "/", {})
+        node.setdefault(path.split("/")[-1], None)
+
+    lines: list[str] = []
+
+    def walk(node: dict, level: int) -> None:
+        files = sorted(name for name, child in node.items() if child is None)
+        folders = sorted(name for name, child in node.items() if child is not None)
+        lines.extend("    " * level + name for name in files)
+        for name in folders:
+            lines.append("    " * level + name)
+            walk(node[name], level + 1)
+
+    walk(tree, 0)
+    return "\n".join(lines)
@@ -0,0 +1,211 @@
+"""Pull request and merge request URLs as diff ranges.
+
+``--diff-from`` accepts the web URL of a GitHub pull request, a GitLab merge
+request or a Bitbucket pull request. The host's API names the target branch
+and where the proposed commits live; both are fetched into the local clone
+under ``refs/codeconcat/`` and the diff runs from their merge base to the
+head of the request, which is the diff the host's review page shows.
+
+Credentials are the ones used for cloning; see :mod:`.git_auth`.
+"""
+
+from __future__ import annotations
+
+import asyncio
+import concurrent.futures
+import logging
+import re
+from dataclasses import dataclass
+from typing import Any
+from urllib.parse import quote
+
+from ..utils.offline import require_network
+from .git_auth import GitAuthError, resolve_clone_auth, resolve_token
+from .git_hosts import BITBUCKET, GITHUB, GITLAB, RemoteRepository, parse_remote_url
+
+logger = logging.getLogger(__name__)
+
+GITHUB_API_URL = "https://api.github.com"
+BITBUCKET_API_URL = "https://api.bitbucket.org/2.0"
+
+# The path segment that introduces the request number, by host
+_NUMBER_RES = {
+    GITHUB.name: re.compile(r"/pull/(\d+)(?:/|$)"),
+    GITLAB.name: re.compile(r"/-/merge_requests/(\d+)(?:/|$)"),
+    BITBUCKET.name: re.compile(r"/pull-requests/(\d+)(?:/|$)"),
+}
+
+
+@dataclass(frozen=True)
+class PullRequest:
+    """A pull or merge request and where its commits can be fetched.
+
+    Attributes:
+        remote: The repository the request targets.
+        number: The request number.
+        base_branch: The target branch.
+        head_refspec: Ref holding the proposed commits.
+        head_remote: Repository holding ``head_refspec`` if it is a fork
+            (Bitbucket only; GitHub and GitLab mirror requests into the
+            target repository).
+    """
+
+    remote: RemoteRepository
+    number: int
+    base_branch: str
+    head_refspec: str
+    head_remote: RemoteRepository | None = None
+
+    @property
+    def local_ref(self) -> str:
+        """Namespace for the fetched refs."""
+        return f"refs/codeconcat/pr-{self.number}"
+
+
+def parse_pull_request_url(
+    url: str, gitlab_url: str | None = None
+) -> tuple[RemoteRepository, int] | None:
+    """Return the repository and number of a pull request URL.
+
+    Returns:
+        ``None`` if ``url`` is not a pull request or merge request URL.
+    """
+    if "://" not in url:
+        return None
+    for host_name, pattern in _NUMBER_RES.items():
+        match = pattern.search(url)
+        if not match:
+            continue
+        try:
+            remote = parse_remote_url(url[: match.start()], gitlab_url)
+        except ValueError:
+            return None
+        if remote.host.name == host_name:
+            return remote, int(match.group(1))
+    return None
+
+
+def _api_get(url: str, headers: dict[str, str], auth: Any = None) -> dict[str, Any]:
+    import httpx
+
+    try:
+        response = httpx.get(url, headers=headers, auth=auth, timeout=30)
+        response.raise_for_status()
+        return response.json()
+    except httpx.HTTPStatusError as e:
+        raise GitAuthError(
+            f"Pull request lookup failed ({e.response.status_code}): {e.response.text[:200]}"
+        ) from e
+    except (httpx.HTTPError, ValueError) as e:
+        raise GitAuthError(f"Pull request lookup failed: {e}") from e
+
+
+def get_pull_request(remote: RemoteRepository, number: int, config: Any) -> PullRequest:
+    """Look up a pull request through the API of its host.
+
+    Raises:
+        GitAuthError: If the host rejects the request or the request does
+            not exist.
+    """
+    if remote.host is GITLAB:
+        headers = {}
+        token = resolve_token(GITLAB.name, getattr(config, "gitlab_token", None))
+        if token:
+            headers["PRIVATE-TOKEN"] = token
+        project = quote(remote.full_name, safe="")
+        data = _api_get(
+            f"{remote.scheme}://{remote.domain or GITLAB.domain}/api/v4/projects/{project}"
+            f"/merge_requests/{number}",
+            headers,
+        )
+        return PullRequest(
+            remote, number, data["target_branch"], f"refs/merge-requests/{number}/head"
+        )
+
+    if remote.host is BITBUCKET:
+        auth = None
+        headers = {}
+        token = resolve_token(BITBUCKET.name, getattr(config, "bitbucket_token", None))
+        username = getattr(config, "bitbucket_username", None)
+        if token and username:
+            auth = (username, token)
+        elif token:
+            headers["Authorization"] = f"Bearer {token}"
+        data = _api_get(
+            f"{BITBUCKET_API_URL}/repositories/{remote.full_name}/pullrequests/{number}",
+            headers,
+            auth,
+        )
+        source = data["source"]
+        head_remote = None
+        fork = (source.get("repository") or {}).get("full_name")
+        if fork and fork != remote.full_name:
+            head_remote = parse_remote_url(f"bitbucket:{fork}")
+        return PullRequest(
+            remote,
+            number,
+            data["destination"]["branch"]["name"],
+            f"refs/heads/{source['branch']['name']}",
+            head_remote,
+        )
+
+    headers = {"Accept": "application/vnd.github+json", "X-GitHub-Api-Version": "2022-11-28"}
+    token = resolve_token(GITHUB.name, getattr(config, "github_token", None))
+    if token:
+        headers["Authorization"] = f"Bearer {token}"
+    data = _api_get(f"{GITHUB_API_URL}/repos/{remote.full_name}/pulls/{number}", headers)
+    return PullRequest(remote, number, data["base"]["ref"], f"refs/pull/{number}/head")
+
+
+def _fetch(repo: Any, remote: RemoteRepository, refspec: str, config: Any) -> None:
+    try:
+        asyncio.get_running_loop()
+    except RuntimeError:
+        auth = asyncio.run(resolve_clone_auth(remote, config))
+    else:
+        # Called synchronously from async code; resolve on a thread with its own loop
+        with concurrent.futures.ThreadPoolExecutor(max_workers=1) as executor:
+            auth = executor.submit(asyncio.run, resolve_clone_auth(remote, config)).result()
+    logger.info(f"Fetching {refspec} from {remote.full_name} ({auth.method})")
+    repo.git.fetch(auth.url, refspec, env=auth.env)
+
+
+def resolve_pull_request_refs(url: str, repo_path: str, config: Any) -> tuple[str, str] | None:
+    """Fetch a pull request into ``repo_path`` and return its diff range.
+
+    Args:
+        url: Web URL of the pull or merge request.
+        repo_path: Local clone of the target repository.
+        config: The CodeConCatConfig with the credential settings.
+
+    Returns:
+        The merge base and head commit SHAs, or ``None`` if ``url`` is not
+        a pull request URL.
+
+    Raises:
+        GitAuthError: If the request cannot be looked up or fetched.
+        OfflineError: In offline mode.
+    """
+    parsed = parse_pull_request_url(url, getattr(config, "gitlab_url", None))
+    if parsed is None:
+        return None
+    require_network("Resolving a pull request URL")
+
+    from git import Repo
+    from git.exc import GitCommandError
+
+    remote, number = parsed
+    pr = get_pull_request(remote, number, config)
+    repo = Repo(repo_path, search_parent_directories=True)
+    try:
+        _fetch(repo, remote, f"+refs/heads/{pr.base_branch}:{pr.local_ref}/base", config)
+        _fetch(repo, pr.head_remote or remote, f"+{pr.head_refspec}:{pr.local_ref}/head", config)
+    except GitCommandError as e:
+        raise GitAuthError(f"Cannot fetch {remote.full_name}#{number}: {e.stderr.strip()}") from e
+
+    base = repo.commit(f"{pr.local_ref}/base")
+    head = repo.commit(f"{pr.local_ref}/head")
+    merge_bases = repo.merge_base(base, head)
+    start = merge_bases[0].hexsha if merge_bases else base.hexsha
+    logger.info(f"{remote.full_name}#{number} diffs {start[:7]}..{head.hexsha[:7]}")
+    return start, head.hexsha
@@ -0,0 +1,127 @@
+"""Git submodule discovery.
+
+Submodules are declared in ``.gitmodules``. A submodule is initialized when
+its directory holds a checkout: ``git submodule update --init`` writes a
+``.git`` file there. With ``include_submodules`` the local collector walks
+each initialized submodule as a root of its own: the submodule's
+``.gitignore`` applies and path patterns match relative to the submodule, so
+a library kept under ``libs/`` or ``vendor/`` is not dropped by the default
+excludes meant for the parent repository.
+"""
+
+from __future__ import annotations
+
+import fnmatch
+import logging
+import os
+import re
+from dataclasses import dataclass
+
+from codeconcat.base_types import CodeConCatConfig, SubmoduleFilter
+
+logger = logging.getLogger(__name__)
+
+_SECTION_RE = re.compile(r'^\s*\[\s*submodule\s+"(.+)"\s*\]\s*$')
+_KEY_RE = re.compile(r"^\s*(path|url)\s*=\s*(.*?)\s*$")
+
+
+@dataclass
+class Submodule:
+    """A git submodule.
+
+    Attributes:
+        name: Name from its ``[submodule "name"]`` section.
+        path: Path relative to the repository root, with ``/`` separators.
+        url: Remote URL, if declared.
+    """
+
+    name: str
+    path: str
+    url: str | None = None
+
+
+def read_gitmodules(root: str) -> list[Submodule]:
+    """Return the submodules declared in ``root/.gitmodules``, in file order."""
+    try:
+        with open(os.path.join(root, ".gitmodules"), encoding="utf-8") as f:
+            lines = f.read().splitlines()
+    except (OSError, UnicodeDecodeError):
+        return []
+
+    submodules: list[Submodule] = []
+    current: Submodule | None = None
+    for line in lines:
+        if line.lstrip().startswith(("#", ";")):
+            continue
+        section = _SECTION_RE.match(line)
+        if section:
+            current = Submodule(section.group(1), "")
+            submodules.append(current)
+            continue
+        key = _KEY_RE.match(line)
+        if key and current is not None:
+            if key.group(1) == "path":
+                current.path = key.group(2).strip('"').strip("/")
+            else:
+                current.url = key.group(2).strip('"')
+    return [s for s in submodules if s.path and ".." not in s.path.split("/")]
+
+
+def is_initialized(root: str, submodule: Submodule) -> bool:
+    """Return True if the submodule has been checked out under ``root``."""
+    return os.path.exists(os.path.join(root, submodule.path, ".git"))
+
+
+def find_submodules(root: str) -> list[Submodule]:
+    """Return the initialized submodules under ``root``, including nested ones.
+
+    Nested submodules have paths relative to ``root``. The result is sorted
+    by path, so a submodule comes before the submodules inside it.
+    """
+    found = []
+    for submodule in read_gitmodules(root):
+        if not is_initialized(root, submodule):
+            logger.debug(f"Skipping uninitialized submodule {submodule.path}")
+            continue
+        found.append(submodule)
+        for nested in find_submodules(os.path.join(root, submodule.path)):
+            nested.path = f"{submodule.path}/{nested.path}"
+            found.append(nested)
+    return sorted(found, key=lambda s: s.path)
+
+
+def matches_submodule(submodule: Submodule, patterns: list[str]) -> bool:
+    """Return True if a glob in ``patterns`` matches the submodule's path or name."""
+    return any(
+        fnmatch.fnmatch(submodule.path, pattern) or fnmatch.fnmatch(submodule.name, pattern)
+        for pattern in patterns
+    )
+
+
+def select_submodules(submodules: list[Submodule], exclude: list[str]) -> list[Submodule]:
+    """Drop the submodules matching ``exclude`` and the submodules nested in them."""
+    excluded = [s.path for s in submodules if matches_submodule(s, exclude)]
+    return [
+        s
+        for s in submodules
+        if not any(s.path == path or s.path.startswith(f"{path}/") for path in excluded)
+    ]
+
+
+def submodule_config(config: CodeConCatConfig, root: str, submodule: Submodule) -> CodeConCatConfig:
+    """Return the configuration for walking ``submodule`` of the repository at ``root``.
+
+    Paths match relative to the submodule. Its entry in ``submodule_filters``
+    (by path or name) replaces ``include_paths`` when it lists includes and
+    adds its excludes to ``exclude_paths``.
+    """
+    sub_config = config.model_copy(deep=True)
+    sub_config.target_path = os.path.join(os.path.abspath(root), submodule.path)
+    sub_filter: SubmoduleFilter | None = config.submodule_filters.get(
+        submodule.path
+    ) or config.submodule_filters.get(submodule.name)
+    if sub_filter is not None:
+        if sub_filter.include_paths:
+            sub_config.include_paths = list(sub_filter.include_paths)
+        sub_config.exclude_paths = [*config.exclude_paths, *sub_filter.exclude_paths]
+    return sub_config
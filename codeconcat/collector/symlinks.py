@@ -0,0 +1,151 @@
+"""Symbolic link handling.
+
+A link found while walking the tree is handled by a policy:
+
+- ``skip`` leaves it out, as the collector always did.
+- ``follow`` collects what it points to, under the link's own path. Links
+  that loop back to a parent are not walked, and a file reached through
+  several paths is collected once, so following links adds no duplicates.
+- ``stub`` leaves the target out but lists the link with what it points to,
+  so the reader knows it exists.
+
+``symlink_policy`` sets the policy for every link; ``symlink_rules`` maps glob
+patterns on the link's path relative to the root, or on its name, to a policy
+for the links they match. The first matching rule wins.
+"""
+
+from __future__ import annotations
+
+import fnmatch
+import logging
+import os
+from pathlib import Path
+
+from pathspec import PathSpec
+from pathspec.patterns.gitwildmatch import GitWildMatchPattern
+
+from codeconcat.base_types import CodeConCatConfig, ParsedDocData
+
+logger = logging.getLogger(__name__)
+
+
+def symlink_policy(rel_path: str, config: CodeConCatConfig) -> str:
+    """Return the policy for the link at ``rel_path``, relative to the root."""
+    rel_path = Path(rel_path).as_posix()
+    name = os.path.basename(rel_path)
+    for pattern, policy in config.symlink_rules.items():
+        if fnmatch.fnmatch(rel_path, pattern) or fnmatch.fnmatch(name, pattern):
+            return policy
+    return config.symlink_policy
+
+
+def follows_links(config: CodeConCatConfig) -> bool:
+    """Return True if some links may be followed."""
+    return config.symlink_policy == "follow" or "follow" in config.symlink_rules.values()
+
+
+def uses_stubs(config: CodeConCatConfig) -> bool:
+    """Return True if some links may be listed as stubs."""
+    return config.symlink_policy == "stub" or "stub" in config.symlink_rules.values()
+
+
+def describe_symlink(link_path: str, rel_path: str, root_path: str) -> str:
+    """Render a link and its target as a short plain-text block.
+
+    The resolved target is shown relative to ``root_path`` when it is inside
+    it, and as the link's own target otherwise, so the output does not reveal
+    where the tree lives on disk.
+    """
+    target = os.readlink(link_path)
+    real_root = os.path.realpath(root_path)
+    resolved = os.path.realpath(link_path)
+    if os.path.commonpath([real_root, resolved]) == real_root:
+        resolved = Path(os.path.relpath(resolved, real_root)).as_posix()
+    else:
+        resolved = target
+    if os.path.isdir(link_path):
+        kind = "directory"
+    elif os.path.exists(link_path):
+        kind = "file"
+    else:
+        kind = "missing target"
+    return "\n".join(
+        [
+            f"Symbolic link: {rel_path} -> {target} ({kind})",
+            f"Resolves to: {resolved}",
+            "(target not collected)",
+        ]
+    )
+
+
+def collect_symlink_stubs(root_path: str, config: CodeConCatConfig) -> list[ParsedDocData]:
+    """Find the links under ``root_path`` with the ``stub`` policy and list them.
+
+    Directories are pruned like :func:`collect_asset_stubs` prunes them, and
+    the links whose policy is ``follow`` are walked, each real directory
+    once. Links are filtered by ``.codeconcatignore``, by ``.gitignore`` when
+    enabled and by the configured ``exclude_paths``/``include_paths``.
+    """
+    from codeconcat.collector.ignore_rules import get_ignore_rules
+    from codeconcat.collector.local_collector import should_skip_dir
+
+    if not os.path.isdir(root_path):
+        return []
+
+    ignore_rules = get_ignore_rules(root_path, config)
+    exclude_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.exclude_paths)
+        if config.exclude_paths
+        else None
+    )
+    include_spec = (
+        PathSpec.from_lines(GitWildMatchPattern, config.include_paths)
+        if config.include_paths
+        else None
+    )
+
+    stubs: list[ParsedDocData] = []
+    visited: set[str] = set()
+    for dirpath, dirnames, filenames in os.walk(root_path, topdown=True, followlinks=True):
+        real_dir = os.path.realpath(dirpath)
+        if real_dir in visited:
+            dirnames[:] = []
+            continue
+        visited.add(real_dir)
+
+        kept = []
+        for name in sorted(dirnames + filenames):
+            path = os.path.join(dirpath, name)
+            is_dir = name in dirnames
+            pruned = is_dir and (name.startswith(".") or should_skip_dir(path, config))
+            if not os.path.islink(path):
+                if is_dir and not pruned:
+                    kept.append(name)
+                continue
+
+            rel_path = Path(os.path.relpath(path, root_path)).as_posix()
+            match_path = rel_path + ("/" if is_dir else "")
+            if ignore_rules.match_file(match_path):
+                continue
+            if exclude_spec and exclude_spec.match_file(match_path):
+                continue
+            policy = symlink_policy(rel_path, config)
+            if policy == "follow":
+                if is_dir and not pruned:
+                    kept.append(name)
+            elif policy == "stub":
+                if include_spec and not is_dir and not include_spec.match_file(rel_path):
+                    continue
+                stubs.append(
+                    ParsedDocData(
+                        file_path=os.path.abspath(path),
+                        content=describe_symlink(path, rel_path, root_path),
+                        doc_type="symlink_stub",
+                        summary=f"symbolic link to {os.readlink(path)}",
+                        tags=["symlink"],
+                    )
+                )
+        dirnames[:] = kept
+
+    logger.info(f"Listed {len(stubs)} symbolic links as stubs for {root_path}")
+    return stubs
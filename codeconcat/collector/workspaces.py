@@ -0,0 +1,328 @@
+"""Monorepo workspace detection.
+
+A monorepo declares its packages in a workspace manifest at its root. This
+module reads those manifests and lists the packages, so a run can produce one
+output per package instead of one blob for the whole repository.
+
+Supported manifests:
+- pnpm ``pnpm-workspace.yaml`` (``packages``, with ``!`` exclusions)
+- npm and Yarn ``package.json`` (``workspaces``)
+- Lerna ``lerna.json`` (``packages``, default ``packages/*``)
+- Nx ``workspace.json`` (``projects``) or ``project.json`` files under ``nx.json``
+- Cargo ``Cargo.toml`` (``[workspace] members`` and ``exclude``)
+- Go ``go.work`` (``use`` directives)
+
+Package names come from each package's own manifest (``package.json`` name,
+crate name, Go module path or Nx project name), falling back to the package
+directory. Dependencies between packages of the workspace are read from the
+same manifests.
+"""
+
+from __future__ import annotations
+
+import glob
+import json
+import logging
+import os
+import re
+from dataclasses import dataclass, field
+from typing import Any
+
+import yaml  # type: ignore[import-untyped]
+
+try:
+    import tomllib
+except ModuleNotFoundError:  # pragma: no cover - Python 3.10 fallback
+    import tomli as tomllib  # type: ignore[no-redef]
+
+logger = logging.getLogger(__name__)
+
+# Directories never searched for Nx project.json files
+_SKIP_DIR_NAMES = frozenset({".git", "node_modules", "dist", "build", ".nx", "target", "vendor"})
+
+_JS_DEPENDENCY_SECTIONS = ("dependencies", "devDependencies", "peerDependencies")
+_CARGO_DEPENDENCY_SECTIONS = ("dependencies", "dev-dependencies", "build-dependencies")
+
+_GO_USE_RE = re.compile(r"^\s*use\s*(?:\((.*?)\)|(\S+))", re.MULTILINE | re.DOTALL)
+_GO_MODULE_RE = re.compile(r"^\s*module\s+(\S+)", re.MULTILINE)
+_GO_REQUIRE_RE = re.compile(r"^\s*(?:require\s+)?([\w.\-/]+\.[\w.\-/]+)\s+v\S+", re.MULTILINE)
+
+
+@dataclass
+class WorkspacePackage:
+    """A package of a monorepo workspace.
+
+    Attributes:
+        name: Package name from its manifest.
+        path: Path relative to the workspace root, with ``/`` separators.
+        manager: The workspace tool that declares it ('pnpm', 'cargo', ...).
+        depends_on: Names of other packages of the workspace it depends on.
+    """
+
+    name: str
+    path: str
+    manager: str
+    depends_on: list[str] = field(default_factory=list)
+
+
+@dataclass
+class Workspace:
+    """A monorepo and its packages, sorted by path."""
+
+    root: str
+    managers: list[str]
+    packages: list[WorkspacePackage]
+
+    def nested_paths(self, package: WorkspacePackage) -> list[str]:
+        """Return the paths of the packages inside ``package``, relative to it.
+
+        A package at the workspace root, such as a Cargo root crate, would
+        otherwise include every other package.
+        """
+        prefix = "" if package.path == "." else f"{package.path}/"
+        return [
+            other.path[len(prefix) :]
+            for other in self.packages
+            if other is not package and other.path != "." and other.path.startswith(prefix)
+        ]
+
+
+def _read(path: str) -> str | None:
+    try:
+        with open(path, encoding="utf-8") as f:
+            return f.read()
+    except (OSError, UnicodeDecodeError):
+        return None
+
+
+def _read_json(path: str) -> dict[str, Any]:
+    text = _read(path)
+    if text is None:
+        return {}
+    try:
+        data = json.loads(text)
+    except json.JSONDecodeError as e:
+        logger.warning(f"Skipping invalid workspace manifest {path}: {e}")
+        return {}
+    return data if isinstance(data, dict) else {}
+
+
+def _read_toml(path: str) -> dict[str, Any]:
+    text = _read(path)
+    if text is None:
+        return {}
+    try:
+        return tomllib.loads(text)
+    except tomllib.TOMLDecodeError as e:
+        logger.warning(f"Skipping invalid workspace manifest {path}: {e}")
+        return {}
+
+
+def _expand(root: str, patterns: list[str], manifest: str | None) -> list[str]:
+    """Return the directories matching ``patterns``, relative to ``root``.
+
+    Patterns starting with ``!`` exclude directories. With ``manifest``, only
+    directories containing that file are packages.
+    """
+    included: set[str] = set()
+    excluded: set[str] = set()
+    for pattern in patterns:
+        if not isinstance(pattern, str) or not pattern.strip():
+            continue
+        target = excluded if pattern.startswith("!") else included
+        pattern = pattern.lstrip("!").strip().rstrip("/")
+        for match in glob.glob(os.path.join(root, pattern), recursive=True):
+            if os.path.isdir(match):
+                target.add(os.path.relpath(match, root).replace(os.sep, "/"))
+    return sorted(
+        path
+        for path in included - excluded
+        if not any(part in _SKIP_DIR_NAMES for part in path.split("/"))
+        and (manifest is None or os.path.isfile(os.path.join(root, path, manifest)))
+    )
+
+
+def _js_package(root: str, path: str, manager: str) -> WorkspacePackage:
+    data = _read_json(os.path.join(root, path, "package.json"))
+    name = data.get("name") if isinstance(data.get("name"), str) else path
+    package = WorkspacePackage(name, path, manager)
+    package.depends_on = [
+        dep
+        for section in _JS_DEPENDENCY_SECTIONS
+        if isinstance(data.get(section), dict)
+        for dep in data[section]
+    ]
+    return package
+
+
+def _pnpm_packages(root: str) -> list[WorkspacePackage]:
+    text = _read(os.path.join(root, "pnpm-workspace.yaml"))
+    if text is None:
+        return []
+    try:
+        data = yaml.safe_load(text) or {}
+    except yaml.YAMLError as e:
+        logger.warning(f"Skipping invalid pnpm-workspace.yaml: {e}")
+        return []
+    patterns = data.get("packages") if isinstance(data, dict) else None
+    return [
+        _js_package(root, path, "pnpm")
+        for path in _expand(root, list(patterns or []), "package.json")
+    ]
+
+
+def _npm_packages(root: str) -> list[WorkspacePackage]:
+    workspaces = _read_json(os.path.join(root, "package.json")).get("workspaces")
+    if isinstance(workspaces, dict):
+        # Yarn classic also accepts {"packages": [...], "nohoist": [...]}
+        workspaces = workspaces.get("packages")
+    if not isinstance(workspaces, list):
+        return []
+    manager = "yarn" if os.path.isfile(os.path.join(root, "yarn.lock")) else "npm"
+    return [
+        _js_package(root, path, manager)
+        for path in _expand(root, workspaces, "package.json")
+    ]
+
+
+def _lerna_packages(root: str) -> list[WorkspacePackage]:
+    path = os.path.join(root, "lerna.json")
+    if not os.path.isfile(path):
+        return []
+    patterns = _read_json(path).get("packages") or ["packages/*"]
+    return [
+        _js_package(root, package, "lerna")
+        for package in _expand(root, list(patterns), "package.json")
+    ]
+
+
+def _nx_project_paths(root: str) -> dict[str, str]:
+    """Return Nx project directories mapped to their names from workspace.json or project.json."""
+    projects = _read_json(os.path.join(root, "workspace.json")).get("projects")
+    if isinstance(projects, dict):
+        found = {}
+        for name, entry in projects.items():
+            project_root = entry if isinstance(entry, str) else (entry or {}).get("root")
+            if isinstance(project_root, str):
+                found[project_root.strip("/") or "."] = name
+        return found
+
+    found = {}
+    for dirpath, dirnames, filenames in os.walk(root):
+        dirnames[:] = [d for d in dirnames if d not in _SKIP_DIR_NAMES and not d.startswith(".")]
+        if "project.json" in filenames and dirpath != root:
+            rel = os.path.relpath(dirpath, root).replace(os.sep, "/")
+            name = _read_json(os.path.join(dirpath, "project.json")).get("name")
+            found[rel] = name if isinstance(name, str) else rel
+    return found
+
+
+def _nx_packages(root: str) -> list[WorkspacePackage]:
+    if not os.path.isfile(os.path.join(root, "nx.json")):
+        return []
+    packages = []
+    for path, name in sorted(_nx_project_paths(root).items()):
+        package = _js_package(root, path, "nx")
+        package.name = name
+        implicit = _read_json(os.path.join(root, path, "project.json")).get(
+            "implicitDependencies"
+        )
+        if isinstance(implicit, list):
+            package.depends_on.extend(d for d in implicit if isinstance(d, str))
+        packages.append(package)
+    return packages
+
+
+def _cargo_packages(root: str) -> list[WorkspacePackage]:
+    workspace = _read_toml(os.path.join(root, "Cargo.toml")).get("workspace")
+    if not isinstance(workspace, dict):
+        return []
+    members = workspace.get("members") or []
+    excluded = [f"!{path}" for path in workspace.get("exclude") or []]
+    packages = []
+    for path in _expand(root, list(members) + excluded, "Cargo.toml"):
+        manifest = _read_toml(os.path.join(root, path, "Cargo.toml"))
+        name = (manifest.get("package") or {}).get("name") or os.path.basename(path)
+        package = WorkspacePackage(name, path, "cargo")
+        package.depends_on = [
+            dep
+            for section in _CARGO_DEPENDENCY_SECTIONS
+            if isinstance(manifest.get(section), dict)
+            for dep in manifest[section]
+        ]
+        packages.append(package)
+    root_manifest = _read_toml(os.path.join(root, "Cargo.toml"))
+    if isinstance(root_manifest.get("package"), dict) and all(p.path != "." for p in packages):
+        # A root crate is a member of its own workspace
+        name = root_manifest["package"].get("name") or os.path.basename(root)
+        packages.append(WorkspacePackage(name, ".", "cargo"))
+    return packages
+
+
+def _go_packages(root: str) -> list[WorkspacePackage]:
+    text = _read(os.path.join(root, "go.work"))
+    if text is None:
+        return []
+    text = re.sub(r"//.*", "", text)
+    paths = []
+    for block, single in _GO_USE_RE.findall(text):
+        paths.extend(block.split() if block else [single])
+    packages = []
+    for use in paths:
+        path = os.path.normpath(use).replace(os.sep, "/")
+        go_mod = _read(os.path.join(root, path, "go.mod"))
+        if go_mod is None:
+            continue
+        module = _GO_MODULE_RE.search(go_mod)
+        package = WorkspacePackage(module.group(1) if module else path, path, "go")
+        package.depends_on = _GO_REQUIRE_RE.findall(go_mod)
+        packages.append(package)
+    return packages
+
+
+# Detectors in precedence order: the first to declare a path names its package
+_DETECTORS = (
+    _pnpm_packages,
+    _nx_packages,
+    _lerna_packages,
+    _npm_packages,
+    _cargo_packages,
+    _go_packages,
+)
+
+
+def detect_workspace(root: str) -> Workspace | None:
+    """Detect the workspace declared at ``root``.
+
+    Args:
+        root: The repository root.
+
+    Returns:
+        The workspace with its packages, or ``None`` if ``root`` declares no
+        workspace or the workspace has no packages.
+    """
+    root = os.path.abspath(root)
+    if not os.path.isdir(root):
+        return None
+
+    by_path: dict[str, WorkspacePackage] = {}
+    managers = []
+    for detector in _DETECTORS:
+        packages = detector(root)
+        for package in packages:
+            by_path.setdefault(package.path, package)
+        if packages:
+            manager = packages[0].manager
+            if manager not in managers:
+                managers.append(manager)
+    if not by_path:
+        return None
+
+    packages = sorted(by_path.values(), key=lambda p: p.path)
+    names = {package.name for package in packages}
+    for package in packages:
+        package.depends_on = sorted(
+            {dep for dep in package.depends_on if dep in names and dep != package.name}
+        )
+    logger.info(f"Detected {', '.join(managers)} workspace with {len(packages)} packages")
+    return Workspace(root, managers, packages)
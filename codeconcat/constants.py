@@ -39,6 +39,7 @@ DEFAULT_EXCLUDE_PATTERNS: list[str] = [
     ".svnignore",
     # CodeConcat configuration
     ".codeconcat.yml",
+    "**/.codeconcatignore",
     # Dependencies and build artifacts
     "node_modules/",
     "**/node_modules/",
@@ -383,6 +384,9 @@ MAX_FILE_SIZE = 10 * MEGABYTE  # 10 MB
 # Maximum total project size (in bytes)
 MAX_PROJECT_SIZE = 100 * MEGABYTE  # 100 MB
 
+# Maximum size of an input archive, downloaded or read (in bytes)
+MAX_ARCHIVE_SIZE = 500 * MEGABYTE  # 500 MB
+
 # Token limits for different models (updated January 2026)
 TOKEN_LIMITS = {
     # OpenAI GPT models (2026)
@@ -1,5 +1,6 @@
 # codeconcat/language_map.py
 import logging
+import re
 from typing import cast
 
 logger = logging.getLogger(__name__)
@@ -97,6 +98,8 @@ ext_map = {
     ".gitattributes": "gitattributes",
     ".csv": "csv",
     ".tsv": "tsv",
+    ".ndjson": "ndjson",
+    ".jsonl": "ndjson",
     ".http": "http",
     ".graphql": "graphql",
     ".gql": "graphql",
@@ -189,3 +192,168 @@ ext_map = {
     ".metal": "metal",
     ".msl": "metal",
 }
+
+# Well-known file names without a telling extension. Matched case-insensitively
+# against the whole name, after the exact names in ext_map.
+filename_patterns: list[tuple[re.Pattern[str], str]] = [
+    (re.compile(p, re.IGNORECASE), language)
+    for p, language in [
+        (r"(dockerfile|containerfile)([.-].+)?|.+\.(dockerfile|containerfile)", "dockerfile"),
+        (r"(gnu)?makefile([.-].+)?", "makefile"),
+        (r"jenkinsfile([.-].+)?", "groovy"),
+        (
+            r"(vagrantfile|gemfile|rakefile|podfile|fastfile|appfile|guardfile|brewfile|"
+            r"capfile|berksfile|dangerfile)",
+            "ruby",
+        ),
+        (r"(sconstruct|sconscript|snakefile)", "python"),
+        (r"\.(bashrc|bash_profile|bash_logout|profile|zshrc|zshenv|zprofile|kshrc)", "bash"),
+        (r"(pkgbuild|apkbuild)", "bash"),
+    ]
+]
+
+# Interpreter named on a shebang line -> language
+shebang_interpreters = {
+    "python": "python",
+    "pypy": "python",
+    "bash": "bash",
+    "sh": "bash",
+    "dash": "bash",
+    "zsh": "bash",
+    "ksh": "bash",
+    "fish": "bash",
+    "node": "javascript",
+    "nodejs": "javascript",
+    "deno": "typescript",
+    "bun": "javascript",
+    "ts-node": "typescript",
+    "tsx": "typescript",
+    "ruby": "ruby",
+    "perl": "perl",
+    "php": "php",
+    "rscript": "r",
+    "julia": "julia",
+    "lua": "lua",
+    "luajit": "lua",
+    "tclsh": "tcl",
+    "wish": "tcl",
+    "pwsh": "powershell",
+    "powershell": "powershell",
+    "groovy": "groovy",
+    "elixir": "elixir",
+    "escript": "erlang",
+    "runghc": "haskell",
+    "runhaskell": "haskell",
+    "swift": "swift",
+    "crystal": "crystal",
+    "scala": "scala",
+    "kotlin": "kotlin",
+    "dart": "dart",
+    "nim": "nim",
+    "osascript": "applescript",
+    "make": "makefile",
+}
+
+# Names used in vim and Emacs modelines that differ from our identifiers
+modeline_aliases = {
+    "sh": "bash",
+    "zsh": "bash",
+    "shell-script": "bash",
+    "py": "python",
+    "python3": "python",
+    "js": "javascript",
+    "ts": "typescript",
+    "rb": "ruby",
+    "make": "makefile",
+    "cperl": "perl",
+    "c++": "cpp",
+    "docker": "dockerfile",
+    "ps1": "powershell",
+    "rs": "rust",
+}
+
+_SHEBANG = re.compile(r"#!\s*(\S+)(.*)")
+_VIM_MODELINE = re.compile(r"\b(?:vim?|ex):.*?\b(?:ft|filetype|syntax)=([\w+#-]+)")
+_EMACS_MODELINE = re.compile(r"-\*-\s*(?:.*?\bmode:\s*)?([\w+#-]+)\s*(?:;.*?)?-\*-", re.IGNORECASE)
+# Instructions are matched in upper case, as they are conventionally written,
+# so prose starting with "From" or "Run" is not taken for a Dockerfile
+_DOCKERFILE_INSTRUCTION = re.compile(
+    r"(FROM|RUN|CMD|COPY|ADD|ENV|ARG|WORKDIR|ENTRYPOINT|EXPOSE|LABEL|USER|VOLUME|HEALTHCHECK|"
+    r"SHELL|STOPSIGNAL|ONBUILD|MAINTAINER)\s"
+)
+# FROM [--platform=...] image[:tag|@digest] [AS name]
+_DOCKERFILE_FROM = re.compile(r"FROM\s+(--platform=\S+\s+)?[\w.\-/:@${}]+(\s+AS\s+[\w.-]+)?\s*$")
+
+
+def get_language_by_filename(file_name: str) -> str | None:
+    """Return the language of a well-known file name such as ``Dockerfile.dev``."""
+    for pattern, language in filename_patterns:
+        if pattern.fullmatch(file_name):
+            return language
+    return None
+
+
+def _language_from_shebang(line: str) -> str | None:
+    match = _SHEBANG.match(line)
+    if not match:
+        return None
+    interpreter = match.group(1).rsplit("/", 1)[-1]
+    if interpreter == "env":
+        # /usr/bin/env [-S] [VAR=value ...] interpreter [args]
+        words = [w for w in match.group(2).split() if not w.startswith("-") and "=" not in w]
+        if not words:
+            return None
+        interpreter = words[0]
+    # python3.12, ruby2.7, perl5
+    interpreter = re.sub(r"[\d.]+$", "", interpreter.lower())
+    return shebang_interpreters.get(interpreter)
+
+
+def _language_from_modeline(lines: list[str]) -> str | None:
+    for line in lines:
+        match = _VIM_MODELINE.search(line) or _EMACS_MODELINE.search(line)
+        if match:
+            name = match.group(1).lower()
+            name = modeline_aliases.get(name, name)
+            if name in ext_map.values():
+                return name
+    return None
+
+
+def detect_language_from_content(content: str) -> str | None:
+    """Detect the language of a file without a telling name from its text.
+
+    Tries, in order, a shebang line (``#!/usr/bin/env python3``), a vim or
+    Emacs modeline in the first or last five lines (``# vim: ft=ruby``,
+    ``-*- mode: perl -*-``), and a few unambiguous openings: ``<?php``,
+    ``<?xml`` and a Dockerfile made of instructions.
+
+    Returns:
+        The language identifier, or None if the text does not tell.
+    """
+    lines = content[:5000].splitlines()
+    if not lines:
+        return None
+    language = _language_from_shebang(lines[0])
+    if language:
+        return language
+    language = _language_from_modeline(lines[:5] + content[-1000:].splitlines()[-5:])
+    if language:
+        return language
+
+    code = [line.strip() for line in lines[:50] if line.strip() and not line.startswith("#")]
+    if not code:
+        return None
+    if code[0].startswith("<?php"):
+        return "php"
+    if code[0].startswith("<?xml"):
+        return "xml"
+    if _DOCKERFILE_FROM.match(code[0]):
+        # Every line is an instruction or continues the one before it
+        continued = False
+        for line in code:
+            if not continued and not _DOCKERFILE_INSTRUCTION.match(line):
+                return None
+            continued = line.endswith("\\")
+        return "dockerfile"
+    return None
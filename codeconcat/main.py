@@ -16,7 +16,7 @@ import warnings
 from collections.abc import Callable
 from datetime import datetime
 from pathlib import Path
-from typing import TYPE_CHECKING, Literal, Protocol
+from typing import TYPE_CHECKING, Any, Literal, Protocol
 
 from codeconcat.base_types import (
     AnnotatedFileData,
@@ -27,6 +27,7 @@ from codeconcat.base_types import (
 )
 from codeconcat.collector.github_collector import collect_git_repo
 from codeconcat.collector.local_collector import collect_local_files
+from codeconcat.collector.symlinks import collect_symlink_stubs, uses_stubs
 from codeconcat.config.config_builder import ConfigBuilder
 from codeconcat.diagnostics import diagnose_parser, verify_tree_sitter_dependencies
 from codeconcat.errors import (
@@ -42,8 +43,10 @@ from codeconcat.processor.compression_processor import CompressionProcessor
 from codeconcat.quotes import get_random_quote
 from codeconcat.reconstruction import reconstruct_from_file
 from codeconcat.transformer.annotator import annotate
+from codeconcat.utils.offline import apply_offline_mode
 from codeconcat.validation.integration import (
     setup_semgrep,
+    validate_archive_members,
     validate_config_values,
     validate_input_files,
     verify_file_signatures,
@@ -829,10 +832,14 @@ def run_codeconcat(
     except ConfigurationError as e:
         logger.error(f"Configuration validation failed: {e}")
         raise ConfigurationError(f"Invalid configuration: {e}") from e
+    # Fails fast with OfflineError when offline mode is on and a feature needs the network
+    apply_offline_mode(config)
     logger.debug("Running CodeConCat with config: %s", config)
 
     # Track temp directory for GitHub repos - must be cleaned up after processing
     temp_dir_obj: tempfile.TemporaryDirectory | None = None
+    # Clones of the remote entries of config.sources
+    source_temp_dirs: list[tempfile.TemporaryDirectory] = []
 
     try:
         # Validate configuration
@@ -842,6 +849,18 @@ def run_codeconcat(
             )
         if config.format not in ["markdown", "json", "xml", "text"]:
             raise ConfigurationError(f"Invalid format: {config.format}")
+        if config.sources and (config.source_url or getattr(config, "diff_from", None)):
+            raise ConfigurationError(
+                "Multiple sources cannot be combined with source_url or diff mode"
+            )
+        if config.files_from and (config.sources or config.source_url):
+            raise ConfigurationError("files_from cannot be combined with sources or source_url")
+        if config.archive and (
+            config.sources or config.source_url or config.files_from or config.diff_from
+        ):
+            raise ConfigurationError(
+                "An archive cannot be combined with sources, source_url, files_from or diff mode"
+            )
 
         # Collect input files
         logger.info("Collecting input files...")
@@ -855,6 +874,22 @@ def run_codeconcat(
                 progress_callback.skip_stage("Collecting", "cancelled")
             return None
 
+        # A pull request URL, or --diff-from alone, still selects diff mode
+        if getattr(config, "diff_from", None):
+            from codeconcat.collector.pull_requests import resolve_pull_request_refs
+
+            pr_refs = resolve_pull_request_refs(
+                config.diff_from, config.target_path or ".", config
+            )
+            if pr_refs and config.diff_to:
+                raise ConfigurationError(
+                    "--diff-to cannot be combined with a pull request URL in --diff-from"
+                )
+            if pr_refs:
+                config.diff_from, config.diff_to = pr_refs
+            elif not config.diff_to:
+                config.diff_to = "HEAD"
+
         # Check if we're in diff mode
         diff_mode = (
             hasattr(config, "diff_from")
@@ -888,6 +923,9 @@ def run_codeconcat(
             try:
                 diff_collector = DiffCollector(repo_path, config.diff_from, config.diff_to, config)
                 diff_items = diff_collector.collect_diffs()
+                context_reasons = diff_collector.collect_context(
+                    diff_items, getattr(config, "diff_context", "none")
+                )
 
                 # Convert AnnotatedFileData to ParsedFileData for compatibility
                 # Note: In diff mode, the files are already annotated with diff information
@@ -905,11 +943,30 @@ def run_codeconcat(
                         diff_metadata=item.diff_metadata,
                     )
                     files_to_process.append(parsed_file)
+                files_to_process.extend(diff_collector.context_files)
+                object.__setattr__(config, "_diff_context", context_reasons)
 
-                logger.info(f"Collected {len(files_to_process)} file diffs")
+                logger.info(
+                    f"Collected {len(diff_items)} file diffs and "
+                    f"{len(context_reasons)} related files"
+                )
             except ValueError as e:
                 raise ConfigurationError(f"Diff collection error: {e}") from e
 
+        elif config.sources:
+            from codeconcat.collector.multi_source import collect_sources
+
+            # Files come back validated, with paths prefixed by their source
+            files_to_process, source_temp_dirs, sources = collect_sources(config)
+            logger.info(
+                f"Collected {len(files_to_process)} files from {len(sources)} sources: "
+                + ", ".join(f"{s.prefix} ({s.files})" for s in sources)
+            )
+        elif config.archive:
+            from codeconcat.collector.archive_collector import collect_archive
+
+            # Members are checked for unsafe paths and sizes while they are read
+            files_to_process = collect_archive(config.archive, config)
         elif config.source_url:
             logger.info(f"Collecting files from source URL: {config.source_url}")
             # Use the secure async implementation with synchronous wrapper
@@ -918,6 +975,18 @@ def run_codeconcat(
             # PERF: Set target_path for validation to avoid repeated path resolution failures
             if temp_dir_obj is not None:
                 config.target_path = temp_dir_obj.name
+        elif config.files_from:
+            from codeconcat.collector.local_collector import collect_file_list, read_file_list
+
+            try:
+                listed = read_file_list(config.files_from)
+            except (OSError, UnicodeDecodeError) as e:
+                raise ConfigurationError(
+                    f"Could not read file list {config.files_from}: {e}"
+                ) from e
+            config.target_path = os.path.abspath(config.target_path or ".")
+            logger.info(f"Collecting {len(listed)} listed paths under {config.target_path}")
+            files_to_process = collect_file_list(listed, config.target_path, config)
         elif config.target_path:
             logger.info(f"Collecting files from local path: {config.target_path}")
             files_to_process = collect_local_files(config.target_path, config)
@@ -946,28 +1015,35 @@ def run_codeconcat(
                 # Disable Semgrep if not available
                 config.enable_semgrep = False
 
-        # Validate input files
-        logger.info("Validating input files...")
-        try:
-            validated_files = validate_input_files(files_to_process, config)
-            logger.debug(f"Validated {len(validated_files)} of {len(files_to_process)} files")
-            files_to_process = validated_files
-        except ValidationError as e:
-            logger.error(f"File validation error: {e}")
-            # In strict security mode, fail immediately on validation errors
-            if hasattr(config, "strict_security") and config.strict_security:
-                raise
-            # Otherwise, continue with validated files, log warning
-            logger.warning("Continuing with validated files only")
-
-        # Verify file types match expected formats
-        try:
-            file_types = verify_file_signatures(files_to_process)
-            logger.debug(f"Verified file signatures for {len(file_types)} files")
-        except ValidationError as e:
-            logger.error(f"File signature validation error: {e}")
-            # This is a more serious error, but we'll continue with a warning
-            logger.warning("Some files may have incorrect content types")
+        # Archive members never exist on disk, so only their content is checked
+        if config.archive:
+            logger.info("Checking archive files for suspicious content...")
+            files_to_process = validate_archive_members(files_to_process, config)
+
+        # Validate input files (multiple sources are validated per source while
+        # collecting)
+        if not config.sources and not config.archive:
+            logger.info("Validating input files...")
+            try:
+                validated_files = validate_input_files(files_to_process, config)
+                logger.debug(f"Validated {len(validated_files)} of {len(files_to_process)} files")
+                files_to_process = validated_files
+            except ValidationError as e:
+                logger.error(f"File validation error: {e}")
+                # In strict security mode, fail immediately on validation errors
+                if hasattr(config, "strict_security") and config.strict_security:
+                    raise
+                # Otherwise, continue with validated files, log warning
+                logger.warning("Continuing with validated files only")
+
+            # Verify file types match expected formats
+            try:
+                file_types = verify_file_signatures(files_to_process)
+                logger.debug(f"Verified file signatures for {len(file_types)} files")
+            except ValidationError as e:
+                logger.error(f"File signature validation error: {e}")
+                # This is a more serious error, but we'll continue with a warning
+                logger.warning("Some files may have incorrect content types")
 
         # Parse code files (skip if in diff mode as files are already parsed)
         logger.debug("Starting file parsing.")
@@ -1030,6 +1106,10 @@ def run_codeconcat(
 
         # Apply AI summarization if enabled
         logger.debug(f"[CodeConCat] AI summary enabled: {config.enable_ai_summary}")
+        ai_cost: dict[str, Any] = {}
+        ai_failures: dict[str, Any] = {}
+        ai_triage: dict[str, Any] = {}
+        ai_retries = 0
         if config.enable_ai_summary:
             try:
                 logger.info("[CodeConCat] Generating AI summaries...")
@@ -1039,7 +1119,11 @@ def run_codeconcat(
                     create_summarization_processor,
                 )
 
-                summarizer = create_summarization_processor(config)
+                if progress_callback:
+                    progress_callback.start_stage("Summarizing", total=len(parsed_files))
+                summarizer = create_summarization_processor(
+                    config, progress_callback.update_progress if progress_callback else None
+                )
                 if summarizer:
                     logger.info(
                         f"[CodeConCat] Summarizer created, processing {len(parsed_files)} files..."
@@ -1065,15 +1149,57 @@ def run_codeconcat(
                     logger.info(
                         f"[CodeConCat] Processing complete. {summaries_added} of {len(parsed_files)} files have AI summaries."
                     )
+                    summarizer_stats = summarizer.get_statistics()
+                    cache_stats = summarizer_stats.get("cache")
+                    if cache_stats:
+                        logger.info(
+                            f"[CodeConCat] AI summary cache: {cache_stats['hits']} hits, "
+                            f"{cache_stats['misses']} misses ({cache_stats['cache_dir']})"
+                        )
+                    ai_cost = summarizer_stats.get("cost") or {}
+                    if ai_cost.get("estimated_usd") is not None:
+                        logger.info(
+                            f"[CodeConCat] AI cost: estimated ${ai_cost['estimated_usd']:.4f}, "
+                            f"actual ${ai_cost['actual_usd']:.4f} "
+                            f"({ai_cost['requests']} billed requests)"
+                        )
+                    ai_failures = summarizer_stats.get("failures") or {}
+                    ai_triage = summarizer_stats.get("security_triage") or {}
+                    ai_retries = summarizer_stats.get("retries", 0)
+                    if ai_retries:
+                        logger.info(f"[CodeConCat] AI requests needed {ai_retries} retries")
+                    if progress_callback:
+                        progress_callback.complete_stage(
+                            f"{summaries_added} of {len(parsed_files)} files summarized, "
+                            f"{ai_cost.get('input_tokens', 0):,} tokens sent"
+                            + (f", {ai_retries} retries" if ai_retries else "")
+                        )
                 else:
                     logger.warning("[CodeConCat] Summarizer was not created - check configuration")
+                    if progress_callback:
+                        progress_callback.skip_stage("Summarizing", "not configured")
             except Exception as e:
+                if progress_callback:
+                    progress_callback.fail_stage(str(e))
                 logger.error(f"Error during AI summarization: {str(e)}")
                 import traceback
 
                 logger.debug(traceback.format_exc())
                 # Continue without summaries - this is not a fatal error
 
+        # Fall back to a heuristic project overview when no AI overview was produced
+        if config.ai_meta_overview and config.ai_meta_overview_fallback and parsed_files:
+            first = parsed_files[0]
+            if not (first.ai_metadata or {}).get("meta_overview"):
+                from codeconcat.processor.project_overview import build_heuristic_overview
+
+                logger.info("[CodeConCat] No AI meta-overview available, using heuristic overview")
+                first.ai_metadata = {
+                    **(first.ai_metadata or {}),
+                    "meta_overview": build_heuristic_overview(parsed_files, config.target_path),
+                    "meta_overview_source": "heuristic",
+                }
+
         # Extract docs if requested
         docs = []
         if config.extract_docs:
@@ -1083,6 +1209,133 @@ def run_codeconcat(
             except Exception as e:
                 logger.warning(f"Warning: Failed to extract documentation: {str(e)}")
 
+        # Summarize lockfiles and vendored directories if requested
+        if (
+            config.summarize_dependencies
+            and config.target_path
+            and not diff_mode
+            and not config.sources
+            and not config.archive
+        ):
+            try:
+                from codeconcat.collector.dependency_summary import (
+                    collect_dependency_summaries,
+                )
+
+                docs.extend(collect_dependency_summaries(config.target_path, config))
+            except OSError as e:
+                logger.warning(f"Warning: Failed to summarize dependencies: {str(e)}")
+
+        # Emit stub entries for binary assets if requested
+        if (
+            config.include_asset_stubs
+            and config.target_path
+            and not diff_mode
+            and not config.sources
+            and not config.archive
+        ):
+            try:
+                from codeconcat.collector.asset_stubs import collect_asset_stubs
+
+                docs.extend(collect_asset_stubs(config.target_path, config))
+            except OSError as e:
+                logger.warning(f"Warning: Failed to collect asset stubs: {str(e)}")
+
+        # List symbolic links with the stub policy
+        if (
+            uses_stubs(config)
+            and config.target_path
+            and not diff_mode
+            and not config.sources
+            and not config.archive
+        ):
+            try:
+                docs.extend(collect_symlink_stubs(config.target_path, config))
+            except OSError as e:
+                logger.warning(f"Warning: Failed to list symbolic links: {str(e)}")
+
+        # Collect recent commits for the git history section if requested
+        if (
+            (config.include_git_history or config.git_history_since)
+            and config.target_path
+            and not config.sources
+            and not config.archive
+        ):
+            from codeconcat.collector.git_history import collect_git_history
+
+            history_root = config.target_path
+            if os.path.isfile(history_root):
+                history_root = os.path.dirname(history_root) or "."
+            git_history = collect_git_history(
+                history_root, config.git_history_depth, config.git_history_since
+            )
+            # Writers read the history from the config, like other run artifacts
+            object.__setattr__(config, "_git_history", git_history)
+
+        # Shorten oversized string literals (SQL dumps, base64 blobs) in source files
+        literals_truncated = 0
+        literal_chars_truncated = 0
+        if config.max_string_literal_length > 0:
+            from codeconcat.processor.content_processor import truncate_string_literals
+
+            for item in parsed_files:
+                if not item.content:
+                    continue
+                item.content, item_literals, item_chars = truncate_string_literals(
+                    item.content, config.max_string_literal_length, item.language
+                )
+                literals_truncated += item_literals
+                literal_chars_truncated += item_chars
+            if literals_truncated:
+                logger.info(
+                    f"[CodeConCat] Truncated {literals_truncated} string literals "
+                    f"({literal_chars_truncated:,} chars removed)"
+                )
+
+        # Truncate overlong lines (minified code, embedded blobs) if requested
+        lines_truncated = 0
+        chars_truncated = 0
+        if config.max_line_length > 0:
+            from codeconcat.processor.content_processor import truncate_long_lines
+
+            for item in [*parsed_files, *docs]:
+                if not item.content:
+                    continue
+                item.content, item_lines, item_chars = truncate_long_lines(
+                    item.content, config.max_line_length
+                )
+                lines_truncated += item_lines
+                chars_truncated += item_chars
+            if lines_truncated:
+                logger.info(
+                    f"[CodeConCat] Truncated {lines_truncated} long lines ({chars_truncated:,} chars removed)"
+                )
+
+        # Prune comment/docstring prose as a final squeeze if requested
+        if config.prompt_compression:
+            from codeconcat.processor.prompt_compression import PromptCompressor
+            from codeconcat.processor.token_counter import count_tokens
+
+            should_prune = True
+            if config.token_budget > 0:
+                estimated = sum(count_tokens(pf.content or "", "gpt-4") for pf in parsed_files)
+                estimated += sum(count_tokens(d.content or "", "gpt-4") for d in docs)
+                should_prune = estimated >= 0.9 * config.token_budget
+                logger.info(
+                    f"[CodeConCat] Estimated {estimated:,} tokens against budget {config.token_budget:,}"
+                )
+            if should_prune:
+                try:
+                    pruning = PromptCompressor(
+                        config.prompt_compression_ratio, config.prompt_compression_backend
+                    ).compress(parsed_files)
+                    logger.info(
+                        f"[CodeConCat] Prompt compression pruned comments in {pruning.files_changed} files "
+                        f"({pruning.words_before:,} -> {pruning.words_after:,} words)"
+                    )
+                except ValueError as e:
+                    logger.warning(f"Warning: Prompt compression skipped: {str(e)}")
+
         logger.info("[CodeConCat] Starting annotation of parsed files...")
 
         # Start annotation stage
@@ -1100,6 +1353,7 @@ def run_codeconcat(
         try:
             if diff_mode:
                 logger.info("Diff mode: files already annotated, skipping annotation step")
+                context_reasons = getattr(config, "_diff_context", {})
                 # Convert ParsedFileData with diff info to AnnotatedFileData
                 for idx, parsed in enumerate(parsed_files):
                     # Check for cancellation periodically
@@ -1112,7 +1366,11 @@ def run_codeconcat(
                         language=parsed.language or "unknown",
                         content=parsed.content or "",
                         annotated_content=parsed.content or "",
-                        summary=f"Diff for {parsed.file_path}",
+                        summary=(
+                            f"Context for the changes: {context_reasons[parsed.file_path]}"
+                            if parsed.file_path in context_reasons
+                            else f"Diff for {parsed.file_path}"
+                        ),
                         ai_summary=getattr(parsed, "ai_summary", None),  # Preserve AI summary
                         ai_metadata=getattr(parsed, "ai_metadata", None),  # Preserve AI metadata
                         declarations=parsed.declarations,
@@ -1209,11 +1467,87 @@ def run_codeconcat(
         items.extend(annotated_files)
         items.extend(docs)
 
+        if config.sort_files and config.task:
+            logger.info("--task orders files by relevance; ignoring sort_files")
+            config.sort_files = False
+
         if config.sort_files:
             logger.info("Sorting all items alphabetically by path...")
             items.sort(key=lambda x: getattr(x, "file_path", ""))
             logger.debug("Items sorted.")
 
+        # Rank files by relevance to the task and order the output by it
+        task_ranking: list[Any] = []
+        if config.task:
+            if progress_callback:
+                progress_callback.update_progress(0, 0, "ranking files by task relevance...")
+            try:
+                from codeconcat.processor.embeddings import EmbeddingError
+                from codeconcat.processor.relevance import (
+                    describe_ranking,
+                    order_by_relevance,
+                    rank_by_task,
+                )
+
+                task_ranking = rank_by_task(config.task, items, config)
+                order_by_relevance(items, task_ranking)
+                logger.info(
+                    "[CodeConCat] Most relevant files for the task:\n"
+                    + "\n".join(describe_ranking(task_ranking))
+                )
+            except (EmbeddingError, OSError) as e:
+                logger.warning(f"Warning: Task ranking failed, keeping file order: {str(e)}")
+
+        # Compression dry-run: estimate savings per strategy instead of writing output
+        if config.compression_report:
+            from codeconcat.processor.compression_report import build_compression_report
+
+            if progress_callback:
+                progress_callback.update_progress(0, 0, "estimating compression savings...")
+            report = build_compression_report(
+                [item for item in items if isinstance(item, AnnotatedFileData)], config
+            )
+            object.__setattr__(config, "_compression_report", report)
+            if progress_callback:
+                progress_callback.complete_stage("compression report")
+            logger.info("Compression report:\n" + report.to_text())
+            return None
+
+        # Write declaration-aware chunks for vector databases if requested
+        if config.chunk_output:
+            if progress_callback:
+                progress_callback.update_progress(0, 0, "chunking files...")
+            try:
+                from codeconcat.processor.chunker import chunk_items, write_chunks_jsonl
+
+                chunk_root = (
+                    config.target_path if os.path.isdir(config.target_path or "") else None
+                )
+                chunks = chunk_items(
+                    items, config.chunk_max_tokens, config.chunk_overlap_tokens, chunk_root
+                )
+                chunk_count = write_chunks_jsonl(chunks, config.chunk_output)
+                logger.info(f"[CodeConCat] Wrote {chunk_count} chunks to {config.chunk_output}")
+            except (OSError, ValueError) as e:
+                logger.warning(f"Warning: Failed to write chunks: {str(e)}")
+
+        # Embed chunks or declarations and export them to a vector store if requested
+        embed_export = None
+        if config.embed:
+            if progress_callback:
+                progress_callback.update_progress(0, 0, "generating embeddings...")
+            try:
+                from codeconcat.processor.embeddings import EmbeddingError, run_embedding_stage
+
+                embed_export = run_embedding_stage(items, config)
+                logger.info(
+                    f"[CodeConCat] Embedded {embed_export.count} {config.embed_units} "
+                    f"({embed_export.dimensions} dims) into {embed_export.store} "
+                    f"at {embed_export.location}"
+                )
+            except (EmbeddingError, OSError) as e:
+                logger.warning(f"Warning: Failed to generate embeddings: {str(e)}")
+
         # Apply compression if enabled
         if config.enable_compression:
             if progress_callback:
@@ -1351,6 +1685,18 @@ def run_codeconcat(
 
             logger.info("[CodeConCat] Compression complete.")
 
+        # Spend the token budget on the most relevant files first
+        budget_result = None
+        if task_ranking and config.token_budget > 0:
+            from codeconcat.processor.relevance import fit_to_budget
+
+            items, budget_result = fit_to_budget(items, config.token_budget, config)
+            logger.info(
+                f"[CodeConCat] Token budget {config.token_budget:,}: kept "
+                f"{len(budget_result.kept)} files, compressed {len(budget_result.compressed)}, "
+                f"left out {len(budget_result.dropped)} ({budget_result.tokens:,} tokens)"
+            )
+
         # --- Compute run statistics BEFORE any writing ---
         if progress_callback:
             progress_callback.update_progress(0, 0, "computing statistics...")
@@ -1368,6 +1714,24 @@ def run_codeconcat(
                 "languages_count": len(languages_set),
                 "total_lines": total_lines,
                 "total_bytes": total_bytes,
+                "lines_truncated": lines_truncated,
+                "chars_truncated": chars_truncated,
+                "literals_truncated": literals_truncated,
+                "literal_chars_truncated": literal_chars_truncated,
+                "embeddings_written": embed_export.count if embed_export else 0,
+                "embeddings_location": embed_export.location if embed_export else "",
+                "ai_cost_estimated": ai_cost.get("estimated_usd"),
+                "ai_cost_actual": ai_cost.get("actual_usd"),
+                "ai_cost_cap": ai_cost.get("cap_usd"),
+                "ai_cost_cap_exceeded": ai_cost.get("cap_exceeded", False),
+                "ai_files_failed": ai_failures.get("files", 0),
+                "ai_functions_failed": ai_failures.get("functions", 0),
+                "ai_directories_failed": ai_failures.get("directories", 0),
+                "ai_triage_failed": ai_failures.get("security_findings", 0),
+                "ai_findings_hidden": ai_triage.get("hidden_findings", 0),
+                "ai_retries": ai_retries,
+                "task_files_compressed": len(budget_result.compressed) if budget_result else 0,
+                "task_files_dropped": len(budget_result.dropped) if budget_result else 0,
             }
 
             # Store stats in a way that doesn't violate Pydantic model validation
@@ -1386,13 +1750,21 @@ def run_codeconcat(
                 progress_callback.update_progress(0, 0, "generating directory tree...")
             # Generate the actual directory tree
             try:
-                # If target_path is a file, use its parent directory for tree generation
-                tree_root = config.target_path
-                if os.path.isfile(config.target_path):
-                    tree_root = os.path.dirname(config.target_path)
-                    logger.debug(f"Target is a file, using parent directory for tree: {tree_root}")
+                if config.sources or config.archive:
+                    from codeconcat.collector.multi_source import render_source_tree
+
+                    # There is no single root on disk to walk; show what was collected
+                    folder_tree_str = render_source_tree([f.file_path for f in parsed_files])
+                else:
+                    # If target_path is a file, use its parent directory for tree generation
+                    tree_root = config.target_path
+                    if os.path.isfile(config.target_path):
+                        tree_root = os.path.dirname(config.target_path)
+                        logger.debug(
+                            f"Target is a file, using parent directory for tree: {tree_root}"
+                        )
 
-                folder_tree_str = generate_folder_tree(tree_root, config)
+                    folder_tree_str = generate_folder_tree(tree_root, config)
                 if folder_tree_str:
                     logger.info(f"Generated directory tree: {len(folder_tree_str)} characters")
                 else:
@@ -1544,9 +1916,11 @@ def run_codeconcat(
         raise
     finally:
         # Clean up temp directory for GitHub repos after all processing is complete
-        if temp_dir_obj is not None:
+        for clone_dir in [temp_dir_obj, *source_temp_dirs]:
+            if clone_dir is None:
+                continue
             try:
-                temp_dir_obj.cleanup()
+                clone_dir.cleanup()
                 logger.debug("Cleaned up temporary clone directory")
             except Exception as cleanup_error:
                 logger.warning(f"Failed to clean up temp directory: {cleanup_error}")
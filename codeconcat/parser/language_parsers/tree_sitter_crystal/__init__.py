@@ -13,6 +13,8 @@ import subprocess
 import tempfile
 from pathlib import Path
 
+from ....utils.offline import require_network
+
 logger = logging.getLogger(__name__)
 
 # Pinned commit hash for stable grammar (crystal-lang-tools/tree-sitter-crystal)
@@ -84,6 +86,7 @@ def _build_and_install_binding():
 
     Raises:
         RuntimeError: If building the grammar fails.
+        OfflineError: If the grammar must be downloaded in offline mode.
     """
     binding_dir = Path(__file__).parent
     binding_so = binding_dir / "_binding.so"
@@ -114,6 +117,7 @@ def _build_and_install_binding():
 
         # Clone or update repository
         if not repo_path.exists():
+            require_network("Downloading the tree-sitter-crystal grammar")
             logger.info("Downloading tree-sitter-crystal grammar...")
             try:
                 # Clone the repository
@@ -0,0 +1,57 @@
+"""In-memory cache of parse results for repeated runs in one process.
+
+``codeconcat watch`` regenerates the output every time a file changes. Most
+files are unchanged between runs, so their parse results, including
+declarations, imports and security findings, are reused instead of parsed
+again. Entries are keyed by path, language and a hash of the content, so an
+edited file always misses.
+"""
+
+from __future__ import annotations
+
+import copy
+import hashlib
+import logging
+
+from ..base_types import ParsedFileData
+
+logger = logging.getLogger(__name__)
+
+
+def _digest(file_data: ParsedFileData) -> str:
+    content = file_data.content or ""
+    return hashlib.sha256(f"{file_data.language}\0{content}".encode()).hexdigest()
+
+
+class ParseCache:
+    """Parse results of the previous run, one entry per file path.
+
+    Later pipeline stages modify parsed files in place, so results are copied
+    on the way in and on the way out.
+    """
+
+    def __init__(self) -> None:
+        self._entries: dict[str, tuple[str, ParsedFileData]] = {}
+        self.hits = 0
+        self.misses = 0
+
+    def __len__(self) -> int:
+        return len(self._entries)
+
+    def get(self, file_data: ParsedFileData) -> ParsedFileData | None:
+        """Return the cached result for ``file_data`` if its content is unchanged."""
+        entry = self._entries.get(file_data.file_path)
+        if entry is None or entry[0] != _digest(file_data):
+            self.misses += 1
+            return None
+        self.hits += 1
+        return copy.deepcopy(entry[1])
+
+    def put(self, file_data: ParsedFileData, parsed: ParsedFileData) -> None:
+        """Store the result of parsing ``file_data``."""
+        self._entries[file_data.file_path] = (_digest(file_data), copy.deepcopy(parsed))
+
+    def retain(self, paths: set[str]) -> None:
+        """Drop the entries of files that no longer exist or are no longer collected."""
+        for path in set(self._entries) - paths:
+            del self._entries[path]
@@ -49,6 +49,7 @@ from ..errors import (
     UnsupportedLanguageError,
 )
 from ..parser.shared import MergeStrategy, ResultMerger
+from ..processor.data_sampler import sample_data_content, should_sample
 from ..processor.security_processor import SecurityProcessor
 from ..processor.token_counter import get_token_stats
 from ..utils.feature_flags import is_enabled
@@ -631,6 +632,53 @@ class UnifiedPipeline:
         """
         logger.info(f"Starting unified parsing pipeline for {len(files_to_parse)} files")
 
+        # Watch mode keeps the results of unchanged files between runs
+        cache = getattr(self.config, "_parse_cache", None)
+        if cache is not None:
+            return self._parse_cached(files_to_parse, cache)
+        return self._parse_batch(files_to_parse)
+
+    def _parse_cached(
+        self, files_to_parse: list[ParsedFileData], cache: Any
+    ) -> tuple[list[ParsedFileData], list[ParserError]]:
+        """Parse only the files missing from ``cache`` and keep the input order.
+
+        Args:
+            files_to_parse: List of ParsedFileData objects to process
+            cache: The ParseCache of the previous runs
+
+        Returns:
+            Tuple of (parsed_files, errors)
+        """
+        cached = {}
+        misses = []
+        for file_data in files_to_parse:
+            hit = cache.get(file_data)
+            if hit is not None:
+                cached[file_data.file_path] = hit
+            else:
+                misses.append(file_data)
+
+        parsed, errors = self._parse_batch(misses) if misses else ([], [])
+        sources = {file_data.file_path: file_data for file_data in misses}
+        for result in parsed:
+            if result.file_path in sources:
+                cache.put(sources[result.file_path], result)
+                cached[result.file_path] = result
+        cache.retain({file_data.file_path for file_data in files_to_parse})
+        logger.info(
+            f"Parse cache: reused {len(files_to_parse) - len(misses)} files, parsed {len(misses)}"
+        )
+
+        ordered = [cached[f.file_path] for f in files_to_parse if f.file_path in cached]
+        # Results whose path the parser changed are passed through as they are
+        ordered.extend(result for result in parsed if result.file_path not in sources)
+        return ordered, errors
+
+    def _parse_batch(
+        self, files_to_parse: list[ParsedFileData]
+    ) -> tuple[list[ParsedFileData], list[ParserError]]:
+        """Parse files sequentially or in parallel depending on the batch size."""
         # Use sequential processing for small batches (< 50 files)
         # PERFORMANCE: Increased from 4 to 50 because multiprocessing startup overhead
         # (500-1000ms per worker) plus config serialization makes parallel processing
@@ -882,6 +930,14 @@ class UnifiedPipeline:
         if not self._should_process_language(language, file_path):
             return None
 
+        # Replace data files (CSV/TSV/JSON/NDJSON) with a schema summary and sample
+        if self.config.sample_data_files and should_sample(
+            file_path, content, self.config.data_sample_min_json_bytes
+        ):
+            sampled = self._handle_data_file(file_data)
+            if sampled is not None:
+                return sampled
+
         # Handle special file types (documentation, config)
         if language in ["documentation", "config"]:
             return self._handle_special_file(file_data, language)
@@ -968,6 +1024,34 @@ class UnifiedPipeline:
         file_data.parse_result = parse_result
         return file_data
 
+    def _handle_data_file(self, file_data: ParsedFileData) -> ParsedFileData | None:
+        """Replace a data file's content with its schema summary and a row sample.
+
+        Args:
+            file_data: ParsedFileData object for a CSV/TSV/JSON/NDJSON file
+
+        Returns:
+            Processed ParsedFileData, or None if the file could not be sampled
+        """
+        sampled = sample_data_content(
+            file_data.content or "", file_data.file_path, self.config.data_sample_rows
+        )
+        if sampled is None:
+            return None
+
+        logger.debug(f"Sampled data file: {file_data.file_path}")
+        file_data.content = sampled
+
+        parse_result = ParseResult()
+        parse_result.file_path = file_data.file_path
+        parse_result.language = file_data.language or ""
+        parse_result.module_name = f"Data: {os.path.basename(file_data.file_path)}"
+        parse_result.module_docstring = sampled.split("\n", 1)[0]
+        file_data.parse_result = parse_result
+
+        self._apply_post_processing(file_data)
+        return file_data
+
     def _parse_with_fallbacks(
         self, content: str, file_path: str, language: str
     ) -> ParseResult | None:
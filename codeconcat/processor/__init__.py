@@ -5,10 +5,14 @@ from codeconcat.processor.content_processor import (
     generate_directory_structure,
     generate_file_summary,
     process_file_content,
+    truncate_long_lines,
+    truncate_string_literals,
 )
 
 __all__ = [
     "process_file_content",
+    "truncate_long_lines",
+    "truncate_string_literals",
     "generate_file_summary",
     "generate_directory_structure",
     "CompressionProcessor",
@@ -0,0 +1,268 @@
+"""Architecture diagrams from the import graph.
+
+Files are grouped into components, the directories ``ai_diagram_depth``
+levels below the project root, and internal imports are resolved to files to
+give weighted edges between components. The graph and a short summary of each
+component are sent to the AI provider, which returns a Mermaid flowchart that
+names, groups and connects the components. When the provider fails or does
+not answer with Mermaid, the graph itself is rendered as a plain flowchart.
+"""
+
+from __future__ import annotations
+
+import posixpath
+import re
+from dataclasses import dataclass, field
+
+DIAGRAM_SYSTEM_PROMPT = (
+    "You are a software architect who draws clear, accurate component diagrams. You reply "
+    "with valid Mermaid flowchart syntax only and never invent components or dependencies "
+    "that the input does not show."
+)
+
+# Completion budget for the diagram request
+DIAGRAM_MAX_TOKENS = 2000
+
+# Summary characters per component in the prompt
+MAX_COMPONENT_SUMMARY_CHARS = 300
+
+# Larger graphs keep the most connected components
+_MAX_COMPONENTS = 30
+_MAX_LISTED_FILES = 6
+
+# "index" (JS/TS) and "mod" (Rust) name their directory like "__init__" does
+_PACKAGE_STEMS = {"__init__", "index", "mod"}
+
+_MERMAID_BLOCK_RE = re.compile(r"```(?:mermaid)?[ \t]*\n(.*?)```", re.DOTALL)
+_MERMAID_HEADER_RE = re.compile(r"^(flowchart|graph)\b")
+
+
+@dataclass
+class Component:
+    """A group of files drawn as one diagram node.
+
+    Attributes:
+        name: Directory path relative to the project root ("." for the root).
+        files: Relative paths of the files in the component.
+        summary: Short description used in the diagram prompt.
+    """
+
+    name: str
+    files: list[str] = field(default_factory=list)
+    summary: str = ""
+
+
+@dataclass
+class ComponentGraph:
+    """Components and the number of imports between each pair of them."""
+
+    components: dict[str, Component] = field(default_factory=dict)
+    edges: dict[tuple[str, str], int] = field(default_factory=dict)
+
+    def node_ids(self) -> dict[str, str]:
+        """Return stable Mermaid node ids (``c1``, ``c2``, ...) by component name."""
+        return {name: f"c{i}" for i, name in enumerate(sorted(self.components), start=1)}
+
+
+def _stem_parts(path: str) -> list[str]:
+    parts = posixpath.splitext(path)[0].split("/")
+    if len(parts) > 1 and parts[-1] in _PACKAGE_STEMS:
+        parts = parts[:-1]
+    return parts
+
+
+class _ModuleIndex:
+    """Resolve import strings to the files of the project."""
+
+    def __init__(self, paths: list[str]):
+        self.by_stem: dict[str, set[str]] = {}
+        self.by_suffix: dict[str, set[str]] = {}
+        for path in paths:
+            parts = _stem_parts(path)
+            self.by_stem.setdefault("/".join(parts), set()).add(path)
+            # Absolute imports may start below the project root ("pkg.mod" for
+            # src/pkg/mod.py); single-segment suffixes are too ambiguous
+            for start in range(len(parts)):
+                if start == 0 or len(parts) - start >= 2:
+                    self.by_suffix.setdefault(".".join(parts[start:]), set()).add(path)
+
+    @staticmethod
+    def _unique(matches: set[str] | None) -> str | None:
+        return next(iter(matches)) if matches and len(matches) == 1 else None
+
+    def resolve(self, name: str, importer: str) -> str | None:
+        """Return the file ``name`` refers to when imported from ``importer``, if internal."""
+        name = name.strip().strip("'\"")
+        if not name:
+            return None
+        if name.startswith(("./", "../")):
+            target = posixpath.normpath(posixpath.join(posixpath.dirname(importer), name))
+            return self._unique(self.by_stem.get("/".join(_stem_parts(target))))
+        if name.startswith("."):
+            dots = len(name) - len(name.lstrip("."))
+            base = posixpath.dirname(importer)
+            for _ in range(dots - 1):
+                base = posixpath.dirname(base)
+            rest = name[dots:].replace(".", "/")
+            target = posixpath.join(base, rest) if rest else base
+            return self._unique(self.by_stem.get(target.strip("/")))
+
+        parts = [p for p in re.split(r"[./:]+", name) if p]
+        if parts and parts[0] in ("crate", "self", "super"):
+            parts = parts[1:]
+        # "pkg.mod.func" imports a name from pkg/mod.py: try the longest module prefix
+        for end in range(len(parts), 0, -1):
+            match = self._unique(self.by_suffix.get(".".join(parts[:end])))
+            if match:
+                return match
+        return None
+
+
+def file_import_graph(imports: dict[str, list[str]]) -> dict[str, set[str]]:
+    """Resolve each file's imports to the project files it depends on.
+
+    Args:
+        imports: Import strings of each file, by path relative to the project root.
+
+    Returns:
+        The internal files each file imports, by path; external and
+        unresolvable imports are left out.
+    """
+    index = _ModuleIndex(list(imports))
+    graph: dict[str, set[str]] = {}
+    for path, names in imports.items():
+        targets = {index.resolve(name, path) for name in set(names)}
+        graph[path] = {target for target in targets if target and target != path}
+    return graph
+
+
+def component_of(path: str, depth: int) -> str:
+    """Return the component of a relative file path: its directory cut to ``depth`` levels."""
+    directory = posixpath.dirname(path)
+    return "/".join(directory.split("/")[:depth]) if directory else "."
+
+
+def _first_sentence(text: str) -> str:
+    text = " ".join(text.split())
+    end = text.find(". ")
+    return text[: end + 1] if end != -1 else text
+
+
+def _truncate(text: str, limit: int) -> str:
+    return text if len(text) <= limit else text[: limit - 3].rstrip() + "..."
+
+
+def build_component_graph(
+    imports: dict[str, list[str]],
+    depth: int = 2,
+    file_summaries: dict[str, str] | None = None,
+    directory_summaries: dict[str, str] | None = None,
+) -> ComponentGraph:
+    """Group files into components and count the imports between them.
+
+    Args:
+        imports: Import strings of each file, by path relative to the project root.
+        depth: Directory levels below the root that form a component.
+        file_summaries: AI summaries by relative file path.
+        directory_summaries: AI directory summaries by relative directory path;
+            preferred over file summaries for a component with the same path.
+
+    Returns:
+        The graph, limited to the most connected components.
+    """
+    file_summaries = file_summaries or {}
+    directory_summaries = directory_summaries or {}
+    graph = ComponentGraph()
+    for path in sorted(imports):
+        name = component_of(path, depth)
+        graph.components.setdefault(name, Component(name)).files.append(path)
+
+    index = _ModuleIndex(list(imports))
+    for path, names in imports.items():
+        source = component_of(path, depth)
+        for name in set(names):
+            target_file = index.resolve(name, path)
+            if target_file is None or target_file == path:
+                continue
+            target = component_of(target_file, depth)
+            if target != source:
+                graph.edges[(source, target)] = graph.edges.get((source, target), 0) + 1
+
+    if len(graph.components) > _MAX_COMPONENTS:
+        weight = {name: len(c.files) for name, c in graph.components.items()}
+        for (source, target), count in graph.edges.items():
+            weight[source] += count
+            weight[target] += count
+        kept = set(sorted(weight, key=lambda n: (-weight[n], n))[:_MAX_COMPONENTS])
+        graph.components = {n: c for n, c in graph.components.items() if n in kept}
+        graph.edges = {e: n for e, n in graph.edges.items() if e[0] in kept and e[1] in kept}
+
+    for component in graph.components.values():
+        summary = directory_summaries.get(component.name if component.name != "." else "")
+        if not summary:
+            summary = " ".join(
+                _first_sentence(file_summaries[f]) for f in component.files if f in file_summaries
+            )
+        component.summary = _truncate(" ".join(summary.split()), MAX_COMPONENT_SUMMARY_CHARS)
+    return graph
+
+
+def build_diagram_prompt(graph: ComponentGraph) -> str:
+    """Build the prompt asking for a Mermaid component diagram of ``graph``."""
+    ids = graph.node_ids()
+    components = []
+    for name in sorted(graph.components):
+        component = graph.components[name]
+        listed = ", ".join(posixpath.basename(f) for f in component.files[:_MAX_LISTED_FILES])
+        more = len(component.files) - _MAX_LISTED_FILES
+        if more > 0:
+            listed += f", +{more} more"
+        line = f"- `{ids[name]}` **{name}** ({len(component.files)} files: {listed})"
+        components.append(f"{line}: {component.summary}" if component.summary else line)
+    edges = [
+        f"- {ids[source]} --> {ids[target]} ({count} imports)"
+        for (source, target), count in sorted(graph.edges.items())
+    ]
+
+    return "\n".join(
+        [
+            "### Task",
+            "Draw a Mermaid component diagram of this codebase from its components and the "
+            "imports between them.",
+            "",
+            "### Output Requirements",
+            "- Reply with one ```mermaid code block containing a `flowchart TD` diagram and "
+            "nothing else",
+            "- One node per component, using the node ids below, labelled with the component "
+            'name and its role in 2-5 words, e.g. `c1["api: HTTP routes"]`',
+            "- Draw the imports below as edges (`A --> B` means A imports B); omit minor edges "
+            "if the diagram gets crowded, but do not add new ones",
+            "- Group related components with `subgraph` blocks where it helps the reader",
+            "- Quote every label and avoid parentheses outside quotes",
+            "",
+            "### Components",
+            *components,
+            "",
+            "### Imports",
+            *(edges or ["- (no imports between components)"]),
+        ]
+    )
+
+
+def extract_mermaid(text: str) -> str | None:
+    """Return the Mermaid flowchart in a reply, or None if it contains none."""
+    match = _MERMAID_BLOCK_RE.search(text)
+    diagram = (match.group(1) if match else text).strip()
+    return diagram if _MERMAID_HEADER_RE.match(diagram) else None
+
+
+def render_mermaid(graph: ComponentGraph) -> str:
+    """Render ``graph`` as a plain Mermaid flowchart (no AI)."""
+    ids = graph.node_ids()
+    lines = ["flowchart TD"]
+    for name in sorted(graph.components):
+        label = name.replace('"', "#quot;")
+        lines.append(f'    {ids[name]}["{label}"]')
+    for (source, target), count in sorted(graph.edges.items()):
+        lines.append(f"    {ids[source]} -->|{count}| {ids[target]}")
+    return "\n".join(lines)
@@ -0,0 +1,129 @@
+"""AI change summaries for diff mode.
+
+In diff mode the changed files, the declarations their hunks touch and a
+trimmed excerpt of each diff are sent to the AI provider in one request. The
+provider writes a reviewer-oriented changelog, what changed and why it
+matters, which the writers place at the top of the output.
+"""
+
+from __future__ import annotations
+
+import re
+
+from ..base_types import Declaration, ParsedFileData
+
+CHANGE_SUMMARY_SYSTEM_PROMPT = (
+    "You are a senior engineer writing the summary a reviewer reads before a code review. "
+    "You describe only changes the diff shows, explain their impact and risks plainly, and "
+    "never speculate about code that is not shown."
+)
+
+# Completion budget for the change summary request
+CHANGE_SUMMARY_MAX_TOKENS = 1500
+
+# Diff characters shown per file and in total; declarations are always listed
+MAX_FILE_DIFF_CHARS = 3000
+MAX_TOTAL_DIFF_CHARS = 40000
+
+_HUNK_RE = re.compile(r"^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@", re.MULTILINE)
+
+
+def changed_line_ranges(diff_content: str) -> list[tuple[int, int]]:
+    """Return the new-side line ranges each hunk of a unified diff touches.
+
+    Ranges are inclusive and 1-based. A hunk that only deletes lines covers
+    the line after the deletion, so the enclosing declaration still counts as
+    changed.
+    """
+    ranges = []
+    for match in _HUNK_RE.finditer(diff_content or ""):
+        start = int(match.group(1))
+        count = int(match.group(2)) if match.group(2) is not None else 1
+        ranges.append((max(start, 1), max(start, 1) + max(count, 1) - 1))
+    return ranges
+
+
+def _overlaps(decl: Declaration, ranges: list[tuple[int, int]]) -> bool:
+    return any(decl.start_line <= end and start <= decl.end_line for start, end in ranges)
+
+
+def changed_declarations(
+    declarations: list[Declaration], ranges: list[tuple[int, int]]
+) -> list[Declaration]:
+    """Return the innermost declarations that overlap ``ranges``.
+
+    A class whose changes all fall inside its methods is represented by
+    those methods; one changed between its members is listed itself.
+    """
+    changed = []
+    for decl in declarations:
+        if not _overlaps(decl, ranges):
+            continue
+        inner = changed_declarations(decl.children or [], ranges)
+        changed.extend(inner or [decl])
+    return changed
+
+
+def _describe(decl: Declaration) -> str:
+    signature = (decl.signature or "").strip().splitlines()
+    text = signature[0] if signature else decl.name
+    return f"{decl.kind} `{text}` (lines {decl.start_line}-{decl.end_line})"
+
+
+def build_change_summary_prompt(
+    files: list[ParsedFileData], paths: dict[str, str], from_ref: str, to_ref: str
+) -> str:
+    """Build the prompt asking for a reviewer changelog of the changes in ``files``.
+
+    Args:
+        files: Diff-mode files with ``diff_metadata`` and ``diff_content``.
+        paths: Display path of each file, keyed by its file path.
+        from_ref: Git ref the changes start from.
+        to_ref: Git ref the changes end at.
+    """
+    sections = []
+    diff_budget = MAX_TOTAL_DIFF_CHARS
+    for parsed_file in files:
+        metadata = parsed_file.diff_metadata
+        if metadata is None:
+            continue
+        path = paths.get(parsed_file.file_path, parsed_file.file_path)
+        header = f"### {path} ({metadata.change_type}, +{metadata.additions}/-{metadata.deletions})"
+        if metadata.old_path:
+            header += f", renamed from {metadata.old_path}"
+        lines = [header]
+        if parsed_file.ai_summary:
+            lines.append(f"File summary: {parsed_file.ai_summary.strip()}")
+
+        diff = parsed_file.diff_content or ""
+        if metadata.change_type != "deleted":
+            decls = changed_declarations(parsed_file.declarations, changed_line_ranges(diff))
+            if decls:
+                lines.append("Changed declarations:")
+                lines.extend(f"- {_describe(decl)}" for decl in decls)
+
+        if metadata.binary:
+            lines.append("Binary file; no diff shown.")
+        elif diff and diff_budget > 0:
+            excerpt = diff[: min(MAX_FILE_DIFF_CHARS, diff_budget)]
+            diff_budget -= len(excerpt)
+            if len(excerpt) < len(diff):
+                excerpt += "\n... (diff truncated)"
+            lines.append(f"```diff\n{excerpt.rstrip()}\n```")
+        elif diff:
+            lines.append("Diff omitted to stay within the prompt budget.")
+        sections.append("\n".join(lines))
+
+    changes = "\n\n".join(sections)
+    return (
+        f"Summarize the changes from `{from_ref}` to `{to_ref}` for a code reviewer.\n\n"
+        "Write Markdown with these parts:\n"
+        "1. A one-paragraph overview of what the change does as a whole.\n"
+        "2. A bulleted list of the notable changes, grouped by theme rather than by file, "
+        "each saying what changed and why it matters.\n"
+        "3. A short list of risks and things the reviewer should check, such as behavior "
+        "changes, removed or renamed public APIs, and missing tests; write 'None noted' if "
+        "there are none.\n"
+        "Refer to files and declarations by the names shown. Do not repeat the diff.\n\n"
+        f"## Changed files\n\n{changes}\n"
+    )
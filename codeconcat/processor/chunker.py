@@ -0,0 +1,330 @@
+"""Declaration-aware chunking for embedding and retrieval pipelines.
+
+Vector databases work best with chunks of a bounded size that do not cut a
+function in half. This module splits file content into chunks of at most
+``max_tokens`` tokens, aligning chunk boundaries on declaration boundaries
+whenever possible:
+
+1. The file is divided into units: top-level declarations and the code
+   between them.
+2. A unit larger than ``max_tokens`` is split along its child declarations
+   (e.g. methods of a class), carrying the parent as context.
+3. A unit that is still too large and has no children is split on line
+   boundaries.
+4. Units are packed greedily into chunks; each new chunk starts with up to
+   ``overlap_tokens`` of trailing lines from the previous one.
+
+Every chunk records its file path, line range and enclosing declarations
+(e.g. ``["class UserService"]``) so retrieval results can be traced back to
+their source.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+from collections.abc import Callable, Iterable
+from dataclasses import asdict, dataclass, field
+from pathlib import Path
+
+from ..base_types import Declaration, WritableItem
+from .token_counter import count_tokens
+
+logger = logging.getLogger(__name__)
+
+
+@dataclass
+class Chunk:
+    """A contiguous slice of a file sized for embedding.
+
+    Attributes:
+        chunk_id: Stable identifier in the form ``<file_path>#<index>``.
+        file_path: Path of the source file.
+        language: Language of the source file.
+        start_line: First line of the chunk (1-based, inclusive).
+        end_line: Last line of the chunk (1-based, inclusive).
+        content: Chunk text.
+        token_count: Token count of ``content``.
+        parent_context: Enclosing declarations, outermost first.
+        declarations: Names of the declarations that start inside the chunk.
+    """
+
+    chunk_id: str
+    file_path: str
+    language: str
+    start_line: int
+    end_line: int
+    content: str
+    token_count: int
+    parent_context: list[str] = field(default_factory=list)
+    declarations: list[str] = field(default_factory=list)
+
+    @property
+    def context_header(self) -> str:
+        """One-line description of where the chunk lives, for prefixing embeddings."""
+        parts = [self.file_path, *self.parent_context]
+        return " > ".join(parts) + f" (lines {self.start_line}-{self.end_line})"
+
+    def to_dict(self) -> dict:
+        """Convert the chunk to a JSON-serializable dictionary."""
+        data = asdict(self)
+        data["context_header"] = self.context_header
+        return data
+
+
+@dataclass
+class _Unit:
+    start: int  # 0-based inclusive line index
+    end: int  # 0-based exclusive line index
+    context: list[str]
+    names: list[str]
+
+
+def _describe(decl: Declaration) -> str:
+    return f"{decl.kind} {decl.name}".strip()
+
+
+class DeclarationChunker:
+    """Split file content into token-bounded chunks aligned on declarations."""
+
+    def __init__(
+        self,
+        max_tokens: int = 512,
+        overlap_tokens: int = 64,
+        token_counter: Callable[[str], int] | None = None,
+    ):
+        """Initialize the chunker.
+
+        Args:
+            max_tokens: Target maximum tokens per chunk.
+            overlap_tokens: Tokens of trailing context repeated at the start of
+                the next chunk. Must be smaller than ``max_tokens``.
+            token_counter: Function returning the token count of a string.
+                Defaults to :func:`count_tokens`.
+
+        Raises:
+            ValueError: If the limits are not positive or overlap is too large.
+        """
+        if max_tokens <= 0:
+            raise ValueError("max_tokens must be positive")
+        if overlap_tokens < 0 or overlap_tokens >= max_tokens:
+            raise ValueError("overlap_tokens must be between 0 and max_tokens - 1")
+        self.max_tokens = max_tokens
+        self.overlap_tokens = overlap_tokens
+        self._count = token_counter or count_tokens
+
+    def chunk_file(
+        self,
+        file_path: str,
+        content: str,
+        declarations: Iterable[Declaration] = (),
+        language: str = "",
+    ) -> list[Chunk]:
+        """Chunk a single file.
+
+        Args:
+            file_path: Path recorded on each chunk.
+            content: File content to split.
+            declarations: Parsed declarations with 1-based inclusive line ranges.
+            language: Language recorded on each chunk.
+
+        Returns:
+            Chunks in file order. Empty content yields no chunks.
+        """
+        if not content.strip():
+            return []
+
+        lines = content.split("\n")
+        line_tokens = [self._count(line) + 1 for line in lines]  # +1 for the newline
+
+        units: list[_Unit] = []
+        self._build_units(list(declarations), 0, len(lines), [], line_tokens, units)
+
+        chunks: list[Chunk] = []
+        current: list[_Unit] = []
+        current_tokens = 0
+
+        def flush() -> None:
+            nonlocal current, current_tokens
+            if not current:
+                return
+            start = current[0].start
+            if chunks and self.overlap_tokens:
+                start = self._overlap_start(chunks[-1], start, line_tokens)
+            end = current[-1].end
+            text = "\n".join(lines[start:end])
+            if text.strip():
+                chunks.append(
+                    Chunk(
+                        chunk_id=f"{file_path}#{len(chunks)}",
+                        file_path=file_path,
+                        language=language,
+                        start_line=start + 1,
+                        end_line=end,
+                        content=text,
+                        token_count=self._count(text),
+                        parent_context=_common_context(current),
+                        declarations=[n for u in current for n in u.names],
+                    )
+                )
+            current = []
+            current_tokens = 0
+
+        for unit in units:
+            unit_tokens = sum(line_tokens[unit.start : unit.end])
+            budget = self.max_tokens - (self.overlap_tokens if chunks or current else 0)
+            if current and (
+                current_tokens + unit_tokens > budget or unit.context != current[-1].context
+            ):
+                flush()
+            current.append(unit)
+            current_tokens += unit_tokens
+        flush()
+
+        return chunks
+
+    def _build_units(
+        self,
+        declarations: list[Declaration],
+        start: int,
+        end: int,
+        context: list[str],
+        line_tokens: list[int],
+        units: list[_Unit],
+    ) -> None:
+        """Divide lines ``[start, end)`` into units along declaration boundaries."""
+        cursor = start
+        for decl in sorted(declarations, key=lambda d: d.start_line):
+            d_start = max(decl.start_line - 1, cursor)
+            d_end = min(decl.end_line, end)
+            if d_start >= d_end:
+                continue
+            if d_start > cursor:
+                self._add_span(cursor, d_start, context, [], line_tokens, units)
+
+            if sum(line_tokens[d_start:d_end]) > self.max_tokens and decl.children:
+                self._build_units(
+                    decl.children,
+                    d_start,
+                    d_end,
+                    [*context, _describe(decl)],
+                    line_tokens,
+                    units,
+                )
+            else:
+                self._add_span(d_start, d_end, context, [decl.name], line_tokens, units)
+            cursor = d_end
+
+        if cursor < end:
+            self._add_span(cursor, end, context, [], line_tokens, units)
+
+    def _add_span(
+        self,
+        start: int,
+        end: int,
+        context: list[str],
+        names: list[str],
+        line_tokens: list[int],
+        units: list[_Unit],
+    ) -> None:
+        """Add a span as one unit, splitting it on lines if it exceeds the budget."""
+        budget = max(1, self.max_tokens - self.overlap_tokens)
+        if sum(line_tokens[start:end]) <= budget:
+            units.append(_Unit(start, end, list(context), list(names)))
+            return
+
+        piece_start = start
+        piece_tokens = 0
+        for idx in range(start, end):
+            if piece_tokens and piece_tokens + line_tokens[idx] > budget:
+                units.append(
+                    _Unit(piece_start, idx, list(context), list(names) if piece_start == start else [])
+                )
+                piece_start = idx
+                piece_tokens = 0
+            piece_tokens += line_tokens[idx]
+        units.append(
+            _Unit(piece_start, end, list(context), list(names) if piece_start == start else [])
+        )
+
+    def _overlap_start(self, previous: Chunk, start: int, line_tokens: list[int]) -> int:
+        """Move ``start`` back over trailing lines of the previous chunk within the overlap budget."""
+        # Never reach back to the previous chunk's first line, so chunks always advance
+        earliest = previous.start_line
+        tokens = 0
+        new_start = start
+        while new_start > earliest and tokens + line_tokens[new_start - 1] <= self.overlap_tokens:
+            new_start -= 1
+            tokens += line_tokens[new_start]
+        return new_start
+
+
+def _common_context(units: list[_Unit]) -> list[str]:
+    """Return the longest context prefix shared by all units."""
+    common = list(units[0].context)
+    for unit in units[1:]:
+        i = 0
+        while i < min(len(common), len(unit.context)) and common[i] == unit.context[i]:
+            i += 1
+        common = common[:i]
+    return common
+
+
+def relative_file_path(file_path: str, root: Path | None) -> str:
+    """Return ``file_path`` relative to the resolved ``root`` if it lies under it."""
+    if root is not None:
+        try:
+            return Path(file_path).resolve().relative_to(root).as_posix()
+        except ValueError:
+            pass
+    return file_path
+
+
+def chunk_items(
+    items: Iterable[WritableItem],
+    max_tokens: int = 512,
+    overlap_tokens: int = 64,
+    root_path: str | None = None,
+) -> list[Chunk]:
+    """Chunk every writable item (annotated code files and documentation).
+
+    Args:
+        items: Items to chunk.
+        max_tokens: Maximum tokens per chunk.
+        overlap_tokens: Overlap between consecutive chunks of a file.
+        root_path: If given, file paths under it are recorded relative to it.
+
+    Returns:
+        All chunks, grouped by item in input order.
+    """
+    chunker = DeclarationChunker(max_tokens=max_tokens, overlap_tokens=overlap_tokens)
+    root = Path(root_path).resolve() if root_path else None
+    chunks: list[Chunk] = []
+    for item in items:
+        file_path = relative_file_path(item.file_path, root)
+        content = getattr(item, "content", "") or ""
+        declarations = getattr(item, "declarations", None) or []
+        language = getattr(item, "language", "") or getattr(item, "doc_type", "")
+        chunks.extend(chunker.chunk_file(file_path, content, declarations, language))
+    return chunks
+
+
+def write_chunks_jsonl(chunks: Iterable[Chunk], output_path: str) -> int:
+    """Write chunks as JSON Lines.
+
+    Args:
+        chunks: Chunks to write.
+        output_path: Destination file; parent directories are created.
+
+    Returns:
+        Number of chunks written.
+    """
+    path = Path(output_path)
+    path.parent.mkdir(parents=True, exist_ok=True)
+    count = 0
+    with open(path, "w", encoding="utf-8") as f:
+        for chunk in chunks:
+            f.write(json.dumps(chunk.to_dict(), ensure_ascii=False) + "\n")
+            count += 1
+    logger.debug(f"Wrote {count} chunks to {path}")
+    return count
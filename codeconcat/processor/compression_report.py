@@ -0,0 +1,191 @@
+"""Dry-run token savings report for compression settings.
+
+Long runs on large repositories are expensive to repeat, so it helps to know
+up front how much each compression setting would save. This module estimates
+token counts for every file under each compression pattern and content
+stripping strategy, aggregated per directory, without producing the final
+output.
+
+Strategies reported:
+- ``original``: content as collected
+- ``contextual``: compression at level low/medium
+- ``essential``: compression at level high/aggressive
+- ``no-comments``: comments removed
+- ``no-docstrings``: docstrings removed
+- ``combined``: essential compression after removing comments and docstrings
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+from collections.abc import Callable, Iterable
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from ..base_types import CodeConCatConfig, ParsedFileData
+from .compression_processor import CompressionProcessor
+from .content_processor import process_file_content
+from .token_counter import count_tokens
+
+logger = logging.getLogger(__name__)
+
+# Strategy name -> (config overrides, uses compression processor)
+_STRATEGY_SETTINGS: dict[str, tuple[dict, bool]] = {
+    "original": ({}, False),
+    "contextual": ({"compression_level": "medium"}, True),
+    "essential": ({"compression_level": "high"}, True),
+    "no-comments": ({"remove_comments": True}, False),
+    "no-docstrings": ({"remove_docstrings": True}, False),
+    "combined": (
+        {"compression_level": "high", "remove_comments": True, "remove_docstrings": True},
+        True,
+    ),
+}
+
+STRATEGIES = tuple(_STRATEGY_SETTINGS)
+
+
+@dataclass
+class CompressionReport:
+    """Estimated token counts per directory and strategy.
+
+    Attributes:
+        strategies: Strategy names in report order.
+        directories: Mapping of directory to ``{strategy: tokens}``.
+        file_counts: Number of files counted per directory.
+    """
+
+    strategies: list[str] = field(default_factory=lambda: list(STRATEGIES))
+    directories: dict[str, dict[str, int]] = field(default_factory=dict)
+    file_counts: dict[str, int] = field(default_factory=dict)
+
+    def add(self, directory: str, tokens_by_strategy: dict[str, int]) -> None:
+        """Add one file's token counts to a directory row."""
+        row = self.directories.setdefault(directory, dict.fromkeys(self.strategies, 0))
+        for strategy, tokens in tokens_by_strategy.items():
+            row[strategy] += tokens
+        self.file_counts[directory] = self.file_counts.get(directory, 0) + 1
+
+    def totals(self) -> dict[str, int]:
+        """Return token totals across all directories."""
+        totals = dict.fromkeys(self.strategies, 0)
+        for row in self.directories.values():
+            for strategy, tokens in row.items():
+                totals[strategy] += tokens
+        return totals
+
+    def savings(self, strategy: str) -> float:
+        """Return the estimated fractional reduction of ``strategy`` versus the original."""
+        totals = self.totals()
+        original = totals.get("original", 0)
+        if not original:
+            return 0.0
+        return 1 - totals.get(strategy, 0) / original
+
+    def to_text(self) -> str:
+        """Render the report as a plain-text table."""
+        headers = ["directory", "files", *self.strategies]
+        rows = [
+            [directory, str(self.file_counts[directory]), *(f"{row[s]:,}" for s in self.strategies)]
+            for directory, row in sorted(self.directories.items())
+        ]
+        totals = self.totals()
+        rows.append(
+            ["TOTAL", str(sum(self.file_counts.values())), *(f"{totals[s]:,}" for s in self.strategies)]
+        )
+        widths = [max(len(r[i]) for r in [headers, *rows]) for i in range(len(headers))]
+        lines = ["  ".join(h.ljust(w) for h, w in zip(headers, widths, strict=True))]
+        lines.extend("  ".join(c.rjust(w) for c, w in zip(r, widths, strict=True)) for r in rows)
+        return "\n".join(lines)
+
+
+def _directory_key(file_path: str, root_path: str, depth: int) -> str:
+    """Group a file under the first ``depth`` components of its directory."""
+    try:
+        rel = Path(os.path.relpath(file_path, root_path))
+    except ValueError:
+        rel = Path(file_path)
+    parts = rel.parent.parts
+    if not parts or parts[0] in (".", ".."):
+        return "."
+    return "/".join(parts[:depth])
+
+
+def estimate_file_tokens(
+    file_data: ParsedFileData,
+    config: CodeConCatConfig,
+    token_counter: Callable[[str], int] | None = None,
+) -> dict[str, int]:
+    """Estimate token counts for one file under every strategy.
+
+    Args:
+        file_data: File to evaluate.
+        config: Base configuration; each strategy applies its overrides to a copy.
+        token_counter: Token counting function (defaults to GPT-4 counting).
+
+    Returns:
+        Mapping of strategy name to estimated tokens.
+    """
+    count = token_counter or (lambda text: count_tokens(text, "gpt-4"))
+    content = file_data.content or ""
+    results: dict[str, int] = {}
+
+    for strategy, (overrides, compress) in _STRATEGY_SETTINGS.items():
+        strategy_config = config.model_copy(
+            update={
+                "enable_compression": compress,
+                "remove_comments": False,
+                "remove_docstrings": False,
+                "show_line_numbers": False,
+                **overrides,
+            }
+        )
+        text = content
+        if strategy_config.remove_comments or strategy_config.remove_docstrings:
+            text = process_file_content(text, strategy_config, file_data)
+        if compress:
+            stripped = ParsedFileData(
+                file_path=file_data.file_path,
+                language=file_data.language,
+                content=text,
+                declarations=file_data.declarations,
+                security_issues=getattr(file_data, "security_issues", []),
+            )
+            text = CompressionProcessor(strategy_config).apply_compression(stripped)
+        results[strategy] = count(text)
+
+    return results
+
+
+def build_compression_report(
+    files: Iterable[ParsedFileData],
+    config: CodeConCatConfig,
+    token_counter: Callable[[str], int] | None = None,
+) -> CompressionReport:
+    """Build a per-directory compression savings report.
+
+    Args:
+        files: Parsed or annotated files to evaluate.
+        config: Configuration providing ``target_path`` and
+            ``compression_report_depth``.
+        token_counter: Optional token counting function.
+
+    Returns:
+        The populated CompressionReport.
+    """
+    root_path = config.target_path if os.path.isdir(config.target_path or "") else "."
+    depth = max(1, getattr(config, "compression_report_depth", 1))
+    report = CompressionReport()
+
+    for file_data in files:
+        if not file_data.content:
+            continue
+        try:
+            tokens = estimate_file_tokens(file_data, config, token_counter)
+        except (ValueError, AttributeError, TypeError) as e:
+            logger.warning(f"Could not estimate compression for {file_data.file_path}: {e}")
+            continue
+        report.add(_directory_key(file_data.file_path, root_path, depth), tokens)
+
+    return report
@@ -1,5 +1,7 @@
 """Content processing module for CodeConcat."""
 
+import functools
+import hashlib
 import os
 import re
 from typing import Any
@@ -238,6 +240,157 @@ def _remove_comments_from_line(line: str) -> str:
     return "".join(result).rstrip()
 
 
+def truncate_long_lines(content: str, max_line_length: int) -> tuple[str, int, int]:
+    """
+    Truncate lines longer than ``max_line_length`` characters.
+
+    Minified bundles, embedded base64 blobs and giant data literals can put
+    tens of thousands of characters on a single line. Each overlong line is cut
+    to ``max_line_length`` characters and suffixed with a continuation marker
+    such as ``…[truncated 14,203 chars]`` so the reader knows content was
+    dropped. Line count is preserved, so parsed declaration line numbers stay
+    valid.
+
+    Args:
+        content: The content to process.
+        max_line_length: Maximum characters kept per line. Values <= 0 disable
+            truncation.
+
+    Returns:
+        Tuple of (processed content, number of lines truncated, total
+        characters removed).
+
+    Flow:
+        Called by: run_codeconcat() before annotation when
+            config.max_line_length is set
+    """
+    if max_line_length <= 0 or not content:
+        return content, 0, 0
+
+    lines = content.split("\n")
+    truncated_lines = 0
+    removed_chars = 0
+
+    for idx, line in enumerate(lines):
+        if len(line) > max_line_length:
+            removed = len(line) - max_line_length
+            lines[idx] = f"{line[:max_line_length]}…[truncated {removed:,} chars]"
+            truncated_lines += 1
+            removed_chars += removed
+
+    if not truncated_lines:
+        return content, 0, 0
+
+    return "\n".join(lines), truncated_lines, removed_chars
+
+
+# Languages whose backticks delimit strings: JS/TS template literals and Go raw strings
+_BACKTICK_STRING_LANGUAGES = frozenset({"javascript", "typescript", "go"})
+
+# Comment syntax used to skip comments while looking for literals; languages
+# not listed get both, which can only leave a literal untruncated
+_HASH_COMMENT_LANGUAGES = frozenset(
+    """python ruby bash shell perl r powershell yaml toml julia elixir nim crystal
+    makefile dockerfile""".split()
+)
+_SLASH_COMMENT_LANGUAGES = frozenset(
+    """javascript typescript java c cpp csharp go rust swift kotlin scala dart zig
+    solidity terraform hcl""".split()
+)
+
+
+@functools.lru_cache(maxsize=None)
+def _string_literal_re(language: str | None) -> re.Pattern[str]:
+    """Return the pattern matching comments and string literals of ``language``.
+
+    Comments are matched so the scan skips over them: an apostrophe or
+    backtick in a comment never opens a literal. Literals are triple-quoted
+    (may span lines), backtick-delimited for languages that have them (may
+    span lines) and single/double quoted (single line only, so a stray quote
+    cannot swallow the rest of the file).
+    """
+    comments = []
+    if language not in _SLASH_COMMENT_LANGUAGES:
+        comments.append(r"#[^\n]*")
+    if language not in _HASH_COMMENT_LANGUAGES:
+        comments.extend([r"//[^\n]*", r"/\*(?s:.*?)\*/"])
+    alternatives = [
+        f"(?P<comment>{'|'.join(comments)})",
+        r'(?P<triple>"""|\'\'\')(?P<tbody>(?:\\.|(?!(?P=triple))[^\\])*)(?P=triple)',
+    ]
+    if language in _BACKTICK_STRING_LANGUAGES:
+        alternatives.append(r"`(?P<bbody>(?:\\.|[^`\\])*)`")
+    alternatives.append(r'(?P<quote>["\'])(?P<body>(?:\\.|(?!(?P=quote))[^\\\n])*)(?P=quote)')
+    return re.compile("|".join(alternatives))
+
+
+# Characters of an oversized literal kept ahead of the truncation marker
+_LITERAL_PREVIEW_CHARS = 40
+
+
+def truncate_string_literals(
+    content: str, max_literal_length: int, language: str | None = None
+) -> tuple[str, int, int]:
+    """
+    Truncate string literals longer than ``max_literal_length`` characters.
+
+    Embedded SQL dumps, base64 blobs and minified JSON are replaced by a short
+    preview followed by a marker recording the original length and a SHA-256
+    prefix, e.g. ``"iVBORw0KGgo…[string truncated: 48,211 chars, sha256:3f2a9c1be07d4a55]"``.
+    Identical blobs therefore keep identical markers, and changed blobs show a
+    different hash. Quotes are kept and newlines inside multi-line literals are
+    preserved, so line numbers of parsed declarations stay valid.
+
+    Args:
+        content: The source code to process.
+        max_literal_length: Maximum characters allowed inside a literal. Values
+            <= 0 disable truncation.
+        language: Language of the content, used to skip its comments and to
+            decide whether backticks delimit strings.
+
+    Returns:
+        Tuple of (processed content, number of literals truncated, total
+        characters removed).
+
+    Flow:
+        Called by: run_codeconcat() before annotation when
+            config.max_string_literal_length is set
+    """
+    if max_literal_length <= 0 or not content or len(content) <= max_literal_length:
+        return content, 0, 0
+
+    truncated = 0
+    removed_chars = 0
+
+    def _replace(match: re.Match) -> str:
+        nonlocal truncated, removed_chars
+        if match.group("comment") is not None:
+            return match.group(0)
+        group = next(
+            g for g in ("tbody", "bbody", "body") if match.groupdict().get(g) is not None
+        )
+        body = match.group(group)
+        if len(body) <= max_literal_length:
+            return match.group(0)
+
+        preview = body[: min(_LITERAL_PREVIEW_CHARS, max_literal_length)]
+        digest = hashlib.sha256(body.encode("utf-8", "surrogatepass")).hexdigest()[:16]
+        marker = f"…[string truncated: {len(body):,} chars, sha256:{digest}]"
+        newlines = "\n" * (body.count("\n") - preview.count("\n"))
+        truncated += 1
+        removed_chars += len(body) - len(preview)
+
+        start, end = match.span(group)
+        offset = match.start()
+        whole = match.group(0)
+        return whole[: start - offset] + preview + marker + newlines + whole[end - offset :]
+
+    result = _string_literal_re(language).sub(_replace, content)
+    if not truncated:
+        return content, 0, 0
+    return result, truncated, removed_chars
+
+
 def process_file_content(
     content: str,
     config: CodeConCatConfig,
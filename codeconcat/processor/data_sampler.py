@@ -0,0 +1,282 @@
+"""Sampling and schema summaries for data files.
+
+Data files such as CSV exports, JSON fixtures and NDJSON logs are either
+useless to an LLM in full (megabytes of rows) or missing entirely. This
+module replaces their content with a compact representation: a generated
+schema summary (columns or keys, inferred types, row/record count) followed
+by the header and a configurable sample of rows or records.
+
+Supported formats: CSV, TSV, JSON and NDJSON/JSON Lines.
+"""
+
+from __future__ import annotations
+
+import csv
+import io
+import json
+import logging
+import os
+import re
+from dataclasses import dataclass, field
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+# Extension -> data format
+DATA_FILE_FORMATS = {
+    ".csv": "csv",
+    ".tsv": "tsv",
+    ".json": "json",
+    ".ndjson": "ndjson",
+    ".jsonl": "ndjson",
+}
+
+# Rows scanned for type inference; counting continues past this but types are fixed
+_TYPE_SCAN_LIMIT = 10_000
+
+_INT_RE = re.compile(r"^[+-]?\d+$")
+_FLOAT_RE = re.compile(r"^[+-]?(\d+\.\d*|\.\d+|\d+)([eE][+-]?\d+)?$")
+_DATE_RE = re.compile(r"^\d{4}-\d{2}-\d{2}$")
+_DATETIME_RE = re.compile(r"^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?$")
+_BOOL_VALUES = frozenset({"true", "false", "yes", "no"})
+
+# Order used to widen conflicting types: a column with ints and floats is float
+_NUMERIC_ORDER = ("integer", "float")
+
+
+@dataclass
+class FieldSummary:
+    """Inferred type information for a column or JSON key.
+
+    Attributes:
+        name: Column or key name.
+        types: Set of observed types.
+        empty_count: Number of empty/null values seen.
+    """
+
+    name: str
+    types: set[str] = field(default_factory=set)
+    empty_count: int = 0
+
+    @property
+    def inferred_type(self) -> str:
+        """Return the single type that best describes the observed values."""
+        if not self.types:
+            return "empty"
+        if len(self.types) == 1:
+            return next(iter(self.types))
+        if self.types <= set(_NUMERIC_ORDER):
+            return "float"
+        if self.types <= {"date", "datetime"}:
+            return "datetime"
+        return "mixed(" + ", ".join(sorted(self.types)) + ")"
+
+    def describe(self) -> str:
+        """Render as ``name: type`` with an optional empty count."""
+        text = f"{self.name}: {self.inferred_type}"
+        if self.empty_count:
+            text += f" ({self.empty_count:,} empty)"
+        return text
+
+
+def data_format_for(file_path: str) -> str | None:
+    """Return the data format for ``file_path`` or None if it is not a data file."""
+    return DATA_FILE_FORMATS.get(os.path.splitext(file_path)[1].lower())
+
+
+def is_data_file(file_path: str) -> bool:
+    """Return True if the file is a supported data file."""
+    return data_format_for(file_path) is not None
+
+
+def should_sample(file_path: str, content: str, min_json_bytes: int = 16_384) -> bool:
+    """Return True if the file should be replaced by a sample.
+
+    Delimited and NDJSON files are always sampled. Plain JSON is only sampled
+    above ``min_json_bytes``, because small JSON files are usually
+    configuration (``package.json``, ``tsconfig.json``) that should stay intact.
+    """
+    data_format = data_format_for(file_path)
+    if data_format is None:
+        return False
+    if data_format == "json":
+        return len(content.encode("utf-8")) >= min_json_bytes
+    return True
+
+
+def infer_scalar_type(value: str) -> str | None:
+    """Infer the type of a CSV cell. Returns None for empty cells."""
+    value = value.strip()
+    if not value:
+        return None
+    if _INT_RE.match(value):
+        return "integer"
+    if _FLOAT_RE.match(value):
+        return "float"
+    if value.lower() in _BOOL_VALUES:
+        return "boolean"
+    if _DATE_RE.match(value):
+        return "date"
+    if _DATETIME_RE.match(value):
+        return "datetime"
+    return "string"
+
+
+def json_type(value: Any) -> str | None:
+    """Return the JSON type name of ``value`` (None for null)."""
+    if value is None:
+        return None
+    if isinstance(value, bool):
+        return "boolean"
+    if isinstance(value, int):
+        return "integer"
+    if isinstance(value, float):
+        return "float"
+    if isinstance(value, str):
+        return "string"
+    if isinstance(value, list):
+        return "array"
+    return "object"
+
+
+def _sample_delimited(content: str, delimiter: str, sample_rows: int, label: str) -> str:
+    reader = csv.reader(io.StringIO(content), delimiter=delimiter)
+    try:
+        header = next(reader)
+    except StopIteration:
+        return f"{label} file (empty)"
+
+    fields = [FieldSummary(name or f"column_{i + 1}") for i, name in enumerate(header)]
+    sample: list[list[str]] = []
+    row_count = 0
+    for row in reader:
+        if not row:
+            continue
+        row_count += 1
+        if len(sample) < sample_rows:
+            sample.append(row)
+        if row_count <= _TYPE_SCAN_LIMIT:
+            for i, cell in enumerate(row[: len(fields)]):
+                cell_type = infer_scalar_type(cell)
+                if cell_type is None:
+                    fields[i].empty_count += 1
+                else:
+                    fields[i].types.add(cell_type)
+
+    out = io.StringIO()
+    writer = csv.writer(out, delimiter=delimiter, lineterminator="\n")
+    writer.writerow(header)
+    writer.writerows(sample)
+
+    lines = [
+        f"{label} file: {row_count:,} rows, {len(fields)} columns",
+        "Columns:",
+        *(f"  {f.describe()}" for f in fields),
+    ]
+    if row_count > _TYPE_SCAN_LIMIT:
+        lines.append(f"  (types inferred from the first {_TYPE_SCAN_LIMIT:,} rows)")
+    lines.append("")
+    lines.append(f"Sample (header + first {len(sample)} rows):")
+    lines.append(out.getvalue().rstrip("\n"))
+    if row_count > len(sample):
+        lines.append(f"... {row_count - len(sample):,} more rows omitted")
+    return "\n".join(lines)
+
+
+def _record_fields(records: list[Any]) -> list[FieldSummary]:
+    fields: dict[str, FieldSummary] = {}
+    for record in records[:_TYPE_SCAN_LIMIT]:
+        if not isinstance(record, dict):
+            continue
+        for key, value in record.items():
+            summary = fields.setdefault(key, FieldSummary(key))
+            value_type = json_type(value)
+            if value_type is None:
+                summary.empty_count += 1
+            else:
+                summary.types.add(value_type)
+    return list(fields.values())
+
+
+def _truncate_json(value: Any, sample_rows: int, depth: int = 0) -> Any:
+    """Limit every array to ``sample_rows`` items, noting how many were dropped."""
+    if isinstance(value, list):
+        items = [_truncate_json(v, sample_rows, depth + 1) for v in value[:sample_rows]]
+        if len(value) > sample_rows:
+            items.append(f"... {len(value) - sample_rows:,} more items")
+        return items
+    if isinstance(value, dict) and depth < 20:
+        return {k: _truncate_json(v, sample_rows, depth + 1) for k, v in value.items()}
+    return value
+
+
+def _sample_records(records: list[Any], sample_rows: int, label: str) -> str:
+    fields = _record_fields(records)
+    lines = [f"{label} file: {len(records):,} records"]
+    if fields:
+        lines.append("Fields:")
+        lines.extend(f"  {f.describe()}" for f in fields)
+    else:
+        types = sorted({json_type(r) or "null" for r in records[:_TYPE_SCAN_LIMIT]})
+        lines.append(f"Element types: {', '.join(types) or 'none'}")
+    lines.append("")
+    lines.append(f"Sample (first {min(sample_rows, len(records))} records):")
+    for record in records[:sample_rows]:
+        lines.append(json.dumps(_truncate_json(record, sample_rows), ensure_ascii=False))
+    if len(records) > sample_rows:
+        lines.append(f"... {len(records) - sample_rows:,} more records omitted")
+    return "\n".join(lines)
+
+
+def _sample_json(content: str, sample_rows: int) -> str:
+    data = json.loads(content)
+    if isinstance(data, list):
+        return _sample_records(data, sample_rows, "JSON array")
+
+    lines = ["JSON object"]
+    if isinstance(data, dict):
+        lines[0] += f" with {len(data):,} top-level keys"
+        lines.append("Keys:")
+        for key, value in data.items():
+            desc = f"  {key}: {json_type(value) or 'null'}"
+            if isinstance(value, list):
+                desc += f" ({len(value):,} items)"
+            lines.append(desc)
+    lines.append("")
+    lines.append(f"Sample (arrays limited to {sample_rows} items):")
+    lines.append(json.dumps(_truncate_json(data, sample_rows), indent=2, ensure_ascii=False))
+    return "\n".join(lines)
+
+
+def _sample_ndjson(content: str, sample_rows: int) -> str:
+    records = [json.loads(line) for line in content.splitlines() if line.strip()]
+    return _sample_records(records, sample_rows, "NDJSON")
+
+
+def sample_data_content(content: str, file_path: str, sample_rows: int = 5) -> str | None:
+    """Build the schema summary and sample for a data file.
+
+    Args:
+        content: Full file content.
+        file_path: Path used to determine the format.
+        sample_rows: Number of rows or records to include.
+
+    Returns:
+        Replacement content, or None if the file is not a supported data file
+        or cannot be parsed.
+    """
+    data_format = data_format_for(file_path)
+    if data_format is None:
+        return None
+
+    try:
+        if data_format == "csv":
+            return _sample_delimited(content, ",", sample_rows, "CSV")
+        if data_format == "tsv":
+            return _sample_delimited(content, "\t", sample_rows, "TSV")
+        if data_format == "json":
+            return _sample_json(content, sample_rows)
+        return _sample_ndjson(content, sample_rows)
+    except (ValueError, csv.Error) as e:
+        logger.debug(f"Could not sample data file {file_path}: {e}")
+        return None
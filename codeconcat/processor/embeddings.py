@@ -0,0 +1,546 @@
+"""Embedding generation and vector store export.
+
+Turns a run into a ready-to-query retrieval index: the output is split into
+declaration-aware chunks (or one record per declaration), each record is
+embedded, and the vectors are written to a vector store together with the
+metadata needed to trace a hit back to its source.
+
+Embedding backends:
+
+- ``sentence-transformers`` (default): local models via the optional
+  ``sentence-transformers`` package; nothing leaves the machine.
+- ``openai``: the ``/v1/embeddings`` API of OpenAI or any OpenAI-compatible
+  server (vLLM, LM Studio, Ollama) set with ``embed_api_base``.
+- ``hashing``: dependency-free feature hashing of identifiers and words. Much
+  weaker than a model but deterministic and offline, which suits CI and
+  keyword-style lookup.
+
+Stores:
+
+- ``npy``: ``embeddings.npy`` plus ``metadata.jsonl`` (no extra dependencies)
+- ``faiss``: ``index.faiss`` plus ``metadata.jsonl`` (needs ``faiss-cpu``)
+- ``chroma``: a persistent Chroma collection (needs ``chromadb``)
+- ``qdrant``: a local Qdrant path or server URL (needs ``qdrant-client``)
+
+Every store directory also gets a ``manifest.json`` describing the backend,
+model and dimensions, so the index can be queried with the same embedder.
+"""
+
+from __future__ import annotations
+
+import array
+import hashlib
+import json
+import logging
+import math
+import os
+import re
+import sys
+import uuid
+from collections.abc import Iterable
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any, Protocol
+
+from ..base_types import CodeConCatConfig, Declaration, WritableItem
+from ..utils.offline import is_offline
+from .chunker import Chunk, chunk_items, relative_file_path
+
+logger = logging.getLogger(__name__)
+
+EMBEDDING_BACKENDS = ("sentence-transformers", "openai", "hashing")
+VECTOR_STORES = ("npy", "faiss", "chroma", "qdrant")
+EMBEDDING_UNITS = ("chunks", "declarations")
+
+DEFAULT_MODELS = {
+    "sentence-transformers": "all-MiniLM-L6-v2",
+    "openai": "text-embedding-3-small",
+    "hashing": "hashing-384",
+}
+
+# Rough character cap per record; keeps large declarations under model input limits
+_MAX_RECORD_CHARS = 24_000
+
+# Declaration kinds too small to be useful as standalone records
+_SKIPPED_KINDS = frozenset({"variable", "constant", "import", "field", "property"})
+
+_WORD_RE = re.compile(r"[A-Za-z_][A-Za-z0-9_]*|\d+")
+_SUBWORD_RE = re.compile(r"[A-Z]+(?=[A-Z][a-z])|[A-Z]?[a-z]+|[A-Z]+|\d+")
+
+
+class EmbeddingError(Exception):
+    """Raised when embeddings cannot be generated or exported."""
+
+
+class Embedder(Protocol):
+    """Interface implemented by embedding backends."""
+
+    name: str
+    model: str
+
+    def embed(self, texts: list[str]) -> list[list[float]]:
+        """Return one vector per input text."""
+        ...
+
+
+def _normalize(vector: list[float]) -> list[float]:
+    norm = math.sqrt(sum(v * v for v in vector))
+    return [v / norm for v in vector] if norm else vector
+
+
+def cosine_similarity(a: list[float], b: list[float]) -> float:
+    """Cosine similarity of two vectors (0.0 if either is all zeros)."""
+    dot = sum(x * y for x, y in zip(a, b, strict=False))
+    norm = math.sqrt(sum(x * x for x in a)) * math.sqrt(sum(y * y for y in b))
+    return dot / norm if norm else 0.0
+
+
+class HashingEmbedder:
+    """Feature-hashing embedder over identifiers, their subwords and plain words."""
+
+    name = "hashing"
+
+    def __init__(self, dimensions: int = 384):
+        """Initialize with the output dimensionality."""
+        self.dimensions = dimensions
+        self.model = f"hashing-{dimensions}"
+
+    def _features(self, text: str) -> Iterable[str]:
+        for word in _WORD_RE.findall(text):
+            yield word.lower()
+            parts = [p.lower() for piece in word.split("_") for p in _SUBWORD_RE.findall(piece)]
+            if len(parts) > 1:
+                yield from parts
+
+    def embed(self, texts: list[str]) -> list[list[float]]:
+        """Embed texts by hashing each feature into a signed bucket."""
+        vectors = []
+        for text in texts:
+            vector = [0.0] * self.dimensions
+            for feature in self._features(text):
+                digest = int.from_bytes(
+                    hashlib.blake2b(feature.encode("utf-8"), digest_size=8).digest(), "little"
+                )
+                vector[digest % self.dimensions] += 1.0 if digest >> 63 else -1.0
+            vectors.append(_normalize(vector))
+        return vectors
+
+
+class SentenceTransformerEmbedder:
+    """Local embedding models via the optional ``sentence-transformers`` package."""
+
+    name = "sentence-transformers"
+
+    def __init__(self, model: str, batch_size: int = 32):
+        """Load the model.
+
+        Raises:
+            EmbeddingError: If sentence-transformers is not installed or the model fails to load.
+        """
+        try:
+            from sentence_transformers import (  # type: ignore[import-not-found]
+                SentenceTransformer,
+            )
+        except ImportError as e:
+            raise EmbeddingError(
+                "The sentence-transformers backend needs the 'sentence-transformers' package "
+                "(pip install sentence-transformers)"
+            ) from e
+        self.model = model
+        self.batch_size = batch_size
+        try:
+            # Offline mode only loads models already in the Hugging Face cache
+            self._model = SentenceTransformer(model, local_files_only=is_offline())
+        except Exception as e:
+            raise EmbeddingError(f"Could not load embedding model '{model}': {e}") from e
+
+    def embed(self, texts: list[str]) -> list[list[float]]:
+        """Embed texts with normalized model outputs."""
+        vectors = self._model.encode(
+            texts, batch_size=self.batch_size, normalize_embeddings=True, show_progress_bar=False
+        )
+        return [list(map(float, v)) for v in vectors]
+
+
+class OpenAIEmbedder:
+    """Embeddings from the OpenAI API or an OpenAI-compatible server."""
+
+    name = "openai"
+
+    def __init__(
+        self,
+        model: str,
+        api_key: str | None = None,
+        api_base: str | None = None,
+        batch_size: int = 64,
+        timeout: int = 120,
+    ):
+        """Initialize the client settings.
+
+        Raises:
+            EmbeddingError: If no API key is available for the public OpenAI endpoint.
+        """
+        self.model = model
+        self.api_base = (api_base or "https://api.openai.com/v1").rstrip("/")
+        self.api_key = api_key or os.getenv("OPENAI_API_KEY")
+        if not self.api_key and "api.openai.com" in self.api_base:
+            raise EmbeddingError("The openai embedding backend needs OPENAI_API_KEY or ai_api_key")
+        self.batch_size = batch_size
+        self.timeout = timeout
+
+    def embed(self, texts: list[str]) -> list[list[float]]:
+        """Embed texts in batches, preserving input order."""
+        import httpx
+
+        headers = {"Authorization": f"Bearer {self.api_key}"} if self.api_key else {}
+        vectors: list[list[float]] = []
+        with httpx.Client(timeout=self.timeout) as client:
+            for start in range(0, len(texts), self.batch_size):
+                batch = texts[start : start + self.batch_size]
+                try:
+                    response = client.post(
+                        f"{self.api_base}/embeddings",
+                        json={"model": self.model, "input": batch},
+                        headers=headers,
+                    )
+                    response.raise_for_status()
+                    data = sorted(response.json()["data"], key=lambda d: d["index"])
+                except (httpx.HTTPError, KeyError, ValueError) as e:
+                    raise EmbeddingError(f"Embedding request to {self.api_base} failed: {e}") from e
+                vectors.extend(d["embedding"] for d in data)
+        return vectors
+
+
+def create_embedder(config: CodeConCatConfig) -> Embedder:
+    """Create the embedding backend selected in the configuration.
+
+    Raises:
+        EmbeddingError: For unknown backends, missing packages, or a remote
+            endpoint while ``ai_local_only`` or offline mode is set.
+    """
+    backend = config.embed_backend
+    model = config.embed_model or DEFAULT_MODELS.get(backend, "")
+    if backend == "hashing":
+        match = re.fullmatch(r"hashing-(\d+)", model)
+        return HashingEmbedder(int(match.group(1)) if match else 384)
+    if backend == "sentence-transformers":
+        return SentenceTransformerEmbedder(model)
+    if backend == "openai":
+        from ..ai.base import is_on_prem_endpoint
+
+        api_base = config.embed_api_base or "https://api.openai.com/v1"
+        local_only = config.ai_local_only or config.offline or is_offline()
+        if local_only and not is_on_prem_endpoint(api_base, config.ai_allowed_hosts):
+            mode = "Offline mode" if config.offline or is_offline() else "Local-only mode"
+            raise EmbeddingError(f"{mode}: refusing to send code to embedding endpoint {api_base}")
+        return OpenAIEmbedder(
+            model,
+            api_key=config.ai_api_key,
+            api_base=config.embed_api_base,
+            timeout=config.ai_timeout,
+        )
+    raise EmbeddingError(
+        f"Unknown embedding backend '{backend}' (choose from {', '.join(EMBEDDING_BACKENDS)})"
+    )
+
+
+def _walk(
+    declarations: list[Declaration], context: list[str]
+) -> Iterable[tuple[Declaration, list[str]]]:
+    for decl in declarations:
+        yield decl, context
+        if decl.children:
+            yield from _walk(decl.children, [*context, f"{decl.kind} {decl.name}"])
+
+
+def declaration_records(items: Iterable[WritableItem], root_path: str | None = None) -> list[Chunk]:
+    """Build one record per function, class or other block declaration, including nested ones.
+
+    Records reuse the :class:`Chunk` shape so both units share one export
+    path. Files without declarations are skipped.
+    """
+    root = Path(root_path).resolve() if root_path else None
+    records: list[Chunk] = []
+    for item in items:
+        declarations = getattr(item, "declarations", None) or []
+        content = getattr(item, "content", "") or ""
+        if not declarations or not content:
+            continue
+        file_path = relative_file_path(item.file_path, root)
+        lines = content.split("\n")
+        for decl, context in _walk(declarations, []):
+            if decl.kind in _SKIPPED_KINDS:
+                continue
+            end_line = decl.end_line or decl.start_line
+            text = "\n".join(lines[decl.start_line - 1 : end_line])[:_MAX_RECORD_CHARS]
+            if not text.strip():
+                continue
+            qualified = ".".join([*(c.split(" ", 1)[-1] for c in context), decl.name])
+            records.append(
+                Chunk(
+                    chunk_id=f"{file_path}#{qualified}",
+                    file_path=file_path,
+                    language=getattr(item, "language", "") or "",
+                    start_line=decl.start_line,
+                    end_line=end_line,
+                    content=text,
+                    token_count=0,
+                    parent_context=list(context),
+                    declarations=[decl.name],
+                )
+            )
+    return records
+
+
+def embedding_text(record: Chunk) -> str:
+    """Text sent to the embedder: the context header followed by the content."""
+    return f"{record.context_header}\n{record.content}"[:_MAX_RECORD_CHARS]
+
+
+def _metadata(record: Chunk) -> dict[str, Any]:
+    data = record.to_dict()
+    data.pop("token_count", None)
+    return data
+
+
+def _write_npy(path: Path, vectors: list[list[float]]) -> None:
+    """Write a float32 matrix in NumPy ``.npy`` format without requiring NumPy."""
+    rows = len(vectors)
+    cols = len(vectors[0]) if vectors else 0
+    header = f"{{'descr': '<f4', 'fortran_order': False, 'shape': ({rows}, {cols}), }}"
+    # Magic (6) + version (2) + header length (2) + header, padded to a multiple of 64
+    padding = 64 - (10 + len(header) + 1) % 64
+    header_bytes = (header + " " * padding + "\n").encode("latin1")
+    data = array.array("f", (v for vector in vectors for v in vector))
+    if sys.byteorder != "little":
+        data.byteswap()
+    with open(path, "wb") as f:
+        f.write(b"\x93NUMPY\x01\x00")
+        f.write(len(header_bytes).to_bytes(2, "little"))
+        f.write(header_bytes)
+        f.write(data.tobytes())
+
+
+def read_npy(path: Path) -> list[list[float]]:
+    """Read a 2-D little-endian float32 ``.npy`` file as written by this module.
+
+    Raises:
+        EmbeddingError: If the file is not a float32 matrix in NumPy format.
+    """
+    with open(path, "rb") as f:
+        if f.read(6) != b"\x93NUMPY":
+            raise EmbeddingError(f"{path} is not a NumPy .npy file")
+        major = f.read(2)[0]
+        header_len = int.from_bytes(f.read(2 if major == 1 else 4), "little")
+        header = f.read(header_len).decode("latin1")
+        data = f.read()
+    match = re.search(r"'shape':\s*\((\d+),\s*(\d+)\)", header)
+    if "'<f4'" not in header or "'fortran_order': False" not in header or not match:
+        raise EmbeddingError(f"{path} is not a C-ordered float32 matrix")
+    rows, cols = int(match.group(1)), int(match.group(2))
+    values = array.array("f")
+    values.frombytes(data[: rows * cols * 4])
+    if sys.byteorder != "little":
+        values.byteswap()
+    return [values[i * cols : (i + 1) * cols].tolist() for i in range(rows)]
+
+
+def _write_metadata(directory: Path, records: list[Chunk]) -> None:
+    with open(directory / "metadata.jsonl", "w", encoding="utf-8") as f:
+        for record in records:
+            f.write(json.dumps(_metadata(record), ensure_ascii=False) + "\n")
+
+
+def _export_faiss(directory: Path, vectors: list[list[float]], records: list[Chunk]) -> None:
+    try:
+        import faiss  # type: ignore[import-not-found]
+        import numpy as np
+    except ImportError as e:
+        raise EmbeddingError("The faiss store needs 'faiss-cpu' (pip install faiss-cpu)") from e
+    matrix = np.asarray(vectors, dtype="float32")
+    index = faiss.IndexFlatIP(matrix.shape[1])
+    index.add(matrix)
+    faiss.write_index(index, str(directory / "index.faiss"))
+    _write_metadata(directory, records)
+
+
+def _scalar_metadata(record: Chunk) -> dict[str, Any]:
+    """Flatten list fields, since Chroma and Qdrant filters work best on scalars."""
+    data = _metadata(record)
+    data.pop("content", None)
+    for key in ("parent_context", "declarations"):
+        data[key] = " > ".join(data[key]) if key == "parent_context" else ", ".join(data[key])
+    return data
+
+
+def _export_chroma(
+    directory: Path, vectors: list[list[float]], records: list[Chunk], collection_name: str
+) -> None:
+    try:
+        import chromadb  # type: ignore[import-not-found]
+    except ImportError as e:
+        raise EmbeddingError("The chroma store needs 'chromadb' (pip install chromadb)") from e
+    client = chromadb.PersistentClient(path=str(directory))
+    collection = client.get_or_create_collection(collection_name, metadata={"hnsw:space": "cosine"})
+    batch = 1000
+    for start in range(0, len(records), batch):
+        part = records[start : start + batch]
+        collection.upsert(
+            ids=[r.chunk_id for r in part],
+            embeddings=vectors[start : start + batch],
+            documents=[r.content for r in part],
+            metadatas=[_scalar_metadata(r) for r in part],
+        )
+
+
+def _export_qdrant(
+    location: str, vectors: list[list[float]], records: list[Chunk], collection_name: str
+) -> None:
+    try:
+        from qdrant_client import QdrantClient, models  # type: ignore[import-not-found]
+    except ImportError as e:
+        raise EmbeddingError(
+            "The qdrant store needs 'qdrant-client' (pip install qdrant-client)"
+        ) from e
+    if location.startswith(("http://", "https://")):
+        client = QdrantClient(url=location)
+    else:
+        client = QdrantClient(path=location)
+    if client.collection_exists(collection_name):
+        client.delete_collection(collection_name)
+    client.create_collection(
+        collection_name,
+        vectors_config=models.VectorParams(size=len(vectors[0]), distance=models.Distance.COSINE),
+    )
+    points = [
+        models.PointStruct(
+            id=str(uuid.uuid5(uuid.NAMESPACE_URL, record.chunk_id)),
+            vector=vector,
+            payload={**_scalar_metadata(record), "content": record.content},
+        )
+        for record, vector in zip(records, vectors, strict=True)
+    ]
+    for start in range(0, len(points), 256):
+        client.upsert(collection_name, points=points[start : start + 256])
+
+
+@dataclass
+class EmbeddingExport:
+    """Result of an embedding export.
+
+    Attributes:
+        count: Number of records embedded.
+        dimensions: Vector dimensionality.
+        store: Vector store written.
+        location: Output directory or server URL.
+    """
+
+    count: int
+    dimensions: int
+    store: str
+    location: str
+
+
+def export_embeddings(
+    records: list[Chunk],
+    vectors: list[list[float]],
+    store: str,
+    location: str,
+    embedder: Embedder,
+    units: str = "chunks",
+    collection_name: str = "codeconcat",
+) -> EmbeddingExport:
+    """Write vectors and metadata to the selected store.
+
+    Args:
+        records: Embedded records, aligned with ``vectors``.
+        vectors: One vector per record.
+        store: One of :data:`VECTOR_STORES`.
+        location: Output directory (or Qdrant server URL).
+        embedder: Backend used, recorded in the manifest.
+        units: Record granularity, recorded in the manifest.
+        collection_name: Collection name for Chroma and Qdrant.
+
+    Raises:
+        EmbeddingError: For unknown stores or missing store packages.
+    """
+    if store not in VECTOR_STORES:
+        raise EmbeddingError(
+            f"Unknown vector store '{store}' (choose from {', '.join(VECTOR_STORES)})"
+        )
+    if len(records) != len(vectors):
+        raise EmbeddingError(f"Got {len(vectors)} vectors for {len(records)} records")
+    dimensions = len(vectors[0]) if vectors else 0
+    is_url = location.startswith(("http://", "https://"))
+
+    directory = Path(location)
+    if not is_url:
+        directory.mkdir(parents=True, exist_ok=True)
+
+    if records:
+        if store == "npy":
+            _write_npy(directory / "embeddings.npy", vectors)
+            _write_metadata(directory, records)
+        elif store == "faiss":
+            _export_faiss(directory, vectors, records)
+        elif store == "chroma":
+            _export_chroma(directory, vectors, records, collection_name)
+        else:
+            _export_qdrant(location, vectors, records, collection_name)
+
+    if not is_url:
+        manifest = {
+            "backend": embedder.name,
+            "model": embedder.model,
+            "dimensions": dimensions,
+            "count": len(records),
+            "units": units,
+            "store": store,
+            "collection": collection_name if store in ("chroma", "qdrant") else None,
+        }
+        (directory / "manifest.json").write_text(json.dumps(manifest, indent=2), encoding="utf-8")
+
+    return EmbeddingExport(len(records), dimensions, store, location)
+
+
+def default_embed_location(config: CodeConCatConfig) -> str:
+    """Return ``embed_output`` or a directory named after the main output file."""
+    if config.embed_output:
+        return config.embed_output
+    base, _ext = os.path.splitext(config.output or "codeconcat_output")
+    return f"{base}.embeddings"
+
+
+def run_embedding_stage(items: list[WritableItem], config: CodeConCatConfig) -> EmbeddingExport:
+    """Chunk or split the items, embed them and export to the configured store.
+
+    Raises:
+        EmbeddingError: If the backend or store is unavailable or a request fails.
+    """
+    if config.embed_units not in EMBEDDING_UNITS:
+        raise EmbeddingError(
+            f"Unknown embedding unit '{config.embed_units}' "
+            f"(choose from {', '.join(EMBEDDING_UNITS)})"
+        )
+    root = config.target_path if os.path.isdir(config.target_path or "") else None
+    if config.embed_units == "declarations":
+        records = declaration_records(items, root)
+    else:
+        records = chunk_items(items, config.chunk_max_tokens, config.chunk_overlap_tokens, root)
+
+    embedder = create_embedder(config)
+    vectors: list[list[float]] = []
+    batch = max(1, config.embed_batch_size)
+    for start in range(0, len(records), batch):
+        vectors.extend(embedder.embed([embedding_text(r) for r in records[start : start + batch]]))
+        logger.debug(f"Embedded {min(start + batch, len(records))}/{len(records)} records")
+
+    return export_embeddings(
+        records,
+        vectors,
+        config.embed_store,
+        default_embed_location(config),
+        embedder,
+        units=config.embed_units,
+        collection_name=config.embed_collection,
+    )
@@ -0,0 +1,290 @@
+"""Heuristic project overview used when no AI meta-overview is available.
+
+The AI meta-overview synthesizes per-file summaries into a project-level
+description. When summarization is disabled, the provider is unavailable or
+the request fails, this module builds a structured overview from what is
+already known about the repository:
+
+- **Purpose**: the first prose paragraph of the README, or the description
+  from ``pyproject.toml`` / ``package.json`` / ``Cargo.toml``
+- **Architecture**: top-level directories with file counts and languages
+- **Key modules**: entry points and the files with the most declarations
+- **Tech stack**: languages by size, build manifests and frameworks
+  detected from imports
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import os
+import re
+from collections import Counter, defaultdict
+from pathlib import Path
+
+from ..base_types import ParsedFileData
+
+logger = logging.getLogger(__name__)
+
+_README_NAMES = ("README.md", "README.rst", "README.txt", "README")
+
+# Manifest file -> ecosystem label
+_MANIFESTS = {
+    "pyproject.toml": "Python (pyproject)",
+    "setup.py": "Python (setuptools)",
+    "requirements.txt": "Python (pip requirements)",
+    "package.json": "Node.js (npm)",
+    "Cargo.toml": "Rust (Cargo)",
+    "go.mod": "Go modules",
+    "pom.xml": "Java (Maven)",
+    "build.gradle": "JVM (Gradle)",
+    "build.gradle.kts": "JVM (Gradle)",
+    "Gemfile": "Ruby (Bundler)",
+    "composer.json": "PHP (Composer)",
+    "mix.exs": "Elixir (Mix)",
+    "DESCRIPTION": "R package",
+    "Project.toml": "Julia package",
+    "CMakeLists.txt": "C/C++ (CMake)",
+    "Dockerfile": "Docker",
+    "docker-compose.yml": "Docker Compose",
+    "docker-compose.yaml": "Docker Compose",
+}
+
+# Top-level import name -> framework label
+_FRAMEWORKS = {
+    "django": "Django",
+    "flask": "Flask",
+    "fastapi": "FastAPI",
+    "pydantic": "Pydantic",
+    "typer": "Typer",
+    "click": "Click",
+    "sqlalchemy": "SQLAlchemy",
+    "pytest": "pytest",
+    "numpy": "NumPy",
+    "pandas": "pandas",
+    "torch": "PyTorch",
+    "tensorflow": "TensorFlow",
+    "react": "React",
+    "vue": "Vue",
+    "svelte": "Svelte",
+    "next": "Next.js",
+    "express": "Express",
+    "@angular/core": "Angular",
+    "@nestjs/core": "NestJS",
+    "org.springframework": "Spring",
+    "github.com/gin-gonic/gin": "Gin",
+    "tokio": "Tokio",
+    "actix_web": "Actix Web",
+    "serde": "Serde",
+    "rails": "Rails",
+    "laravel": "Laravel",
+}
+
+# Declaration kinds listed for key modules (variables and imports are noise here)
+_MODULE_KINDS = frozenset(
+    {"function", "method", "class", "struct", "interface", "trait", "enum", "module"}
+)
+
+# File stems that usually mark an entry point
+_ENTRY_POINT_STEMS = frozenset(
+    {"main", "__main__", "app", "cli", "server", "index", "manage", "wsgi", "asgi"}
+)
+
+_IMPORT_NAME = re.compile(
+    r"""^(?:from\s+|import\s+|use\s+|require\(?\s*['"]?)?['"]?([\w@./-]+)"""
+)
+
+
+def _read(path: Path, limit: int = 200_000) -> str | None:
+    try:
+        with open(path, encoding="utf-8", errors="replace") as f:
+            return f.read(limit)
+    except OSError:
+        return None
+
+
+def _readme_paragraph(text: str) -> str | None:
+    """Return the first prose paragraph of a README, skipping headings and badges."""
+    for block in re.split(r"\n\s*\n", text):
+        lines = [line.strip() for line in block.strip().splitlines()]
+        lines = [
+            line
+            for line in lines
+            if line
+            and not line.startswith(("#", "![", "[![", "<", "=", "```", "|", ".. "))
+            and not re.match(r"([-*+]|\d+\.)\s", line)
+            and not re.fullmatch(r"[=\-~^*]+", line)
+        ]
+        if lines and len(" ".join(lines)) >= 20:
+            return " ".join(lines)
+    return None
+
+
+def _manifest_description(root: Path) -> str | None:
+    text = _read(root / "package.json")
+    if text:
+        try:
+            description = json.loads(text).get("description")
+            if description:
+                return str(description)
+        except (ValueError, AttributeError):
+            pass
+    for name in ("pyproject.toml", "Cargo.toml"):
+        text = _read(root / name)
+        if text:
+            match = re.search(r'^description\s*=\s*["\'](.+?)["\']\s*$', text, re.MULTILINE)
+            if match:
+                return match.group(1)
+    return None
+
+
+def _project_purpose(files: list[ParsedFileData], root: Path | None) -> str | None:
+    for file_data in files:
+        if os.path.basename(file_data.file_path) in _README_NAMES and file_data.content:
+            paragraph = _readme_paragraph(file_data.content)
+            if paragraph:
+                return paragraph
+    if root is None:
+        return None
+    for name in _README_NAMES:
+        text = _read(root / name)
+        if text:
+            paragraph = _readme_paragraph(text)
+            if paragraph:
+                return paragraph
+    return _manifest_description(root)
+
+
+def _relative(file_path: str, root: Path | None) -> Path:
+    if root is not None:
+        try:
+            return Path(os.path.relpath(file_path, root))
+        except ValueError:
+            pass
+    return Path(file_path)
+
+
+def _common_root(files: list[ParsedFileData]) -> Path | None:
+    try:
+        return Path(os.path.commonpath([os.path.dirname(f.file_path) for f in files]))
+    except ValueError:
+        return None
+
+
+def _first_sentence(text: str, max_chars: int = 140) -> str:
+    text = " ".join(text.split())
+    end = text.find(". ")
+    if 0 < end < max_chars:
+        return text[: end + 1]
+    return text if len(text) <= max_chars else text[: max_chars - 1].rstrip() + "…"
+
+
+def _detect_frameworks(files: list[ParsedFileData]) -> list[str]:
+    found: Counter[str] = Counter()
+    for file_data in files:
+        for imp in file_data.imports or ():
+            match = _IMPORT_NAME.match(imp.strip())
+            if not match:
+                continue
+            name = match.group(1).lower()
+            for key, label in _FRAMEWORKS.items():
+                if name == key or name.startswith((key + ".", key + "/", key + "::")):
+                    found[label] += 1
+    return [label for label, _ in found.most_common(10)]
+
+
+def build_heuristic_overview(
+    files: list[ParsedFileData], root_path: str | None = None, max_modules: int = 10
+) -> str:
+    """Build a structured project overview without calling an AI provider.
+
+    Args:
+        files: Parsed files included in the run.
+        root_path: Repository root used for README/manifest lookup and
+            relative paths. Ignored if it is not a directory.
+        max_modules: Maximum number of key modules to list.
+
+    Returns:
+        Markdown with Purpose, Architecture, Key Modules and Tech Stack sections.
+    """
+    root = Path(root_path) if root_path and os.path.isdir(root_path) else None
+    base = root or _common_root(files)
+    sections: list[str] = []
+
+    purpose = _project_purpose(files, root)
+    sections.append("### Purpose\n")
+    sections.append(purpose or "No README or package description found.")
+
+    # Architecture: group files by directory below the shared prefix (so a
+    # single package directory is broken down into its subpackages)
+    rel_parts = [_relative(f.file_path, base).parts for f in files]
+    prefix_len = 0
+    dir_parts = [p[:-1] for p in rel_parts]
+    while dir_parts and all(
+        len(d) > prefix_len and d[prefix_len] == dir_parts[0][prefix_len] for d in dir_parts
+    ):
+        prefix_len += 1
+    directories: dict[str, list[ParsedFileData]] = defaultdict(list)
+    for file_data, parts in zip(files, rel_parts, strict=True):
+        if len(parts) > prefix_len + 1 and parts[0] not in (".", ".."):
+            key = "/".join(parts[: prefix_len + 1]) + "/"
+        else:
+            key = "/".join(parts[:prefix_len]) + "/" if prefix_len else "(root)"
+        directories[key].append(file_data)
+
+    sections.append("\n### Architecture\n")
+    for directory, members in sorted(directories.items(), key=lambda kv: (-len(kv[1]), kv[0])):
+        languages = Counter(f.language for f in members if f.language)
+        language_text = ", ".join(lang for lang, _ in languages.most_common(3))
+        line = f"- `{directory}`: {len(members)} file{'s' if len(members) != 1 else ''}"
+        if language_text:
+            line += f" ({language_text})"
+        sections.append(line)
+
+    # Key modules: entry points first, then by declaration count
+    def score(file_data: ParsedFileData) -> tuple[int, int]:
+        stem = Path(file_data.file_path).stem.lower()
+        declared = sum(1 for d in file_data.declarations or () if d.kind in _MODULE_KINDS)
+        return (1 if stem in _ENTRY_POINT_STEMS else 0, declared)
+
+    ranked = [f for f in sorted(files, key=score, reverse=True) if any(score(f))][:max_modules]
+    if ranked:
+        sections.append("\n### Key Modules\n")
+        for file_data in ranked:
+            rel = _relative(file_data.file_path, base).as_posix()
+            if file_data.ai_summary:
+                detail = _first_sentence(file_data.ai_summary)
+            else:
+                names = [
+                    d.name
+                    for d in file_data.declarations or ()
+                    if d.kind in _MODULE_KINDS and d.name and not d.name.startswith("_")
+                ][:5]
+                detail = "defines " + ", ".join(f"`{n}`" for n in names) if names else ""
+            if Path(file_data.file_path).stem.lower() in _ENTRY_POINT_STEMS:
+                detail = "entry point" + ("; " + detail if detail else "")
+            sections.append(f"- `{rel}`" + (f": {detail}" if detail else ""))
+
+    # Tech stack
+    sections.append("\n### Tech Stack\n")
+    loc: Counter[str] = Counter()
+    for file_data in files:
+        if file_data.language:
+            loc[file_data.language] += len((file_data.content or "").splitlines())
+    if loc:
+        total = sum(loc.values()) or 1
+        sections.append(
+            "- Languages: "
+            + ", ".join(f"{lang} ({count / total:.0%})" for lang, count in loc.most_common(6))
+        )
+    if root is not None:
+        manifests = sorted({label for name, label in _MANIFESTS.items() if (root / name).exists()})
+        if (root / ".github" / "workflows").is_dir():
+            manifests.append("GitHub Actions")
+        if manifests:
+            sections.append("- Build & tooling: " + ", ".join(manifests))
+    frameworks = _detect_frameworks(files)
+    if frameworks:
+        sections.append("- Frameworks & libraries: " + ", ".join(frameworks))
+
+    return "\n".join(sections)
@@ -0,0 +1,348 @@
+"""Statistical prompt compression for comments and docstrings.
+
+A final squeeze for runs that are about to exceed their token budget. Code is
+left untouched; only natural-language prose inside comments and docstrings
+is pruned, in the spirit of LLMLingua / Selective Context: the least
+informative words are dropped until the requested fraction remains.
+
+Two backends are available:
+
+- ``self-information`` (default, no dependencies): scores each word by its
+  self-information ``-log p(w)`` estimated from word frequencies across all
+  prose in the run, so boilerplate words that appear everywhere are removed
+  first. Identifiers, numbers, tags such as ``@param`` and inline code are
+  always kept.
+- ``llmlingua``: uses the optional ``llmlingua`` package for
+  perplexity-based pruning with a small language model. Falls back to
+  ``self-information`` when the package is not installed.
+"""
+
+from __future__ import annotations
+
+import ast
+import io
+import logging
+import math
+import re
+import tokenize
+from collections import Counter
+from collections.abc import Callable, Iterable
+from dataclasses import dataclass
+
+from codeconcat.parser.language_parsers.pattern_library import CommentPatterns
+
+logger = logging.getLogger(__name__)
+
+# Languages whose docstrings are triple-quoted string literals
+_DOCSTRING_LANGUAGES = frozenset({"python"})
+
+# Extra line-comment markers not covered by CommentPatterns.SINGLE_LINE
+_EXTRA_LINE_MARKERS = {"rust": ("//",), "typescript": ("//",), "c": ("//",), "kotlin": ("//",)}
+_EXTRA_BLOCK_MARKERS = {
+    "typescript": ("/*", "*/"),
+    "c": ("/*", "*/"),
+    "kotlin": ("/*", "*/"),
+    "swift": ("/*", "*/"),
+}
+
+# Words carrying almost no information in technical prose
+_STOPWORDS = frozenset(
+    """a an the this that these those is are was were be been being of to in on at by for
+    with from as it its and or but if then so such which who whom whose there here we you
+    they i he she them us our your their will would should can could may might must do does
+    did just also very really simply basically actually""".split()
+)
+
+# Words matching this are never dropped (identifiers, numbers, tags, inline code, URLs)
+_PROTECTED = re.compile(r"[_`@#\d()\[\]{}<>=/\\:.]|^[A-Z][a-z]+[A-Z]|^[A-Z]{2,}")
+
+_WORD_SPLIT = re.compile(r"(\s+)")
+
+
+@dataclass
+class ProseSpan:
+    """Prose text located within a single line.
+
+    Attributes:
+        line: 0-based line index.
+        start: Column where the prose starts (after comment markers).
+        end: Column where the prose ends (before closing markers).
+    """
+
+    line: int
+    start: int
+    end: int
+
+
+def find_prose_spans(content: str, language: str | None) -> list[ProseSpan]:
+    """Locate comment and docstring prose in ``content``.
+
+    Only full-line comments, block comments and docstrings are considered;
+    trailing comments after code are left alone to avoid touching string
+    literals. Python is tokenized, so only real comments and the string
+    literals that open a module, class or function count as prose.
+
+    Args:
+        content: Source code.
+        language: Language identifier used to pick comment markers.
+
+    Returns:
+        Spans in line order.
+    """
+    language = (language or "").lower()
+    if language in _DOCSTRING_LANGUAGES:
+        return _python_prose_spans(content)
+
+    line_markers = [m for m in (CommentPatterns.SINGLE_LINE.get(language),) if m]
+    line_markers.extend(_EXTRA_LINE_MARKERS.get(language, ()))
+    line_markers = sorted({m.replace("\\", "") for m in line_markers}, key=len, reverse=True)
+
+    block = CommentPatterns.BLOCK_COMMENT.get(language)
+    block_markers = (
+        (block[0].replace("\\", ""), block[1].replace("\\", ""))
+        if block
+        else _EXTRA_BLOCK_MARKERS.get(language)
+    )
+
+    spans: list[ProseSpan] = []
+    lines = content.split("\n")
+    closing: str | None = None
+
+    for idx, line in enumerate(lines):
+        stripped = line.lstrip()
+        indent = len(line) - len(stripped)
+
+        if closing is not None:
+            end_pos = line.find(closing)
+            if end_pos == -1:
+                start = indent + (2 if stripped.startswith("* ") else 0)
+                spans.append(ProseSpan(idx, start, len(line)))
+            else:
+                spans.append(ProseSpan(idx, indent, end_pos))
+                closing = None
+            continue
+
+        if block_markers and stripped.startswith(block_markers[0]):
+            body_start = indent + len(block_markers[0])
+            while body_start < len(line) and line[body_start] in "*!":
+                body_start += 1
+            end_pos = line.find(block_markers[1], body_start)
+            if end_pos == -1:
+                spans.append(ProseSpan(idx, body_start, len(line)))
+                closing = block_markers[1]
+            else:
+                spans.append(ProseSpan(idx, body_start, end_pos))
+            continue
+
+        for marker in line_markers:
+            if stripped.startswith(marker):
+                body_start = indent + len(marker)
+                while body_start < len(line) and line[body_start] in "/!#":
+                    body_start += 1
+                spans.append(ProseSpan(idx, body_start, len(line)))
+                break
+
+    return [s for s in spans if lines[s.line][s.start : s.end].strip()]
+
+
+def _docstring_starts(tree: ast.AST, lines: list[str]) -> set[tuple[int, int]]:
+    """Return the (1-based row, column) where each docstring literal starts."""
+    starts = set()
+    for node in ast.walk(tree):
+        if not isinstance(
+            node, (ast.Module, ast.ClassDef, ast.FunctionDef, ast.AsyncFunctionDef)
+        ):
+            continue
+        first = node.body[0] if node.body else None
+        if (
+            isinstance(first, ast.Expr)
+            and isinstance(first.value, ast.Constant)
+            and isinstance(first.value.value, str)
+        ):
+            # ast columns count UTF-8 bytes, tokenize columns count characters
+            line = lines[first.lineno - 1]
+            col = len(line.encode("utf-8")[: first.col_offset].decode("utf-8", "replace"))
+            starts.add((first.lineno, col))
+    return starts
+
+
+def _python_prose_spans(content: str) -> list[ProseSpan]:
+    """Locate full-line comments and docstrings in Python source.
+
+    Source that does not parse yields no spans, so it is left untouched.
+    """
+    lines = content.split("\n")
+    try:
+        tree = ast.parse(content)
+        tokens = list(tokenize.generate_tokens(io.StringIO(content).readline))
+    except (SyntaxError, ValueError, tokenize.TokenError):
+        return []
+    docstrings = _docstring_starts(tree, lines)
+
+    spans: list[ProseSpan] = []
+    for token in tokens:
+        (start_row, start_col), (end_row, end_col) = token.start, token.end
+        if token.type == tokenize.COMMENT:
+            line = lines[start_row - 1]
+            if line[:start_col].strip():
+                continue  # Trailing comment after code
+            body_start = start_col + 1
+            while body_start < len(line) and line[body_start] in "/!#":
+                body_start += 1
+            spans.append(ProseSpan(start_row - 1, body_start, len(line)))
+        elif token.type == tokenize.STRING and (start_row, start_col) in docstrings:
+            prefix = len(token.string) - len(token.string.lstrip("rRuUbBfF"))
+            quote = 3 if token.string[prefix : prefix + 3] in ('"""', "'''") else 1
+            for row in range(start_row, end_row + 1):
+                line = lines[row - 1]
+                if row == start_row:
+                    start = start_col + prefix + quote
+                else:
+                    start = len(line) - len(line.lstrip())
+                end = end_col - quote if row == end_row else len(line)
+                spans.append(ProseSpan(row - 1, start, end))
+
+    return [s for s in spans if lines[s.line][s.start : s.end].strip()]
+
+
+def _normalize(word: str) -> str:
+    return word.strip(",;:!?.\"'").lower()
+
+
+class SelfInformationPruner:
+    """Drop low self-information words from prose, keeping a target ratio."""
+
+    def __init__(self, corpus: Iterable[str] = ()):
+        """Initialize with prose used to estimate word frequencies."""
+        self.counts: Counter[str] = Counter()
+        for text in corpus:
+            self.counts.update(_normalize(w) for w in text.split() if w.strip())
+        self.total = sum(self.counts.values())
+
+    def information(self, word: str) -> float:
+        """Return the self-information of ``word`` in bits (higher = more informative)."""
+        if _PROTECTED.search(word.strip(",;:!?.\"'")):
+            return math.inf
+        key = _normalize(word)
+        if not key or key in _STOPWORDS:
+            return 0.0
+        vocab = len(self.counts) + 1
+        p = (self.counts.get(key, 0) + 1) / (self.total + vocab)
+        return -math.log2(p)
+
+    def prune(self, text: str, keep_ratio: float) -> str:
+        """Prune ``text`` so roughly ``keep_ratio`` of its words remain.
+
+        Word order and leading/trailing whitespace are preserved; protected
+        words are always kept even if that exceeds the ratio.
+        """
+        parts = _WORD_SPLIT.split(text)
+        word_positions = [i for i, p in enumerate(parts) if p and not p.isspace()]
+        keep_count = max(1, math.ceil(len(word_positions) * keep_ratio))
+        if keep_count >= len(word_positions):
+            return text
+
+        ranked = sorted(
+            word_positions, key=lambda i: (self.information(parts[i]), -i), reverse=True
+        )
+        keep = set(ranked[:keep_count])
+        keep.update(i for i in word_positions if self.information(parts[i]) == math.inf)
+
+        result = [parts[i] for i in word_positions if i in keep]
+        leading = text[: len(text) - len(text.lstrip())]
+        trailing = text[len(text.rstrip()) :]
+        return leading + " ".join(result) + trailing
+
+
+def _llmlingua_pruner() -> Callable[[str, float], str] | None:
+    try:
+        from llmlingua import PromptCompressor as LinguaCompressor  # type: ignore[import-not-found]
+    except ImportError:
+        return None
+
+    compressor = LinguaCompressor()
+
+    def prune(text: str, keep_ratio: float) -> str:
+        return compressor.compress_prompt(text, rate=keep_ratio)["compressed_prompt"]
+
+    return prune
+
+
+@dataclass
+class PromptCompressionResult:
+    """Statistics from a prompt compression pass.
+
+    Attributes:
+        files_changed: Number of files whose prose was pruned.
+        words_before: Prose words before pruning.
+        words_after: Prose words after pruning.
+    """
+
+    files_changed: int = 0
+    words_before: int = 0
+    words_after: int = 0
+
+
+class PromptCompressor:
+    """Apply statistical pruning to comment and docstring prose across files."""
+
+    def __init__(self, keep_ratio: float = 0.5, backend: str = "self-information"):
+        """Initialize the compressor.
+
+        Args:
+            keep_ratio: Fraction of prose words to keep (0 < ratio <= 1).
+            backend: ``"self-information"`` or ``"llmlingua"``.
+
+        Raises:
+            ValueError: If ``keep_ratio`` is out of range.
+        """
+        if not 0 < keep_ratio <= 1:
+            raise ValueError("keep_ratio must be in (0, 1]")
+        self.keep_ratio = keep_ratio
+        self.backend = backend
+
+    def compress(self, files: list) -> PromptCompressionResult:
+        """Prune prose in place on objects with ``content`` and ``language`` attributes.
+
+        Args:
+            files: ParsedFileData-like objects; their ``content`` is replaced.
+
+        Returns:
+            Aggregate statistics for the pass.
+        """
+        located = []
+        corpus = []
+        for file_data in files:
+            if not file_data.content:
+                continue
+            spans = find_prose_spans(file_data.content, file_data.language)
+            if spans:
+                lines = file_data.content.split("\n")
+                located.append((file_data, lines, spans))
+                corpus.extend(lines[s.line][s.start : s.end] for s in spans)
+
+        pruner: Callable[[str, float], str] | None = None
+        if self.backend == "llmlingua":
+            pruner = _llmlingua_pruner()
+            if pruner is None:
+                logger.warning("llmlingua is not installed; using self-information pruning")
+        if pruner is None:
+            pruner = SelfInformationPruner(corpus).prune
+
+        result = PromptCompressionResult()
+        for file_data, lines, spans in located:
+            changed = False
+            for span in reversed(spans):
+                line = lines[span.line]
+                prose = line[span.start : span.end]
+                pruned = pruner(prose, self.keep_ratio)
+                result.words_before += len(prose.split())
+                result.words_after += len(pruned.split())
+                if pruned != prose:
+                    lines[span.line] = line[: span.start] + pruned + line[span.end :]
+                    changed = True
+            if changed:
+                file_data.content = "\n".join(lines)
+                result.files_changed += 1
+
+        return result
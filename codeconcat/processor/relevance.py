@@ -0,0 +1,191 @@
+"""Task-aware relevance ranking.
+
+With ``--task``, the task description, every file and every declaration are
+embedded with the configured embedding backend (see
+:mod:`codeconcat.processor.embeddings`). A file scores the best cosine
+similarity of the file itself or any of its declarations. The ranking then:
+
+- orders the output, most relevant file first (instead of ``sort_files``);
+- spends ``token_budget``, when set, in rank order: files that fit are kept as
+  they are, the next ones are compressed at the aggressive level, and files
+  that do not fit even compressed are left out.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from ..base_types import CodeConCatConfig, WritableItem
+from .chunker import relative_file_path
+from .compression_processor import CompressionProcessor
+from .embeddings import (
+    EmbeddingError,
+    HashingEmbedder,
+    cosine_similarity,
+    create_embedder,
+    declaration_records,
+    embedding_text,
+)
+from .token_counter import count_tokens
+
+logger = logging.getLogger(__name__)
+
+# Leading characters of a file embedded as its file-level record
+_FILE_RECORD_CHARS = 4_000
+
+
+@dataclass
+class FileRelevance:
+    """Relevance of one file to the task.
+
+    Attributes:
+        file_path: Path of the file as collected.
+        score: Best cosine similarity of the file or one of its declarations.
+        best_match: Qualified name of the best-matching declaration, or "" when
+            the file-level text matched best.
+    """
+
+    file_path: str
+    score: float
+    best_match: str = ""
+
+
+@dataclass
+class BudgetResult:
+    """Outcome of fitting ranked items into the token budget.
+
+    Attributes:
+        kept: Paths kept unchanged.
+        compressed: Paths compressed to fit.
+        dropped: Paths left out of the output.
+        tokens: Content tokens of the kept and compressed items.
+    """
+
+    kept: list[str] = field(default_factory=list)
+    compressed: list[str] = field(default_factory=list)
+    dropped: list[str] = field(default_factory=list)
+    tokens: int = 0
+
+
+def _embedder_for_ranking(config: CodeConCatConfig):
+    """Return the configured embedder, or the offline hashing embedder if it is unavailable."""
+    try:
+        return create_embedder(config)
+    except EmbeddingError as e:
+        logger.warning(f"Task ranking uses the hashing embedder: {e}")
+        return HashingEmbedder()
+
+
+def rank_by_task(
+    task: str, items: list[WritableItem], config: CodeConCatConfig, embedder=None
+) -> list[FileRelevance]:
+    """Score every item against ``task``, most relevant first.
+
+    Args:
+        task: Natural-language description of the task.
+        items: Files and documents to rank.
+        config: Configuration selecting the embedding backend and batch size.
+        embedder: Embedder to use instead of the configured one.
+
+    Returns:
+        One entry per item, sorted by descending score.
+    """
+    root_path = config.target_path if os.path.isdir(config.target_path or "") else None
+    root = Path(root_path).resolve() if root_path else None
+    by_relative = {relative_file_path(item.file_path, root): item.file_path for item in items}
+
+    # (original path, declaration name or "", text)
+    records: list[tuple[str, str, str]] = [
+        (
+            item.file_path,
+            "",
+            f"{relative_file_path(item.file_path, root)}\n"
+            f"{(getattr(item, 'content', '') or '')[:_FILE_RECORD_CHARS]}",
+        )
+        for item in items
+    ]
+    for record in declaration_records(items, root_path):
+        path = by_relative.get(record.file_path, record.file_path)
+        name = record.chunk_id.split("#", 1)[-1]
+        records.append((path, name, embedding_text(record)))
+
+    embedder = embedder or _embedder_for_ranking(config)
+    task_vector = embedder.embed([task])[0]
+    batch = max(1, config.embed_batch_size)
+    scores: dict[str, FileRelevance] = {
+        item.file_path: FileRelevance(item.file_path, -1.0) for item in items
+    }
+    for start in range(0, len(records), batch):
+        chunk = records[start : start + batch]
+        vectors = embedder.embed([text for _path, _name, text in chunk])
+        for (path, name, _text), vector in zip(chunk, vectors, strict=True):
+            score = cosine_similarity(task_vector, vector)
+            if score > scores[path].score:
+                scores[path] = FileRelevance(path, score, name)
+
+    return sorted(scores.values(), key=lambda r: (-r.score, r.file_path))
+
+
+def order_by_relevance(items: list[WritableItem], ranking: list[FileRelevance]) -> None:
+    """Sort ``items`` in place by their rank; unranked items go last in their current order."""
+    position = {r.file_path: i for i, r in enumerate(ranking)}
+    items.sort(key=lambda item: position.get(item.file_path, len(position)))
+
+
+def fit_to_budget(
+    items: list[WritableItem], budget: int, config: CodeConCatConfig
+) -> tuple[list[WritableItem], BudgetResult]:
+    """Keep ranked items within ``budget`` content tokens, compressing or dropping the tail.
+
+    ``items`` must already be in rank order. The first files are kept as
+    they are while they fit; after that each file is compressed at the
+    aggressive level, and left out if it still does not fit. Smaller, later
+    files may still fit after a large one is dropped.
+
+    Returns:
+        The items to write and a summary of what was kept, compressed and dropped.
+    """
+    result = BudgetResult()
+    compressor = CompressionProcessor(
+        config.model_copy(update={"enable_compression": True, "compression_level": "aggressive"})
+    )
+    fitted: list[WritableItem] = []
+    for item in items:
+        content = getattr(item, "content", "") or ""
+        tokens = count_tokens(content, "gpt-4")
+        if result.tokens + tokens <= budget:
+            fitted.append(item)
+            result.kept.append(item.file_path)
+            result.tokens += tokens
+            continue
+
+        compressed = None
+        if content and getattr(item, "declarations", None) is not None:
+            try:
+                compressed = compressor.apply_compression(item)  # type: ignore[arg-type]
+            except (AttributeError, TypeError, ValueError) as e:
+                logger.debug(f"Could not compress {item.file_path} for the budget: {e}")
+        compressed_tokens = count_tokens(compressed, "gpt-4") if compressed else 0
+        if compressed and compressed_tokens < tokens and (
+            result.tokens + compressed_tokens <= budget
+        ):
+            item.content = compressed
+            fitted.append(item)
+            result.compressed.append(item.file_path)
+            result.tokens += compressed_tokens
+        else:
+            result.dropped.append(item.file_path)
+    return fitted, result
+
+
+def describe_ranking(ranking: list[FileRelevance], limit: int = 10) -> list[str]:
+    """Return log lines for the top of the ranking."""
+    lines = []
+    for number, entry in enumerate(ranking[:limit], start=1):
+        match = f" ({entry.best_match})" if entry.best_match else ""
+        lines.append(f"  {number:>2}. {entry.score:.3f}  {entry.file_path}{match}")
+    return lines
+
@@ -0,0 +1,257 @@
+"""Retrieval and question answering over generated output.
+
+Backs the ``codeconcat ask`` command. Relevant chunks are found in one of two
+ways:
+
+- **Embedding index**: a directory written by ``--embed`` (see
+  :mod:`codeconcat.processor.embeddings`). The question is embedded with the
+  backend and model recorded in ``manifest.json`` and matched against the
+  stored vectors (npy, FAISS, Chroma or a local Qdrant path).
+- **Output file only**: the files inside a Markdown, JSON or XML output are
+  recovered, chunked and ranked in memory with the offline hashing embedder.
+
+The top chunks are numbered and placed in a prompt that asks the configured
+AI provider to answer from that context only and cite the sources it used.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import os
+import re
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any
+
+from ..ai.base import AIProvider, SummarizationResult
+from ..base_types import CodeConCatConfig, ParsedFileData
+from .chunker import Chunk, chunk_items
+from .embeddings import (
+    EmbeddingError,
+    HashingEmbedder,
+    cosine_similarity,
+    create_embedder,
+    read_npy,
+)
+
+logger = logging.getLogger(__name__)
+
+QA_SYSTEM_PROMPT = (
+    "You are a senior engineer answering questions about a codebase. Answer only from "
+    "the provided context. Cite the sources you rely on by their number, e.g. [2]. If the "
+    "context does not contain the answer, say so and suggest where to look instead."
+)
+
+# Rough cap on context placed in the prompt (characters; ~4 per token)
+_MAX_CONTEXT_CHARS = 48_000
+
+
+@dataclass
+class RetrievedChunk:
+    """A chunk returned by a search, with its similarity score.
+
+    Attributes:
+        chunk: The matching chunk.
+        score: Cosine similarity to the question (higher is better).
+    """
+
+    chunk: Chunk
+    score: float
+
+
+def _chunk_from_metadata(data: dict[str, Any], content: str | None = None) -> Chunk:
+    """Rebuild a chunk from ``metadata.jsonl`` rows or flattened store payloads."""
+    parent_context = data.get("parent_context") or []
+    declarations = data.get("declarations") or []
+    if isinstance(parent_context, str):
+        parent_context = parent_context.split(" > ")
+    if isinstance(declarations, str):
+        declarations = declarations.split(", ")
+    return Chunk(
+        chunk_id=str(data.get("chunk_id", "")),
+        file_path=str(data.get("file_path", "")),
+        language=str(data.get("language", "")),
+        start_line=int(data.get("start_line", 0) or 0),
+        end_line=int(data.get("end_line", 0) or 0),
+        content=content if content is not None else str(data.get("content", "")),
+        token_count=0,
+        parent_context=[p for p in parent_context if p],
+        declarations=[d for d in declarations if d],
+    )
+
+
+def _rank(
+    query: list[float], chunks: list[Chunk], vectors: list[list[float]], top_k: int
+) -> list[RetrievedChunk]:
+    scored = [
+        RetrievedChunk(chunk, cosine_similarity(query, vector))
+        for chunk, vector in zip(chunks, vectors, strict=True)
+    ]
+    scored.sort(key=lambda r: r.score, reverse=True)
+    return scored[:top_k]
+
+
+def _read_metadata(directory: Path) -> list[Chunk]:
+    with open(directory / "metadata.jsonl", encoding="utf-8") as f:
+        return [_chunk_from_metadata(json.loads(line)) for line in f if line.strip()]
+
+
+class EmbeddingIndex:
+    """Query side of an embedding directory written by ``--embed``."""
+
+    def __init__(self, directory: str | Path, config: CodeConCatConfig):
+        """Read the manifest and create the embedder it names.
+
+        Raises:
+            EmbeddingError: If the manifest is missing or the embedder is unavailable.
+        """
+        self.directory = Path(directory)
+        manifest_path = self.directory / "manifest.json"
+        try:
+            self.manifest: dict[str, Any] = json.loads(manifest_path.read_text(encoding="utf-8"))
+        except (OSError, ValueError) as e:
+            raise EmbeddingError(f"No readable manifest.json in {self.directory}: {e}") from e
+        self.store = self.manifest.get("store", "npy")
+        self.embedder = create_embedder(
+            config.model_copy(
+                update={
+                    "embed_backend": self.manifest.get("backend", config.embed_backend),
+                    "embed_model": self.manifest.get("model", config.embed_model),
+                }
+            )
+        )
+
+    def search(self, question: str, top_k: int = 8) -> list[RetrievedChunk]:
+        """Return the ``top_k`` chunks most similar to ``question``.
+
+        Raises:
+            EmbeddingError: If the store files are missing or its package is not installed.
+        """
+        if not self.manifest.get("count"):
+            return []
+        query = self.embedder.embed([question])[0]
+        try:
+            if self.store == "npy":
+                chunks = _read_metadata(self.directory)
+                return _rank(query, chunks, read_npy(self.directory / "embeddings.npy"), top_k)
+            if self.store == "faiss":
+                return self._search_faiss(query, top_k)
+            if self.store == "chroma":
+                return self._search_chroma(query, top_k)
+            if self.store == "qdrant":
+                return self._search_qdrant(query, top_k)
+        except OSError as e:
+            raise EmbeddingError(f"Could not read embedding index {self.directory}: {e}") from e
+        raise EmbeddingError(f"Unknown vector store '{self.store}' in {self.directory}")
+
+    def _search_faiss(self, query: list[float], top_k: int) -> list[RetrievedChunk]:
+        try:
+            import faiss  # type: ignore[import-not-found]
+            import numpy as np
+        except ImportError as e:
+            raise EmbeddingError("The faiss store needs 'faiss-cpu' (pip install faiss-cpu)") from e
+        index = faiss.read_index(str(self.directory / "index.faiss"))
+        chunks = _read_metadata(self.directory)
+        scores, ids = index.search(np.asarray([query], dtype="float32"), top_k)
+        return [
+            RetrievedChunk(chunks[i], float(s))
+            for i, s in zip(ids[0], scores[0], strict=True)
+            if 0 <= i < len(chunks)
+        ]
+
+    def _search_chroma(self, query: list[float], top_k: int) -> list[RetrievedChunk]:
+        try:
+            import chromadb  # type: ignore[import-not-found]
+        except ImportError as e:
+            raise EmbeddingError("The chroma store needs 'chromadb' (pip install chromadb)") from e
+        client = chromadb.PersistentClient(path=str(self.directory))
+        collection = client.get_collection(self.manifest.get("collection") or "codeconcat")
+        result = collection.query(query_embeddings=[query], n_results=top_k)
+        return [
+            RetrievedChunk(_chunk_from_metadata(meta or {}, doc or ""), 1.0 - float(distance))
+            for doc, meta, distance in zip(
+                result["documents"][0], result["metadatas"][0], result["distances"][0], strict=True
+            )
+        ]
+
+    def _search_qdrant(self, query: list[float], top_k: int) -> list[RetrievedChunk]:
+        try:
+            from qdrant_client import QdrantClient  # type: ignore[import-not-found]
+        except ImportError as e:
+            raise EmbeddingError(
+                "The qdrant store needs 'qdrant-client' (pip install qdrant-client)"
+            ) from e
+        client = QdrantClient(path=str(self.directory))
+        response = client.query_points(
+            self.manifest.get("collection") or "codeconcat", query=query, limit=top_k
+        )
+        return [
+            RetrievedChunk(_chunk_from_metadata(point.payload or {}), float(point.score))
+            for point in response.points
+        ]
+
+
+def chunks_from_output(output_file: str, max_tokens: int = 512) -> list[Chunk]:
+    """Recover the files inside a Markdown, JSON or XML output and chunk them.
+
+    Raises:
+        ValueError: If the output format cannot be determined or parsed.
+    """
+    from ..language_map import ext_map
+    from ..reconstruction import CodeConcatReconstructor
+
+    files = CodeConcatReconstructor(output_dir=".").parse_files(output_file)
+    items = [
+        ParsedFileData(path, content, ext_map.get(os.path.splitext(path)[1].lower(), ""))
+        for path, content in files.items()
+    ]
+    return chunk_items(items, max_tokens=max_tokens, overlap_tokens=max_tokens // 8)
+
+
+def search_chunks(question: str, chunks: list[Chunk], top_k: int = 8) -> list[RetrievedChunk]:
+    """Rank chunks in memory with the hashing embedder (no model or index needed)."""
+    if not chunks:
+        return []
+    embedder = HashingEmbedder()
+    vectors = embedder.embed([f"{c.context_header}\n{c.content}" for c in chunks])
+    return _rank(embedder.embed([question])[0], chunks, vectors, top_k)
+
+
+def find_latest_output(directory: str = ".") -> str | None:
+    """Return the newest auto-named output file (``ccc_codeconcat_*``) in ``directory``."""
+    candidates = [
+        os.path.join(directory, name)
+        for name in os.listdir(directory)
+        if re.fullmatch(r"ccc_codeconcat_.*\.(md|markdown|json|xml)", name)
+    ]
+    return max(candidates, key=os.path.getmtime) if candidates else None
+
+
+def build_question_prompt(
+    question: str, results: list[RetrievedChunk], max_chars: int = _MAX_CONTEXT_CHARS
+) -> str:
+    """Build the prompt with numbered context sources followed by the question."""
+    sections: list[str] = []
+    used = 0
+    for number, result in enumerate(results, start=1):
+        chunk = result.chunk
+        body = chunk.content.replace("```", "` ` `")
+        section = f"[{number}] {chunk.context_header}\n```{chunk.language}\n{body}\n```"
+        if sections and used + len(section) > max_chars:
+            break
+        sections.append(section)
+        used += len(section)
+    context = "\n\n".join(sections) if sections else "(no matching context found)"
+    return f"### Context\n\n{context}\n\n### Question\n\n{question}\n\n### Answer"
+
+
+async def answer_question(
+    provider: AIProvider,
+    question: str,
+    results: list[RetrievedChunk],
+    max_tokens: int | None = None,
+) -> SummarizationResult:
+    """Ask the provider to answer ``question`` from the retrieved chunks."""
+    prompt = build_question_prompt(question, results)
+    return await provider.complete(prompt, system_prompt=QA_SYSTEM_PROMPT, max_tokens=max_tokens)
@@ -0,0 +1,149 @@
+"""AI triage of security findings.
+
+With ``ai_security_triage``, the findings of each file are sent to the AI
+provider with the code around them. The provider rates how likely each
+finding is a true positive and which severity it deserves, with a short
+rationale, so reviewers can skip test fixtures, placeholders and other
+noise. Flagged lines are sent as the scanner reported them, with secret
+values already masked.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+
+from ..base_types import SecurityIssue, SecuritySeverity, SecurityTriage
+
+TRIAGE_SYSTEM_PROMPT = (
+    "You are an application security engineer triaging the output of a static security "
+    "scanner. You judge each finding from the code shown, reply with JSON only, and say so "
+    "when the code does not show enough to decide."
+)
+
+# Completion budget for one triage request
+TRIAGE_MAX_TOKENS = 1500
+
+# Findings sent in one request; files with more are split
+MAX_FINDINGS_PER_REQUEST = 20
+
+# Lines of code shown above and below each finding
+_CONTEXT_LINES = 3
+
+_JSON_BLOCK_RE = re.compile(r"```(?:json)?[ \t]*\n(.*?)```", re.DOTALL)
+
+
+def triageable_issues(issues: list) -> list[SecurityIssue]:
+    """Return the findings that can carry a triage (dict findings from some parsers cannot)."""
+    return [issue for issue in issues if isinstance(issue, SecurityIssue)]
+
+
+def _snippet(lines: list[str], issue: SecurityIssue, flagged: dict[int, str]) -> str:
+    start = max(1, issue.line_number - _CONTEXT_LINES)
+    end = min(len(lines), issue.line_number + _CONTEXT_LINES)
+    width = len(str(end))
+    rendered = []
+    for number in range(start, end + 1):
+        marker = ">" if number == issue.line_number else " "
+        text = flagged.get(number, lines[number - 1])
+        rendered.append(f"{marker} {number:>{width}} | {text}")
+    return "\n".join(rendered) or issue.context
+
+
+def build_triage_prompt(
+    file_path: str, language: str, content: str, issues: list[SecurityIssue], all_issues: list
+) -> str:
+    """Build the prompt asking for a triage of ``issues``, numbered from 1.
+
+    Args:
+        file_path: Path of the file shown to the provider.
+        language: Language of the file, for the code fences.
+        content: File content the findings refer to.
+        issues: Findings to triage in this request.
+        all_issues: Every finding of the file; their flagged lines are shown
+            as the scanner reported them, so secret values stay masked.
+    """
+    lines = content.splitlines()
+    flagged = {
+        issue.line_number: issue.context
+        for issue in triageable_issues(all_issues)
+        if issue.context and "\n" not in issue.context
+    }
+    findings = []
+    for number, issue in enumerate(issues, start=1):
+        findings.extend(
+            [
+                f"#### Finding {number}: {issue.rule_id} (scanner severity "
+                f"{issue.severity.name}), line {issue.line_number}",
+                issue.description,
+                f"```{language}",
+                _snippet(lines, issue, flagged),
+                "```",
+                "",
+            ]
+        )
+
+    return "\n".join(
+        [
+            "### Task",
+            f"Triage the security findings below from `{file_path}`. For each finding, judge "
+            "from the code how likely it is a real issue rather than a false positive (test "
+            "fixtures, placeholders, example values, code that is already safe) and which "
+            "severity it deserves.",
+            "",
+            "### Output Requirements",
+            "- Reply with a JSON array only, one object per finding: "
+            '`{"id": <finding number>, "likelihood": <0.0-1.0 probability that the finding is '
+            'a true positive>, "severity": "CRITICAL" | "HIGH" | "MEDIUM" | "LOW" | "INFO", '
+            '"rationale": "<one or two sentences>"}`',
+            "- The flagged line is marked with `>`; secret values in it are masked with `****`",
+            "",
+            "### Findings",
+            *findings,
+        ]
+    ).rstrip()
+
+
+def _parse_severity(value, default: SecuritySeverity) -> SecuritySeverity:
+    try:
+        return SecuritySeverity[str(value).strip().upper()]
+    except KeyError:
+        return default
+
+
+def parse_triage(text: str, issues: list[SecurityIssue]) -> dict[int, SecurityTriage]:
+    """Parse a triage reply for ``issues``.
+
+    Returns:
+        Triage by position in ``issues``; findings the reply skips or answers
+        malformed are missing.
+    """
+    match = _JSON_BLOCK_RE.search(text)
+    body = match.group(1) if match else text
+    start, end = body.find("["), body.rfind("]")
+    if start == -1 or end < start:
+        return {}
+    try:
+        entries = json.loads(body[start : end + 1])
+    except json.JSONDecodeError:
+        return {}
+    if not isinstance(entries, list):
+        return {}
+
+    triage: dict[int, SecurityTriage] = {}
+    for entry in entries:
+        if not isinstance(entry, dict):
+            continue
+        try:
+            index = int(entry.get("id")) - 1
+            likelihood = float(entry.get("likelihood"))
+        except (TypeError, ValueError):
+            continue
+        if not 0 <= index < len(issues) or index in triage:
+            continue
+        triage[index] = SecurityTriage(
+            likelihood=min(1.0, max(0.0, likelihood)),
+            severity=_parse_severity(entry.get("severity"), issues[index].severity),
+            rationale=" ".join(str(entry.get("rationale") or "").split()),
+        )
+    return triage
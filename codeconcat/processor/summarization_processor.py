@@ -2,28 +2,203 @@
 
 import asyncio
 import logging
+import math
+import os
+import posixpath
+import time
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass
 from pathlib import Path
 from typing import Any
 
 from ..ai import AIProvider, AIProviderConfig, SummarizationResult, get_ai_provider
-from ..ai.base import AIProviderType
-from ..base_types import CodeConCatConfig, ParsedFileData
+from ..ai.base import (
+    LOCAL_PROVIDER_TYPES,
+    PROMPT_TEMPLATE_NAMES,
+    AIProviderType,
+    RequestActivity,
+    is_on_prem_endpoint,
+    provider_type_from_name,
+    request_activity,
+)
+from ..ai.token_counter import TokenCounter, TokenTracker
+from ..base_types import CodeConCatConfig, Declaration, ParsedFileData, SecurityIssue
+from ..utils.offline import is_offline
+from .architecture import (
+    DIAGRAM_MAX_TOKENS,
+    DIAGRAM_SYSTEM_PROMPT,
+    MAX_COMPONENT_SUMMARY_CHARS,
+    build_component_graph,
+    build_diagram_prompt,
+    extract_mermaid,
+    render_mermaid,
+)
+from .change_summary import (
+    CHANGE_SUMMARY_MAX_TOKENS,
+    CHANGE_SUMMARY_SYSTEM_PROMPT,
+    build_change_summary_prompt,
+)
+from .security_triage import (
+    MAX_FINDINGS_PER_REQUEST,
+    TRIAGE_MAX_TOKENS,
+    TRIAGE_SYSTEM_PROMPT,
+    build_triage_prompt,
+    parse_triage,
+    triageable_issues,
+)
 
 logger = logging.getLogger(__name__)
 
+# Declaration kinds that get their own summary when ai_summarize_functions is on
+_SUMMARIZABLE_KINDS = frozenset(
+    {"function", "method", "class", "struct", "interface", "trait", "enum"}
+)
+
+_NON_PUBLIC_MODIFIERS = frozenset({"private", "protected", "internal", "fileprivate"})
+
+# Reply budget providers use for function summaries
+_FUNCTION_SUMMARY_TOKENS = 200
+
+# Declarations listed in the {declarations} variable of a file prompt template
+_MAX_TEMPLATE_DECLARATIONS = 50
+
+
+@dataclass
+class CostEstimate:
+    """Projected token usage and spend for a summarization run.
+
+    Output tokens are counted at each request's ``max_tokens``, so the
+    estimate is an upper bound; cache hits make the actual spend lower.
+
+    Attributes:
+        requests: Number of AI requests expected.
+        input_tokens: Prompt tokens across all requests.
+        output_tokens: Maximum reply tokens across all requests.
+        cost_usd: Estimated cost in USD at the model's list price.
+    """
+
+    requests: int = 0
+    input_tokens: int = 0
+    output_tokens: int = 0
+    cost_usd: float = 0.0
+
+
+def _declaration_source(lines: list[str], decl: Declaration) -> str:
+    """Return the source lines of ``decl`` (20 lines if its end is unknown)."""
+    end = decl.end_line or decl.start_line + 20
+    return "\n".join(lines[decl.start_line - 1 : end])
+
+
+def _relative_summary_paths(paths: list[str]) -> dict[str, str]:
+    """Map file paths to POSIX paths relative to their deepest common directory."""
+    if not paths:
+        return {}
+    absolute = {path: os.path.abspath(path) for path in paths}
+    root = os.path.commonpath([os.path.dirname(a) for a in absolute.values()])
+    return {path: Path(os.path.relpath(a, root)).as_posix() for path, a in absolute.items()}
+
+
+def _directory_children(paths: list[str]) -> dict[str, list[str]]:
+    """Return the files and subdirectories directly inside each directory of ``paths``.
+
+    Paths are relative POSIX paths and the root directory is "".
+    """
+    children: dict[str, set[str]] = {"": set()}
+    for path in paths:
+        node = path
+        while node:
+            parent = posixpath.dirname(node)
+            children.setdefault(parent, set()).add(node)
+            node = parent
+    return {directory: sorted(entries) for directory, entries in children.items()}
+
+
+def _fold_sizes(count: int, fan_in: int) -> tuple[list[int], int]:
+    """Return the input counts of the part requests that fold ``count`` summaries to ``fan_in``.
+
+    Also returns the number of summaries left after folding.
+    """
+    sizes: list[int] = []
+    while count > fan_in:
+        parts = math.ceil(count / fan_in)
+        # A trailing group of one is passed through without a request
+        sizes.extend([fan_in] * (parts - 1))
+        if count - fan_in * (parts - 1) > 1:
+            sizes.append(count - fan_in * (parts - 1))
+        count = parts
+    return sizes, count
+
+
+def _hierarchy_plan(paths: list[str], fan_in: int) -> tuple[list[int], int]:
+    """Plan a summary hierarchy over relative file paths without making requests.
+
+    Returns the input count of every directory request and the number of
+    top-level summaries the meta-overview is built from.
+    """
+    children = _directory_children(paths)
+    available = set(paths)
+    requests: list[int] = []
+    for directory in sorted((d for d in children if d), key=lambda d: d.count("/"), reverse=True):
+        count = sum(1 for entry in children[directory] if entry in available)
+        if not count:
+            continue
+        available.add(directory)
+        if count > 1:
+            sizes, remaining = _fold_sizes(count, fan_in)
+            requests.extend(sizes)
+            if remaining > 1:
+                requests.append(remaining)
+    sizes, top = _fold_sizes(sum(1 for entry in children[""] if entry in available), fan_in)
+    requests.extend(sizes)
+    return requests, top
+
+
+def _is_public_declaration(decl: Declaration) -> bool:
+    """Return True if ``decl`` is part of its module's public surface."""
+    name = decl.name or ""
+    if name.startswith("_") and not (name.startswith("__") and name.endswith("__")):
+        return False
+    return not _NON_PUBLIC_MODIFIERS.intersection(decl.modifiers or ())
+
 
 class SummarizationProcessor:
     """Processor that adds AI-generated summaries to code files and functions."""
 
-    def __init__(self, config: CodeConCatConfig):
+    def __init__(
+        self,
+        config: CodeConCatConfig,
+        progress_callback: Callable[[int, int, str], None] | None = None,
+    ):
         """Initialize the summarization processor.
 
         Args:
             config: Global configuration object
+            progress_callback: Optional ``(done, total, message)`` callback called
+                as each file of a batch finishes
         """
         self.config = config
+        self.progress_callback = progress_callback
         self.ai_provider: AIProvider | None = None
         self.summary_writer = None
+        self.usage = TokenTracker()
+        self.cost_estimate: CostEstimate | None = None
+        self.cost_cap_exceeded = False
+        # Files whose summary failed, with the error, and the count of failed declarations
+        self.failed_files: dict[str, str] = {}
+        self.failed_functions = 0
+        self.failed_directories = 0
+        # Security findings left without a triage, and those rated below ai_triage_min_likelihood
+        self.failed_triage = 0
+        self.hidden_findings = 0
+        # Per-file progress of the current batch and the retries its requests needed
+        self.files_done = 0
+        self.files_total = 0
+        self.retries = 0
+        self._progress_paths: dict[str, str] = {}
+        # Generated directory summaries, by path relative to the project root
+        self.directory_summaries: dict[str, str] = {}
+        self._request_slots = asyncio.Semaphore(max(1, getattr(config, "ai_max_concurrent", 5)))
+        self.prompt_templates = self._load_prompt_templates()
         self._initialize_provider()
 
         # Initialize summary writer if file persistence is enabled
@@ -47,27 +222,51 @@ class SummarizationProcessor:
         logger.info(f"Initializing AI provider: {provider_type_str} with model {model}")
         logger.debug(f"API key present: {bool(api_key)}")
 
-        # Map string to enum
-        provider_map = {
-            "openai": AIProviderType.OPENAI,
-            "anthropic": AIProviderType.ANTHROPIC,
-            "openrouter": AIProviderType.OPENROUTER,
-            "ollama": AIProviderType.OLLAMA,
-            "llamacpp": AIProviderType.LLAMACPP,
-            "local_server": AIProviderType.LOCAL_SERVER,
-            "vllm": AIProviderType.VLLM,
-            "lmstudio": AIProviderType.LMSTUDIO,
-            "llamacpp_server": AIProviderType.LLAMACPP_SERVER,
-        }
-
-        provider_type = provider_map.get(provider_type_str.lower())
+        provider_type = provider_type_from_name(provider_type_str)
         if not provider_type:
             logger.error(f"Unknown AI provider '{provider_type_str}'. Summarization disabled.")
             return
 
-        # Build extra params for llama.cpp performance tuning
-        extra_params: dict[str, int | str] = {}
-        api_base = getattr(self.config, "ai_api_base", None)
+        # Offline mode implies local-only; it also fails fast in run_codeconcat
+        offline = getattr(self.config, "offline", False) or is_offline()
+        local_only = getattr(self.config, "ai_local_only", False) or offline
+        guard = "offline mode is enabled" if offline else "ai_local_only is set"
+        if local_only and provider_type not in LOCAL_PROVIDER_TYPES:
+            logger.error(
+                f"AI provider '{provider_type_str}' sends code to an external API, but "
+                f"{guard}. Summarization disabled."
+            )
+            return
+
+        # Per-provider settings from config; explicit ai_model/ai_api_base take precedence
+        provider_settings = dict(
+            (getattr(self.config, "ai_provider_settings", None) or {}).get(provider_type.value, {})
+        )
+        settings_model = provider_settings.pop("model", None)
+        settings_api_base = provider_settings.pop("api_base", None)
+        settings_key_env = provider_settings.pop("api_key_env", None)
+        temperature = provider_settings.pop(
+            "temperature", getattr(self.config, "ai_temperature", 0.3)
+        )
+        max_tokens = provider_settings.pop("max_tokens", getattr(self.config, "ai_max_tokens", 500))
+        timeout = provider_settings.pop("timeout", getattr(self.config, "ai_timeout", 600))
+        requests_per_minute = provider_settings.pop(
+            "requests_per_minute", getattr(self.config, "ai_requests_per_minute", None)
+        )
+        max_retries = provider_settings.pop(
+            "max_retries", getattr(self.config, "ai_max_retries", 2)
+        )
+        # Pricing for models the built-in tables do not know, in USD per 1K tokens
+        cost_per_1k_input = provider_settings.pop("cost_per_1k_input_tokens", 0.0)
+        cost_per_1k_output = provider_settings.pop("cost_per_1k_output_tokens", 0.0)
+        model = model or settings_model
+        if not api_key and settings_key_env:
+            api_key = os.getenv(settings_key_env)
+
+        # Build extra params for llama.cpp performance tuning; remaining provider
+        # settings (top_p, seed, ...) are passed through to the request
+        extra_params: dict[str, Any] = dict(provider_settings)
+        api_base = getattr(self.config, "ai_api_base", None) or settings_api_base
         if api_base and isinstance(api_base, str) and not api_base.strip():
             api_base = None
         if provider_type == AIProviderType.LLAMACPP:
@@ -107,15 +306,41 @@ class SummarizationProcessor:
             provider_type=provider_type,
             api_key=api_key,
             model=model or "",
-            temperature=getattr(self.config, "ai_temperature", 0.3),
-            max_tokens=getattr(self.config, "ai_max_tokens", 500),
+            temperature=temperature,
+            max_tokens=max_tokens,
+            timeout=timeout,
+            max_retries=max_retries + 1,
+            requests_per_minute=requests_per_minute,
+            cost_per_1k_input_tokens=cost_per_1k_input,
+            cost_per_1k_output_tokens=cost_per_1k_output,
             cache_enabled=getattr(self.config, "ai_cache_enabled", True),
+            cache_dir=getattr(self.config, "ai_cache_dir", None),
+            cache_ttl=getattr(self.config, "ai_cache_ttl", 2592000),
             api_base=api_base,
             extra_params=extra_params,
+            prompt_templates=self.prompt_templates,
+            summary_language=getattr(self.config, "ai_summary_language", None),
         )
 
+        allowed_hosts = getattr(self.config, "ai_allowed_hosts", None) or []
+        if local_only and not is_on_prem_endpoint(api_base, allowed_hosts):
+            logger.error(
+                f"AI endpoint '{api_base}' is not local or private, but {guard}. "
+                "Summarization disabled. Add the host to ai_allowed_hosts if it is on-prem."
+            )
+            return
+
         try:
             self.ai_provider = get_ai_provider(ai_config)
+            # Providers resolve default endpoints from the environment, so re-check
+            resolved_base = self.ai_provider.config.api_base
+            if local_only and not is_on_prem_endpoint(resolved_base, allowed_hosts):
+                logger.error(
+                    f"AI endpoint '{resolved_base}' is not local or private, but {guard}. "
+                    "Summarization disabled."
+                )
+                self.ai_provider = None
+                return
             logger.info(
                 f"Successfully initialized {provider_type_str} AI provider with model {model}"
             )
@@ -143,14 +368,19 @@ class SummarizationProcessor:
             return parsed_file
 
         # Skip if summarization is disabled or file is too small
-        if not self._should_summarize_file(parsed_file):
+        if not self._should_summarize_file(parsed_file) or not self._within_budget():
+            self._report_file(parsed_file, "skipped", RequestActivity())
             return parsed_file
 
+        activity = RequestActivity()
+        token = request_activity.set(activity)
+        status = "skipped"
         try:
             logger.info(f"Generating AI summary for {parsed_file.file_path}...")
             # Generate file-level summary
             file_summary = await self._generate_file_summary(parsed_file)
             if file_summary and not file_summary.error:
+                status = "cached" if file_summary.cached else "summarized"
                 parsed_file.ai_summary = file_summary.summary
                 logger.info(
                     f"✓ Generated summary for {parsed_file.file_path}: {len(file_summary.summary)} chars"
@@ -176,6 +406,8 @@ class SummarizationProcessor:
                     except Exception as e:
                         logger.warning(f"Failed to save summary to disk: {e}")
             elif file_summary and file_summary.error:
+                status = "failed"
+                self.failed_files[parsed_file.file_path] = file_summary.error
                 logger.warning(
                     f"Failed to generate summary for {parsed_file.file_path}: {file_summary.error}"
                 )
@@ -185,13 +417,52 @@ class SummarizationProcessor:
                 await self._add_function_summaries(parsed_file)
 
         except Exception as e:
+            status = "failed"
+            self.failed_files[parsed_file.file_path] = str(e)
             logger.error(f"Exception while generating summary for {parsed_file.file_path}: {e}")
             import traceback
 
             logger.debug(traceback.format_exc())
+        finally:
+            request_activity.reset(token)
+            self._report_file(parsed_file, status, activity)
 
         return parsed_file
 
+    def _report_file(
+        self, parsed_file: ParsedFileData, status: str, activity: RequestActivity
+    ) -> None:
+        """Log a finished file's requests and pass the batch progress to the callback."""
+        self.files_done += 1
+        self.retries += activity.retries
+        path = self._progress_paths.get(parsed_file.file_path, parsed_file.file_path)
+        log = logger.debug if status == "skipped" else logger.info
+        log(
+            f"AI progress {self.files_done}/{self.files_total}: {path} {status}, "
+            f"{activity.input_tokens} tokens sent in {activity.requests} requests, "
+            f"{activity.latency:.1f}s, {activity.retries} retries",
+            extra={
+                "ai_progress": {
+                    "file": parsed_file.file_path,
+                    "status": status,
+                    "done": self.files_done,
+                    "total": self.files_total,
+                    "requests": activity.requests,
+                    "tokens_sent": activity.input_tokens,
+                    "latency_s": round(activity.latency, 3),
+                    "retries": activity.retries,
+                }
+            },
+        )
+        if self.progress_callback:
+            details = [status]
+            if activity.requests or activity.latency:
+                details += [f"{activity.input_tokens:,} tokens", f"{activity.latency:.1f}s"]
+            if activity.retries:
+                details.append(f"{activity.retries} retries")
+            message = f"{path} ({', '.join(details)})"
+            self.progress_callback(self.files_done, self.files_total, message)
+
     async def process_batch(self, files: list[ParsedFileData]) -> list[ParsedFileData]:
         """Process multiple files in batch for efficiency.
 
@@ -204,29 +475,76 @@ class SummarizationProcessor:
         if not self.ai_provider:
             return files
 
-        # Process files concurrently with a semaphore to limit concurrent requests
-        # PERFORMANCE: Increased from 5 to 25 - cloud AI APIs can handle higher concurrency
-        # and this significantly reduces total AI processing time for large codebases
-        max_concurrent = getattr(self.config, "ai_max_concurrent", 25)
-        semaphore = asyncio.Semaphore(max_concurrent)
+        self.cost_estimate = self.estimate_cost(files)
+        cap = getattr(self.config, "ai_max_cost_usd", None)
+        logger.info(
+            f"Estimated AI cost: ${self.cost_estimate.cost_usd:.4f} for "
+            f"{self.cost_estimate.requests} requests (~{self.cost_estimate.input_tokens:,} input, "
+            f"<={self.cost_estimate.output_tokens:,} output tokens)"
+        )
+        provider_config = self.ai_provider.config
+        if (
+            cap is not None
+            and not provider_config.cost_per_1k_input_tokens
+            and not provider_config.cost_per_1k_output_tokens
+            and provider_config.provider_type not in LOCAL_PROVIDER_TYPES
+        ):
+            # Without rates the estimate and the spend stay at $0, so the cap would never apply
+            logger.error(
+                f"ai_max_cost_usd is set, but model '{provider_config.model}' has no pricing, so "
+                "its cost cannot be tracked; skipping AI summarization. Set "
+                "cost_per_1k_input_tokens and cost_per_1k_output_tokens in ai_provider_settings "
+                "or remove the cap."
+            )
+            return files
+        if cap is not None and self.cost_estimate.cost_usd > cap:
+            self.cost_cap_exceeded = True
+            logger.error(
+                f"Estimated AI cost ${self.cost_estimate.cost_usd:.4f} exceeds ai_max_cost_usd "
+                f"${cap:.2f}; skipping AI summarization. Raise the cap, choose a cheaper model "
+                "or narrow the files to summarize (ai_include_languages, ai_exclude_patterns)."
+            )
+            return files
 
-        async def process_with_semaphore(file_data):
-            """Process a file with semaphore-controlled concurrency.
+        self.files_done = 0
+        self.files_total = len(files)
+        self._progress_paths = _relative_summary_paths([f.file_path for f in files])
 
-            Args:
-                file_data: The parsed file data to process
+        # All files are processed at once; _dispatch holds each file, function and
+        # meta-overview request to ai_max_concurrent in flight and the provider's rate
+        # limiter spaces them. A failed file keeps its content and the run continues.
+        results = await asyncio.gather(
+            *(self.process_file(f) for f in files), return_exceptions=True
+        )
+        processed_files = []
+        for file_data, result in zip(files, results, strict=True):
+            if isinstance(result, BaseException):
+                self.failed_files[file_data.file_path] = str(result)
+                logger.error(f"Summarization failed for {file_data.file_path}: {result}")
+                result = file_data
+            processed_files.append(result)
+
+        if self.failed_files or self.failed_functions:
+            logger.warning(
+                f"AI summaries failed for {len(self.failed_files)} of {len(files)} files and "
+                f"{self.failed_functions} declarations; the output includes everything else"
+            )
 
-            Returns:
-                ParsedFileData with AI summaries added
-            """
-            async with semaphore:
-                return await self.process_file(file_data)
+        if getattr(self.config, "ai_security_triage", False):
+            await self.triage_security_findings(processed_files)
 
-        tasks = [process_with_semaphore(f) for f in files]
-        processed_files = await asyncio.gather(*tasks)
+        if getattr(self.config, "ai_change_summary", True) and processed_files:
+            change_summary = await self.generate_change_summary(processed_files)
+            if change_summary:
+                if processed_files[0].ai_metadata is None:
+                    processed_files[0].ai_metadata = {}
+                processed_files[0].ai_metadata["change_summary"] = change_summary
 
-        # Generate meta-overview if enabled
+        # Generate meta-overview if enabled; in hierarchical mode it also builds
+        # the directory summaries the overview is folded from
         ai_meta_enabled = getattr(self.config, "ai_meta_overview", False)
+        if not ai_meta_enabled and getattr(self.config, "ai_hierarchical_summary", False):
+            self.directory_summaries, _top = await self.summarize_hierarchy(processed_files)
         if ai_meta_enabled:
             logger.info("Generating meta-overview...")
             meta_overview = await self.generate_meta_overview(processed_files)
@@ -237,6 +555,7 @@ class SummarizationProcessor:
                 if processed_files[0].ai_metadata is None:
                     processed_files[0].ai_metadata = {}
                 processed_files[0].ai_metadata["meta_overview"] = meta_overview
+                processed_files[0].ai_metadata["meta_overview_source"] = "ai"
 
                 # Save meta-overview to disk if enabled
                 if self.summary_writer:
@@ -265,8 +584,334 @@ class SummarizationProcessor:
             else:
                 logger.warning("Meta-overview generation returned None or no files to store in")
 
+        if self.directory_summaries and processed_files:
+            if processed_files[0].ai_metadata is None:
+                processed_files[0].ai_metadata = {}
+            processed_files[0].ai_metadata["directory_summaries"] = dict(
+                sorted(self.directory_summaries.items())
+            )
+
+        if getattr(self.config, "ai_architecture_diagram", False) and processed_files:
+            diagram, diagram_source = await self.generate_architecture_diagram(processed_files)
+            if diagram:
+                if processed_files[0].ai_metadata is None:
+                    processed_files[0].ai_metadata = {}
+                processed_files[0].ai_metadata["architecture_diagram"] = diagram
+                processed_files[0].ai_metadata["architecture_diagram_source"] = diagram_source
+
         return processed_files
 
+    async def summarize_hierarchy(
+        self, files: list[ParsedFileData]
+    ) -> tuple[dict[str, str], dict[str, str]]:
+        """Fold file summaries into directory summaries, bottom up.
+
+        Each directory with more than one summarized file or subdirectory gets a
+        summary built from its children's summaries, deepest directories first;
+        a directory with a single child inherits that child's summary. Children
+        beyond ``ai_hierarchy_fan_in`` are folded in parts first, so no request
+        grows with the size of the repository.
+
+        Args:
+            files: Files with AI summaries
+
+        Returns:
+            The generated directory summaries and the top-level summaries the
+            meta-overview is built from, keyed by path relative to the project
+            root (directories end in "/")
+        """
+        summarized = [f for f in files if f.ai_summary]
+        relative = _relative_summary_paths([f.file_path for f in summarized])
+        folded: dict[str, str] = {relative[f.file_path]: f.ai_summary or "" for f in summarized}
+        children = _directory_children(list(folded))
+        generated: dict[str, str] = {}
+
+        def inputs(directory: str) -> dict[str, str]:
+            return {
+                f"{entry}/" if entry in children else entry: folded[entry]
+                for entry in children[directory]
+                if entry in folded
+            }
+
+        directories = [d for d in children if d]
+        for depth in sorted({d.count("/") for d in directories}, reverse=True):
+            pending: dict[str, dict[str, str]] = {}
+            for directory in (d for d in directories if d.count("/") == depth):
+                entries = inputs(directory)
+                if len(entries) == 1:
+                    folded[directory] = next(iter(entries.values()))
+                elif entries:
+                    pending[directory] = entries
+            results = await asyncio.gather(
+                *(self._fold_directory(d, entries) for d, entries in pending.items())
+            )
+            for directory, summary in zip(pending, results, strict=True):
+                if summary:
+                    folded[directory] = generated[directory] = summary
+
+        if generated:
+            logger.info(f"Generated {len(generated)} directory summaries")
+        return generated, await self._fold_parts("", inputs(""))
+
+    async def _fold_directory(self, directory: str, summaries: dict[str, str]) -> str | None:
+        """Summarize a directory from its children's summaries, folding them in parts if needed."""
+        summaries = await self._fold_parts(directory, summaries)
+        if len(summaries) == 1:
+            return next(iter(summaries.values()))
+        return await self._summarize_directory(directory, summaries)
+
+    async def _fold_parts(self, directory: str, summaries: dict[str, str]) -> dict[str, str]:
+        """Fold summaries in groups of ``ai_hierarchy_fan_in`` until at most that many remain."""
+        fan_in = max(2, getattr(self.config, "ai_hierarchy_fan_in", 20))
+        name = directory or "(project root)"
+        while len(summaries) > fan_in:
+            items = list(summaries.items())
+            groups = [dict(items[i : i + fan_in]) for i in range(0, len(items), fan_in)]
+            results = await asyncio.gather(
+                *(
+                    self._summarize_directory(directory, group)
+                    if len(group) > 1
+                    else self._passthrough(group)
+                    for group in groups
+                )
+            )
+            summaries = {
+                f"{name} (part {number}/{len(groups)})": summary
+                for number, summary in enumerate(results, start=1)
+                if summary
+            }
+        return summaries
+
+    @staticmethod
+    async def _passthrough(summaries: dict[str, str]) -> str | None:
+        return next(iter(summaries.values()), None)
+
+    async def _summarize_directory(self, directory: str, summaries: dict[str, str]) -> str | None:
+        """Request one directory summary."""
+        provider = self.ai_provider
+        if not provider:
+            return None
+
+        result = await self._cached_complete(
+            provider._create_directory_summary_prompt(directory, summaries),
+            provider._system_prompt("directory"),
+            getattr(self.config, "ai_max_tokens", 500),
+            "summarize_directory",
+        )
+        if result is None:
+            return None
+        if result.error or not result.summary:
+            self.failed_directories += 1
+            logger.warning(
+                f"Failed to summarize directory {directory or '(project root)'}: {result.error}"
+            )
+            return None
+        return result.summary
+
+    async def _cached_complete(
+        self, prompt: str, system_prompt: str, max_tokens: int, task: str
+    ) -> SummarizationResult | None:
+        """Send a free-form completion through the request slots, reusing the summary cache.
+
+        Returns None when the spend cap stops the request; errors are returned
+        in the result.
+        """
+        provider = self.ai_provider
+        if not provider:
+            return None
+
+        cache = getattr(provider, "cache", None)
+        cache_key = None
+        if cache is not None:
+            cache_key = cache.generate_key(
+                prompt, provider.config.provider_type.value, provider.config.model, task
+            )
+            cached = await cache.get(cache_key)
+            if cached:
+                return SummarizationResult(summary=cached, cached=True)
+
+        try:
+            result = await self._dispatch(
+                lambda: provider.complete(
+                    prompt, system_prompt=system_prompt, max_tokens=max_tokens
+                )
+            )
+        except Exception as e:
+            result = SummarizationResult(summary="", error=str(e))
+
+        if result and result.summary and not result.error and cache is not None and cache_key:
+            await cache.set(cache_key, result.summary, {"tokens": result.tokens_used})
+        return result
+
+    async def generate_architecture_diagram(
+        self, files: list[ParsedFileData]
+    ) -> tuple[str | None, str]:
+        """Generate a Mermaid component diagram from the import graph and summaries.
+
+        Files are grouped into components ``ai_diagram_depth`` directories
+        deep. The provider is asked to label and group the components; when it
+        fails or replies without Mermaid, the import graph is rendered as is.
+
+        Args:
+            files: Processed files, with AI summaries where available
+
+        Returns:
+            The diagram (None for fewer than two components) and its source,
+            ``"ai"`` or ``"graph"``
+        """
+        relative = _relative_summary_paths([f.file_path for f in files])
+        graph = build_component_graph(
+            {relative[f.file_path]: list(f.imports or []) for f in files},
+            depth=getattr(self.config, "ai_diagram_depth", 2),
+            file_summaries={relative[f.file_path]: f.ai_summary for f in files if f.ai_summary},
+            directory_summaries=self.directory_summaries,
+        )
+        if len(graph.components) < 2:
+            logger.info("Skipping architecture diagram: fewer than two components")
+            return None, "graph"
+
+        # Node labels follow ai_summary_language like the summaries do
+        language = self.ai_provider._language_instruction() if self.ai_provider else ""
+        result = await self._cached_complete(
+            build_diagram_prompt(graph),
+            f"{DIAGRAM_SYSTEM_PROMPT}\n\n{language}" if language else DIAGRAM_SYSTEM_PROMPT,
+            DIAGRAM_MAX_TOKENS,
+            "architecture_diagram",
+        )
+        diagram = extract_mermaid(result.summary) if result and not result.error else None
+        if diagram:
+            return diagram, "ai"
+
+        reason = result.error if result and result.error else "no Mermaid flowchart in the reply"
+        logger.warning(f"AI architecture diagram unavailable ({reason}); using the import graph")
+        return render_mermaid(graph), "graph"
+
+    def _change_summary_prompt(self, files: list[ParsedFileData]) -> str | None:
+        """Return the change summary prompt, or None outside diff mode."""
+        changed = [f for f in files if f.diff_metadata is not None]
+        if not changed:
+            return None
+        metadata = changed[0].diff_metadata
+        from_ref = getattr(self.config, "diff_from", None) or metadata.from_ref
+        to_ref = getattr(self.config, "diff_to", None) or metadata.to_ref
+        paths = _relative_summary_paths([f.file_path for f in changed])
+        return build_change_summary_prompt(changed, paths, from_ref, to_ref)
+
+    async def generate_change_summary(self, files: list[ParsedFileData]) -> str | None:
+        """Write a reviewer changelog of the diff-mode changes in ``files``.
+
+        Args:
+            files: Processed files; only those with diff metadata are described
+
+        Returns:
+            The Markdown change summary, or None outside diff mode or if the
+            request failed
+        """
+        prompt = self._change_summary_prompt(files)
+        if prompt is None:
+            return None
+
+        language = self.ai_provider._language_instruction() if self.ai_provider else ""
+        result = await self._cached_complete(
+            prompt,
+            f"{CHANGE_SUMMARY_SYSTEM_PROMPT}\n\n{language}"
+            if language
+            else CHANGE_SUMMARY_SYSTEM_PROMPT,
+            CHANGE_SUMMARY_MAX_TOKENS,
+            "change_summary",
+        )
+        if result and result.summary and not result.error:
+            return result.summary.strip()
+
+        if result is None:
+            reason = "the spend cap was reached"
+        else:
+            reason = result.error or "the reply was empty"
+        logger.warning(f"AI change summary unavailable: {reason}")
+        return None
+
+    def _triage_requests(
+        self, files: list[ParsedFileData]
+    ) -> list[tuple[ParsedFileData, list[SecurityIssue], str]]:
+        """Return (file, findings, prompt) for every triage request ``files`` need."""
+        relative = _relative_summary_paths([f.file_path for f in files])
+        requests = []
+        for parsed_file in files:
+            issues = triageable_issues(parsed_file.security_issues or [])
+            for start in range(0, len(issues), MAX_FINDINGS_PER_REQUEST):
+                chunk = issues[start : start + MAX_FINDINGS_PER_REQUEST]
+                prompt = build_triage_prompt(
+                    relative[parsed_file.file_path],
+                    parsed_file.language or "",
+                    parsed_file.content or "",
+                    chunk,
+                    parsed_file.security_issues,
+                )
+                requests.append((parsed_file, chunk, prompt))
+        return requests
+
+    async def triage_security_findings(self, files: list[ParsedFileData]) -> int:
+        """Attach an AI triage to the security findings of ``files``.
+
+        Each file's findings are sent with the code around them, at most
+        ``MAX_FINDINGS_PER_REQUEST`` per request. With
+        ``ai_triage_min_likelihood``, findings rated below it are then removed
+        from their file.
+
+        Args:
+            files: Processed files with their security findings
+
+        Returns:
+            Number of findings triaged
+        """
+        if getattr(self.config, "mask_output_content", False):
+            # Security sections are left out of masked output, so there is nothing to show
+            logger.info("Skipping security triage: findings are not written with masked output")
+            return 0
+
+        language = self.ai_provider._language_instruction() if self.ai_provider else ""
+        system_prompt = (
+            f"{TRIAGE_SYSTEM_PROMPT}\n\n{language}" if language else TRIAGE_SYSTEM_PROMPT
+        )
+
+        async def triage(issues: list[SecurityIssue], prompt: str) -> int:
+            result = await self._cached_complete(
+                prompt, system_prompt, TRIAGE_MAX_TOKENS, "security_triage"
+            )
+            assessed = parse_triage(result.summary, issues) if result and not result.error else {}
+            for index, assessment in assessed.items():
+                issues[index].triage = assessment
+            self.failed_triage += len(issues) - len(assessed)
+            if result and result.error:
+                logger.warning(f"Security triage failed for {issues[0].file_path}: {result.error}")
+            return len(assessed)
+
+        requests = self._triage_requests(files)
+        triaged = sum(
+            await asyncio.gather(*(triage(issues, prompt) for _f, issues, prompt in requests))
+        )
+        if self.failed_triage:
+            logger.warning(f"{self.failed_triage} security findings were left without a triage")
+
+        threshold = getattr(self.config, "ai_triage_min_likelihood", 0.0)
+        if threshold > 0:
+            for parsed_file in files:
+                kept = [
+                    issue
+                    for issue in parsed_file.security_issues or []
+                    if getattr(issue, "triage", None) is None
+                    or issue.triage.likelihood >= threshold
+                ]
+                self.hidden_findings += len(parsed_file.security_issues or []) - len(kept)
+                parsed_file.security_issues = kept
+        logger.info(f"Triaged {triaged} security findings")
+        if self.hidden_findings:
+            logger.info(
+                f"Left out {self.hidden_findings} security findings rated below "
+                f"ai_triage_min_likelihood {threshold:.2f}"
+            )
+        return triaged
+
     def _build_tree_structure(self, files: list[ParsedFileData]) -> str:
         """Build a tree structure visualization from file paths.
 
@@ -333,6 +978,61 @@ class SummarizationProcessor:
             "total_loc": total_loc,
         }
 
+    def _load_prompt_templates(self) -> dict[str, str]:
+        """Collect prompt template overrides from the config and the templates directory.
+
+        Files in ``ai_prompt_templates_dir`` named ``<template>.txt`` or ``<template>.md``
+        take precedence over inline ``ai_prompt_templates`` entries. Unknown names
+        and unreadable files are logged and ignored.
+        """
+        templates: dict[str, str] = {}
+        for name, text in (getattr(self.config, "ai_prompt_templates", None) or {}).items():
+            if name not in PROMPT_TEMPLATE_NAMES:
+                logger.warning(
+                    f"Ignoring unknown prompt template '{name}'; expected one of "
+                    f"{', '.join(PROMPT_TEMPLATE_NAMES)}"
+                )
+            elif text:
+                templates[name] = text
+
+        templates_dir = getattr(self.config, "ai_prompt_templates_dir", None)
+        if templates_dir:
+            directory = Path(templates_dir)
+            if not directory.is_dir():
+                logger.warning(f"Prompt templates directory {templates_dir} does not exist")
+            for name in PROMPT_TEMPLATE_NAMES:
+                for suffix in (".txt", ".md"):
+                    path = directory / f"{name}{suffix}"
+                    if not path.is_file():
+                        continue
+                    try:
+                        templates[name] = path.read_text(encoding="utf-8")
+                    except OSError as e:
+                        logger.warning(f"Could not read prompt template {path}: {e}")
+                    break
+
+        if templates:
+            logger.info(f"Using custom prompt templates: {', '.join(sorted(templates))}")
+        return templates
+
+    def _resolve_meta_overview_prompt(self) -> str | None:
+        """Return the custom meta-overview prompt, reading ``ai_meta_overview_prompt_file`` if set.
+
+        An unreadable prompt file is logged and the default prompt is used. Without
+        a meta-overview prompt, an ``overview`` prompt template is used if there is one.
+        """
+        prompt_file = getattr(self.config, "ai_meta_overview_prompt_file", None)
+        if prompt_file:
+            try:
+                return Path(prompt_file).read_text(encoding="utf-8")
+            except OSError as e:
+                logger.warning(f"Could not read meta-overview prompt file {prompt_file}: {e}")
+        return (
+            getattr(self.config, "ai_meta_overview_prompt", None)
+            or self.prompt_templates.get("overview")
+            or None
+        )
+
     async def generate_meta_overview(self, files: list[ParsedFileData]) -> str | None:
         """Generate a meta-overview from all file summaries with enhanced context.
 
@@ -356,6 +1056,9 @@ class SummarizationProcessor:
             logger.info("No file summaries available for meta-overview generation")
             return None
 
+        if not self._within_budget():
+            return None
+
         try:
             logger.info(f"Generating meta-overview from {len(file_summaries)} file summaries...")
 
@@ -367,17 +1070,27 @@ class SummarizationProcessor:
             context = self._collect_overview_context(files)
             logger.debug(f"Context: {context}")
 
+            if getattr(self.config, "ai_hierarchical_summary", False):
+                logger.info("Folding file summaries into directory summaries...")
+                self.directory_summaries, top_summaries = await self.summarize_hierarchy(files)
+                if top_summaries:
+                    context["summarized_files"] = len(file_summaries)
+                    file_summaries = top_summaries
+
             # Get custom prompt and max tokens from config
-            custom_prompt = getattr(self.config, "ai_meta_overview_prompt", None)
+            custom_prompt = self._resolve_meta_overview_prompt()
             max_tokens = getattr(self.config, "ai_meta_overview_max_tokens", 2000)
 
             # Generate the meta-overview with enhanced context
-            result = await self.ai_provider.generate_meta_overview(
-                file_summaries,
-                custom_prompt=custom_prompt,
-                max_tokens=max_tokens,
-                tree_structure=tree_structure,
-                context=context,
+            provider = self.ai_provider
+            result = await self._dispatch(
+                lambda: provider.generate_meta_overview(
+                    file_summaries,
+                    custom_prompt=custom_prompt,
+                    max_tokens=max_tokens,
+                    tree_structure=tree_structure,
+                    context=context,
+                )
             )
 
             if result and not result.error:
@@ -450,17 +1163,8 @@ class SummarizationProcessor:
         logger.debug(f"Will summarize {parsed_file.file_path}")
         return True
 
-    async def _generate_file_summary(
-        self, parsed_file: ParsedFileData
-    ) -> SummarizationResult | None:
-        """Generate a summary for an entire file.
-
-        Args:
-            parsed_file: The parsed file data
-
-        Returns:
-            SummarizationResult or None if failed
-        """
+    def _file_request(self, parsed_file: ParsedFileData) -> tuple[str | None, dict[str, Any]]:
+        """Return the content and context sent to the provider for a file summary."""
         # Check if this is a diff and prepare appropriate context
         is_diff = hasattr(parsed_file, "diff_metadata") and parsed_file.diff_metadata
 
@@ -496,6 +1200,10 @@ class SummarizationProcessor:
                 "imports": parsed_file.imports[:10] if parsed_file.imports else [],  # Limit imports
                 "num_functions": sum(1 for d in parsed_file.declarations if d.kind == "function"),
                 "num_classes": sum(1 for d in parsed_file.declarations if d.kind == "class"),
+                "declarations": [
+                    f"{d.kind} {d.name} (lines {d.start_line}-{d.end_line})"
+                    for d in parsed_file.declarations[:_MAX_TEMPLATE_DECLARATIONS]
+                ],
             }
 
             # Use annotated content if available and comments were removed, otherwise use original
@@ -506,14 +1214,31 @@ class SummarizationProcessor:
         if content and len(content) > max_chars:
             content = content[:max_chars] + "\n... (content truncated)"
 
+        return content, context
+
+    async def _generate_file_summary(
+        self, parsed_file: ParsedFileData
+    ) -> SummarizationResult | None:
+        """Generate a summary for an entire file.
+
+        Args:
+            parsed_file: The parsed file data
+
+        Returns:
+            SummarizationResult or None if failed
+        """
+        content, context = self._file_request(parsed_file)
         if not content:
             return SummarizationResult(summary="", error="No content to summarize")
 
-        if not self.ai_provider:
+        provider = self.ai_provider
+        if not provider:
             return SummarizationResult(summary="", error="AI provider not initialized")
 
-        return await self.ai_provider.summarize_code(
-            str(content), parsed_file.language or "unknown", context
+        return await self._dispatch(
+            lambda: provider.summarize_code(
+                str(content), parsed_file.language or "unknown", context
+            )
         )
 
     def _create_diff_summary_prompt(self, parsed_file: ParsedFileData, diff_content: str) -> str:
@@ -563,60 +1288,229 @@ class SummarizationProcessor:
 
         return "\n".join(prompt_parts)
 
+    def _select_declarations_to_summarize(self, declarations: list[Declaration]) -> list[Declaration]:
+        """Pick the functions and classes worth summarizing in a file.
+
+        Nested declarations (methods inside classes) are included. With
+        ``ai_functions_public_only`` set, declarations with a leading underscore
+        or a private/protected modifier are skipped, as are members of private
+        classes. Declarations below ``ai_min_function_lines`` are dropped and the
+        largest ``ai_max_functions_per_file`` remain.
+        """
+        public_only = getattr(self.config, "ai_functions_public_only", True)
+        min_lines = getattr(self.config, "ai_min_function_lines", 10)
+        max_functions = getattr(self.config, "ai_max_functions_per_file", 10)
+
+        candidates: list[Declaration] = []
+
+        def visit(decls: list[Declaration]):
+            for decl in decls:
+                if public_only and not _is_public_declaration(decl):
+                    continue
+                if decl.kind in _SUMMARIZABLE_KINDS:
+                    if not decl.end_line or decl.end_line - decl.start_line >= min_lines:
+                        candidates.append(decl)
+                visit(decl.children)
+
+        visit(declarations)
+        candidates.sort(key=lambda d: (d.end_line or d.start_line) - d.start_line, reverse=True)
+        return candidates[:max_functions]
+
     async def _add_function_summaries(self, parsed_file: ParsedFileData):
-        """Add summaries to individual functions in a file.
+        """Add summaries to individual functions and classes in a file.
 
         Args:
             parsed_file: The parsed file data
         """
-        # Filter for functions and methods
-        functions = [d for d in parsed_file.declarations if d.kind in ("function", "method")]
+        content = parsed_file.content
+        provider = self.ai_provider
+        if not content or not provider:
+            return
 
-        # Limit number of functions to summarize
-        max_functions = getattr(self.config, "ai_max_functions_per_file", 10)
-        min_function_lines = getattr(self.config, "ai_min_function_lines", 10)
+        lines = content.splitlines()
+        language = parsed_file.language or "unknown"
+
+        async def summarize(decl: Declaration) -> None:
+            try:
+                result = await self._dispatch(
+                    lambda: provider.summarize_function(
+                        _declaration_source(lines, decl),
+                        decl.name,
+                        language,
+                        {"file_path": parsed_file.file_path, "kind": decl.kind},
+                    )
+                )
+            except Exception as e:
+                result = SummarizationResult(summary="", error=str(e))
+
+            if result and not result.error:
+                decl.ai_summary = result.summary
+            elif result:
+                self.failed_functions += 1
+                logger.warning(f"Failed to summarize {decl.kind} {decl.name}: {result.error}")
+
+        declarations = self._select_declarations_to_summarize(parsed_file.declarations)
+        await asyncio.gather(*(summarize(d) for d in declarations))
+
+    def estimate_cost(self, files: list[ParsedFileData]) -> CostEstimate:
+        """Estimate the requests, tokens and cost of summarizing ``files``.
+
+        Prompts are built exactly as they would be sent and counted with the
+        model's tokenizer. Replies are counted at their token limit, and the
+        meta-overview prompt is approximated from the expected file summaries.
+        """
+        estimate = CostEstimate()
+        if not self.ai_provider:
+            return estimate
+
+        provider_config = self.ai_provider.config
+        model = str(provider_config.model or "")
+        max_tokens = int(provider_config.max_tokens)
+        system_tokens = TokenCounter.count_tokens(self.ai_provider._system_prompt("file"), model)
+        function_system_tokens = TokenCounter.count_tokens(
+            self.ai_provider._system_prompt("function"), model
+        )
 
-        # Sort by complexity/size and take top N
-        functions = sorted(
-            functions, key=lambda f: f.end_line - f.start_line if f.end_line else 0, reverse=True
-        )[:max_functions]
+        def add(input_tokens: int, output_tokens: int) -> None:
+            estimate.requests += 1
+            estimate.input_tokens += input_tokens
+            estimate.output_tokens += output_tokens
 
-        # Generate summaries for selected functions
-        for func in functions:
-            # Skip small functions
-            if func.end_line and (func.end_line - func.start_line) < min_function_lines:
+        summarized: list[str] = []
+        for parsed_file in files:
+            if not self._should_summarize_file(parsed_file):
+                continue
+            content, context = self._file_request(parsed_file)
+            if not content:
                 continue
+            language = parsed_file.language or "unknown"
+            prompt = self.ai_provider._create_code_summary_prompt(content, language, context)
+            add(system_tokens + TokenCounter.count_tokens(prompt, model), max_tokens)
+            summarized.append(parsed_file.file_path)
+
+            if getattr(self.config, "ai_summarize_functions", False) and parsed_file.content:
+                lines = parsed_file.content.splitlines()
+                for decl in self._select_declarations_to_summarize(parsed_file.declarations):
+                    prompt = self.ai_provider._create_function_summary_prompt(
+                        _declaration_source(lines, decl),
+                        decl.name,
+                        language,
+                        {"file_path": parsed_file.file_path, "kind": decl.kind},
+                    )
+                    add(
+                        function_system_tokens + TokenCounter.count_tokens(prompt, model),
+                        _FUNCTION_SUMMARY_TOKENS,
+                    )
 
-            try:
-                # Extract function code
-                content = parsed_file.content
-                if not content:
-                    continue
+        top_summaries = len(summarized)
+        if getattr(self.config, "ai_hierarchical_summary", False) and summarized:
+            directory_system_tokens = TokenCounter.count_tokens(
+                self.ai_provider._system_prompt("directory"), model
+            )
+            fan_in = max(2, getattr(self.config, "ai_hierarchy_fan_in", 20))
+            relative = list(_relative_summary_paths(summarized).values())
+            requests, top_summaries = _hierarchy_plan(relative, fan_in)
+            for inputs in requests:
+                add(directory_system_tokens + inputs * max_tokens, max_tokens)
+
+        if getattr(self.config, "ai_meta_overview", False) and summarized:
+            tree_tokens = TokenCounter.count_tokens(self._build_tree_structure(files), model)
+            add(
+                system_tokens + tree_tokens + top_summaries * max_tokens,
+                getattr(self.config, "ai_meta_overview_max_tokens", 2000),
+            )
 
-                lines = content.splitlines()
-                if func.end_line:
-                    func_lines = lines[func.start_line - 1 : func.end_line]
-                else:
-                    func_lines = lines[func.start_line - 1 : func.start_line + 20]  # Fallback
+        if getattr(self.config, "ai_security_triage", False) and not getattr(
+            self.config, "mask_output_content", False
+        ):
+            triage_system_tokens = TokenCounter.count_tokens(TRIAGE_SYSTEM_PROMPT, model)
+            for _file, _issues, prompt in self._triage_requests(files):
+                add(
+                    triage_system_tokens + TokenCounter.count_tokens(prompt, model),
+                    TRIAGE_MAX_TOKENS,
+                )
 
-                function_code = "\n".join(func_lines)
+        change_prompt = (
+            self._change_summary_prompt(files)
+            if getattr(self.config, "ai_change_summary", True)
+            else None
+        )
+        if change_prompt:
+            add(
+                TokenCounter.count_tokens(
+                    f"{CHANGE_SUMMARY_SYSTEM_PROMPT}\n{change_prompt}", model
+                ),
+                CHANGE_SUMMARY_MAX_TOKENS,
+            )
 
-                # Generate summary
-                if not self.ai_provider:
-                    continue
+        if getattr(self.config, "ai_architecture_diagram", False) and files:
+            relative_paths = _relative_summary_paths([f.file_path for f in files])
+            graph = build_component_graph(
+                {relative_paths[f.file_path]: list(f.imports or []) for f in files},
+                depth=getattr(self.config, "ai_diagram_depth", 2),
+            )
+            if len(graph.components) >= 2:
+                # Component summaries are not known yet; assume each uses its full allowance
+                summary_tokens = len(graph.components) * MAX_COMPONENT_SUMMARY_CHARS // 4
+                prompt_tokens = TokenCounter.count_tokens(
+                    f"{DIAGRAM_SYSTEM_PROMPT}\n{build_diagram_prompt(graph)}", model
+                )
+                add(prompt_tokens + summary_tokens, DIAGRAM_MAX_TOKENS)
 
-                result = await self.ai_provider.summarize_function(
-                    function_code,
-                    func.name,
-                    parsed_file.language or "unknown",
-                    {"file_path": parsed_file.file_path},
+        estimate.cost_usd = (
+            estimate.input_tokens / 1000 * float(provider_config.cost_per_1k_input_tokens)
+            + estimate.output_tokens / 1000 * float(provider_config.cost_per_1k_output_tokens)
+        )
+        return estimate
+
+    async def _dispatch(
+        self, request: Callable[[], Awaitable[SummarizationResult]]
+    ) -> SummarizationResult | None:
+        """Send a provider request once a concurrency slot is free.
+
+        Returns None without sending it when the spend cap has been reached.
+        """
+        async with self._request_slots:
+            if not self._within_budget():
+                return None
+            started = time.monotonic()
+            result = await request()
+        activity = request_activity.get()
+        if activity is not None and result is not None:
+            activity.latency += time.monotonic() - started
+            if not result.cached:
+                activity.requests += 1
+                activity.input_tokens += int(
+                    result.metadata.get("input_tokens", result.tokens_used)
                 )
+        self._record_usage(result)
+        return result
 
-                if result and not result.error:
-                    func.ai_summary = result.summary
+    def _within_budget(self) -> bool:
+        """Return False once actual spend has reached ``ai_max_cost_usd``.
 
-            except Exception as e:
-                logger.warning(f"Failed to summarize function {func.name}: {e}")
+        Spend is counted when a reply arrives, so the requests already in flight
+        when the cap is reached, up to ``ai_max_concurrent``, still complete and
+        can take the total past it.
+        """
+        cap = getattr(self.config, "ai_max_cost_usd", None)
+        if cap is None or self.usage.total_cost < cap:
+            return True
+        if not self.cost_cap_exceeded:
+            self.cost_cap_exceeded = True
+            logger.warning(
+                f"AI spend ${self.usage.total_cost:.4f} reached ai_max_cost_usd ${cap:.2f}; "
+                "skipping the remaining AI requests"
+            )
+        return False
+
+    def _record_usage(self, result: SummarizationResult | None) -> None:
+        """Add the tokens and cost of a completed, uncached request to the usage totals."""
+        if not result or result.cached or result.error:
+            return
+        input_tokens = int(result.metadata.get("input_tokens", result.tokens_used))
+        output_tokens = int(result.metadata.get("output_tokens", 0))
+        self.usage.track(result.model_used, input_tokens, output_tokens, result.cost_estimate)
 
     async def cleanup(self):
         """Clean up resources."""
@@ -635,18 +1529,42 @@ class SummarizationProcessor:
             "model": getattr(self.config, "ai_model", "none"),
         }
 
-        if self.ai_provider and hasattr(self.ai_provider, "cache"):
-            cache_stats = self.ai_provider.cache.get_stats()
-            stats["cache"] = cache_stats
+        cache = getattr(self.ai_provider, "cache", None) if self.ai_provider else None
+        if cache is not None:
+            stats["cache"] = cache.get_stats()
+
+        usage = self.usage.get_summary()
+        stats["cost"] = {
+            "estimated_usd": self.cost_estimate.cost_usd if self.cost_estimate else None,
+            "actual_usd": usage["total_cost"],
+            "requests": usage["total_requests"],
+            "input_tokens": usage["total_input_tokens"],
+            "output_tokens": usage["total_output_tokens"],
+            "cap_usd": getattr(self.config, "ai_max_cost_usd", None),
+            "cap_exceeded": self.cost_cap_exceeded,
+        }
+        stats["security_triage"] = {"hidden_findings": self.hidden_findings}
+        stats["retries"] = self.retries
+        stats["failures"] = {
+            "files": len(self.failed_files),
+            "functions": self.failed_functions,
+            "directories": self.failed_directories,
+            "security_findings": self.failed_triage,
+            "failed_files": dict(self.failed_files),
+        }
 
         return stats
 
 
-def create_summarization_processor(config: CodeConCatConfig) -> SummarizationProcessor | None:
+def create_summarization_processor(
+    config: CodeConCatConfig,
+    progress_callback: Callable[[int, int, str], None] | None = None,
+) -> SummarizationProcessor | None:
     """Factory function to create a summarization processor.
 
     Args:
         config: Global configuration
+        progress_callback: Optional ``(done, total, message)`` per-file progress callback
 
     Returns:
         SummarizationProcessor instance or None if disabled
@@ -654,4 +1572,4 @@ def create_summarization_processor(config: CodeConCatConfig) -> SummarizationPro
     if not getattr(config, "enable_ai_summary", False):
         return None
 
-    return SummarizationProcessor(config)
+    return SummarizationProcessor(config, progress_callback)
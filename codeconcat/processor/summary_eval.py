@@ -0,0 +1,255 @@
+"""Quality evaluation of AI summaries against golden reference summaries.
+
+Summarizes a corpus of source files that have hand-written reference
+summaries and scores every generated summary with ROUGE-1, ROUGE-2 and
+ROUGE-L F1 and, when an embedder is given, the cosine similarity of the
+embedded summary and reference. Scores are averaged per provider and model
+so candidate models can be compared on the same files before one is
+configured for a project.
+
+The golden file is JSON mapping corpus-relative POSIX paths to references::
+
+    {"version": 1, "summaries": {"python/basic.py": "A Python module that ..."}}
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+from ..base_types import CodeConCatConfig, ParsedFileData
+from .embeddings import Embedder, EmbeddingError, cosine_similarity
+
+logger = logging.getLogger(__name__)
+
+# Golden file looked up in the corpus directory when none is given
+GOLDEN_FILE = "golden_summaries.json"
+
+METRICS = ("rouge1", "rouge2", "rougeL", "embedding")
+
+_TOKEN_RE = re.compile(r"[a-z0-9]+")
+
+
+@dataclass
+class GoldenCase:
+    """A corpus file and its reference summary."""
+
+    path: str  # POSIX path relative to the corpus directory
+    reference: str
+
+
+@dataclass
+class CaseScore:
+    """Scores of one generated summary; all None when the summary failed."""
+
+    path: str
+    summary: str = ""
+    rouge1: float | None = None
+    rouge2: float | None = None
+    rougeL: float | None = None
+    embedding: float | None = None
+    error: str | None = None
+
+
+@dataclass
+class ModelReport:
+    """Scores of one provider and model over the corpus."""
+
+    provider: str
+    model: str
+    cases: list[CaseScore] = field(default_factory=list)
+    cost_usd: float = 0.0
+    input_tokens: int = 0
+    output_tokens: int = 0
+    error: str | None = None  # set when the provider could not be used at all
+
+    @property
+    def failures(self) -> int:
+        """Number of files without a generated summary."""
+        return sum(1 for case in self.cases if case.error)
+
+    def mean(self, metric: str) -> float | None:
+        """Average ``metric`` over the files that were scored with it."""
+        values = [getattr(c, metric) for c in self.cases if getattr(c, metric) is not None]
+        return sum(values) / len(values) if values else None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Return the report as a JSON-serializable dictionary."""
+        return {
+            "provider": self.provider,
+            "model": self.model,
+            "error": self.error,
+            "files": len(self.cases),
+            "failures": self.failures,
+            "scores": {metric: self.mean(metric) for metric in METRICS},
+            "cost_usd": self.cost_usd,
+            "input_tokens": self.input_tokens,
+            "output_tokens": self.output_tokens,
+            "cases": [case.__dict__ for case in self.cases],
+        }
+
+
+def load_golden(corpus: Path, golden: Path | None = None) -> list[GoldenCase]:
+    """Read the reference summaries of ``corpus``.
+
+    Raises:
+        ValueError: If the golden file is malformed or names a file that is
+            not in the corpus.
+    """
+    golden = golden or corpus / GOLDEN_FILE
+    try:
+        data = json.loads(golden.read_text(encoding="utf-8"))
+    except (OSError, json.JSONDecodeError) as e:
+        raise ValueError(f"Cannot read golden summaries {golden}: {e}") from e
+    summaries = data.get("summaries") if isinstance(data, dict) else None
+    if not isinstance(summaries, dict) or not summaries:
+        raise ValueError(f"{golden} has no 'summaries' mapping of paths to references")
+
+    cases = []
+    for path, reference in sorted(summaries.items()):
+        if not isinstance(reference, str) or not reference.strip():
+            raise ValueError(f"Reference summary for {path} in {golden} is empty")
+        if not (corpus / path).is_file():
+            raise ValueError(f"{golden} references {path}, which is not in {corpus}")
+        cases.append(GoldenCase(path, reference.strip()))
+    return cases
+
+
+def _tokens(text: str) -> list[str]:
+    return _TOKEN_RE.findall(text.lower())
+
+
+def _f1(overlap: int, candidate_count: int, reference_count: int) -> float:
+    if not overlap:
+        return 0.0
+    precision = overlap / candidate_count
+    recall = overlap / reference_count
+    return 2 * precision * recall / (precision + recall)
+
+
+def rouge_n(candidate: str, reference: str, n: int = 1) -> float:
+    """ROUGE-N F1: overlap of the word n-grams of two texts."""
+
+    def ngrams(words: list[str]) -> Counter[tuple[str, ...]]:
+        return Counter(tuple(words[i : i + n]) for i in range(len(words) - n + 1))
+
+    candidate_grams = ngrams(_tokens(candidate))
+    reference_grams = ngrams(_tokens(reference))
+    overlap = sum((candidate_grams & reference_grams).values())
+    return _f1(overlap, sum(candidate_grams.values()), sum(reference_grams.values()))
+
+
+def rouge_l(candidate: str, reference: str) -> float:
+    """ROUGE-L F1: longest common subsequence of the words of two texts."""
+    candidate_words = _tokens(candidate)
+    reference_words = _tokens(reference)
+    previous = [0] * (len(reference_words) + 1)
+    for word in candidate_words:
+        current = [0]
+        for j, other in enumerate(reference_words):
+            current.append(previous[j] + 1 if word == other else max(previous[j + 1], current[j]))
+        previous = current
+    return _f1(previous[-1], len(candidate_words), len(reference_words))
+
+
+def score_cases(
+    summaries: dict[str, str], cases: list[GoldenCase], embedder: Embedder | None = None
+) -> list[CaseScore]:
+    """Score generated summaries, keyed by corpus path, against their references.
+
+    Cases without a summary are recorded with an error. Embedding similarity
+    is left out, with a warning, if the embedder fails.
+    """
+    scores = []
+    for case in cases:
+        summary = summaries.get(case.path, "")
+        if not summary:
+            scores.append(CaseScore(case.path, error="no summary generated"))
+            continue
+        scores.append(
+            CaseScore(
+                case.path,
+                summary,
+                rouge1=rouge_n(summary, case.reference, 1),
+                rouge2=rouge_n(summary, case.reference, 2),
+                rougeL=rouge_l(summary, case.reference),
+            )
+        )
+
+    scored = [score for score in scores if not score.error]
+    if embedder is not None and scored:
+        references = {case.path: case.reference for case in cases}
+        texts = [text for s in scored for text in (s.summary, references[s.path])]
+        try:
+            vectors = embedder.embed(texts)
+        except EmbeddingError as e:
+            logger.warning(f"Embedding similarity unavailable: {e}")
+        else:
+            for i, score in enumerate(scored):
+                score.embedding = cosine_similarity(vectors[2 * i], vectors[2 * i + 1])
+    return scores
+
+
+def corpus_files(corpus: Path, cases: list[GoldenCase]) -> list[ParsedFileData]:
+    """Read the corpus files of ``cases`` with their detected languages."""
+    from ..parser.unified_pipeline import determine_language
+
+    files = []
+    for case in cases:
+        path = corpus / case.path
+        files.append(
+            ParsedFileData(
+                file_path=str(path),
+                content=path.read_text(encoding="utf-8"),
+                language=determine_language(str(path)) or "unknown",
+            )
+        )
+    return files
+
+
+async def evaluate_model(
+    config: CodeConCatConfig,
+    corpus: Path,
+    cases: list[GoldenCase],
+    embedder: Embedder | None = None,
+) -> ModelReport:
+    """Summarize the corpus with the provider and model of ``config`` and score it."""
+    from ..parser.unified_pipeline import parse_code_files
+    from .summarization_processor import SummarizationProcessor
+
+    report = ModelReport(config.ai_provider, config.ai_model or "")
+    processor = SummarizationProcessor(config)
+    if processor.ai_provider is None:
+        report.error = "provider could not be initialized; check its API key and settings"
+        return report
+    report.model = processor.ai_provider.config.model
+
+    try:
+        files = corpus_files(corpus, cases)
+        parsed, _errors = parse_code_files(files, config)
+        parsed_paths = {f.file_path for f in parsed}
+        # Files the parser could not handle are still summarized as plain content
+        parsed += [f for f in files if f.file_path not in parsed_paths]
+        await processor.process_batch(parsed)
+    finally:
+        await processor.cleanup()
+
+    by_file = {Path(f.file_path).resolve(): f.ai_summary or "" for f in parsed}
+    summaries = {
+        case.path: by_file.get((corpus / case.path).resolve(), "") for case in cases
+    }
+    report.cases = score_cases(summaries, cases, embedder)
+    for score in report.cases:
+        if score.error:
+            failed = processor.failed_files.get(str(corpus / score.path))
+            score.error = failed or score.error
+    cost = processor.get_statistics()["cost"]
+    report.cost_usd = cost["actual_usd"]
+    report.input_tokens = cost["input_tokens"]
+    report.output_tokens = cost["output_tokens"]
+    return report
@@ -7,6 +7,7 @@ import tiktoken
 from transformers import GPT2TokenizerFast
 
 from ..base_types import TokenStats
+from ..utils.offline import is_offline, require_tiktoken_encoding
 
 # Cache for encoders to avoid recreating them
 _ENCODER_CACHE: dict[str, tiktoken.Encoding] = {}
@@ -24,7 +25,9 @@ def get_claude_tokenizer() -> GPT2TokenizerFast | Any:
         with _CLAUDE_TOKENIZER_LOCK:
             if _CLAUDE_TOKENIZER is None:
                 try:
-                    _CLAUDE_TOKENIZER = GPT2TokenizerFast.from_pretrained("Xenova/claude-tokenizer")
+                    _CLAUDE_TOKENIZER = GPT2TokenizerFast.from_pretrained(
+                        "Xenova/claude-tokenizer", local_files_only=is_offline()
+                    )
                 except Exception:
                     # If loading fails, create a dummy tokenizer for testing
                     # This helps with test isolation issues
@@ -41,6 +44,7 @@ def get_encoder(model: str = "gpt-3.5-turbo") -> tiktoken.Encoding:
     """Get or create a tiktoken encoder for the specified model."""
     with _ENCODER_CACHE_LOCK:
         if model not in _ENCODER_CACHE:
+            require_tiktoken_encoding(tiktoken.model.encoding_name_for_model(model))
             _ENCODER_CACHE[model] = tiktoken.encoding_for_model(model)
         return _ENCODER_CACHE[model]
 
@@ -56,6 +56,34 @@ class CodeConcatReconstructor:
         """
         Reconstruct files from a CodeConCat output file.
 
+        Args:
+            input_file: Path to the CodeConCat output file
+            format_type: Format type ('markdown', 'xml', 'json', or None for auto-detection)
+        """
+        files = self.parse_files(input_file, format_type)
+
+        # Create output directory if it doesn't exist
+        self.output_dir.mkdir(parents=True, exist_ok=True)
+
+        # Write files
+        for file_path, content in files.items():
+            self._write_file(file_path, content)
+
+        logger.info("\nReconstruction complete!")
+        logger.info(f"Files processed: {self.files_processed}")
+        logger.info(f"Files created: {self.files_created}")
+        logger.info(f"Errors: {self.errors}")
+
+        return {
+            "files_processed": self.files_processed,
+            "files_created": self.files_created,
+            "errors": self.errors,
+        }
+
+    def parse_files(self, input_file: str, format_type: str | None = None) -> dict[str, str]:
+        """
+        Parse a CodeConCat output file into a mapping of file path to content.
+
         Args:
             input_file: Path to the CodeConCat output file
             format_type: Format type ('markdown', 'xml', 'json', or None for auto-detection)
@@ -79,33 +107,14 @@ class CodeConcatReconstructor:
 
         logger.info(f"Processing {input_file} as {format_type}...")
 
-        # Create output directory if it doesn't exist
-        self.output_dir.mkdir(parents=True, exist_ok=True)
-
         # Process based on format
         if format_type == "markdown":
-            files = self._parse_markdown(input_path)
-        elif format_type == "xml":
-            files = self._parse_xml(input_path)
-        elif format_type == "json":
-            files = self._parse_json(input_path)
-        else:
-            raise ValueError(f"Unsupported format: {format_type}")
-
-        # Write files
-        for file_path, content in files.items():
-            self._write_file(file_path, content)
-
-        logger.info("\nReconstruction complete!")
-        logger.info(f"Files processed: {self.files_processed}")
-        logger.info(f"Files created: {self.files_created}")
-        logger.info(f"Errors: {self.errors}")
-
-        return {
-            "files_processed": self.files_processed,
-            "files_created": self.files_created,
-            "errors": self.errors,
-        }
+            return self._parse_markdown(input_path)
+        if format_type == "xml":
+            return self._parse_xml(input_path)
+        if format_type == "json":
+            return self._parse_json(input_path)
+        raise ValueError(f"Unsupported format: {format_type}")
 
     def _is_diff_fence(self, info: str) -> bool:
         token = info.strip().split(" ", 1)[0].lower() if info else ""
@@ -0,0 +1,216 @@
+"""Strict offline mode for air-gapped environments.
+
+Offline mode is a process-wide switch that is kept in the environment so
+that parser worker processes inherit it. When it is on, every feature that
+needs the network either refuses to start with an :class:`OfflineError` or,
+for incidental downloads such as tokenizer vocabularies, falls back to the
+local behaviour that is used when the download is unavailable.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import logging
+import os
+import tempfile
+from typing import Any
+
+from ..errors import ConfigurationError
+
+logger = logging.getLogger(__name__)
+
+OFFLINE_ENV = "CODECONCAT_OFFLINE"
+
+# Where tiktoken downloads its BPE files; the cache key is the SHA-1 of the URL
+_TIKTOKEN_BLOB_URL = "https://openaipublic.blob.core.windows.net/encodings/{}.tiktoken"
+
+_warned: set[str] = set()
+
+
+class OfflineError(ConfigurationError):
+    """A feature would need network access while offline mode is enabled."""
+
+
+def is_offline() -> bool:
+    """Whether strict offline mode is enabled for this process."""
+    return os.environ.get(OFFLINE_ENV, "").lower() in ("1", "true", "yes", "on")
+
+
+def enable_offline_mode() -> None:
+    """Turn on offline mode for this process and the processes it starts.
+
+    Also sets the Hugging Face offline switches so libraries that honour them
+    never try to reach the hub.
+    """
+    os.environ[OFFLINE_ENV] = "1"
+    os.environ["HF_HUB_OFFLINE"] = "1"
+    os.environ["TRANSFORMERS_OFFLINE"] = "1"
+
+
+def require_network(feature: str) -> None:
+    """Raise if ``feature`` would access the network in offline mode.
+
+    Raises:
+        OfflineError: If offline mode is enabled.
+    """
+    if is_offline():
+        raise OfflineError(f"{feature} needs network access, but offline mode is enabled")
+
+
+def warn_once(key: str, message: str) -> None:
+    """Log an offline-mode warning the first time ``key`` is seen."""
+    if key not in _warned:
+        _warned.add(key)
+        logger.warning(message)
+
+
+def tiktoken_cached(encoding_name: str) -> bool:
+    """Whether tiktoken can load ``encoding_name`` without downloading it."""
+    try:
+        import tiktoken.registry
+
+        if encoding_name in tiktoken.registry.ENCODINGS:
+            return True
+    except (ImportError, AttributeError):
+        pass
+
+    if "TIKTOKEN_CACHE_DIR" in os.environ:
+        cache_dir = os.environ["TIKTOKEN_CACHE_DIR"]
+    elif "DATA_GYM_CACHE_DIR" in os.environ:
+        cache_dir = os.environ["DATA_GYM_CACHE_DIR"]
+    else:
+        cache_dir = os.path.join(tempfile.gettempdir(), "data-gym-cache")
+    if not cache_dir:
+        return False
+    key = hashlib.sha1(_TIKTOKEN_BLOB_URL.format(encoding_name).encode()).hexdigest()
+    return os.path.exists(os.path.join(cache_dir, key))
+
+
+def require_tiktoken_encoding(encoding_name: str) -> None:
+    """Raise if loading tiktoken's ``encoding_name`` would download it.
+
+    Callers already fall back to estimated token counts when tiktoken fails,
+    so a warning is logged once and the error lets that fallback take over.
+
+    Raises:
+        OfflineError: If offline mode is enabled and the encoding is not cached.
+    """
+    if not is_offline() or tiktoken_cached(encoding_name):
+        return
+    warn_once(
+        f"tiktoken:{encoding_name}",
+        f"Offline mode: tiktoken encoding '{encoding_name}' is not cached, so token counts "
+        "are estimated. Set TIKTOKEN_CACHE_DIR to a directory holding the encoding files "
+        "for exact counts.",
+    )
+    raise OfflineError(f"tiktoken encoding '{encoding_name}' is not cached locally")
+
+
+def _is_remote_ruleset(ruleset: str | None) -> bool:
+    # Registry names (p/ci, auto) and URLs are fetched by semgrep; None uses the bundled rules
+    return bool(ruleset) and not os.path.exists(str(ruleset))
+
+
+def offline_violations(config: Any) -> list[str]:
+    """List the enabled features of ``config`` that would need the network.
+
+    Args:
+        config: The resolved CodeConCatConfig
+
+    Returns:
+        One human-readable line per feature, with the option that turns it off
+    """
+    from ..ai.base import LOCAL_PROVIDER_TYPES, is_on_prem_endpoint, provider_type_from_name
+
+    violations = []
+    allowed_hosts = getattr(config, "ai_allowed_hosts", None) or []
+
+    if getattr(config, "source_url", None):
+        violations.append(f"remote collection of {config.source_url} (pass a local path instead)")
+
+    from ..collector.multi_source import remote_locations
+
+    for source in remote_locations(
+        getattr(config, "sources", None) or [], getattr(config, "gitlab_url", None)
+    ):
+        violations.append(f"remote collection of {source} (pass a local path instead)")
+
+    archive = str(getattr(config, "archive", None) or "")
+    if archive.startswith(("http://", "https://")):
+        violations.append(f"downloading the archive {archive} (pass a local archive instead)")
+
+    if getattr(config, "enable_ai_summary", False):
+        provider = str(getattr(config, "ai_provider", "") or "")
+        provider_type = provider_type_from_name(provider)
+        settings = (getattr(config, "ai_provider_settings", None) or {}).get(provider, {})
+        api_base = getattr(config, "ai_api_base", None) or settings.get("api_base")
+        if provider_type not in LOCAL_PROVIDER_TYPES:
+            violations.append(
+                f"AI summaries with the '{provider}' provider, which calls a hosted API "
+                "(use a local provider such as ollama or llamacpp, or --no-ai-summary)"
+            )
+        elif not is_on_prem_endpoint(api_base, allowed_hosts):
+            violations.append(
+                f"AI summaries via {api_base}, which is not on the local machine or private "
+                "network (add the host to ai_allowed_hosts if it is self-hosted)"
+            )
+
+    # Task ranking embeds with the configured backend too
+    embeds = getattr(config, "embed", False) or getattr(config, "task", None)
+    if embeds and getattr(config, "embed_backend", "") == "openai":
+        embed_base = getattr(config, "embed_api_base", None)
+        if not embed_base or not is_on_prem_endpoint(embed_base, allowed_hosts):
+            violations.append(
+                f"embeddings from {embed_base or 'the OpenAI API'} "
+                "(use the 'sentence-transformers' or 'hashing' embed_backend, or a local "
+                "embed_api_base)"
+            )
+
+    embed_output = str(getattr(config, "embed_output", "") or "")
+    if (
+        getattr(config, "embed", False)
+        and getattr(config, "embed_store", "") == "qdrant"
+        and embed_output.startswith(("http://", "https://"))
+        and not is_on_prem_endpoint(embed_output, allowed_hosts)
+    ):
+        violations.append(f"exporting embeddings to the Qdrant server {embed_output}")
+
+    if getattr(config, "install_semgrep", False):
+        violations.append("installing semgrep and its ruleset (--install-semgrep)")
+
+    ruleset = getattr(config, "semgrep_ruleset", None)
+    if getattr(config, "enable_semgrep", False) and _is_remote_ruleset(ruleset):
+        violations.append(
+            f"semgrep scanning with the registry ruleset '{ruleset}' "
+            "(set semgrep_ruleset to a local rules path)"
+        )
+
+    return violations
+
+
+def apply_offline_mode(config: Any) -> None:
+    """Enable offline mode for ``config`` and fail fast if it needs the network.
+
+    Does nothing unless ``config.offline`` or the ``CODECONCAT_OFFLINE``
+    environment variable is set. Incidental network use that has a local
+    fallback is switched off instead of being reported.
+
+    Raises:
+        OfflineError: If an enabled feature cannot run without the network.
+    """
+    if not (getattr(config, "offline", False) or is_offline()):
+        return
+    enable_offline_mode()
+    config.offline = True
+
+    violations = offline_violations(config)
+    if violations:
+        listed = "\n".join(f"  - {v}" for v in violations)
+        raise OfflineError(
+            f"Offline mode is enabled, but these features need network access:\n{listed}"
+        )
+
+    ruleset = getattr(config, "semgrep_ruleset", None)
+    if getattr(config, "enable_external_semgrep", False) and _is_remote_ruleset(ruleset):
+        logger.info(f"Offline mode: skipping external semgrep scan with registry ruleset {ruleset}")
+        config.enable_external_semgrep = False
@@ -424,6 +424,8 @@ class InputValidator:
             # Data files
             ".csv",
             ".tsv",
+            ".ndjson",
+            ".jsonl",
             ".xml",
             ".svg",
             # Documentation
@@ -100,7 +100,7 @@ def validate_input_files(
 
             # 2. File size validation (skip for diff mode if file doesn't exist)
             if not is_diff_mode:
-                max_size = getattr(config, "max_file_size", 10 * 1024 * 1024)  # Default 10MB
+                max_size = getattr(config, "max_file_size", None) or 10 * 1024 * 1024  # 10MB
                 file_size = Path(file_path).stat().st_size
                 if file_size > max_size:
                     raise ValidationError(
@@ -191,6 +191,64 @@ def validate_input_files(
     return validated_files
 
 
+def validate_archive_members(
+    files: list[ParsedFileData], config: CodeConCatConfig
+) -> list[ParsedFileData]:
+    """
+    Run the content security checks on files read from an archive.
+
+    Archive members never exist on disk, so the path and size checks of
+    :func:`validate_input_files` do not apply (the archive collector bounds
+    their paths and sizes) and Semgrep, which scans files, is not run. The
+    pattern and attack checks run on the in-memory content instead, with the
+    same reporting and ``strict_security`` rejection.
+
+    Args:
+        files: Files collected from the archive
+        config: The application configuration
+
+    Returns:
+        The files that passed
+
+    Raises:
+        ValidationError: If ``strict_security`` is set and a file has
+            suspicious content
+    """
+    if not (getattr(config, "strict_validation", False) or config.enable_security_scanning):
+        return files
+
+    reporter = get_reporter()
+    validated_files = []
+    rejected = []
+    for file_data in files:
+        if file_data.content is None:
+            continue
+        findings = security_validator.check_content_for_suspicious_patterns(
+            file_data.content, file_data.file_path
+        )
+        for finding in findings:
+            reporter.add_finding(Path(file_data.file_path), finding)
+        if findings and config.strict_security:
+            pattern_str = ", ".join(f.get("name", str(f)) for f in findings)
+            logger.warning(
+                f"Validation error for file {file_data.file_path}: "
+                f"Suspicious content detected: {pattern_str}"
+            )
+            rejected.append(file_data.file_path)
+            continue
+        validated_files.append(file_data)
+
+    reporter.display_summary(verbose=logger.isEnabledFor(logging.INFO))
+
+    if rejected:
+        raise ValidationError(
+            f"Security validation failed for {len(rejected)} archive files with strict_security "
+            f"enabled. First file: {rejected[0]}",
+            field="file_security",
+        )
+    return validated_files
+
+
 def validate_config_values(config: dict[str, Any] | CodeConCatConfig) -> bool:
     """
     Validate configuration values against the schema.
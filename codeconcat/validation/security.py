@@ -261,6 +261,92 @@ class SecurityValidator:
 
         return sanitized
 
+    @staticmethod
+    def check_content_for_suspicious_patterns(
+        content: str, file_path: str | Path
+    ) -> list[dict[str, Any]]:
+        """
+        Check text content for dangerous code patterns without reading a file.
+
+        Runs the pattern and language-specific attack checks of
+        :meth:`check_for_suspicious_content`, so content that never exists on
+        disk, such as archive members, can be scanned too.
+
+        Args:
+            content: The text to scan
+            file_path: Path used to pick the language and to label findings
+
+        Returns:
+            List of detected suspicious patterns, as for
+            :meth:`check_for_suspicious_content`
+        """
+        path = Path(file_path)
+        findings = []
+        pattern_findings = []
+        for name, pattern in DANGEROUS_PATTERNS.items():
+            if pattern.search(content):
+                pattern_findings.append(name)
+                findings.append(
+                    {
+                        "type": "pattern",
+                        "name": name,
+                        "severity": "MEDIUM" if "secrets" in name else "HIGH",
+                        "message": f"Suspicious pattern detected: {name}",
+                        "path": str(path),
+                    }
+                )
+
+        # Log at debug level instead of warning
+        if pattern_findings:
+            logger.debug("Suspicious patterns in %s: %s", path, ", ".join(pattern_findings))
+
+        # Use comprehensive attack patterns for language-specific vulnerabilities
+        # Map file extensions to programming languages
+        extension_map = {
+            ".py": "python",
+            ".js": "javascript",
+            ".jsx": "javascript",
+            ".ts": "typescript",
+            ".tsx": "typescript",
+            ".c": "c",
+            ".cpp": "cpp",
+            ".cc": "cpp",
+            ".cxx": "cpp",
+            ".h": "c",
+            ".hpp": "cpp",
+            ".cs": "csharp",
+            ".go": "go",
+            ".php": "php",
+            ".r": "r",
+            ".R": "r",
+            ".jl": "julia",
+            ".rs": "rust",
+            ".java": "java",
+        }
+
+        suffix = path.suffix.lower()
+        language = extension_map.get(suffix)
+
+        if language:
+            try:
+                attack_findings = scan_attack_patterns(content, language)
+                for finding in attack_findings:
+                    findings.append(
+                        {
+                            "type": "pattern",
+                            "name": finding["name"],
+                            "severity": finding["severity"],
+                            "message": finding["message"],
+                            "path": str(path),
+                            "line": finding.get("line", 0),
+                            "cwe_id": finding.get("cwe_id", ""),
+                        }
+                    )
+            except Exception as e:
+                logger.warning(f"Attack pattern scan failed for {path}: {e}")
+
+        return findings
+
     @staticmethod
     def check_for_suspicious_content(
         file_path: str | Path, use_semgrep: bool = False
@@ -333,23 +419,7 @@ class SecurityValidator:
             with open(path, errors="replace") as f:
                 content = f.read()
 
-            pattern_findings = []
-            for name, pattern in DANGEROUS_PATTERNS.items():
-                if pattern.search(content):
-                    pattern_findings.append(name)
-                    findings.append(
-                        {
-                            "type": "pattern",
-                            "name": name,
-                            "severity": "MEDIUM" if "secrets" in name else "HIGH",
-                            "message": f"Suspicious pattern detected: {name}",
-                            "path": str(path),
-                        }
-                    )
-
-            # Log at debug level instead of warning
-            if pattern_findings:
-                logger.debug("Suspicious patterns in %s: %s", path, ", ".join(pattern_findings))
+            findings.extend(SecurityValidator.check_content_for_suspicious_patterns(content, path))
 
             # Use semgrep if available and requested
             if use_semgrep and semgrep_validator.is_available():
@@ -362,51 +432,6 @@ class SecurityValidator:
                 except Exception as e:
                     logger.warning(f"Semgrep scan failed for {path}: {e}")
 
-            # Use comprehensive attack patterns for language-specific vulnerabilities
-            # Map file extensions to programming languages
-            extension_map = {
-                ".py": "python",
-                ".js": "javascript",
-                ".jsx": "javascript",
-                ".ts": "typescript",
-                ".tsx": "typescript",
-                ".c": "c",
-                ".cpp": "cpp",
-                ".cc": "cpp",
-                ".cxx": "cpp",
-                ".h": "c",
-                ".hpp": "cpp",
-                ".cs": "csharp",
-                ".go": "go",
-                ".php": "php",
-                ".r": "r",
-                ".R": "r",
-                ".jl": "julia",
-                ".rs": "rust",
-                ".java": "java",
-            }
-
-            suffix = path.suffix.lower()
-            language = extension_map.get(suffix)
-
-            if language:
-                try:
-                    attack_findings = scan_attack_patterns(content, language)
-                    for finding in attack_findings:
-                        findings.append(
-                            {
-                                "type": "pattern",
-                                "name": finding["name"],
-                                "severity": finding["severity"],
-                                "message": finding["message"],
-                                "path": str(path),
-                                "line": finding.get("line", 0),
-                                "cwe_id": finding.get("cwe_id", ""),
-                            }
-                        )
-                except Exception as e:
-                    logger.warning(f"Attack pattern scan failed for {path}: {e}")
-
             return findings
 
         except Exception as e:
@@ -16,6 +16,7 @@ import tempfile
 from pathlib import Path
 
 from ..errors import ValidationError
+from ..utils.offline import require_network
 
 logger = logging.getLogger(__name__)
 
@@ -42,6 +43,7 @@ def install_semgrep():
     Returns:
         bool: True if installation successful, False otherwise
     """
+    require_network("Installing semgrep")
     try:
         logger.info(f"Installing semgrep version {SEMGREP_VERSION}...")
 
@@ -107,6 +109,8 @@ def install_apiiro_ruleset(target_dir: str | None = None):
     Raises:
         ValidationError: If installation fails
     """
+    require_network("Installing the Apiiro ruleset")
+
     # Determine target directory
     if target_dir is None:
         target_path = Path(__file__).parent / "rules" / "apiiro-ruleset"
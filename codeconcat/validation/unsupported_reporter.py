@@ -143,6 +143,7 @@ class UnsupportedFilesReporter:
                 "parse_error": "Parse Errors",
                 "permission_denied": "Permission Denied",
                 "too_large": "File Too Large",
+                "filtered": "Filtered by Size, Age or Author",
             }
 
             for category, count in sorted(stats["categories"].items()):
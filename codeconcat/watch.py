@@ -0,0 +1,203 @@
+"""Watch mode: regenerate the output whenever the input directory changes.
+
+The watcher polls the modification times of the files under the target
+directory, so it needs no platform file-notification support. Directories
+excluded from collection and files ignored by ``.gitignore`` or
+``.codeconcatignore`` are not watched, and neither are CodeConCat's own
+outputs, so writing the output does not trigger another run.
+
+Each regeneration runs the full pipeline on a copy of the configuration.
+Parse results of unchanged files are reused from a :class:`ParseCache` kept
+for the whole session, and AI summaries come from the on-disk summary cache,
+so a run after a small edit only re-parses and re-summarizes what changed.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+import time
+from collections.abc import Callable
+from dataclasses import dataclass
+
+from .base_types import CodeConCatConfig
+from .parser.parse_cache import ParseCache
+
+logger = logging.getLogger(__name__)
+
+# Names of files CodeConCat writes next to its input
+OUTPUT_PREFIXES = ("ccc_codeconcat", ".codeconcat_")
+
+Snapshot = dict[str, tuple[int, int]]
+
+
+@dataclass
+class Regeneration:
+    """The result of one run in watch mode."""
+
+    changed: list[str]
+    output: str | None
+    seconds: float
+    parse_hits: int = 0
+    parse_misses: int = 0
+
+
+def _output_stems(config: CodeConCatConfig) -> list[str]:
+    """Absolute paths without extension of the outputs of ``config``.
+
+    Split parts, chunk files and embedding directories start with the stem of
+    the output they belong to.
+    """
+    stems = []
+    for path in (config.output, getattr(config, "chunk_output", None)):
+        if path:
+            stems.append(os.path.splitext(os.path.abspath(path))[0])
+    return stems
+
+
+def is_output_path(path: str, config: CodeConCatConfig) -> bool:
+    """Whether ``path`` is written by CodeConCat rather than part of the input."""
+    if os.path.basename(path).startswith(OUTPUT_PREFIXES):
+        return True
+    absolute = os.path.abspath(path)
+    return any(absolute.startswith(stem) for stem in _output_stems(config))
+
+
+def take_snapshot(root: str, config: CodeConCatConfig) -> Snapshot:
+    """Return the modification time and size of every watched file under ``root``."""
+    from .collector.ignore_rules import get_ignore_rules
+    from .collector.local_collector import should_skip_dir
+
+    root = os.path.abspath(root)
+    if os.path.isfile(root):
+        stat = os.stat(root)
+        return {root: (stat.st_mtime_ns, stat.st_size)}
+
+    ignore_rules = get_ignore_rules(root, config)
+    snapshot: Snapshot = {}
+    for dirpath, dirnames, filenames in os.walk(root):
+        dirnames[:] = [
+            d
+            for d in dirnames
+            if d != ".git" and not should_skip_dir(os.path.join(dirpath, d), config)
+        ]
+        for filename in filenames:
+            path = os.path.join(dirpath, filename)
+            if is_output_path(path, config):
+                continue
+            if ignore_rules.match_file(os.path.relpath(path, root)):
+                continue
+            try:
+                stat = os.stat(path)
+            except OSError:
+                # Deleted between listing and stat; the next poll sees it gone
+                continue
+            snapshot[path] = (stat.st_mtime_ns, stat.st_size)
+    return snapshot
+
+
+def changed_paths(before: Snapshot, after: Snapshot) -> list[str]:
+    """Return the files added, modified or removed between two snapshots."""
+    return sorted(
+        path for path in before.keys() | after.keys() if before.get(path) != after.get(path)
+    )
+
+
+class Watcher:
+    """Regenerate the output of ``config`` when its input changes.
+
+    Args:
+        config: Configuration of a local run; ``target_path`` is watched.
+        interval: Seconds between polls.
+        debounce: Seconds the tree must stay unchanged before a run, so a
+            save that touches several files triggers one regeneration.
+        write: Called with the output text and the run's configuration;
+            writes the output files.
+    """
+
+    def __init__(
+        self,
+        config: CodeConCatConfig,
+        interval: float = 1.0,
+        debounce: float = 0.5,
+        write: Callable[[str, CodeConCatConfig], None] | None = None,
+    ):
+        if not config.target_path:
+            raise ValueError("Watch mode needs a local target path")
+        self.config = config
+        self.interval = interval
+        self.debounce = debounce
+        self.write = write
+        self.parse_cache = ParseCache()
+        self._snapshot: Snapshot = {}
+
+    def regenerate(self, changed: list[str] | None = None) -> Regeneration:
+        """Run the pipeline once and write its output."""
+        from .main import run_codeconcat
+
+        run_config = self.config.model_copy(deep=True)
+        object.__setattr__(run_config, "_parse_cache", self.parse_cache)
+        hits, misses = self.parse_cache.hits, self.parse_cache.misses
+        started = time.monotonic()
+
+        output = run_codeconcat(run_config)
+        if output and self.write is not None:
+            self.write(output, run_config)
+        # The first run may pick the default output name; keep it for later runs
+        if not self.config.output and run_config.output:
+            self.config.output = run_config.output
+
+        return Regeneration(
+            changed=changed or [],
+            output=output,
+            seconds=time.monotonic() - started,
+            parse_hits=self.parse_cache.hits - hits,
+            parse_misses=self.parse_cache.misses - misses,
+        )
+
+    def poll(self) -> list[str]:
+        """Return the files changed since the previous poll, once they have settled."""
+        current = take_snapshot(self.config.target_path, self.config)
+        changed = changed_paths(self._snapshot, current)
+        while changed and self.debounce > 0:
+            time.sleep(self.debounce)
+            settled = take_snapshot(self.config.target_path, self.config)
+            if settled == current:
+                break
+            changed = changed_paths(self._snapshot, settled)
+            current = settled
+        self._snapshot = current
+        return changed
+
+    def run(
+        self,
+        on_run: Callable[[Regeneration], None] | None = None,
+        on_error: Callable[[Exception], None] | None = None,
+        should_stop: Callable[[], bool] | None = None,
+    ) -> None:
+        """Generate the output, then regenerate it after every change until stopped.
+
+        Args:
+            on_run: Called after every regeneration.
+            on_error: Called when a run fails; watching continues. Without it
+                the error is raised.
+            should_stop: Polled between checks; watching ends when it
+                returns True.
+        """
+        self._snapshot = take_snapshot(self.config.target_path, self.config)
+        changed: list[str] = []
+        while True:
+            try:
+                result = self.regenerate(changed)
+                if on_run is not None:
+                    on_run(result)
+            except Exception as e:
+                if on_error is None:
+                    raise
+                on_error(e)
+            # Outputs written by the run are not watched, but pick up edits made meanwhile
+            while not (changed := self.poll()):
+                if should_stop is not None and should_stop():
+                    return
+                time.sleep(self.interval)
+            logger.info(f"{len(changed)} files changed; regenerating")
@@ -0,0 +1,182 @@
+"""Per-package runs for monorepos.
+
+With ``per_package`` enabled the workspace declared at the target is
+detected (see :mod:`codeconcat.collector.workspaces`) and the pipeline runs
+once per package, writing each output to ``workspace_output_dir``. An index
+next to them lists every package with its path, file count, output and the
+other packages it depends on, so a reader can pick the packages relevant to
+a question instead of loading the whole monorepo.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import os
+import re
+from collections.abc import Callable
+from dataclasses import dataclass
+
+from .base_types import CodeConCatConfig
+from .collector.workspaces import Workspace, WorkspacePackage, detect_workspace
+from .errors import CodeConcatError, ConfigurationError
+
+logger = logging.getLogger(__name__)
+
+FORMAT_EXTENSIONS = {"markdown": "md", "json": "json", "xml": "xml", "text": "txt"}
+
+
+@dataclass
+class PackageOutput:
+    """The result of the run for one package."""
+
+    package: WorkspacePackage
+    output: str | None = None
+    files: int = 0
+    error: str | None = None
+
+
+@dataclass
+class WorkspaceRun:
+    """The results of a per-package run."""
+
+    workspace: Workspace
+    outputs: list[PackageOutput]
+    index_path: str
+
+
+def output_file_name(name: str, ext: str, taken: set[str]) -> str:
+    """Return a file name for the package ``name`` that is not in ``taken``.
+
+    Scoped npm names such as ``@acme/ui`` become ``acme-ui``.
+    """
+    stem = re.sub(r"[^\w.-]+", "-", name.lstrip("@")).strip("-.") or "package"
+    candidate, number = f"{stem}.{ext}", 2
+    while candidate in taken:
+        candidate, number = f"{stem}-{number}.{ext}", number + 1
+    taken.add(candidate)
+    return candidate
+
+
+def _package_config(
+    config: CodeConCatConfig, workspace: Workspace, package: WorkspacePackage, output: str
+) -> CodeConCatConfig:
+    package_config = config.model_copy(deep=True)
+    package_config.target_path = os.path.normpath(os.path.join(workspace.root, package.path))
+    package_config.output = output
+    package_config.per_package = False
+    package_config.disable_copy = True
+    for nested in workspace.nested_paths(package):
+        package_config.exclude_paths.extend([nested, f"{nested}/**"])
+    return package_config
+
+
+def render_index(run: WorkspaceRun, fmt: str) -> str:
+    """Render the workspace index: JSON for JSON output, Markdown otherwise."""
+    index_dir = os.path.dirname(run.index_path)
+
+    def link(result: PackageOutput) -> str | None:
+        return os.path.relpath(result.output, index_dir) if result.output else None
+
+    if fmt == "json":
+        return json.dumps(
+            {
+                "workspace": {
+                    "root": os.path.basename(run.workspace.root),
+                    "managers": run.workspace.managers,
+                },
+                "packages": [
+                    {
+                        "name": result.package.name,
+                        "path": result.package.path,
+                        "manager": result.package.manager,
+                        "output": link(result),
+                        "files": result.files,
+                        "depends_on": result.package.depends_on,
+                        **({"error": result.error} if result.error else {}),
+                    }
+                    for result in run.outputs
+                ],
+            },
+            indent=2,
+        )
+
+    lines = [
+        f"# Workspace Index: {os.path.basename(run.workspace.root)}",
+        "",
+        f"**Workspace**: {', '.join(run.workspace.managers)}",
+        f"**Packages**: {len(run.outputs)}",
+        "",
+        "| Package | Path | Files | Output | Depends on |",
+        "|---------|------|-------|--------|------------|",
+    ]
+    for result in run.outputs:
+        if result.output:
+            output = f"[{link(result)}]({link(result)})"
+        else:
+            output = f"failed: {result.error}" if result.error else "no output"
+        depends = ", ".join(result.package.depends_on) or "-"
+        lines.append(
+            f"| {result.package.name} | `{result.package.path}` | {result.files} | {output} "
+            f"| {depends} |"
+        )
+    return "\n".join(lines) + "\n"
+
+
+def run_workspace(
+    config: CodeConCatConfig,
+    on_package: Callable[[PackageOutput], None] | None = None,
+) -> WorkspaceRun:
+    """Run the pipeline once per workspace package and write the index.
+
+    A package whose run fails is recorded in the index with its error; the
+    other packages still run.
+
+    Args:
+        config: Configuration of a local run; ``target_path`` is the
+            workspace root.
+        on_package: Called after each package.
+
+    Raises:
+        ConfigurationError: If no workspace is declared at the target.
+    """
+    from .main import _write_output_files, run_codeconcat
+
+    root = config.target_path or "."
+    workspace = detect_workspace(root)
+    if workspace is None:
+        raise ConfigurationError(
+            f"No workspace manifest with packages found in {os.path.abspath(root)} "
+            "(looked for pnpm-workspace.yaml, package.json workspaces, lerna.json, nx.json, "
+            "Cargo.toml [workspace] and go.work)"
+        )
+
+    output_dir = os.path.abspath(config.workspace_output_dir)
+    os.makedirs(output_dir, exist_ok=True)
+    ext = FORMAT_EXTENSIONS.get(config.format, config.format)
+    index_name = "index.json" if config.format == "json" else "index.md"
+    taken = {index_name}
+
+    outputs = []
+    for package in workspace.packages:
+        output = os.path.join(output_dir, output_file_name(package.name, ext, taken))
+        package_config = _package_config(config, workspace, package, output)
+        result = PackageOutput(package)
+        try:
+            text = run_codeconcat(package_config)
+            if text:
+                _write_output_files(text, package_config)
+                result.output = output
+            result.files = getattr(package_config, "_run_stats", {}).get("files_parsed", 0)
+        except CodeConcatError as e:
+            logger.warning(f"Package {package.name} failed: {e}")
+            result.error = str(e)
+        outputs.append(result)
+        if on_package is not None:
+            on_package(result)
+
+    run = WorkspaceRun(workspace, outputs, os.path.join(output_dir, index_name))
+    with open(run.index_path, "w", encoding="utf-8") as f:
+        f.write(render_index(run, config.format))
+    logger.info(f"Wrote {len(outputs)} package outputs and {run.index_path}")
+    return run
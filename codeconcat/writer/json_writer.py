@@ -6,6 +6,16 @@ from typing import Any
 
 from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, ParsedDocData
 from codeconcat.writer.compression_helper import CompressionHelper
+from codeconcat.writer.rendering_adapters import (
+    declaration_gloss,
+    get_architecture_diagram,
+    get_change_summary,
+    get_diff_metadata,
+    get_directory_summaries,
+    get_git_history,
+    get_meta_overview,
+    triage_to_dict,
+)
 
 
 def _get_decl_attr(decl, attr: str, default=None):
@@ -77,8 +87,8 @@ def write_json(
 
     # Add diff metadata if in diff mode
     diff_info = {}
-    if is_diff_mode and items and hasattr(items[0], "diff_metadata") and items[0].diff_metadata:
-        diff_meta = items[0].diff_metadata
+    diff_meta = get_diff_metadata(items)
+    if diff_meta:
         diff_info = {
             "mode": "diff",
             "from_ref": diff_meta.from_ref,
@@ -86,6 +96,27 @@ def write_json(
             "changes": _calculate_diff_statistics(items),
         }
 
+    meta_info: dict[str, Any] = {}
+    change_summary = get_change_summary(items)
+    if change_summary:
+        meta_info["change_summary"] = change_summary
+    meta_overview, meta_source = get_meta_overview(items)
+    if meta_overview:
+        meta_info["meta_overview"] = {"source": meta_source, "content": meta_overview}
+    directory_summaries = get_directory_summaries(items)
+    if directory_summaries:
+        meta_info["directory_summaries"] = directory_summaries
+    diagram, diagram_source = get_architecture_diagram(items)
+    if diagram:
+        meta_info["architecture_diagram"] = {
+            "format": "mermaid",
+            "source": diagram_source,
+            "content": diagram,
+        }
+    git_history = get_git_history(config)
+    if git_history:
+        meta_info["git_history"] = [commit.to_dict() for commit in git_history]
+
     output: dict[str, Any] = {
         "metadata": {
             "version": "2.0",
@@ -99,6 +130,7 @@ def write_json(
             **diff_info,  # Merge diff info if present
         },
         "statistics": _calculate_statistics(items),
+        **meta_info,
         "repository": {},
         "files": [],
         "relationships": {},
@@ -154,20 +186,24 @@ def write_json(
 
         # Add analysis data if available
         if hasattr(item, "declarations") and item.declarations:
+            declarations = []
+            for d in item.declarations:
+                entry = {
+                    "name": _get_decl_attr(d, "name", "unnamed"),
+                    "type": _get_decl_attr(d, "kind", "unknown"),
+                    "line_range": [
+                        _get_decl_attr(d, "start_line", 0),
+                        _get_decl_attr(d, "end_line", 0),
+                    ],
+                    "children_count": len(_get_decl_attr(d, "children", []) or []),
+                }
+                gloss = declaration_gloss(d)
+                if gloss:
+                    entry["summary"] = gloss
+                declarations.append(entry)
             file_data["analysis"] = {
                 "declaration_count": len(item.declarations),
-                "declarations": [
-                    {
-                        "name": _get_decl_attr(d, "name", "unnamed"),
-                        "type": _get_decl_attr(d, "kind", "unknown"),
-                        "line_range": [
-                            _get_decl_attr(d, "start_line", 0),
-                            _get_decl_attr(d, "end_line", 0),
-                        ],
-                        "children_count": len(_get_decl_attr(d, "children", []) or []),
-                    }
-                    for d in item.declarations
-                ],
+                "declarations": declarations,
             }
             indexes["with_declarations"].append(file_path)
 
@@ -180,15 +216,7 @@ def write_json(
             file_data["security"] = {
                 "issue_count": len(item.security_issues),
                 "by_severity": _group_by_severity(item.security_issues),
-                "issues": [
-                    {
-                        "rule": _get_issue_attr(issue, "rule_id", ""),
-                        "severity": _get_severity_str(_get_issue_attr(issue, "severity", "INFO")),
-                        "line": _get_issue_attr(issue, "line_number", 0),
-                        "description": _get_issue_attr(issue, "description", ""),
-                    }
-                    for issue in item.security_issues
-                ],
+                "issues": [_issue_entry(issue) for issue in item.security_issues],
             }
             indexes["with_issues"].append(file_path)
 
@@ -383,6 +411,20 @@ def _group_by_severity(issues: list[Any]) -> dict[str, int]:
     return severity_counts
 
 
+def _issue_entry(issue: Any) -> dict[str, Any]:
+    """Return one security issue for the file's ``security`` section, with its AI triage if any."""
+    entry = {
+        "rule": _get_issue_attr(issue, "rule_id", ""),
+        "severity": _get_severity_str(_get_issue_attr(issue, "severity", "INFO")),
+        "line": _get_issue_attr(issue, "line_number", 0),
+        "description": _get_issue_attr(issue, "description", ""),
+    }
+    triage = triage_to_dict(issue)
+    if triage:
+        entry["triage"] = triage
+    return entry
+
+
 def _build_relationships(
     items: list[AnnotatedFileData | ParsedDocData],
     config: CodeConCatConfig,
@@ -441,6 +483,12 @@ def _sanitize_path(file_path: str, config: CodeConCatConfig) -> str:
             logger.debug(f"Failed to resolve target_path, using cwd: {e}")
             base = Path.cwd()
 
+        # A followed symbolic link keeps its own path, so match it unresolved first
+        try:
+            return p.relative_to(Path(getattr(config, "target_path", ".")).absolute()).as_posix()
+        except ValueError:
+            pass
+
         try:
             rel = p.resolve().relative_to(base)
             return rel.as_posix()
@@ -4,6 +4,17 @@ import os
 import re
 
 from codeconcat.base_types import CodeConCatConfig, Declaration, WritableItem
+from codeconcat.collector.git_history import format_git_history
+from codeconcat.writer.rendering_adapters import (
+    declaration_gloss,
+    get_architecture_diagram,
+    get_change_summary,
+    get_diff_metadata,
+    get_directory_summaries,
+    get_git_history,
+    get_meta_overview,
+    triage_note,
+)
 
 
 def write_markdown(
@@ -55,8 +66,8 @@ def write_markdown(
     is_diff_mode = any(hasattr(item, "diff_metadata") and item.diff_metadata for item in items)
 
     # Title and metadata
-    if is_diff_mode and items and hasattr(items[0], "diff_metadata") and items[0].diff_metadata:
-        diff_meta = items[0].diff_metadata
+    diff_meta = get_diff_metadata(items)
+    if diff_meta:
         output_parts.append(
             f"# Differential Analysis: {diff_meta.from_ref[:7]}...{diff_meta.to_ref[:7]}\n"
         )
@@ -101,19 +112,46 @@ def write_markdown(
 
     output_parts.append("")
 
-    # Add meta-overview if present and position is "top"
-    meta_overview = None
-    if items and hasattr(items[0], "ai_metadata") and items[0].ai_metadata:
-        meta_overview = items[0].ai_metadata.get("meta_overview")
+    change_summary = get_change_summary(items)
+    if change_summary:
+        output_parts.append("## Change Summary\n")
+        output_parts.append("> *AI summary of the changes for reviewers*\n")
+        output_parts.append(change_summary)
+        output_parts.append("\n---\n")
 
-    if meta_overview and getattr(config, "ai_meta_overview_position", "top") == "top":
-        output_parts.append("## AI Meta-Overview\n")
-        output_parts.append(
+    # Add meta-overview if present and position is "top"
+    meta_overview, meta_source = get_meta_overview(items)
+    if meta_source == "heuristic":
+        meta_heading = "## Meta-Overview\n"
+        meta_note = "> *Derived from the README, layout and parsed declarations (no AI)*\n"
+    else:
+        meta_heading = "## AI Meta-Overview\n"
+        meta_note = (
             "> *This comprehensive overview was generated based on all file summaries in the codebase*\n"
         )
+
+    if meta_overview and getattr(config, "ai_meta_overview_position", "top") == "top":
+        output_parts.append(meta_heading)
+        output_parts.append(meta_note)
         output_parts.append(meta_overview)
         output_parts.append("\n---\n")
 
+    directory_summaries = get_directory_summaries(items)
+    if directory_summaries:
+        output_parts.append("## Directory Summaries\n")
+        for directory, summary in directory_summaries.items():
+            output_parts.append(f"### `{directory or '.'}/`\n")
+            output_parts.append(f"{summary}\n")
+        output_parts.append("---\n")
+
+    diagram, diagram_source = get_architecture_diagram(items)
+    if diagram:
+        output_parts.append("## Architecture Diagram\n")
+        if diagram_source == "graph":
+            output_parts.append("> *Imports between components (no AI)*\n")
+        output_parts.append(f"```mermaid\n{diagram}\n```\n")
+        output_parts.append("---\n")
+
     # Table of Contents with anchor links
     output_parts.append("## Table of Contents\n")
     output_parts.append("- [Project Overview](#project-overview)")
@@ -259,7 +297,9 @@ def write_markdown(
                     severity_badge = _get_severity_badge(severity)
                     line_num = _get_issue_attr(issue, "line_number", 0)
                     description = _get_issue_attr(issue, "description", "")
-                    output_parts.append(f"- {severity_badge} **Line {line_num}**: {description}")
+                    entry = f"- {severity_badge} **Line {line_num}**: {description}"
+                    note = triage_note(issue)
+                    output_parts.append(f"{entry} — *AI triage: {note}*" if note else entry)
                 output_parts.append("\n</details>\n")
 
         # File content with syntax highlighting or diff
@@ -295,13 +335,18 @@ def write_markdown(
 
         output_parts.append("---\n")
 
+    git_history = get_git_history(config)
+    if git_history:
+        output_parts.append("## Git History\n")
+        output_parts.append("> *Recent commits, newest first*\n")
+        output_parts.extend(f"- {line}" for line in format_git_history(git_history))
+        output_parts.append("")
+
     # Add meta-overview at bottom if configured
     if meta_overview and getattr(config, "ai_meta_overview_position", "top") == "bottom":
         output_parts.append("\n---\n")
-        output_parts.append("## AI Meta-Overview\n")
-        output_parts.append(
-            "> *This comprehensive overview was generated based on all file summaries in the codebase*\n"
-        )
+        output_parts.append(meta_heading)
+        output_parts.append(meta_note)
         output_parts.append(meta_overview)
 
     # Footer with generation info
@@ -418,7 +463,11 @@ def _render_declarations_tree(declarations: list[Declaration], indent: int = 0)
         start_line = _get_decl_attr(decl, "start_line", 0)
         end_line = _get_decl_attr(decl, "end_line", 0)
         children = _get_decl_attr(decl, "children", [])
-        result.append(f"{prefix}**{kind}** `{name}` (lines {start_line}-{end_line})")
+        line = f"{prefix}**{kind}** `{name}` (lines {start_line}-{end_line})"
+        gloss = declaration_gloss(decl)
+        if gloss:
+            line += f" — {gloss}"
+        result.append(line)
         if children:
             result.append(_render_declarations_tree(children, indent + 1))
     return "\n".join(result)
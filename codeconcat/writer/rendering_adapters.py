@@ -35,6 +35,76 @@ def _get_decl_attr(decl, attr: str, default=None):
     return getattr(decl, attr, default)
 
 
+def get_meta_overview(items) -> tuple[str | None, str]:
+    """Return the project meta-overview attached to the output items and its source.
+
+    The overview is stored in the first item's ``ai_metadata``; the source is
+    ``"ai"`` or ``"heuristic"``.
+    """
+    metadata = getattr(items[0], "ai_metadata", None) if items else None
+    if not metadata:
+        return None, "ai"
+    return metadata.get("meta_overview"), metadata.get("meta_overview_source", "ai")
+
+
+def get_change_summary(items) -> str | None:
+    """Return the AI reviewer changelog attached to diff-mode output items, if any."""
+    metadata = getattr(items[0], "ai_metadata", None) if items else None
+    return (metadata or {}).get("change_summary")
+
+
+def get_diff_metadata(items):
+    """Return the diff metadata of the first changed item; context files have none."""
+    changed = (getattr(item, "diff_metadata", None) for item in items)
+    return next((meta for meta in changed if meta), None)
+
+
+def get_git_history(config) -> list:
+    """Return the condensed git history collected for this run, newest commit first."""
+    return list(getattr(config, "_git_history", None) or [])
+
+
+def get_architecture_diagram(items) -> tuple[str | None, str]:
+    """Return the Mermaid architecture diagram attached to the output items and its source.
+
+    The source is ``"ai"``, or ``"graph"`` when the plain import graph was
+    rendered because no AI diagram was available.
+    """
+    metadata = getattr(items[0], "ai_metadata", None) if items else None
+    if not metadata:
+        return None, "ai"
+    return metadata.get("architecture_diagram"), metadata.get("architecture_diagram_source", "ai")
+
+
+def get_directory_summaries(items) -> dict[str, str]:
+    """Return the AI directory summaries attached to the output items.
+
+    Keys are directory paths relative to the project root ("" for the root
+    itself); summaries are only present with ``ai_hierarchical_summary``.
+    """
+    metadata = getattr(items[0], "ai_metadata", None) if items else None
+    return (metadata or {}).get("directory_summaries") or {}
+
+
+def declaration_gloss(decl, max_chars: int = 160) -> str:
+    """Return a declaration's AI summary collapsed to a single line.
+
+    Whitespace is collapsed and the text is cut at the first sentence end
+    that fits, or at ``max_chars`` with an ellipsis. Returns an empty string
+    when the declaration has no summary.
+    """
+    summary = _get_decl_attr(decl, "ai_summary", None)
+    if not summary:
+        return ""
+    text = " ".join(str(summary).split())
+    if len(text) <= max_chars:
+        return text
+    cut = text.rfind(". ", 0, max_chars)
+    if cut > 0:
+        return text[: cut + 1]
+    return text[: max_chars - 1].rstrip() + "…"
+
+
 def _get_issue_attr(issue, attr: str, default=None):
     """Safely get attribute from security issue (handles both dict and object)."""
     if isinstance(issue, dict):
@@ -42,6 +112,43 @@ def _get_issue_attr(issue, attr: str, default=None):
     return getattr(issue, attr, default)
 
 
+def triage_note(issue) -> str:
+    """Return a security finding's AI triage as one line, or "" when it has none.
+
+    For example ``likely false positive (15%, LOW): Placeholder key in a test``.
+    """
+    triage = _get_issue_attr(issue, "triage", None)
+    if triage is None:
+        return ""
+    note = f"{triage.verdict} ({triage.likelihood:.0%}, {triage.severity.name})"
+    return f"{note}: {triage.rationale}" if triage.rationale else note
+
+
+def triage_to_dict(issue) -> dict[str, Any] | None:
+    """Return a security finding's AI triage for JSON output, or None when it has none."""
+    triage = _get_issue_attr(issue, "triage", None)
+    if triage is None:
+        return None
+    return {
+        "likelihood": round(triage.likelihood, 2),
+        "verdict": triage.verdict,
+        "severity": triage.severity.name,
+        "rationale": triage.rationale,
+    }
+
+
+def add_triage_attributes(element: ET.Element, issue) -> None:
+    """Set the ``triage_*`` attributes of a finding's XML element when it has a triage."""
+    triage = _get_issue_attr(issue, "triage", None)
+    if triage is None:
+        return
+    element.set("triage_likelihood", f"{triage.likelihood:.2f}")
+    element.set("triage_verdict", triage.verdict)
+    element.set("triage_severity", triage.severity.name)
+    if triage.rationale:
+        element.set("triage_rationale", triage.rationale)
+
+
 class MarkdownRenderAdapter:
     """Adapter for rendering structured data to Markdown format."""
 
@@ -85,6 +192,10 @@ class MarkdownRenderAdapter:
                 mods = ", ".join(modifiers)
                 decl_line += f" [{mods}]"
 
+            gloss = declaration_gloss(decl)
+            if gloss:
+                decl_line += f" — {gloss}"
+
             result.append(decl_line)
 
             # Process children with increased indentation
@@ -127,9 +238,14 @@ class MarkdownRenderAdapter:
 
         sorted_issues = sorted(issues, key=get_severity_sort_key, reverse=True)
 
+        triaged = any(triage_note(issue) for issue in issues)
         result = ["### Security Issues\n"]
-        result.append("| Severity | Rule | Line | Description |")
-        result.append("|----------|------|------|-------------|")
+        if triaged:
+            result.append("| Severity | Rule | Line | Description | AI Triage |")
+            result.append("|----------|------|------|-------------|-----------|")
+        else:
+            result.append("| Severity | Rule | Line | Description |")
+            result.append("|----------|------|------|-------------|")
 
         for issue in sorted_issues:
             # Get attributes defensively
@@ -159,7 +275,11 @@ class MarkdownRenderAdapter:
             else:  # INFO
                 severity_display = "ℹ️ INFO"
 
-            result.append(f"| {severity_display} | {rule_id} | {line_number} | {description} |")
+            row = f"| {severity_display} | {rule_id} | {line_number} | {description} |"
+            if triaged:
+                note = triage_note(issue).replace("|", "\\|")
+                row += f" {note} |"
+            result.append(row)
 
         return "\n".join(result)
 
@@ -357,6 +477,12 @@ def _sanitize_path(file_path: str, config: CodeConCatConfig) -> str:
             logger.debug(f"Failed to resolve target_path, using cwd: {e}")
             base = Path.cwd()
 
+        # A followed symbolic link keeps its own path, so match it unresolved first
+        try:
+            return p.relative_to(Path(getattr(config, "target_path", ".")).absolute()).as_posix()
+        except ValueError:
+            pass
+
         try:
             rel = p.resolve().relative_to(base)
             return rel.as_posix()
@@ -392,6 +518,7 @@ class JsonRenderAdapter:
             "end_line": _get_decl_attr(decl, "end_line", 0),
             "modifiers": list(modifiers) if modifiers else [],
             "docstring": _get_decl_attr(decl, "docstring", ""),
+            "ai_summary": declaration_gloss(decl) or None,
             "children": [JsonRenderAdapter.declaration_to_dict(child) for child in children],
         }
 
@@ -401,13 +528,17 @@ class JsonRenderAdapter:
         severity = _get_issue_attr(issue, "severity", "INFO")
         # Handle enum with .name attribute
         severity_str = severity.name if hasattr(severity, "name") else str(severity)
-        return {
+        result = {
             "rule_id": _get_issue_attr(issue, "rule_id", ""),
             "description": _get_issue_attr(issue, "description", ""),
             "line_number": _get_issue_attr(issue, "line_number", 0),
             "severity": severity_str,
             "context": _get_issue_attr(issue, "context", ""),
         }
+        triage = triage_to_dict(issue)
+        if triage:
+            result["triage"] = triage
+        return result
 
     @staticmethod
     def token_stats_to_dict(token_stats: TokenStats | None) -> dict[str, Any] | None:
@@ -541,6 +672,10 @@ class XmlRenderAdapter:
             doc_elem = ET.SubElement(decl_elem, "docstring")
             doc_elem.text = docstring
 
+        gloss = declaration_gloss(decl)
+        if gloss:
+            decl_elem.set("summary", gloss)
+
         # Add children recursively
         children = _get_decl_attr(decl, "children", [])
         if children:
@@ -559,6 +694,7 @@ class XmlRenderAdapter:
         severity = _get_issue_attr(issue, "severity", "INFO")
         severity_str = str(severity.name) if hasattr(severity, "name") else str(severity)
         issue_elem.set("severity", severity_str)
+        add_triage_attributes(issue_elem, issue)
 
         # Add description
         desc_elem = ET.SubElement(issue_elem, "description")
@@ -743,6 +879,10 @@ class TextRenderAdapter:
                 mods = ", ".join(modifiers)
                 decl_line += f" [{mods}]"
 
+            gloss = declaration_gloss(decl)
+            if gloss:
+                decl_line += f" — {gloss}"
+
             result.append(decl_line)
 
             # Process children with increased indentation
@@ -796,6 +936,9 @@ class TextRenderAdapter:
             line_number = _get_issue_attr(issue, "line_number", 0)
             description = _get_issue_attr(issue, "description", "")
             result.append(f"[{severity_name}] {rule_id} - Line {line_number}: {description}")
+            note = triage_note(issue)
+            if note:
+                result.append(f"    AI triage: {note}")
 
         return result
 
@@ -5,6 +5,15 @@ from collections.abc import Sequence
 from typing import Any
 
 from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, ParsedDocData, WritableItem
+from codeconcat.collector.git_history import format_git_history
+from codeconcat.writer.rendering_adapters import (
+    get_architecture_diagram,
+    get_change_summary,
+    get_diff_metadata,
+    get_directory_summaries,
+    get_git_history,
+    get_meta_overview,
+)
 
 # Terminal width constants
 TERM_WIDTH = 80
@@ -61,8 +70,8 @@ def write_text(
     is_diff_mode = any(hasattr(item, "diff_metadata") and item.diff_metadata for item in items)
 
     # Header with box drawing - adjust for diff mode
-    if is_diff_mode and items and hasattr(items[0], "diff_metadata") and items[0].diff_metadata:
-        diff_meta = items[0].diff_metadata
+    diff_meta = get_diff_metadata(items)
+    if diff_meta:
         header_title = f"DIFF: {diff_meta.from_ref[:7]}...{diff_meta.to_ref[:7]}"
     else:
         header_title = "CODECONCAT OUTPUT"
@@ -70,6 +79,27 @@ def write_text(
     output_lines.append(_create_header(header_title))
     output_lines.append("")
 
+    change_summary = get_change_summary(items)
+    if change_summary:
+        output_lines.append(_create_section_header("CHANGE SUMMARY"))
+        output_lines.extend(f"  {line}" if line else "" for line in change_summary.splitlines())
+        output_lines.append("")
+
+    meta_overview, _source = get_meta_overview(items)
+    meta_position = getattr(config, "ai_meta_overview_position", "top")
+    if meta_overview and meta_position == "top":
+        output_lines.extend(_render_meta_overview(meta_overview))
+
+    directory_summaries = get_directory_summaries(items)
+    if directory_summaries:
+        output_lines.extend(_render_directory_summaries(directory_summaries))
+
+    diagram, _diagram_source = get_architecture_diagram(items)
+    if diagram:
+        output_lines.append(_create_section_header("ARCHITECTURE DIAGRAM (MERMAID)"))
+        output_lines.extend(f"  {line}" for line in diagram.splitlines())
+        output_lines.append("")
+
     # Summary section
     output_lines.append(_create_section_header("SUMMARY"))
     stats = _calculate_statistics(items)
@@ -204,6 +234,20 @@ def write_text(
 
         output_lines.append("")
 
+    git_history = get_git_history(config)
+    if git_history:
+        output_lines.append(_create_section_header("GIT HISTORY"))
+        for line in format_git_history(git_history):
+            output_lines.extend(
+                textwrap.wrap(
+                    line, width=TERM_WIDTH, initial_indent="  ", subsequent_indent="      "
+                )
+            )
+        output_lines.append("")
+
+    if meta_overview and meta_position == "bottom":
+        output_lines.extend(_render_meta_overview(meta_overview))
+
     # Footer
     output_lines.append(_create_footer())
 
@@ -239,6 +283,24 @@ def _create_file_header(file_path: str, current: int, total: int) -> str:
     return header
 
 
+def _render_meta_overview(meta_overview: str) -> list[str]:
+    """Render the project meta-overview section."""
+    lines = [_create_section_header("META-OVERVIEW")]
+    lines.extend(f"  {line}" if line else "" for line in meta_overview.splitlines())
+    lines.append("")
+    return lines
+
+
+def _render_directory_summaries(summaries: dict[str, str]) -> list[str]:
+    """Render the AI directory summaries section."""
+    lines = [_create_section_header("DIRECTORY SUMMARIES")]
+    for directory, summary in summaries.items():
+        lines.append(f"  {directory or '.'}/")
+        lines.extend(f"    {line}" if line else "" for line in summary.splitlines())
+        lines.append("")
+    return lines
+
+
 def _create_footer() -> str:
     """Create a footer."""
     footer = SEPARATOR_CHAR * TERM_WIDTH + "\n"
@@ -5,6 +5,16 @@ from xml.dom import minidom
 
 from codeconcat.base_types import CodeConCatConfig, WritableItem
 from codeconcat.writer.compression_helper import CompressionHelper
+from codeconcat.writer.rendering_adapters import (
+    add_triage_attributes,
+    declaration_gloss,
+    get_architecture_diagram,
+    get_change_summary,
+    get_diff_metadata,
+    get_directory_summaries,
+    get_git_history,
+    get_meta_overview,
+)
 
 
 def _get_decl_attr(decl, attr: str, default=None):
@@ -51,8 +61,8 @@ def write_xml(
         ET.SubElement(metadata, "analysis_type").text = "differential"
 
         # Add diff-specific metadata
-        if items and hasattr(items[0], "diff_metadata") and items[0].diff_metadata:
-            diff_meta = items[0].diff_metadata
+        diff_meta = get_diff_metadata(items)
+        if diff_meta:
             diff_info = ET.SubElement(metadata, "diff_info")
             ET.SubElement(diff_info, "from_ref").text = diff_meta.from_ref
             ET.SubElement(diff_info, "to_ref").text = diff_meta.to_ref
@@ -69,6 +79,27 @@ def write_xml(
     else:
         ET.SubElement(metadata, "analysis_type").text = "full_codebase"
 
+    # Project-level overview before navigation so it is read first
+    change_summary = get_change_summary(items)
+    if change_summary:
+        ET.SubElement(root, "change_summary").text = change_summary
+
+    meta_overview, meta_source = get_meta_overview(items)
+    if meta_overview:
+        ET.SubElement(root, "meta_overview", source=meta_source).text = meta_overview
+
+    directory_summaries = get_directory_summaries(items)
+    if directory_summaries:
+        directories = ET.SubElement(root, "directory_summaries")
+        for directory, summary in directory_summaries.items():
+            ET.SubElement(directories, "directory", path=f"{directory or '.'}/").text = summary
+
+    diagram, diagram_source = get_architecture_diagram(items)
+    if diagram:
+        ET.SubElement(
+            root, "architecture_diagram", format="mermaid", source=diagram_source
+        ).text = diagram
+
     # Navigation section with clear hierarchy
     if config.include_repo_overview:
         navigation = ET.SubElement(root, "navigation")
@@ -167,13 +198,16 @@ def write_xml(
                 for decl in item.declarations:
                     start_line = _get_decl_attr(decl, "start_line", 0)
                     end_line = _get_decl_attr(decl, "end_line", 0)
-                    ET.SubElement(
+                    decl_elem = ET.SubElement(
                         declarations,
                         "declaration",
                         type=_get_decl_attr(decl, "kind", "unknown"),
                         name=_get_decl_attr(decl, "name", "unnamed"),
                         lines=f"{start_line}-{end_line}",
                     )
+                    gloss = declaration_gloss(decl)
+                    if gloss:
+                        decl_elem.set("summary", gloss)
 
             # Add security findings (respect mask_output_content)
             if (
@@ -189,13 +223,15 @@ def write_xml(
                         severity_str = str(severity.name)
                     else:
                         severity_str = str(severity)
-                    ET.SubElement(
+                    issue_elem = ET.SubElement(
                         security,
                         "issue",
                         severity=severity_str,
                         line=str(_get_issue_attr(issue, "line_number", 0)),
                         rule=_get_issue_attr(issue, "rule_id", ""),
-                    ).text = _get_issue_attr(issue, "description", "")
+                    )
+                    issue_elem.text = _get_issue_attr(issue, "description", "")
+                    add_triage_attributes(issue_elem, issue)
 
         # File content with CDATA preservation
         if hasattr(item, "diff_content") and item.diff_content:
@@ -223,6 +259,25 @@ def write_xml(
                     )
                     seg_elem.text = seg.content
 
+    git_history = get_git_history(config)
+    if git_history:
+        history = ET.SubElement(root, "git_history")
+        for commit in git_history:
+            commit_elem = ET.SubElement(
+                history,
+                "commit",
+                sha=commit.sha,
+                author=commit.author,
+                date=commit.date,
+                insertions=str(commit.insertions),
+                deletions=str(commit.deletions),
+            )
+            ET.SubElement(commit_elem, "subject").text = commit.subject
+            if commit.files_changed:
+                files_elem = ET.SubElement(commit_elem, "files", count=str(commit.files_changed))
+                for path in commit.files:
+                    ET.SubElement(files_elem, "file", path=path)
+
     # Relationships section for understanding connections
     relationships = ET.SubElement(root, "file_relationships")
     relationships.text = "File dependency and import relationships would be analyzed here"
@@ -424,3 +424,38 @@ def test_meta_overview_position_bottom(test_project_files):
     details_index = next(i for i, line in enumerate(lines) if "File Details" in line)
 
     assert meta_index > details_index
+
+
+def test_heuristic_meta_overview_in_outputs(test_project_files):
+    """Test a heuristic overview is labelled as such and placed at the top of each format."""
+    import json
+
+    from codeconcat.base_types import AnnotatedFileData
+    from codeconcat.writer.json_writer import write_json
+    from codeconcat.writer.markdown_writer import write_markdown
+    from codeconcat.writer.xml_writer import write_xml
+
+    test_files = [
+        AnnotatedFileData(
+            file_path="main.py",
+            language="python",
+            content="test content",
+            annotated_content="test content",
+            ai_metadata={
+                "meta_overview": "### Purpose\n\nHeuristic overview text.",
+                "meta_overview_source": "heuristic",
+            },
+        ),
+    ]
+    config = CodeConCatConfig(target_path=str(test_project_files), format="markdown")
+
+    markdown = write_markdown(test_files, config)
+    assert "## Meta-Overview" in markdown
+    assert "AI Meta-Overview" not in markdown
+    assert markdown.index("Heuristic overview text.") < markdown.index("Table of Contents")
+
+    data = json.loads(write_json(test_files, config))
+    assert data["meta_overview"]["source"] == "heuristic"
+    assert list(data).index("meta_overview") < list(data).index("files")
+
+    assert '<meta_overview source="heuristic">' in write_xml(test_files, config)
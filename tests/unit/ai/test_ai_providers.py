@@ -1,11 +1,66 @@
 """Unit tests for AI provider implementations."""
 
+from functools import partial
 from unittest.mock import AsyncMock, Mock, patch
 
 import pytest
 
-from codeconcat.ai.base import AIProviderConfig, AIProviderType, SummarizationResult
+from codeconcat.ai.base import (
+    AIProviderConfig,
+    AIProviderType,
+    ProviderAPIError,
+    RateLimiter,
+    SummarizationResult,
+    is_on_prem_endpoint,
+    language_name,
+    parse_retry_after,
+    render_prompt_template,
+)
 from codeconcat.ai.factory import get_ai_provider, list_available_providers
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.processor.summarization_processor import SummarizationProcessor
+
+# Twenty lines of Python, long enough to be summarized
+PYTHON_BODY = "\n".join(f"value_{i} = compute({i})" for i in range(20))
+
+
+@pytest.fixture
+def provider_factory():
+    """Patch the provider factory of the summarization processor; it returns a Mock."""
+    with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+        factory.return_value = Mock()
+        yield factory
+
+
+@pytest.fixture
+def make_processor(provider_factory):
+    """Return a function building a SummarizationProcessor for Ollama.
+
+    ``provider``, if given, is what the patched provider factory returns, and
+    keyword arguments override the config.
+    """
+
+    def make(provider=None, progress_callback=None, **overrides):
+        if provider is not None:
+            provider_factory.return_value = provider
+        settings = {"enable_ai_summary": True, "ai_provider": "ollama", **overrides}
+        return SummarizationProcessor(CodeConCatConfig(**settings), progress_callback)
+
+    return make
+
+
+def _ollama_provider(**overrides):
+    """A real Ollama provider with caching off; requests are patched by the tests."""
+    return get_ai_provider(
+        AIProviderConfig(
+            provider_type=AIProviderType.OLLAMA, model="llama3.2", cache_enabled=False, **overrides
+        )
+    )
+
+
+def _python_files(*paths, content=PYTHON_BODY):
+    """Parsed Python files under ``/repo``."""
+    return [ParsedFileData(f"/repo/{path}", content, "python") for path in paths]
 
 
 class TestAIProviderBase:
@@ -167,6 +222,34 @@ class TestOpenAIProvider:
             assert result.error == "API Error"
             assert result.provider == "openai"
 
+    @pytest.mark.asyncio
+    async def test_openai_complete_sends_prompt_verbatim(self):
+        """Test free-form completion passes the prompt and system prompt unchanged."""
+        config = AIProviderConfig(
+            provider_type=AIProviderType.OPENAI, api_key="test-key", cache_enabled=False
+        )
+
+        from codeconcat.ai.providers.openai_provider import OpenAIProvider
+
+        provider = OpenAIProvider(config)
+        mock_response = {
+            "choices": [{"message": {"content": " Retries are configured in [1]. "}}],
+            "usage": {"prompt_tokens": 40, "completion_tokens": 8, "total_tokens": 48},
+        }
+
+        with patch.object(provider, "_make_api_call", new_callable=AsyncMock) as mock_api:
+            mock_api.return_value = mock_response
+
+            result = await provider.complete("Where are retries?", system_prompt="Be brief.")
+
+            messages = mock_api.call_args.args[0]
+            assert messages == [
+                {"role": "system", "content": "Be brief."},
+                {"role": "user", "content": "Where are retries?"},
+            ]
+            assert result.summary == "Retries are configured in [1]."
+            assert result.tokens_used == 48
+
 
 class TestAnthropicProvider:
     """Tests for the Anthropic provider implementation."""
@@ -182,6 +265,30 @@ class TestAnthropicProvider:
             assert provider.config.api_base == "https://api.anthropic.com/v1"
             assert provider.config.model == "claude-3-5-haiku-20241022"  # Updated to current model
 
+    @pytest.mark.asyncio
+    async def test_anthropic_complete(self):
+        """Test free-form completion reads the Anthropic content blocks."""
+        config = AIProviderConfig(
+            provider_type=AIProviderType.ANTHROPIC, api_key="test-key", cache_enabled=False
+        )
+        provider = get_ai_provider(config)
+        mock_response = {
+            "content": [{"type": "text", "text": "It uses a token bucket."}],
+            "usage": {"input_tokens": 30, "output_tokens": 6},
+        }
+
+        with patch.object(provider, "_make_api_call", new_callable=AsyncMock) as mock_api:
+            mock_api.return_value = mock_response
+
+            result = await provider.complete("How is rate limiting done?")
+
+            assert mock_api.call_args.args[0] == [
+                {"role": "user", "content": "How is rate limiting done?"}
+            ]
+            assert result.summary == "It uses a token bucket."
+            assert result.tokens_used == 36
+            assert result.error is None
+
 
 class TestProviderFactory:
     """Tests for the AI provider factory."""
@@ -246,3 +353,563 @@ class TestProviderFactory:
             # Should always have openrouter and ollama (mocked as available)
             assert "openrouter" in providers
             assert "ollama" in providers
+
+
+class TestLocalOnly:
+    """Tests for the on-prem (local-only) endpoint guard."""
+
+    @pytest.mark.parametrize(
+        "api_base",
+        [
+            None,
+            "http://localhost:11434",
+            "http://127.0.0.1:8000/v1",
+            "http://10.0.4.12:8080",
+            "http://192.168.1.20",
+            "http://[::1]:8000",
+            "localhost:11434",
+        ],
+    )
+    def test_on_prem_endpoints_accepted(self, api_base):
+        """Test loopback and private endpoints are accepted."""
+        assert is_on_prem_endpoint(api_base)
+
+    @pytest.mark.parametrize(
+        "api_base",
+        ["https://api.openai.com/v1", "http://8.8.8.8:8000", "http://gpu.corp.example:8000"],
+    )
+    def test_external_endpoints_rejected(self, api_base):
+        """Test public addresses and unlisted hostnames are rejected."""
+        assert not is_on_prem_endpoint(api_base)
+
+    def test_allowed_hosts(self):
+        """Test explicitly approved hostnames are accepted."""
+        assert is_on_prem_endpoint("http://GPU.corp.example:8000", ["gpu.corp.example"])
+
+    def test_cloud_provider_refused(self, make_processor, provider_factory):
+        """Test summarization is disabled for cloud providers in local-only mode."""
+        processor = make_processor(ai_provider="openai", ai_api_key="test", ai_local_only=True)
+
+        provider_factory.assert_not_called()
+        assert processor.ai_provider is None
+
+    def test_external_local_server_refused(self, make_processor, provider_factory):
+        """Test an OpenAI-compatible server on a public host is refused."""
+        processor = make_processor(
+            ai_provider="local_server",
+            ai_api_base="https://inference.example.com/v1",
+            ai_local_only=True,
+        )
+
+        provider_factory.assert_not_called()
+        assert processor.ai_provider is None
+
+
+class TestFunctionSummaries:
+    """Tests for declaration-level summaries."""
+
+    @pytest.fixture
+    def make(self, make_processor):
+        return partial(make_processor, ai_summarize_functions=True, ai_min_function_lines=5)
+
+    @staticmethod
+    def _declarations():
+        from codeconcat.base_types import Declaration
+
+        method = Declaration("method", "run", 3, 12)
+        helper = Declaration("method", "_helper", 13, 30)
+        return [
+            Declaration("class", "Worker", 1, 30, children=[method, helper]),
+            Declaration("function", "tiny", 32, 34),
+            Declaration("function", "_private_big", 36, 60),
+            Declaration("function", "secret", 62, 80, modifiers={"private"}),
+        ]
+
+    def test_public_functions_and_classes_selected(self, make):
+        """Test nested public declarations over the threshold are picked, largest first."""
+        processor = make()
+        selected = processor._select_declarations_to_summarize(self._declarations())
+        assert [d.name for d in selected] == ["Worker", "run"]
+
+    def test_private_included_when_not_public_only(self, make):
+        """Test private declarations are considered when the public filter is off."""
+        processor = make(ai_functions_public_only=False, ai_max_functions_per_file=3)
+        selected = processor._select_declarations_to_summarize(self._declarations())
+        assert [d.name for d in selected] == ["Worker", "_private_big", "secret"]
+
+    @pytest.mark.asyncio
+    async def test_summary_attached_to_declaration(self, make):
+        """Test the summary is stored on the declaration record."""
+        processor = make()
+        processor.ai_provider.summarize_function = AsyncMock(
+            return_value=SummarizationResult(summary="Runs queued jobs.")
+        )
+        declarations = self._declarations()
+        parsed = ParsedFileData(
+            "/repo/worker.py", "\n".join(f"line {i}" for i in range(80)), "python", declarations
+        )
+
+        await processor._add_function_summaries(parsed)
+
+        assert declarations[0].ai_summary == "Runs queued jobs."
+        assert declarations[0].children[0].ai_summary == "Runs queued jobs."
+        assert declarations[1].ai_summary is None
+        call = processor.ai_provider.summarize_function.await_args_list[0]
+        assert call.args[1] == "Worker"
+        assert call.args[3]["kind"] == "class"
+
+
+class TestMetaOverviewPrompt:
+    """Tests for configurable meta-overview prompts."""
+
+    def test_custom_prompt_placeholders(self):
+        """Test placeholders in a custom prompt are filled from the run context."""
+        provider = get_ai_provider(AIProviderConfig(provider_type=AIProviderType.OLLAMA))
+        prompt = provider._create_meta_overview_prompt(
+            {"a.py": "Does A."},
+            tree_structure="└── a.py",
+            context={"languages": {"python": 1}, "total_loc": 1200},
+            custom_prompt="Review {file_count} files ({languages}, {total_loc} LOC):\n{tree}",
+        )
+        assert prompt.startswith("Review 1 files (python: 1 files, 1,200 LOC):\n└── a.py")
+        assert "**a.py**\nDoes A." in prompt
+
+    def test_placeholders_in_values_are_not_filled(self):
+        """Test a tree entry that looks like a placeholder is kept as is."""
+        provider = get_ai_provider(AIProviderConfig(provider_type=AIProviderType.OLLAMA))
+        prompt = provider._create_meta_overview_prompt(
+            {"a.py": "Does A."},
+            tree_structure="└── {total_loc}.py",
+            context={"languages": {"python": 1}, "total_loc": 1200},
+            custom_prompt="{tree}\n{total_loc} LOC",
+        )
+        assert prompt.startswith("└── {total_loc}.py\n1,200 LOC")
+
+    def test_prompt_file_overrides_inline_prompt(self, tmp_path, make_processor):
+        """Test the prompt file wins over the inline prompt."""
+        prompt_file = tmp_path / "overview.txt"
+        prompt_file.write_text("From file")
+        processor = make_processor(
+            ai_meta_overview_prompt="Inline", ai_meta_overview_prompt_file=str(prompt_file)
+        )
+
+        assert processor._resolve_meta_overview_prompt() == "From file"
+        processor.config.ai_meta_overview_prompt_file = str(tmp_path / "missing.txt")
+        assert processor._resolve_meta_overview_prompt() == "Inline"
+
+
+class TestPromptTemplates:
+    """Tests for user prompt template overrides."""
+
+    @staticmethod
+    def _provider(**templates):
+        return get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA, cache_enabled=False, prompt_templates=templates
+            )
+        )
+
+    def test_render_is_single_pass(self):
+        """Test substituted values are not re-expanded and unknown placeholders stay."""
+        rendered = render_prompt_template(
+            "{code} in {path} {unknown}", {"code": "f'{path}'", "path": "a.py"}
+        )
+        assert rendered == "f'{path}' in a.py {unknown}"
+
+    def test_file_template_variables(self):
+        """Test a file template receives language, path and declarations."""
+        provider = self._provider(
+            file="Summarize this {language} pipeline step {path}.\n{declarations}\n{code}"
+        )
+        prompt = provider._create_code_summary_prompt(
+            "def align(reads):\n    pass",
+            "python",
+            {"file_path": "pipeline/align.py", "declarations": ["function align (lines 1-2)"]},
+        )
+        assert prompt == (
+            "Summarize this python pipeline step pipeline/align.py.\n"
+            "- function align (lines 1-2)\n"
+            "def align(reads):\n    pass"
+        )
+
+    def test_function_template_without_code_appends_it(self):
+        """Test the code is appended as a fenced block when the template omits {code}."""
+        provider = self._provider(function="Explain {kind} {name} for a biologist.")
+        prompt = provider._create_function_summary_prompt(
+            "def gc(seq): ...", "gc", "python", {"file_path": "seq.py", "kind": "function"}
+        )
+        assert prompt == "Explain function gc for a biologist.\n\n```python\ndef gc(seq): ...\n```"
+
+    def test_system_prompt_overrides(self):
+        """Test system templates replace only the prompt they name."""
+        provider = self._provider(function_system="You document bioinformatics code.")
+
+        assert provider._system_prompt("function") == "You document bioinformatics code."
+        assert provider._system_prompt("file") == provider.SYSTEM_PROMPT_CODE_SUMMARY
+        assert provider._system_prompt("overview") == provider.SYSTEM_PROMPT_META_OVERVIEW
+
+    def test_templates_change_cache_keys(self, tmp_path):
+        """Test summaries made with other templates are not reused."""
+        from codeconcat.ai.cache import SummaryCache
+
+        def key(templates):
+            config = AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA,
+                cache_dir=str(tmp_path),
+                prompt_templates=templates,
+            )
+            cache = SummaryCache.from_provider_config(config)
+            return cache.generate_key("x = 1", "ollama", "m", "summarize_code")
+
+        assert key({}) != key({"file": "Summarize {code}"})
+        assert key({"file": "A {code}"}) != key({"file": "B {code}"})
+
+    def test_templates_dir_overrides_config(self, tmp_path, make_processor, provider_factory):
+        """Test template files win over inline templates and unknown names are dropped."""
+        (tmp_path / "file.md").write_text("From file {code}")
+        (tmp_path / "overview.txt").write_text("Overview of {file_count} files")
+        processor = make_processor(
+            ai_prompt_templates={"file": "Inline {code}", "function": "Fn", "bogus": "x"},
+            ai_prompt_templates_dir=str(tmp_path),
+        )
+
+        assert processor.prompt_templates == {
+            "file": "From file {code}",
+            "function": "Fn",
+            "overview": "Overview of {file_count} files",
+        }
+        assert provider_factory.call_args.args[0].prompt_templates == processor.prompt_templates
+        assert processor._resolve_meta_overview_prompt() == "Overview of {file_count} files"
+
+
+class TestSummaryLanguage:
+    """Tests for writing summaries in another natural language."""
+
+    @staticmethod
+    def _provider(language, **templates):
+        return get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA,
+                model="llama3.2",
+                cache_enabled=False,
+                prompt_templates=templates,
+                summary_language=language,
+            )
+        )
+
+    def test_language_names(self):
+        """Test codes map to names, unknown values pass through and English needs nothing."""
+        assert language_name("ja") == "Japanese"
+        assert language_name("pt_BR") == "Brazilian Portuguese"
+        assert language_name("de-AT") == "German"
+        assert language_name("Esperanto") == "Esperanto"
+        assert language_name("en") is None
+        assert language_name(" ") is None
+
+    def test_instruction_added_to_every_system_prompt(self):
+        """Test default and overridden system prompts both ask for the language."""
+        provider = self._provider("ja", file_system="You document pipelines.")
+
+        for kind in ("file", "function", "directory", "overview"):
+            assert "Write your entire response in Japanese" in provider._system_prompt(kind)
+        assert provider._system_prompt("file").startswith("You document pipelines.\n\n")
+        assert self._provider("en")._system_prompt("file") == (
+            provider.SYSTEM_PROMPT_CODE_SUMMARY
+        )
+
+    def test_language_changes_cache_keys(self, tmp_path):
+        """Test summaries in one language are not reused for another."""
+        from codeconcat.ai.cache import SummaryCache
+
+        def key(language):
+            config = AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA,
+                cache_dir=str(tmp_path),
+                summary_language=language,
+            )
+            cache = SummaryCache.from_provider_config(config)
+            return cache.generate_key("x = 1", "ollama", "llama3.2", "summarize")
+
+        assert key("ja") != key(None)
+        assert key("ja") == key("Japanese")
+        assert key("en") == key(None)
+
+
+class TestCostCap:
+    """Tests for AI cost estimation and the spend cap."""
+
+    @pytest.fixture
+    def make(self, make_processor):
+        def build(input_cost=1.0, output_cost=2.0, **overrides):
+            provider = _ollama_provider(max_tokens=300)
+            # Local providers zero their prices; set list prices as for a paid model
+            provider.config.cost_per_1k_input_tokens = input_cost
+            provider.config.cost_per_1k_output_tokens = output_cost
+            return make_processor(provider, ai_max_concurrent=1, **overrides)
+
+        return build
+
+    @staticmethod
+    def _files(count=2):
+        files = _python_files(*(f"m{i}.py" for i in range(count)))
+        return files + _python_files("short.py", content="x = 1")
+
+    def test_estimate_counts_prompts_and_reply_limit(self, make):
+        """Test each summarizable file is one request priced at prompt plus max reply tokens."""
+        processor = make()
+        with patch(
+            "codeconcat.processor.summarization_processor.TokenCounter.count_tokens",
+            side_effect=lambda text, model: len(text) // 4,
+        ):
+            estimate = processor.estimate_cost(self._files())
+
+        assert estimate.requests == 2
+        assert estimate.output_tokens == 600
+        assert estimate.input_tokens > 0
+        assert estimate.cost_usd == pytest.approx(
+            estimate.input_tokens / 1000 * 1.0 + estimate.output_tokens / 1000 * 2.0
+        )
+
+    @pytest.mark.asyncio
+    async def test_estimate_over_cap_skips_summarization(self, make):
+        """Test no request is made when the estimate exceeds ai_max_cost_usd."""
+        processor = make(ai_max_cost_usd=0.01)
+        with patch.object(processor.ai_provider, "summarize_code", new_callable=AsyncMock) as call:
+            files = await processor.process_batch(self._files())
+
+        call.assert_not_called()
+        assert all(f.ai_summary is None for f in files)
+        cost = processor.get_statistics()["cost"]
+        assert cost["cap_exceeded"] is True
+        assert cost["estimated_usd"] > 0.01
+        assert cost["actual_usd"] == 0
+
+    @pytest.mark.asyncio
+    async def test_actual_spend_stops_requests_at_cap(self, make):
+        """Test requests stop once actual spend reaches the cap and spend is reported."""
+        processor = make(input_cost=0.0, output_cost=0.0, ai_max_cost_usd=0.05)
+        result = SummarizationResult(
+            summary="Computes values.",
+            cost_estimate=0.05,
+            model_used="llama3.2",
+            metadata={"input_tokens": 100, "output_tokens": 20},
+        )
+        with patch.object(processor.ai_provider, "summarize_code", new_callable=AsyncMock) as call:
+            call.return_value = result
+            files = await processor.process_batch(self._files(count=3))
+
+        assert call.await_count == 1
+        assert sum(1 for f in files if f.ai_summary) == 1
+        cost = processor.get_statistics()["cost"]
+        assert cost["actual_usd"] == 0.05
+        assert cost["input_tokens"] == 100
+        assert cost["cap_exceeded"] is True
+
+    @pytest.mark.asyncio
+    async def test_cap_on_unpriced_cloud_model_skips_summarization(self, make):
+        """Test a cap is not silently ignored for a cloud model without pricing."""
+        processor = make(input_cost=0.0, output_cost=0.0, ai_max_cost_usd=1.0)
+        processor.ai_provider.config.provider_type = AIProviderType.OPENAI
+        with patch.object(processor.ai_provider, "summarize_code", new_callable=AsyncMock) as call:
+            files = await processor.process_batch(self._files())
+
+        call.assert_not_called()
+        assert all(f.ai_summary is None for f in files)
+
+
+class TestRetriesAndRateLimits:
+    """Tests for request retries, rate limiting and partial failures."""
+
+    @staticmethod
+    def _provider(**overrides):
+        return _ollama_provider(retry_delay=0.0, **overrides)
+
+    @pytest.mark.asyncio
+    async def test_rate_limited_request_is_retried_after_retry_after(self):
+        """Test a 429 is retried after the server's Retry-After delay."""
+        provider = self._provider()
+        with (
+            patch.object(provider, "_make_api_call", new_callable=AsyncMock) as call,
+            patch("codeconcat.ai.base.asyncio.sleep", new_callable=AsyncMock) as sleep,
+        ):
+            call.side_effect = [
+                ProviderAPIError("Ollama API error (429): slow down", 429, retry_after=7.0),
+                {"response": "Summary.", "prompt_eval_count": 10, "eval_count": 2},
+            ]
+            result = await provider.summarize_code("x = 1", "python")
+
+        assert result.error is None
+        assert result.summary == "Summary."
+        assert call.await_count == 2
+        assert sleep.await_args_list[0].args[0] == 7.0
+
+    @pytest.mark.asyncio
+    async def test_client_errors_are_not_retried(self):
+        """Test a 401 fails on the first attempt while network errors are retried."""
+        provider = self._provider(max_retries=3)
+        with patch.object(provider, "_make_api_call", new_callable=AsyncMock) as call:
+            call.side_effect = ProviderAPIError("Ollama API error (401): bad key", 401)
+            result = await provider.summarize_code("x = 1", "python")
+
+            assert call.await_count == 1
+            assert "401" in (result.error or "")
+
+            call.reset_mock()
+            call.side_effect = ConnectionError("reset by peer")
+            result = await provider.summarize_code("x = 1", "python")
+
+            assert call.await_count == 3
+            assert result.error == "reset by peer"
+
+    @pytest.mark.asyncio
+    async def test_rate_limiter_spaces_requests(self):
+        """Test requests are spaced at the configured requests per minute."""
+        limiter = RateLimiter(requests_per_minute=60)
+        with patch("codeconcat.ai.base.asyncio.sleep", new_callable=AsyncMock) as sleep:
+            for _ in range(3):
+                await limiter.acquire()
+
+        waits = [c.args[0] for c in sleep.await_args_list]
+        assert len(waits) == 2
+        assert waits[0] == pytest.approx(1.0, abs=0.05)
+        assert waits[1] == pytest.approx(2.0, abs=0.05)
+
+    def test_parse_retry_after(self):
+        """Test delay-seconds headers are parsed and HTTP dates are ignored."""
+        assert parse_retry_after({"Retry-After": "12"}) == 12.0
+        assert parse_retry_after({"Retry-After": "Wed, 21 Oct 2026 07:28:00 GMT"}) is None
+        assert parse_retry_after({}) is None
+
+    @pytest.mark.asyncio
+    async def test_failed_files_do_not_stop_the_batch(self, make_processor, provider_factory):
+        """Test the other files are summarized and failures are reported."""
+        provider = self._provider()
+        processor = make_processor(provider, ai_requests_per_minute=600, ai_max_retries=4)
+        files = _python_files("m0.py", "m1.py", "m2.py")
+
+        async def summarize(code, language, context):
+            if context["file_path"] == "/repo/m1.py":
+                return SummarizationResult(summary="", error="Ollama API error (500): boom")
+            if context["file_path"] == "/repo/m2.py":
+                raise RuntimeError("unexpected")
+            return SummarizationResult(summary="Computes values.")
+
+        with patch.object(provider, "summarize_code", side_effect=summarize):
+            processed = await processor.process_batch(files)
+
+        assert provider_factory.call_args.args[0].requests_per_minute == 600
+        assert provider_factory.call_args.args[0].max_retries == 5
+        assert [f.file_path for f in processed] == [f.file_path for f in files]
+        assert processed[0].ai_summary == "Computes values."
+        failures = processor.get_statistics()["failures"]
+        assert failures["files"] == 2
+        assert failures["failed_files"]["/repo/m1.py"] == "Ollama API error (500): boom"
+        assert failures["failed_files"]["/repo/m2.py"] == "unexpected"
+
+    @pytest.mark.asyncio
+    async def test_batch_reports_per_file_progress(self, make_processor):
+        """Test each finished file reports its tokens, latency and retries."""
+        provider = self._provider()
+        updates = []
+        processor = make_processor(provider, lambda *update: updates.append(update))
+        files = _python_files("pkg/a.py") + _python_files("pkg/tiny.py", content="x = 1")
+        reply = {"response": "Computes values.", "prompt_eval_count": 120, "eval_count": 8}
+        with (
+            patch.object(provider, "_make_api_call", new_callable=AsyncMock) as call,
+            patch("codeconcat.ai.base.asyncio.sleep", new_callable=AsyncMock),
+            patch("codeconcat.processor.summarization_processor.logger") as logger,
+        ):
+            call.side_effect = [ProviderAPIError("Ollama API error (503): busy", 503), reply]
+            await processor.process_batch(files)
+
+        assert [update[:2] for update in updates] == [(1, 2), (2, 2)]
+        messages = dict((update[2].split(" ")[0], update[2]) for update in updates)
+        assert messages["a.py"].startswith("a.py (summarized, 120 tokens, ")
+        assert messages["a.py"].endswith(", 1 retries)")
+        assert messages["tiny.py"] == "tiny.py (skipped)"
+        logged = [c.kwargs["extra"]["ai_progress"] for c in logger.info.call_args_list if c.kwargs]
+        assert logged == [
+            {
+                "file": "/repo/pkg/a.py",
+                "status": "summarized",
+                "done": logged[0]["done"],
+                "total": 2,
+                "requests": 1,
+                "tokens_sent": 120,
+                "latency_s": logged[0]["latency_s"],
+                "retries": 1,
+            }
+        ]
+        assert processor.get_statistics()["retries"] == 1
+
+
+class TestHierarchicalSummaries:
+    """Tests for folding file summaries into directory summaries."""
+
+    @pytest.fixture
+    def make(self, make_processor):
+        return lambda **overrides: make_processor(
+            _ollama_provider(), ai_hierarchical_summary=True, **overrides
+        )
+
+    @staticmethod
+    def _files(*paths):
+        files = _python_files(*paths, content="x = 1")
+        for parsed_file in files:
+            parsed_file.ai_summary = f"Summary of {parsed_file.file_path[6:]}."
+        return files
+
+    @pytest.mark.asyncio
+    async def test_directories_fold_bottom_up(self, make):
+        """Test directories with several children are summarized and single children pass up."""
+        processor = make()
+        files = self._files("pkg/io/read.py", "pkg/io/write.py", "pkg/cli/main.py", "setup.py")
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.side_effect = lambda prompt, **kwargs: SummarizationResult(
+                summary=f"Folded {prompt.count('**') // 2} entries."
+            )
+            generated, top = await processor.summarize_hierarchy(files)
+
+        assert generated == {"pkg/io": "Folded 2 entries.", "pkg": "Folded 2 entries."}
+        assert top == {"pkg/": "Folded 2 entries.", "setup.py": "Summary of setup.py."}
+        first_prompt = call.await_args_list[0].args[0]
+        assert "**pkg/io/read.py**" in first_prompt
+        assert call.await_args_list[0].kwargs["system_prompt"] == (
+            processor.ai_provider.SYSTEM_PROMPT_DIRECTORY_SUMMARY
+        )
+        assert "**pkg/io/**" in call.await_args_list[1].args[0]
+
+    @pytest.mark.asyncio
+    async def test_fan_in_folds_in_parts(self, make):
+        """Test wide directories are folded in parts and the estimate plans the same requests."""
+        from codeconcat.processor.summarization_processor import _hierarchy_plan
+
+        processor = make(ai_hierarchy_fan_in=2)
+        paths = [f"src/m{i}.py" for i in range(5)] + ["README.md"]
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary="Part.")
+            _generated, top = await processor.summarize_hierarchy(self._files(*paths))
+
+        assert call.await_count == 4
+        assert len(top) == 2
+        assert _hierarchy_plan(paths, 2) == ([2, 2, 2, 2], 2)
+
+    @pytest.mark.asyncio
+    async def test_directory_summaries_attached_to_output(self, make):
+        """Test the summaries reach writers without a meta-overview."""
+        from codeconcat.writer.rendering_adapters import get_directory_summaries
+
+        processor = make()
+        files = self._files("lib/a.py", "lib/b.py", "main.py")
+        for parsed_file in files:
+            parsed_file.content = PYTHON_BODY
+        with (
+            patch.object(processor.ai_provider, "summarize_code", new_callable=AsyncMock) as code,
+            patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call,
+        ):
+            code.return_value = SummarizationResult(summary="Computes values.")
+            call.return_value = SummarizationResult(summary="Library helpers.")
+            processed = await processor.process_batch(files)
+
+        assert get_directory_summaries(processed) == {"lib": "Library helpers."}
+        assert "meta_overview" not in processed[0].ai_metadata
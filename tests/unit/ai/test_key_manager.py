@@ -238,11 +238,11 @@ class TestSetupAPIKeys:
         mock_manager = MagicMock()
         mock_manager_class.return_value = mock_manager
 
-        # Simulate user interaction for 8 providers:
+        # Simulate user interaction for 10 providers:
         # openai (y), anthropic (n), openrouter (y), google (n),
-        # deepseek (n), minimax (n), qwen (n), zhipu (n)
+        # deepseek (n), minimax (n), qwen (n), zhipu (n), mistral (n), groq (n)
         # Note: testing is automatic, not prompted
-        mock_input.side_effect = ["y", "n", "y", "n", "n", "n", "n", "n"]
+        mock_input.side_effect = ["y", "n", "y", "n", "n", "n", "n", "n", "n", "n"]
         mock_getpass.side_effect = ["sk-test-openai-key", "sk-or-test-router-key"]
 
         # Mock validation and testing
@@ -267,7 +267,7 @@ class TestSetupAPIKeys:
         mock_manager = MagicMock()
         mock_manager_class.return_value = mock_manager
 
-        # Simulate existing keys for 8 providers (only openai has a key)
+        # Simulate existing keys for 10 providers (only openai has a key)
         mock_manager.get_key.side_effect = [
             "sk-existing-openai",  # openai
             None,  # anthropic
@@ -277,6 +277,8 @@ class TestSetupAPIKeys:
             None,  # minimax
             None,  # qwen
             None,  # zhipu
+            None,  # mistral
+            None,  # groq
         ]
 
         # Run setup
@@ -298,8 +300,8 @@ class TestSetupAPIKeys:
         mock_manager = MagicMock()
         mock_manager_class.return_value = mock_manager
 
-        # User wants to configure OpenAI only, skip all others (8 providers)
-        mock_input.side_effect = ["y", "n", "n", "n", "n", "n", "n", "n"]
+        # User wants to configure OpenAI only, skip all others (10 providers)
+        mock_input.side_effect = ["y", "n", "n", "n", "n", "n", "n", "n", "n", "n"]
         mock_getpass.return_value = "invalid-key"
 
         # Mock validation failure
@@ -322,8 +324,8 @@ class TestSetupAPIKeys:
         mock_manager = MagicMock()
         mock_manager_class.return_value = mock_manager
 
-        # User wants to configure OpenAI only, skip all others (8 providers)
-        mock_input.side_effect = ["y", "n", "n", "n", "n", "n", "n", "n"]
+        # User wants to configure OpenAI only, skip all others (10 providers)
+        mock_input.side_effect = ["y", "n", "n", "n", "n", "n", "n", "n", "n", "n"]
         mock_getpass.return_value = "sk-test-key-but-invalid"
 
         # Mock validation success but test failure
@@ -1,4 +1,4 @@
-"""Unit tests for new AI providers (Google, Zhipu, DeepSeek, MiniMax, Qwen)."""
+"""Unit tests for new AI providers (Google, Zhipu, DeepSeek, MiniMax, Qwen, Mistral, Groq)."""
 
 import json
 from collections.abc import Callable
@@ -17,6 +17,7 @@ class StubResponse:
         self.status = status
         self._payload = payload
         self._text = text_body if text_body is not None else json.dumps(payload or {})
+        self.headers: dict[str, str] = {}
 
     async def __aenter__(self):
         return self
@@ -718,3 +719,358 @@ class TestProviderExports:
         assert "GoogleProvider" in providers.__all__
         assert "ZhipuProvider" in providers.__all__
         assert "LocalServerProvider" in providers.__all__
+
+
+# =============================================================================
+# Mistral and Groq Provider Tests (via LocalServerProvider)
+# =============================================================================
+
+
+class TestMistralGroqProviders:
+    """Tests for the Mistral and Groq OpenAI-compatible presets."""
+
+    @pytest.mark.parametrize(
+        "provider_type, env_var, api_base, model, server_kind",
+        [
+            (
+                AIProviderType.MISTRAL,
+                "MISTRAL_API_KEY",
+                "https://api.mistral.ai",
+                "codestral-latest",
+                "Mistral",
+            ),
+            (
+                AIProviderType.GROQ,
+                "GROQ_API_KEY",
+                "https://api.groq.com/openai",
+                "llama-3.3-70b-versatile",
+                "Groq",
+            ),
+        ],
+    )
+    def test_preset_initialization(self, provider_type, env_var, api_base, model, server_kind):
+        """Test presets resolve key, endpoint, default model and pricing."""
+        with patch.dict("os.environ", {env_var: "test-key"}):
+            provider = get_ai_provider(AIProviderConfig(provider_type=provider_type))
+
+        from codeconcat.ai.providers.local_server_provider import LocalServerProvider
+
+        assert isinstance(provider, LocalServerProvider)
+        assert provider.config.api_key == "test-key"
+        assert provider.config.api_base == api_base
+        assert provider.config.model == model
+        assert provider.server_kind == server_kind
+        assert provider.config.cost_per_1k_input_tokens > 0
+
+    @pytest.mark.asyncio
+    async def test_mistral_summarize_code(self):
+        """Test code summarization against the Mistral chat completions endpoint."""
+        api_base = "https://api.mistral.ai"
+        routes = {
+            ("POST", f"{api_base}/v1/chat/completions"): lambda payload: StubResponse(
+                200,
+                {
+                    "choices": [{"message": {"content": f"Mistral summary ({payload['top_p']})"}}],
+                    "usage": {"prompt_tokens": 40, "completion_tokens": 8, "total_tokens": 48},
+                },
+            ),
+        }
+        config = AIProviderConfig(
+            provider_type=AIProviderType.MISTRAL,
+            api_key="test-key",
+            cache_enabled=False,
+            extra_params={"top_p": 0.9},
+        )
+
+        from codeconcat.ai.providers.local_server_provider import LocalServerProvider
+
+        provider = LocalServerProvider(config)
+        provider._session = FakeSession(routes)
+
+        try:
+            result = await provider.summarize_code("def test(): pass", "python")
+            assert result.summary == "Mistral summary (0.9)"
+            assert result.provider == "mistral"
+            assert result.error is None
+        finally:
+            await provider.close()
+
+    @pytest.mark.parametrize("name", ["mistral", "groq"])
+    def test_provider_info(self, name):
+        """Test get_provider_info describes the new providers."""
+        info = get_provider_info(name)
+
+        assert info["requires_api_key"] is True
+        assert info["env_var"] == f"{name.upper()}_API_KEY"
+        assert info["models"]
+
+
+class TestProviderSelection:
+    """Tests for name resolution and per-provider settings."""
+
+    def test_provider_type_from_name(self):
+        """Test names resolve case-insensitively and unknown names return None."""
+        from codeconcat.ai.base import provider_type_from_name
+
+        assert provider_type_from_name("Mistral") is AIProviderType.MISTRAL
+        assert provider_type_from_name("google") is AIProviderType.GOOGLE
+        assert provider_type_from_name("nope") is None
+        assert provider_type_from_name(None) is None
+
+    def test_every_provider_has_key_env_entry(self):
+        """Test the API key environment map covers every provider type."""
+        from codeconcat.ai.base import PROVIDER_API_KEY_ENV
+
+        assert set(PROVIDER_API_KEY_ENV) == set(AIProviderType)
+
+    def test_provider_settings_applied(self):
+        """Test per-provider settings configure the selected provider only."""
+        from codeconcat.base_types import CodeConCatConfig
+        from codeconcat.processor.summarization_processor import SummarizationProcessor
+
+        config = CodeConCatConfig(
+            enable_ai_summary=True,
+            ai_provider="groq",
+            ai_provider_settings={
+                "groq": {
+                    "model": "llama-3.1-8b-instant",
+                    "api_key_env": "TEAM_GROQ_KEY",
+                    "temperature": 0.1,
+                    "top_p": 0.5,
+                },
+                "mistral": {"model": "mistral-large-latest"},
+            },
+        )
+        with (
+            patch.dict("os.environ", {"TEAM_GROQ_KEY": "gsk_team"}),
+            patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory,
+        ):
+            SummarizationProcessor(config)
+
+        ai_config = factory.call_args.args[0]
+        assert ai_config.provider_type is AIProviderType.GROQ
+        assert ai_config.model == "llama-3.1-8b-instant"
+        assert ai_config.api_key == "gsk_team"
+        assert ai_config.temperature == 0.1
+        assert ai_config.extra_params == {"top_p": 0.5}
+
+    def test_explicit_model_overrides_provider_settings(self):
+        """Test --ai-model wins over the per-provider default model."""
+        from codeconcat.base_types import CodeConCatConfig
+        from codeconcat.processor.summarization_processor import SummarizationProcessor
+
+        config = CodeConCatConfig(
+            enable_ai_summary=True,
+            ai_provider="mistral",
+            ai_api_key="key",
+            ai_model="codestral-latest",
+            ai_provider_settings={"mistral": {"model": "mistral-large-latest"}},
+        )
+        with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+            SummarizationProcessor(config)
+
+        assert factory.call_args.args[0].model == "codestral-latest"
+
+
+# =============================================================================
+# Azure OpenAI Provider Tests
+# =============================================================================
+
+
+class RecordingSession:
+    """Fake aiohttp session that records the posted request and returns one response."""
+
+    def __init__(self, response: StubResponse):
+        self.response = response
+        self.requests: list[dict] = []
+
+    def post(self, url: str, json: dict, headers: dict | None = None):
+        self.requests.append({"url": url, "json": json, "headers": headers or {}})
+        return self.response
+
+    async def close(self):
+        return None
+
+
+AZURE_ENV = {
+    "AZURE_OPENAI_ENDPOINT": "https://contoso.openai.azure.com/",
+    "AZURE_OPENAI_API_KEY": "0123456789abcdef0123456789abcdef",
+}
+
+CHAT_REPLY = {
+    "choices": [{"message": {"content": "Parses the config file."}, "finish_reason": "stop"}],
+    "usage": {"prompt_tokens": 40, "completion_tokens": 6, "total_tokens": 46},
+}
+
+
+class TestAzureOpenAIProvider:
+    """Tests for the Azure OpenAI deployment provider."""
+
+    def test_azure_requires_endpoint_and_deployment(self):
+        """Test missing endpoint or deployment names fail with a clear error."""
+        with patch.dict("os.environ", {}, clear=True):
+            with pytest.raises(ValueError, match="AZURE_OPENAI_ENDPOINT"):
+                get_ai_provider(AIProviderConfig(provider_type=AIProviderType.AZURE_OPENAI))
+        with patch.dict("os.environ", AZURE_ENV, clear=True):
+            with pytest.raises(ValueError, match="deployment"):
+                get_ai_provider(AIProviderConfig(provider_type=AIProviderType.AZURE_OPENAI))
+
+    @pytest.mark.asyncio
+    async def test_azure_calls_deployment_with_api_key(self):
+        """Test requests go to the deployment URL with the api-key header."""
+        with patch.dict("os.environ", AZURE_ENV, clear=True):
+            provider = get_ai_provider(
+                AIProviderConfig(
+                    provider_type=AIProviderType.AZURE_OPENAI,
+                    model="summaries-prod",
+                    cache_enabled=False,
+                    extra_params={"base_model": "gpt-4o-mini", "top_p": 0.9},
+                )
+            )
+        session = RecordingSession(StubResponse(200, CHAT_REPLY))
+        provider._session = session
+
+        result = await provider.summarize_code("def load(): ...", "python")
+
+        assert result.summary == "Parses the config file."
+        assert result.provider == "azure_openai"
+        assert result.cost_estimate > 0  # priced as gpt-4o-mini
+        request = session.requests[0]
+        assert request["url"] == (
+            "https://contoso.openai.azure.com/openai/deployments/summaries-prod"
+            "/chat/completions?api-version=2024-10-21"
+        )
+        assert request["headers"] == {"api-key": AZURE_ENV["AZURE_OPENAI_API_KEY"]}
+        assert request["json"]["top_p"] == 0.9
+        assert "base_model" not in request["json"]
+        assert "model" not in request["json"]
+
+    @pytest.mark.asyncio
+    async def test_azure_entra_token_and_errors(self):
+        """Test an Entra ID token is sent as a bearer token and 429s are retryable errors."""
+        from codeconcat.ai.base import ProviderAPIError, is_retryable_error
+
+        env = {
+            "AZURE_OPENAI_ENDPOINT": "https://contoso.openai.azure.com",
+            "AZURE_OPENAI_AD_TOKEN": "entra-token",
+            "AZURE_OPENAI_DEPLOYMENT": "gpt4o",
+        }
+        with patch.dict("os.environ", env, clear=True):
+            provider = get_ai_provider(
+                AIProviderConfig(provider_type=AIProviderType.AZURE_OPENAI, cache_enabled=False)
+            )
+        session = RecordingSession(StubResponse(429, text_body="Rate limit is exceeded"))
+        provider._session = session
+
+        with pytest.raises(ProviderAPIError) as error:
+            await provider._make_api_call([{"role": "user", "content": "hi"}])
+
+        assert session.requests[0]["headers"] == {"Authorization": "Bearer entra-token"}
+        assert error.value.status == 429
+        assert is_retryable_error(error.value)
+
+
+# =============================================================================
+# AWS Bedrock Provider Tests
+# =============================================================================
+
+
+def _fake_boto3(region: str | None = "us-east-1", credentials: object | None = object()):
+    """Return a stand-in boto3 module whose session has the given region and credentials."""
+    import types
+    from unittest.mock import MagicMock
+
+    session = MagicMock()
+    session.region_name = region
+    session.get_credentials.return_value = credentials
+    module = types.ModuleType("boto3")
+    module.Session = MagicMock(return_value=session)  # type: ignore[attr-defined]
+    return module
+
+
+CONVERSE_REPLY = {
+    "output": {"message": {"role": "assistant", "content": [{"text": "Loads settings."}]}},
+    "usage": {"inputTokens": 30, "outputTokens": 4, "totalTokens": 34},
+}
+
+
+class TestBedrockProvider:
+    """Tests for the AWS Bedrock Converse provider."""
+
+    @staticmethod
+    def _provider(model: str, boto3_module=None):
+        with patch.dict("sys.modules", {"boto3": boto3_module or _fake_boto3()}):
+            return get_ai_provider(
+                AIProviderConfig(
+                    provider_type=AIProviderType.BEDROCK, model=model, cache_enabled=False
+                )
+            )
+
+    def test_bedrock_requires_region_and_credentials(self):
+        """Test a missing region or credential chain fails with a clear error."""
+        with patch.dict("os.environ", {}, clear=True):
+            with pytest.raises(ValueError, match="region"):
+                self._provider("amazon.titan-text-express-v1", _fake_boto3(region=None))
+            with pytest.raises(ValueError, match="credentials"):
+                self._provider("amazon.titan-text-express-v1", _fake_boto3(credentials=None))
+
+    @pytest.mark.asyncio
+    async def test_bedrock_claude_converse_request(self):
+        """Test Claude gets a system prompt, usage is mapped and profile ids are priced."""
+        from unittest.mock import MagicMock
+
+        provider = self._provider("us.anthropic.claude-3-5-haiku-20241022-v1:0")
+        client = MagicMock()
+        client.converse.return_value = CONVERSE_REPLY
+        provider._client = client
+
+        result = await provider.summarize_code("def load(): ...", "python")
+
+        assert result.summary == "Loads settings."
+        assert result.tokens_used == 34
+        assert result.provider == "bedrock"
+        assert provider.config.cost_per_1k_input_tokens == 0.0008
+        request = client.converse.call_args.kwargs
+        assert request["modelId"] == "us.anthropic.claude-3-5-haiku-20241022-v1:0"
+        assert request["system"][0]["text"] == provider._system_prompt("file")
+        assert [m["role"] for m in request["messages"]] == ["user"]
+
+    @pytest.mark.asyncio
+    async def test_bedrock_titan_folds_system_prompt(self):
+        """Test Titan models, which take no system prompt, get it in the first turn."""
+        from unittest.mock import MagicMock
+
+        provider = self._provider("amazon.titan-text-express-v1")
+        client = MagicMock()
+        client.converse.return_value = CONVERSE_REPLY
+        provider._client = client
+
+        result = await provider.complete("Summarize.", system_prompt="Be brief.")
+
+        request = client.converse.call_args.kwargs
+        assert result.summary == "Loads settings."
+        assert "system" not in request
+        assert request["messages"][0]["content"][0]["text"] == "Be brief.\n\nSummarize."
+
+    @pytest.mark.asyncio
+    async def test_bedrock_throttling_is_retryable(self):
+        """Test botocore client errors become ProviderAPIError with the HTTP status."""
+        from unittest.mock import MagicMock
+
+        from codeconcat.ai.base import ProviderAPIError, is_retryable_error
+
+        error = Exception("ThrottlingException")
+        error.response = {  # type: ignore[attr-defined]
+            "Error": {"Code": "ThrottlingException", "Message": "Too many requests"},
+            "ResponseMetadata": {"HTTPStatusCode": 429},
+        }
+        provider = self._provider("anthropic.claude-3-haiku-20240307-v1:0")
+        provider._client = MagicMock()
+        provider._client.converse.side_effect = error
+
+        with pytest.raises(ProviderAPIError) as raised:
+            await provider._make_api_call([{"role": "user", "content": "hi"}])
+
+        assert raised.value.status == 429
+        assert is_retryable_error(raised.value)
+        assert "ThrottlingException" in str(raised.value)
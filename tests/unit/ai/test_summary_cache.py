@@ -0,0 +1,75 @@
+"""Unit tests for the AI summary cache."""
+
+import json
+
+import pytest
+
+from codeconcat.ai import cache as cache_module
+from codeconcat.ai.base import AIProviderConfig, AIProviderType
+from codeconcat.ai.cache import SummaryCache, default_cache_dir
+
+
+class TestCacheKeys:
+    """Tests for cache key composition."""
+
+    def test_key_depends_on_content_model_and_prompt_version(self, tmp_path, monkeypatch):
+        """Test changed content, model or prompt version produce a different key."""
+        cache = SummaryCache(cache_dir=tmp_path)
+        base = cache.generate_key("def f(): return 1", "openai", "gpt-4o", "summarize_code")
+
+        assert base == cache.generate_key("def f(): return 1", "openai", "gpt-4o", "summarize_code")
+        assert base != cache.generate_key("def f(): return 2", "openai", "gpt-4o", "summarize_code")
+        assert base != cache.generate_key("def f(): return 1", "openai", "gpt-5", "summarize_code")
+
+        monkeypatch.setattr(cache_module, "PROMPT_TEMPLATE_VERSION", "test-next")
+        assert base != cache.generate_key("def f(): return 1", "openai", "gpt-4o", "summarize_code")
+
+    def test_formatting_changes_keep_key(self, tmp_path):
+        """Test whitespace and comment-only edits still hit the cache."""
+        cache = SummaryCache(cache_dir=tmp_path)
+        a = cache.generate_key("x = 1\ny = 2", "openai", "m", "summarize_code")
+        b = cache.generate_key("x = 1   # note\n\n   y = 2", "openai", "m", "summarize_code")
+        assert a == b
+
+
+class TestCacheStorage:
+    """Tests for persistence, expiry and statistics."""
+
+    @pytest.mark.asyncio
+    async def test_hits_survive_new_instance(self, tmp_path):
+        """Test summaries persist on disk across runs and hits/misses are counted."""
+        first = SummaryCache(cache_dir=tmp_path)
+        await first.set("abc", "cached summary")
+
+        second = SummaryCache(cache_dir=tmp_path)
+        assert await second.get("abc") == "cached summary"
+        assert await second.get("missing") is None
+
+        stats = second.get_stats()
+        assert stats["hits"] == 1
+        assert stats["misses"] == 1
+
+    @pytest.mark.asyncio
+    async def test_zero_ttl_never_expires(self, tmp_path):
+        """Test ttl=0 keeps entries regardless of age."""
+        (tmp_path / "old.json").write_text(
+            json.dumps({"summary": "ancient", "timestamp": 0, "metadata": {}})
+        )
+
+        assert await SummaryCache(cache_dir=tmp_path, ttl=0).get("old") == "ancient"
+        assert await SummaryCache(cache_dir=tmp_path, ttl=60).get("old") is None
+
+    def test_from_provider_config(self, tmp_path):
+        """Test provider configs control cache directory and TTL."""
+        config = AIProviderConfig(
+            provider_type=AIProviderType.OLLAMA, cache_dir=str(tmp_path / "c"), cache_ttl=0
+        )
+        cache = SummaryCache.from_provider_config(config)
+
+        assert cache.cache_dir == tmp_path / "c"
+        assert cache.ttl == 0
+
+    def test_default_dir_honours_env(self, tmp_path, monkeypatch):
+        """Test CODECONCAT_CACHE_DIR relocates the default cache directory."""
+        monkeypatch.setenv("CODECONCAT_CACHE_DIR", str(tmp_path))
+        assert default_cache_dir() == tmp_path / "ai-summaries"
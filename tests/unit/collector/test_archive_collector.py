@@ -0,0 +1,132 @@
+"""Tests for collecting files from zip and tar archives."""
+
+import io
+import tarfile
+import time
+import zipfile
+from contextlib import contextmanager
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.archive_collector import collect_archive, is_archive
+from codeconcat.errors import FileProcessingError
+
+FILES = {
+    "project-1.2.0/.gitignore": "generated.py\n",
+    "project-1.2.0/src/app.py": "import util\n",
+    "project-1.2.0/src/util.py": "VALUE = 1\n",
+    "project-1.2.0/src/generated.py": "GENERATED = 1\n",
+    "project-1.2.0/node_modules/pkg/index.js": "module.exports = 1\n",
+    "project-1.2.0/tests/test_app.py": "def test(): pass\n",
+}
+
+
+def _tar(path, files, mtime=None):
+    with tarfile.open(path, "w:gz") as archive:
+        for name, content in files.items():
+            data = content.encode()
+            info = tarfile.TarInfo(name)
+            info.size = len(data)
+            info.mtime = time.time() if mtime is None else mtime.get(name, time.time())
+            archive.addfile(info, io.BytesIO(data))
+    return path
+
+
+def _zip(path, files):
+    with zipfile.ZipFile(path, "w") as archive:
+        for name, content in files.items():
+            archive.writestr(name, content)
+    return path
+
+
+def _paths(location, **kwargs):
+    config = CodeConCatConfig(disable_progress_bar=True, **kwargs)
+    return [(f.file_path, f.language) for f in collect_archive(str(location), config)]
+
+
+class TestCollectArchive:
+    """Test reading archive members through the collection filters."""
+
+    @pytest.mark.parametrize("make, name", [(_tar, "project.tar.gz"), (_zip, "project.zip")])
+    def test_formats(self, tmp_path, make, name):
+        """Test the common root is stripped and .gitignore and default skips apply."""
+        archive = make(tmp_path / name, FILES)
+
+        assert _paths(archive, exclude_paths=["tests/**"]) == [
+            ("src/app.py", "python"),
+            ("src/util.py", "python"),
+        ]
+
+    def test_unsafe_members(self, tmp_path):
+        """Test members that escape the root and links are skipped."""
+        path = tmp_path / "evil.tar"
+        with tarfile.open(path, "w") as archive:
+            for name in ("../escape.py", "/etc/abs.py", "ok.py"):
+                info = tarfile.TarInfo(name)
+                info.size = 2
+                archive.addfile(info, io.BytesIO(b"x\n"))
+            link = tarfile.TarInfo("link.py")
+            link.type = tarfile.SYMTYPE
+            link.linkname = "/etc/passwd"
+            archive.addfile(link)
+
+        assert _paths(path) == [("ok.py", "python")]
+
+    def test_read_in_archive_order_returned_sorted(self, tmp_path):
+        """Test members are read once each, front to back, and returned by path."""
+        archive = _tar(tmp_path / "p.tar.gz", {"b.py": "B = 1\n", "a.py": "A = 1\n"})
+        reads = []
+        extract = tarfile.TarFile.extractfile
+
+        def tracking(self, member):
+            reads.append(member.name)
+            return extract(self, member)
+
+        with patch.object(tarfile.TarFile, "extractfile", tracking):
+            assert _paths(archive) == [("a.py", "python"), ("b.py", "python")]
+        assert reads == ["b.py", "a.py"]
+
+    def test_size_and_age_filters(self, tmp_path):
+        """Test max_file_size and modified_since use the archive's sizes and dates."""
+        files = {"big.py": "x = 1\n" * 500, "old.py": "x = 1\n", "new.py": "x = 1\n"}
+        archive = _tar(tmp_path / "p.tar.gz", files, mtime={"old.py": 0})
+
+        assert _paths(archive, max_file_size="1kb", modified_since="30d") == [
+            ("new.py", "python")
+        ]
+
+    def test_not_an_archive(self, tmp_path):
+        """Test unreadable input fails with a clear error."""
+        path = tmp_path / "broken.tar.gz"
+        path.write_bytes(b"not an archive")
+
+        with pytest.raises(FileProcessingError, match="Could not read archive|Not a zip"):
+            collect_archive(str(path), CodeConCatConfig())
+
+    def test_url_is_downloaded_in_memory(self, tmp_path):
+        """Test an archive URL is downloaded and read without touching the disk."""
+        data = _zip(tmp_path / "src.zip", {"repo-main/lib.py": "X = 1\n"}).read_bytes()
+        response = MagicMock()
+        response.iter_bytes.return_value = [data[:10], data[10:]]
+
+        @contextmanager
+        def fake_stream(method, url, **kwargs):
+            yield response
+
+        with patch("httpx.stream", side_effect=fake_stream) as stream:
+            collected = _paths("https://github.com/acme/repo/archive/refs/heads/main.zip")
+
+        assert collected == [("lib.py", "python")]
+        assert stream.call_args.args[1].endswith("/main.zip")
+
+
+def test_is_archive(tmp_path):
+    """Test archives are recognized by suffix, locally only when the file exists."""
+    _zip(tmp_path / "a.zip", {"x.py": ""})
+
+    assert is_archive(str(tmp_path / "a.zip"))
+    assert is_archive("https://example.com/v1.0.tar.gz?download=1")
+    assert not is_archive(str(tmp_path / "missing.tgz"))
+    assert not is_archive("https://github.com/acme/repo")
@@ -0,0 +1,132 @@
+"""Tests for binary and asset stub entries."""
+
+import struct
+import tarfile
+import zipfile
+import zlib
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.asset_stubs import (
+    build_asset_stub,
+    collect_asset_stubs,
+    format_size,
+    guess_mime_type,
+    list_archive,
+    read_image_dimensions,
+)
+
+
+def _png_bytes(width: int, height: int) -> bytes:
+    ihdr = struct.pack(">IIBBBBB", width, height, 8, 2, 0, 0, 0)
+    chunk = b"IHDR" + ihdr
+    return (
+        b"\x89PNG\r\n\x1a\n"
+        + struct.pack(">I", len(ihdr))
+        + chunk
+        + struct.pack(">I", zlib.crc32(chunk))
+    )
+
+
+class TestImageDimensions:
+    """Test header-based image dimension parsing."""
+
+    def test_png(self, tmp_path):
+        """Test PNG IHDR parsing."""
+        path = tmp_path / "logo.png"
+        path.write_bytes(_png_bytes(640, 480))
+        assert read_image_dimensions(str(path)) == (640, 480)
+
+    def test_gif(self, tmp_path):
+        """Test GIF logical screen size parsing."""
+        path = tmp_path / "anim.gif"
+        path.write_bytes(b"GIF89a" + struct.pack("<HH", 32, 16) + b"\x00" * 8)
+        assert read_image_dimensions(str(path)) == (32, 16)
+
+    def test_jpeg(self, tmp_path):
+        """Test JPEG start-of-frame parsing after an APP0 segment."""
+        app0 = b"\xff\xe0" + struct.pack(">H", 16) + b"JFIF\x00" + b"\x00" * 9
+        sof0 = b"\xff\xc0" + struct.pack(">HBHH", 17, 8, 200, 300) + b"\x00" * 10
+        path = tmp_path / "photo.jpg"
+        path.write_bytes(b"\xff\xd8" + app0 + sof0)
+        assert read_image_dimensions(str(path)) == (300, 200)
+
+    def test_unknown_format_returns_none(self, tmp_path):
+        """Test that unrecognized headers yield no dimensions."""
+        path = tmp_path / "icon.ico"
+        path.write_bytes(b"\x00\x00\x01\x00garbage")
+        assert read_image_dimensions(str(path)) is None
+
+
+class TestArchivesAndMetadata:
+    """Test archive listing and basic stub metadata."""
+
+    def test_zip_listing_truncated(self, tmp_path):
+        """Test zip listing respects max entries and reports the total."""
+        path = tmp_path / "bundle.zip"
+        with zipfile.ZipFile(path, "w") as zf:
+            for i in range(5):
+                zf.writestr(f"dir/file{i}.txt", "x")
+        entries, total = list_archive(str(path), max_entries=2)
+        assert total == 5
+        assert entries == ["dir/file0.txt", "dir/file1.txt"]
+
+    def test_tar_listing(self, tmp_path):
+        """Test tar archives are listed."""
+        member = tmp_path / "a.txt"
+        member.write_text("a")
+        path = tmp_path / "data.tar"
+        with tarfile.open(path, "w") as tf:
+            tf.add(member, arcname="a.txt")
+        assert list_archive(str(path), max_entries=10) == (["a.txt"], 1)
+
+    def test_stub_text_without_details(self, tmp_path):
+        """Test disabling details skips dimensions."""
+        path = tmp_path / "logo.png"
+        path.write_bytes(_png_bytes(10, 20))
+        config = CodeConCatConfig(asset_stub_details=False)
+
+        stub = build_asset_stub(str(path), config)
+
+        assert stub is not None
+        assert stub.dimensions is None
+        assert stub.category == "image"
+        text = stub.to_text()
+        assert "MIME type: image/png" in text
+        assert "(content omitted)" in text
+
+    def test_mime_fallback_and_size_format(self):
+        """Test MIME fallbacks and human-readable sizes."""
+        assert guess_mime_type("font.woff2") == "font/woff2"
+        assert guess_mime_type("blob.bin") == "application/octet-stream"
+        assert format_size(512) == "512 B"
+        assert format_size(2048) == "2.0 KB"
+
+
+class TestCollectAssetStubs:
+    """Test the tree walk that produces doc items."""
+
+    def test_collects_assets_and_respects_excludes(self, tmp_path):
+        """Test assets are stubbed, code is ignored, and exclude patterns apply."""
+        (tmp_path / "main.py").write_text("print('hi')\n")
+        (tmp_path / "static").mkdir()
+        (tmp_path / "static" / "logo.png").write_bytes(_png_bytes(64, 64))
+        (tmp_path / "static" / "skip.ttf").write_bytes(b"\x00\x01\x00\x00")
+        (tmp_path / ".git").mkdir()
+        (tmp_path / ".git" / "pack.png").write_bytes(_png_bytes(1, 1))
+
+        config = CodeConCatConfig(
+            target_path=str(tmp_path), exclude_paths=["**/*.ttf"], use_gitignore=False
+        )
+        docs = collect_asset_stubs(str(tmp_path), config)
+
+        assert len(docs) == 1
+        doc = docs[0]
+        assert doc.doc_type == "asset_stub"
+        assert doc.file_path.endswith("logo.png")
+        assert "Dimensions: 64x64 px" in doc.content
+        assert doc.tags == ["asset", "image"]
+
+    def test_missing_directory_returns_empty(self, tmp_path):
+        """Test a non-directory root yields no stubs."""
+        config = CodeConCatConfig()
+        assert collect_asset_stubs(str(tmp_path / "missing"), config) == []
@@ -0,0 +1,201 @@
+"""Tests for lockfile and vendored-dependency summarization."""
+
+import json
+import os
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.dependency_summary import (
+    collect_dependency_summaries,
+    is_lockfile,
+    summarize_lockfile,
+    summarize_vendored_dir,
+)
+
+
+class TestLockfileParsers:
+    """Test parsing of individual lockfile formats."""
+
+    def test_package_lock_v3(self, tmp_path):
+        """Test npm lockfile v3 with root dependencies and duplicate versions."""
+        lock = {
+            "lockfileVersion": 3,
+            "packages": {
+                "": {"dependencies": {"react": "^18.2.0"}, "devDependencies": {"jest": "^29"}},
+                "node_modules/react": {"version": "18.2.0"},
+                "node_modules/jest": {"version": "29.7.0"},
+                "node_modules/ms": {"version": "2.1.3"},
+                "node_modules/debug/node_modules/ms": {"version": "2.0.0"},
+            },
+        }
+        path = tmp_path / "package-lock.json"
+        path.write_text(json.dumps(lock))
+
+        summary = summarize_lockfile(str(path))
+
+        assert summary is not None
+        assert summary.ecosystem == "npm"
+        assert summary.dependency_count == 3
+        assert summary.direct == ["jest", "react"]
+        assert any("ms: multiple versions (2.0.0, 2.1.3)" in n for n in summary.notable_versions())
+
+    def test_yarn_lock_uses_package_json_for_direct(self, tmp_path):
+        """Test Yarn classic lockfile with sibling package.json."""
+        (tmp_path / "yarn.lock").write_text(
+            '# yarn lockfile v1\n\n"lodash@^4.17.0", lodash@^4.17.21:\n'
+            '  version "4.17.21"\n  resolved "https://example"\n\n'
+            'left-pad@1.3.0:\n  version "1.3.0"\n'
+        )
+        (tmp_path / "package.json").write_text(json.dumps({"dependencies": {"lodash": "^4"}}))
+
+        summary = summarize_lockfile(str(tmp_path / "yarn.lock"))
+
+        assert summary is not None
+        assert summary.packages["lodash"] == {"4.17.21"}
+        assert summary.dependency_count == 2
+        assert summary.direct == ["lodash"]
+
+    def test_non_object_package_json_is_ignored(self, tmp_path):
+        """Test a package.json holding an array does not break the lockfile summary."""
+        (tmp_path / "yarn.lock").write_text('left-pad@1.3.0:\n  version "1.3.0"\n')
+        (tmp_path / "package.json").write_text("[1, 2]")
+
+        summary = summarize_lockfile(str(tmp_path / "yarn.lock"))
+
+        assert summary is not None
+        assert summary.direct == []
+
+    def test_cargo_lock_excludes_own_crate(self, tmp_path):
+        """Test Cargo.lock parsing drops the workspace crate itself."""
+        (tmp_path / "Cargo.lock").write_text(
+            '[[package]]\nname = "myapp"\nversion = "0.1.0"\n\n'
+            '[[package]]\nname = "serde"\nversion = "1.0.190"\n\n'
+            '[[package]]\nname = "rand"\nversion = "0.9.0-beta.1"\n'
+        )
+        (tmp_path / "Cargo.toml").write_text(
+            '[package]\nname = "myapp"\n\n[dependencies]\nserde = "1"\nrand = "0.9.0-beta.1"\n'
+        )
+
+        summary = summarize_lockfile(str(tmp_path / "Cargo.lock"))
+
+        assert summary is not None
+        assert "myapp" not in summary.packages
+        assert summary.direct == ["rand", "serde"]
+        assert "rand: pre-release 0.9.0-beta.1" in summary.notable_versions()
+
+    def test_go_sum_prefers_go_mod_versions(self, tmp_path):
+        """Test go.sum parsing with go.mod direct/indirect requirements."""
+        (tmp_path / "go.sum").write_text(
+            "github.com/pkg/errors v0.8.1 h1:abc=\n"
+            "github.com/pkg/errors v0.9.1 h1:def=\n"
+            "github.com/pkg/errors v0.9.1/go.mod h1:ghi=\n"
+            "golang.org/x/sys v0.1.0/go.mod h1:jkl=\n"
+        )
+        (tmp_path / "go.mod").write_text(
+            "module example.com/app\n\nrequire (\n"
+            "\tgithub.com/pkg/errors v0.9.1\n"
+            "\tgolang.org/x/sys v0.1.0 // indirect\n)\n"
+        )
+
+        summary = summarize_lockfile(str(tmp_path / "go.sum"))
+
+        assert summary is not None
+        assert summary.packages["github.com/pkg/errors"] == {"v0.9.1"}
+        assert summary.direct == ["github.com/pkg/errors"]
+
+    def test_gemfile_lock(self, tmp_path):
+        """Test Bundler lockfile specs and DEPENDENCIES sections."""
+        (tmp_path / "Gemfile.lock").write_text(
+            "GEM\n  remote: https://rubygems.org/\n  specs:\n"
+            "    rack (3.0.8)\n    rails (7.1.2)\n      rack (>= 2.2)\n\n"
+            "DEPENDENCIES\n  rails (~> 7.1)\n"
+        )
+
+        summary = summarize_lockfile(str(tmp_path / "Gemfile.lock"))
+
+        assert summary is not None
+        assert summary.dependency_count == 2
+        assert summary.direct == ["rails"]
+
+    def test_invalid_lockfile_returns_none(self, tmp_path):
+        """Test that unparseable lockfiles are skipped rather than raising."""
+        path = tmp_path / "package-lock.json"
+        path.write_text("{not json")
+
+        assert summarize_lockfile(str(path)) is None
+
+    def test_is_lockfile(self):
+        """Test lockfile name detection."""
+        assert is_lockfile("/repo/poetry.lock")
+        assert not is_lockfile("/repo/poetry.toml")
+
+
+class TestVendoredDirectories:
+    """Test vendored directory summaries."""
+
+    def test_node_modules_with_scopes(self, tmp_path):
+        """Test node_modules listing including scoped packages and versions."""
+        modules = tmp_path / "node_modules"
+        (modules / "express").mkdir(parents=True)
+        (modules / "express" / "package.json").write_text(json.dumps({"version": "4.18.2"}))
+        (modules / "@types" / "node").mkdir(parents=True)
+        (modules / ".bin").mkdir()
+
+        summary = summarize_vendored_dir(str(modules))
+
+        assert summary is not None
+        assert summary.ecosystem == "npm"
+        assert set(summary.packages) == {"express", "@types/node"}
+        assert summary.packages["express"] == {"4.18.2"}
+
+    def test_go_vendor_modules_txt(self, tmp_path):
+        """Test Go vendor directory summarized from modules.txt."""
+        vendor = tmp_path / "vendor"
+        vendor.mkdir()
+        (vendor / "modules.txt").write_text(
+            "# github.com/pkg/errors v0.9.1\n## explicit\ngithub.com/pkg/errors\n"
+        )
+
+        summary = summarize_vendored_dir(str(vendor))
+
+        assert summary is not None
+        assert summary.ecosystem == "go"
+        assert summary.packages == {"github.com/pkg/errors": {"v0.9.1"}}
+
+
+class TestCollectDependencySummaries:
+    """Test the tree walk that produces doc items."""
+
+    def test_collects_lockfiles_and_skips_inside_vendored(self, tmp_path):
+        """Test lockfiles are summarized and vendored dirs are not descended."""
+        (tmp_path / "poetry.lock").write_text('[[package]]\nname = "requests"\nversion = "2.31.0"\n')
+        nested = tmp_path / "node_modules" / "left-pad"
+        nested.mkdir(parents=True)
+        (nested / "package-lock.json").write_text(json.dumps({"packages": {}}))
+
+        config = CodeConCatConfig(target_path=str(tmp_path))
+        docs = collect_dependency_summaries(str(tmp_path), config)
+
+        assert len(docs) == 2
+        assert all(d.doc_type == "dependency_summary" for d in docs)
+        lock_doc = next(d for d in docs if d.file_path.endswith("poetry.lock"))
+        assert "Total dependencies: 1" in lock_doc.content
+        assert "poetry" in lock_doc.tags
+
+    def test_ignored_and_excluded_lockfiles_are_skipped(self, tmp_path):
+        """Test lockfiles follow .codeconcatignore, .gitignore and exclude_paths."""
+        lock = '[[package]]\nname = "requests"\nversion = "2.31.0"\n'
+        for name in ("app", "ignored", "excluded", "scratch"):
+            (tmp_path / name).mkdir()
+            (tmp_path / name / "poetry.lock").write_text(lock)
+        (tmp_path / ".codeconcatignore").write_text("ignored/poetry.lock\n")
+        (tmp_path / ".gitignore").write_text("scratch/\n")
+
+        config = CodeConCatConfig(target_path=str(tmp_path), exclude_paths=["excluded/**"])
+        docs = collect_dependency_summaries(str(tmp_path), config)
+
+        assert [os.path.basename(os.path.dirname(d.file_path)) for d in docs] == ["app"]
+
+    def test_missing_directory_returns_empty(self, tmp_path):
+        """Test a non-directory root yields no summaries."""
+        config = CodeConCatConfig()
+        assert collect_dependency_summaries(str(tmp_path / "missing"), config) == []
@@ -0,0 +1,192 @@
+"""Tests for pull request URLs and import-graph context in diff mode."""
+
+import asyncio
+import os
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, DiffMetadata
+from codeconcat.collector.diff_context import related_files
+from codeconcat.collector.git_hosts import parse_remote_url
+from codeconcat.collector.git_auth import CloneAuth
+from codeconcat.collector.pull_requests import _fetch, get_pull_request, parse_pull_request_url
+from codeconcat.writer.markdown_writer import write_markdown
+
+IMPORTS = {
+    "app/api.py": ["app.service", "json"],
+    "app/service.py": ["app.models", ".util"],
+    "app/models.py": ["dataclasses"],
+    "app/util.py": [],
+    "app/cli.py": ["app.service"],
+    "tests/test_service.py": ["app.service.Service"],
+}
+
+
+class _FakeResponse:
+    def __init__(self, data):
+        self._data = data
+
+    def json(self):
+        return self._data
+
+    def raise_for_status(self):
+        pass
+
+
+class TestRelatedFiles:
+    """Test picking the direct neighbours of changed files."""
+
+    def test_dependencies(self):
+        """Test files imported by the changes are added with the importing file."""
+        assert related_files(["app/service.py"], IMPORTS, "dependencies") == {
+            "app/models.py": "imported by app/service.py",
+            "app/util.py": "imported by app/service.py",
+        }
+
+    def test_dependents(self):
+        """Test files importing the changes are added; the changes themselves are not."""
+        related = related_files(["app/service.py", "app/models.py"], IMPORTS, "dependents")
+
+        assert related == {
+            "app/api.py": "imports app/service.py",
+            "app/cli.py": "imports app/service.py",
+            "tests/test_service.py": "imports app/service.py",
+        }
+
+    def test_both_and_none(self):
+        """Test 'both' combines the two directions and 'none' adds nothing."""
+        related = related_files(["app/service.py"], IMPORTS, "both")
+
+        assert sorted(related) == [
+            "app/api.py",
+            "app/cli.py",
+            "app/models.py",
+            "app/util.py",
+            "tests/test_service.py",
+        ]
+        assert related_files(["app/service.py"], IMPORTS, "none") == {}
+
+    def test_reasons_are_capped(self):
+        """Test a file shared by many changes lists a few of them and counts the rest."""
+        imports = {f"app/mod{i}.py": ["app.shared"] for i in range(5)}
+        imports["app/shared.py"] = []
+
+        related = related_files(sorted(imports)[:-1], imports, "dependencies")
+
+        assert related["app/shared.py"].endswith("imported by app/mod2.py and 2 more")
+
+
+class TestPullRequestUrls:
+    """Test recognising pull and merge request URLs."""
+
+    def test_hosts(self):
+        """Test GitHub, GitLab and Bitbucket request URLs name their repository and number."""
+        cases = {
+            "https://github.com/acme/service/pull/42/files": ("github", "acme/service", 42),
+            "https://gitlab.com/group/sub/app/-/merge_requests/7": ("gitlab", "group/sub/app", 7),
+            "https://bitbucket.org/team/app/pull-requests/3/diff": ("bitbucket", "team/app", 3),
+        }
+        for url, (host, full_name, number) in cases.items():
+            remote, found = parse_pull_request_url(url)
+            assert (remote.host.name, remote.full_name, found) == (host, full_name, number)
+
+    def test_refs_are_not_pull_requests(self):
+        """Test branch names and other URLs are left to git."""
+        assert parse_pull_request_url("main") is None
+        assert parse_pull_request_url("feature/pull/1") is None
+        assert parse_pull_request_url("https://github.com/acme/service/tree/main") is None
+
+    @patch.dict(os.environ, {"GITHUB_TOKEN": "", "GH_TOKEN": "", "GITLAB_TOKEN": ""})
+    def test_api_lookup(self):
+        """Test the request's target branch and head ref come from the host API."""
+        config = CodeConCatConfig(github_token="ghp_x")
+        with patch("httpx.get", return_value=_FakeResponse({"base": {"ref": "main"}})) as api_get:
+            pr = get_pull_request(parse_remote_url("acme/service"), 42, config)
+
+        assert api_get.call_args.args[0] == "https://api.github.com/repos/acme/service/pulls/42"
+        assert api_get.call_args.kwargs["headers"]["Authorization"] == "Bearer ghp_x"
+        assert (pr.base_branch, pr.head_refspec) == ("main", "refs/pull/42/head")
+
+        with (
+            patch("keyring.get_password", return_value=None),
+            patch("httpx.get", return_value=_FakeResponse({"target_branch": "develop"})) as api_get,
+        ):
+            pr = get_pull_request(parse_remote_url("gitlab:group/app"), 7, CodeConCatConfig())
+
+        assert api_get.call_args.args[0].endswith("/projects/group%2Fapp/merge_requests/7")
+        assert (pr.base_branch, pr.head_refspec) == ("develop", "refs/merge-requests/7/head")
+
+    def test_bitbucket_fork(self):
+        """Test Bitbucket requests from forks are fetched from the fork."""
+        data = {
+            "destination": {"branch": {"name": "main"}},
+            "source": {"branch": {"name": "fix"}, "repository": {"full_name": "alice/app"}},
+        }
+        with patch("httpx.get", return_value=_FakeResponse(data)):
+            pr = get_pull_request(parse_remote_url("bitbucket:team/app"), 3, CodeConCatConfig())
+
+        assert pr.head_refspec == "refs/heads/fix"
+        assert pr.head_remote.full_name == "alice/app"
+        assert pr.local_ref == "refs/codeconcat/pr-3"
+
+    def test_fetch_inside_running_event_loop(self):
+        """Test credentials resolve when the caller already runs an event loop."""
+
+        async def resolve(remote, config):
+            return CloneAuth(url="https://github.com/acme/service.git", env={}, method="anonymous")
+
+        async def caller(repo):
+            _fetch(repo, parse_remote_url("acme/service"), "+refs/heads/main:x", None)
+
+        repo = MagicMock()
+        with patch("codeconcat.collector.pull_requests.resolve_clone_auth", resolve):
+            asyncio.run(caller(repo))
+
+        repo.git.fetch.assert_called_once_with(
+            "https://github.com/acme/service.git", "+refs/heads/main:x", env={}
+        )
+
+
+class TestContextOutput:
+    """Test context files next to the diffs in the output."""
+
+    def test_title_uses_first_changed_file(self):
+        """Test the diff header is taken from a changed file even if context comes first."""
+        meta = DiffMetadata("a" * 40, "b" * 40, "modified", additions=1, deletions=0, binary=False)
+        items = [
+            AnnotatedFileData("app/models.py", "python", "", "", summary="Context"),
+            AnnotatedFileData("app/service.py", "python", "", "", diff_metadata=meta),
+        ]
+
+        markdown = write_markdown(items, CodeConCatConfig())
+
+        assert markdown.startswith("# Differential Analysis: aaaaaaa...bbbbbbb")
+
+
+@pytest.mark.parametrize("mode", ["dependents", "both"])
+def test_collect_context_reads_to_ref(tmp_path, mode):
+    """Test DiffCollector adds dependents as they are at the diff's end ref."""
+    from git import Repo
+
+    from codeconcat.collector.diff_collector import DiffCollector
+
+    repo = Repo.init(tmp_path)
+    repo.config_writer().set_value("user", "name", "Test User").release()
+    repo.config_writer().set_value("user", "email", "test@example.com").release()
+    (tmp_path / "service.py").write_text("def run():\n    return 1\n")
+    (tmp_path / "api.py").write_text("from service import run\n\n\ndef handler():\n    return 1\n")
+    repo.index.add(["service.py", "api.py"])
+    base = repo.index.commit("Initial commit").hexsha
+    (tmp_path / "service.py").write_text("def run():\n    return 2\n")
+    repo.index.add(["service.py"])
+    repo.index.commit("Change the service")
+    # Uncommitted edits are not part of the diff's end ref
+    (tmp_path / "api.py").write_text("# edited\n")
+
+    collector = DiffCollector(str(tmp_path), base, "HEAD", CodeConCatConfig())
+    changed = collector.collect_diffs()
+    reasons = collector.collect_context(changed, mode)
+
+    assert reasons == {str(tmp_path.resolve() / "api.py"): "imports service.py"}
+    assert "def handler" in collector.context_files[0].content
@@ -0,0 +1,123 @@
+"""Tests for the size, age and author filters."""
+
+import os
+import time
+
+import pytest
+from git import Actor, Repo
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.file_filters import FileFilters, parse_since, parse_size
+from codeconcat.collector.local_collector import collect_local_files
+
+ADA = Actor("Ada Lovelace", "ada@example.com")
+BOB = Actor("Bob Smith", "bob@example.org")
+
+
+def _commit(repo, path, name, author, date):
+    target = path / name
+    target.parent.mkdir(parents=True, exist_ok=True)
+    target.write_text(f"# {name}\n")
+    repo.index.add([name])
+    repo.index.commit(
+        f"Add {name}", author=author, committer=author, author_date=date, commit_date=date
+    )
+
+
+def _repo(path):
+    """old.py by Ada in 2020, new.py by Bob now, shared.py by both."""
+    repo = Repo.init(path)
+    now = time.strftime("%Y-%m-%dT%H:%M:%S")
+    _commit(repo, path, "old.py", ADA, "2020-01-01T00:00:00")
+    _commit(repo, path, "shared.py", ADA, "2020-01-01T00:00:00")
+    _commit(repo, path, "new.py", BOB, now)
+    (path / "shared.py").write_text("# changed\n")
+    repo.index.add(["shared.py"])
+    repo.index.commit("Change shared.py", author=BOB, committer=BOB, author_date=now)
+    return repo
+
+
+def _collected(root, **kwargs):
+    config = CodeConCatConfig(target_path=str(root), disable_progress_bar=True, **kwargs)
+    return sorted(
+        os.path.relpath(f.file_path, os.path.realpath(root)).replace(os.sep, "/")
+        for f in collect_local_files(str(root), config)
+    )
+
+
+class TestParsing:
+    """Test parsing sizes and ages."""
+
+    @pytest.mark.parametrize(
+        "value, expected",
+        [("512", 512), ("200kb", 200 * 1024), ("1.5MB", 1536 * 1024), ("2g", 2 * 1024**3)],
+    )
+    def test_parse_size(self, value, expected):
+        assert parse_size(value) == expected
+
+    def test_parse_size_rejects_unknown_units(self):
+        with pytest.raises(ValueError):
+            parse_size("10 parsecs")
+
+    def test_parse_since(self):
+        assert parse_since("30d", now=100 * 86400) == 70 * 86400
+        assert parse_since("2w", now=100 * 86400) == 86 * 86400
+        assert parse_since("2024-06-01") == time.mktime((2024, 6, 1, 0, 0, 0, 0, 0, -1))
+        with pytest.raises(ValueError):
+            parse_since("last tuesday")
+
+    def test_config_parses_size_and_validates_age(self):
+        assert CodeConCatConfig(max_file_size="200kb").max_file_size == 200 * 1024
+        with pytest.raises(ValueError):
+            CodeConCatConfig(modified_since="soon")
+
+
+class TestFileFilters:
+    """Test filtering collected files."""
+
+    def test_max_file_size(self, tmp_path):
+        (tmp_path / "small.py").write_text("x = 1\n")
+        (tmp_path / "big.py").write_text("x = 1\n" * 1000)
+
+        assert _collected(tmp_path, max_file_size="1kb") == ["small.py"]
+
+    def test_modified_since_uses_commit_dates(self, tmp_path):
+        _repo(tmp_path)
+        (tmp_path / "untracked.py").write_text("x = 1\n")
+
+        assert _collected(tmp_path, modified_since="30d") == ["new.py", "shared.py", "untracked.py"]
+
+    def test_modified_since_uses_mtime_outside_git(self, tmp_path):
+        (tmp_path / "fresh.py").write_text("x = 1\n")
+        (tmp_path / "stale.py").write_text("x = 1\n")
+        stale = time.time() - 90 * 86400
+        os.utime(tmp_path / "stale.py", (stale, stale))
+
+        assert _collected(tmp_path, modified_since="30d") == ["fresh.py"]
+
+    def test_author_matches_any_commit(self, tmp_path):
+        _repo(tmp_path)
+
+        assert _collected(tmp_path, authors=["ada@"]) == ["old.py", "shared.py"]
+        assert _collected(tmp_path, authors=["BOB SMITH"]) == ["new.py", "shared.py"]
+        assert _collected(tmp_path, authors=["ada@", "example.org"]) == [
+            "new.py",
+            "old.py",
+            "shared.py",
+        ]
+
+    def test_non_ascii_names_keep_their_git_metadata(self, tmp_path):
+        """Test names git would quote, such as café.py, match the files on disk."""
+        repo = _repo(tmp_path)
+        _commit(repo, tmp_path, "café.py", ADA, "2020-01-01T00:00:00")
+
+        assert _collected(tmp_path, authors=["ada@"]) == ["café.py", "old.py", "shared.py"]
+        assert "café.py" not in _collected(tmp_path, modified_since="30d")
+
+    def test_author_filter_skipped_outside_git(self, tmp_path):
+        (tmp_path / "app.py").write_text("x = 1\n")
+
+        filters = FileFilters(str(tmp_path), CodeConCatConfig(authors=["ada"]))
+
+        assert not filters.active
+        assert filters.skip_reason(str(tmp_path / "app.py")) is None
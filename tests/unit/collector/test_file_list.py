@@ -0,0 +1,75 @@
+"""Tests for collecting an explicit list of files."""
+
+import io
+import os
+from unittest.mock import patch
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.local_collector import collect_file_list, read_file_list
+
+
+def _write(root, files):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+def _collected(root, paths, **kwargs):
+    config = CodeConCatConfig(target_path=str(root), disable_progress_bar=True, **kwargs)
+    return sorted(
+        os.path.relpath(f.file_path, os.path.realpath(root)).replace(os.sep, "/")
+        for f in collect_file_list(paths, str(root), config)
+    )
+
+
+class TestReadFileList:
+    """Test reading paths from a file or stdin."""
+
+    def test_newlines_from_stdin(self):
+        """Test entries are stripped, blank lines and repeats dropped, order kept."""
+        with patch("sys.stdin", io.StringIO("b.py\n\n  a.py \nb.py\n")):
+            assert read_file_list("-") == ["b.py", "a.py"]
+
+    def test_nul_separated_file(self, tmp_path):
+        """Test NUL-separated lists keep names with spaces and newlines intact."""
+        listing = tmp_path / "files.txt"
+        listing.write_text("my file.py\0odd\nname.py\0")
+
+        assert read_file_list(str(listing)) == ["my file.py", "odd\nname.py"]
+
+
+class TestCollectFileList:
+    """Test collecting only the listed paths."""
+
+    def test_only_listed_files(self, tmp_path):
+        """Test unlisted files are not collected and missing paths are skipped."""
+        _write(tmp_path, {"app.py": "x = 1\n", "util.py": "y = 2\n", "other.py": "z = 3\n"})
+
+        collected = _collected(tmp_path, ["app.py", "./util.py", "deleted.py"])
+
+        assert collected == ["app.py", "util.py"]
+
+    def test_filters_and_directories(self, tmp_path):
+        """Test listed directories are walked and exclude patterns still apply."""
+        _write(
+            tmp_path,
+            {
+                "src/core.py": "A = 1\n",
+                "src/gen/schema.py": "B = 2\n",
+                "top.py": "C = 3\n",
+            },
+        )
+
+        collected = _collected(
+            tmp_path, ["src", str(tmp_path / "top.py")], exclude_paths=["src/gen/**"]
+        )
+
+        assert collected == ["src/core.py", "top.py"]
+
+    def test_paths_outside_root(self, tmp_path):
+        """Test paths that escape the target directory are skipped."""
+        root = tmp_path / "repo"
+        _write(tmp_path, {"repo/app.py": "x = 1\n", "secret.py": "KEY = 1\n"})
+
+        assert _collected(root, ["app.py", "../secret.py"]) == ["app.py"]
@@ -0,0 +1,82 @@
+"""Tests for the condensed git history section."""
+
+from git import Actor, Repo
+
+from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig
+from codeconcat.collector.git_history import collect_git_history, format_git_history
+from codeconcat.writer.json_writer import write_json
+from codeconcat.writer.markdown_writer import write_markdown
+from codeconcat.writer.xml_writer import write_xml
+
+AUTHOR = Actor("Ada Lovelace", "ada@example.com")
+
+
+def _repo(path):
+    repo = Repo.init(path)
+    for name, message in [
+        ("README.md", "Initial commit"),
+        ("src/app.py", "Add the app\n\nLonger body that is not shown."),
+        ("src/util.py", "Add helpers"),
+    ]:
+        target = path / name
+        target.parent.mkdir(exist_ok=True)
+        target.write_text(f"# {name}\n")
+        repo.index.add([name])
+        repo.index.commit(message, author=AUTHOR, committer=AUTHOR)
+    return repo
+
+
+class TestCollectGitHistory:
+    """Test reading recent commits."""
+
+    def test_recent_commits_newest_first(self, tmp_path):
+        """Test commits carry hash, author, subject and files, newest first."""
+        repo = _repo(tmp_path)
+
+        commits = collect_git_history(str(tmp_path), max_commits=2)
+
+        assert [c.subject for c in commits] == ["Add helpers", "Add the app"]
+        assert commits[0].sha == repo.head.commit.hexsha
+        assert commits[0].author == "Ada Lovelace"
+        assert commits[0].files == ["src/util.py"]
+        assert (commits[0].insertions, commits[0].deletions) == (1, 0)
+
+    def test_since_ref_and_subdirectory(self, tmp_path):
+        """Test since_ref bounds the range and subdirectories scope files and commits."""
+        repo = _repo(tmp_path)
+        first = repo.commit("HEAD~2").hexsha
+
+        since = collect_git_history(str(tmp_path), since_ref=first)
+        scoped = collect_git_history(str(tmp_path / "src"))
+
+        assert [c.subject for c in since] == ["Add helpers", "Add the app"]
+        assert [c.files for c in scoped] == [["util.py"], ["app.py"]]
+
+    def test_not_a_repository(self, tmp_path):
+        """Test directories outside git and unknown refs yield no history."""
+        assert collect_git_history(str(tmp_path)) == []
+
+        _repo(tmp_path)
+        assert collect_git_history(str(tmp_path), since_ref="no-such-ref") == []
+
+
+class TestGitHistoryOutput:
+    """Test the writers append the history section."""
+
+    def test_history_in_each_format(self, tmp_path):
+        """Test Markdown, JSON and XML output include the collected commits."""
+        _repo(tmp_path)
+        config = CodeConCatConfig(include_git_history=True)
+        commits = collect_git_history(str(tmp_path))
+        object.__setattr__(config, "_git_history", commits)
+        items = [AnnotatedFileData(str(tmp_path / "src/app.py"), "python", "", "")]
+
+        line = format_git_history(commits)[0]
+        assert line.startswith(f"{commits[0].short_sha} ")
+        assert line.endswith("Ada Lovelace: Add helpers [+1/-0; src/util.py]")
+
+        markdown = write_markdown(items, config)
+        assert markdown.index("## Git History") > markdown.index("## File Details")
+        assert f"- {line}" in markdown
+        assert '"subject": "Add the app"' in write_json(items, config)
+        assert f'sha="{commits[2].sha}"' in write_xml(items, config)
@@ -0,0 +1,130 @@
+"""Tests for nested .gitignore and .codeconcatignore handling."""
+
+import os
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.ignore_rules import IgnoreRules, get_ignore_rules
+from codeconcat.collector.local_collector import collect_local_files
+
+
+def _rules(files):
+    """Rules over an in-memory tree given as {path of ignore file: content}."""
+
+    def read_lines(directory, name):
+        content = files.get(f"{directory}/{name}" if directory else name)
+        return content.splitlines() if content is not None else None
+
+    return IgnoreRules(read_lines)
+
+
+def _write(root, files):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+def _collected(root, **kwargs):
+    config = CodeConCatConfig(target_path=str(root), disable_progress_bar=True, **kwargs)
+    return sorted(
+        os.path.relpath(f.file_path, os.path.realpath(root)).replace(os.sep, "/")
+        for f in collect_local_files(str(root), config)
+    )
+
+
+class TestIgnoreRules:
+    """Test matching paths against the ignore files of a tree."""
+
+    def test_pattern_without_slash_matches_at_any_depth(self):
+        rules = _rules({".gitignore": "*.log\n"})
+        assert rules.match_file("app.log")
+        assert rules.match_file("src/deep/app.log")
+        assert not rules.match_file("src/app.py")
+
+    def test_pattern_with_slash_is_anchored(self):
+        rules = _rules({".gitignore": "/gen.py\ndocs/out\n"})
+        assert rules.match_file("gen.py")
+        assert not rules.match_file("src/gen.py")
+        assert rules.match_file("docs/out/index.html")
+        assert not rules.match_file("src/docs/out/index.html")
+
+    def test_nested_file_is_relative_to_its_directory(self):
+        rules = _rules({"src/.gitignore": "/tmp.py\n"})
+        assert rules.match_file("src/tmp.py")
+        assert not rules.match_file("tmp.py")
+        assert not rules.match_file("src/sub/tmp.py")
+
+    def test_negation_re_includes(self):
+        rules = _rules({".gitignore": "*.log\n!keep.log\n"})
+        assert rules.match_file("other.log")
+        assert not rules.match_file("keep.log")
+
+    def test_nested_negation_overrides_parent(self):
+        rules = _rules({".gitignore": "*.json\n", "config/.gitignore": "!settings.json\n"})
+        assert rules.match_file("data.json")
+        assert not rules.match_file("config/settings.json")
+        assert rules.match_file("config/other.json")
+
+    def test_directory_only_pattern(self):
+        rules = _rules({".gitignore": "cache/\n"})
+        assert rules.match_file("cache/data.py")
+        assert rules.match_file("src/cache/data.py")
+        assert rules.match_file("cache/")
+        # A file named like the directory is not ignored
+        assert not rules.match_file("src/cache")
+
+    def test_file_in_ignored_directory_cannot_be_re_included(self):
+        rules = _rules({".gitignore": "build/\n!build/keep.py\n"})
+        assert rules.match_file("build/keep.py")
+
+    def test_codeconcatignore_applies_after_gitignore(self):
+        rules = _rules({".gitignore": "*.md\n", ".codeconcatignore": "!README.md\nfixtures/\n"})
+        assert rules.match_file("CHANGES.md")
+        assert not rules.match_file("README.md")
+        assert rules.match_file("tests/fixtures/big.py")
+
+    def test_paths_outside_root_are_not_ignored(self):
+        rules = _rules({".gitignore": "*\n"})
+        assert not rules.match_file("../other.py")
+
+    def test_no_gitignore_keeps_codeconcatignore(self, tmp_path):
+        _write(tmp_path, {".gitignore": "a.py\n", ".codeconcatignore": "b.py\n"})
+        config = CodeConCatConfig(target_path=str(tmp_path), use_gitignore=False)
+        rules = get_ignore_rules(str(tmp_path), config)
+        assert not rules.match_file("a.py")
+        assert rules.match_file("b.py")
+
+
+class TestCollectWithIgnoreFiles:
+    """Test that collection honors nested ignore files."""
+
+    def test_nested_ignore_files(self, tmp_path):
+        _write(
+            tmp_path,
+            {
+                ".gitignore": "*.gen.py\nscratch/\n",
+                ".codeconcatignore": "fixtures/\n",
+                "main.py": "x = 1\n",
+                "main.gen.py": "x = 2\n",
+                "scratch/notes.py": "x = 3\n",
+                "pkg/.gitignore": "!keep.gen.py\nlocal.py\n",
+                "pkg/keep.gen.py": "x = 4\n",
+                "pkg/local.py": "x = 5\n",
+                "pkg/mod.py": "x = 6\n",
+                "pkg/fixtures/data.py": "x = 7\n",
+            },
+        )
+        assert _collected(tmp_path) == ["main.py", "pkg/keep.gen.py", "pkg/mod.py"]
+
+    def test_no_gitignore_still_honors_codeconcatignore(self, tmp_path):
+        _write(
+            tmp_path,
+            {
+                ".gitignore": "main.gen.py\n",
+                ".codeconcatignore": "skip.py\n",
+                "main.py": "x = 1\n",
+                "main.gen.py": "x = 2\n",
+                "skip.py": "x = 3\n",
+            },
+        )
+        assert _collected(tmp_path, use_gitignore=False) == ["main.gen.py", "main.py"]
@@ -228,3 +228,42 @@ class TestEdgeCases:
 
             # Should process both files
             assert len(result) >= 1
+
+
+class TestExtensionlessFiles:
+    """Test collecting scripts and build files without an extension."""
+
+    def test_detected_from_name_and_content(self, tmp_path):
+        """Test shebang scripts and Dockerfile variants are collected with their language."""
+        (tmp_path / "bin").mkdir()
+        (tmp_path / "bin" / "deploy").write_text("#!/usr/bin/env bash\necho deploying\n")
+        (tmp_path / "bin" / "manage").write_text("#!/usr/bin/env python3\nprint('ok')\n")
+        (tmp_path / "Dockerfile.dev").write_text("FROM python:3.12\n")
+        (tmp_path / "Jenkinsfile").write_text("pipeline { agent any }\n")
+        (tmp_path / "NOTES").write_text("Plain prose without a language.\n")
+
+        config = CodeConCatConfig(target_path=str(tmp_path), disable_progress_bar=True)
+        root = os.path.realpath(tmp_path)
+        languages = {
+            os.path.relpath(f.file_path, root).replace(os.sep, "/"): f.language
+            for f in collect_local_files(str(tmp_path), config)
+        }
+
+        assert languages == {
+            "bin/deploy": "bash",
+            "bin/manage": "python",
+            "Dockerfile.dev": "dockerfile",
+            "Jenkinsfile": "groovy",
+        }
+
+    def test_language_filters_apply_after_detection(self, tmp_path):
+        """Test include_languages keeps detected scripts of that language only."""
+        (tmp_path / "deploy").write_text("#!/bin/bash\necho hi\n")
+        (tmp_path / "manage").write_text("#!/usr/bin/python3\nprint('ok')\n")
+
+        config = CodeConCatConfig(
+            target_path=str(tmp_path), disable_progress_bar=True, include_languages=["bash"]
+        )
+        collected = collect_local_files(str(tmp_path), config)
+
+        assert [os.path.basename(f.file_path) for f in collected] == ["deploy"]
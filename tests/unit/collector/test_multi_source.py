@@ -0,0 +1,113 @@
+"""Tests for collecting several sources into one output."""
+
+import tempfile
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.collector.multi_source import collect_sources, parse_sources, render_source_tree
+from codeconcat.errors import ConfigurationError, FileProcessingError
+
+
+def _write(root, files):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+class TestParseSources:
+    """Test naming sources and telling paths from repositories."""
+
+    def test_prefixes(self, tmp_path):
+        """Test prefixes come from directory and repository names, made unique."""
+        (tmp_path / "a" / "lib").mkdir(parents=True)
+        (tmp_path / "b" / "lib").mkdir(parents=True)
+
+        sources = parse_sources(
+            [str(tmp_path / "a" / "lib"), str(tmp_path / "b" / "lib"), "acme/billing#v2"]
+        )
+
+        assert [(s.prefix, s.remote) for s in sources] == [
+            ("lib", False),
+            ("lib-2", False),
+            ("billing", True),
+        ]
+
+    def test_explicit_prefix(self, tmp_path):
+        """Test 'prefix=location' names the source."""
+        sources = parse_sources([f"shared={tmp_path}", "api=gitlab:team/api"])
+
+        assert [(s.prefix, s.location) for s in sources] == [
+            ("shared", str(tmp_path)),
+            ("api", "gitlab:team/api"),
+        ]
+
+    def test_errors(self, tmp_path):
+        """Test missing paths and repeated explicit prefixes are rejected."""
+        with pytest.raises(ConfigurationError, match="does not exist"):
+            parse_sources([str(tmp_path / "missing")])
+        with pytest.raises(ConfigurationError, match="used twice"):
+            parse_sources([f"x={tmp_path}", f"x={tmp_path}"])
+
+
+class TestCollectSources:
+    """Test collecting and prefixing the files of each source."""
+
+    def test_local_and_remote(self, tmp_path):
+        """Test files are filtered per source and renamed under their prefixes."""
+        service = tmp_path / "service"
+        _write(service, {"app.py": "import money\n", "tests/test_app.py": "x = 1\n"})
+        clone = tempfile.TemporaryDirectory()
+        remote_file = Path(clone.name) / "money" / "core.py"
+        _write(Path(clone.name), {"money/core.py": "RATE = 1\n"})
+        config = CodeConCatConfig(
+            sources=[str(service), "acme/money"],
+            include_paths=["**/*.py"],
+            exclude_paths=["tests/**"],
+            disable_progress_bar=True,
+        )
+        remote = [
+            ParsedFileData(file_path=str(remote_file), content="RATE = 1\n", language="python")
+        ]
+
+        with patch(
+            "codeconcat.collector.multi_source.collect_git_repo", return_value=(remote, clone)
+        ) as clone_repo:
+            files, temp_dirs, sources = collect_sources(config)
+
+        assert [f.file_path for f in files] == ["service/app.py", "money/money/core.py"]
+        assert [(s.prefix, s.files) for s in sources] == [("service", 1), ("money", 1)]
+        assert temp_dirs == [clone]
+        assert clone_repo.call_args.args[1].source_url == "acme/money"
+        clone.cleanup()
+
+    def test_failed_clone_cleans_up(self):
+        """Test earlier clones are removed when a later source cannot be cloned."""
+        first = tempfile.TemporaryDirectory()
+        config = CodeConCatConfig(sources=["acme/one", "acme/two"], disable_progress_bar=True)
+
+        with patch(
+            "codeconcat.collector.multi_source.collect_git_repo",
+            side_effect=[([], first), ([], None)],
+        ):
+            with pytest.raises(FileProcessingError, match="Could not clone source acme/two"):
+                collect_sources(config)
+
+        assert not Path(first.name).exists()
+
+
+def test_render_source_tree():
+    """Test the tree lists each source as a top-level folder."""
+    tree = render_source_tree(["service/app.py", "money/core.py", "service/api/routes.py"])
+
+    assert tree.splitlines() == [
+        "money/",
+        "    core.py",
+        "service/",
+        "    app.py",
+        "    api/",
+        "        routes.py",
+    ]
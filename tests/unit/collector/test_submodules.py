@@ -0,0 +1,132 @@
+"""Tests for git submodule discovery and collection."""
+
+import os
+
+from codeconcat.base_types import CodeConCatConfig, SubmoduleFilter
+from codeconcat.collector.local_collector import collect_local_files
+from codeconcat.collector.submodules import find_submodules, read_gitmodules, select_submodules
+
+
+def _write(root, files):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+
+def _gitmodules(*paths):
+    return "".join(
+        f'[submodule "{os.path.basename(p)}"]\n\tpath = {p}\n\turl = https://example.com/{p}.git\n'
+        for p in paths
+    )
+
+
+def _repo(root):
+    """A repository with an initialized submodule under libs/ that has its own submodule."""
+    _write(
+        root,
+        {
+            ".gitmodules": _gitmodules("libs/core", "vendor/missing"),
+            "app.py": "import core\n",
+            "libs/core/.git": "gitdir: ../../.git/modules/core\n",
+            "libs/core/.gitmodules": _gitmodules("ext/parser"),
+            "libs/core/.gitignore": "generated.py\n",
+            "libs/core/core.py": "VALUE = 1\n",
+            "libs/core/generated.py": "GENERATED = 1\n",
+            "libs/core/tests/test_core.py": "def test(): pass\n",
+            "libs/core/ext/parser/.git": "gitdir: ../../../../.git/modules/parser\n",
+            "libs/core/ext/parser/parse.py": "def parse(): pass\n",
+        },
+    )
+    (root / "vendor" / "missing").mkdir(parents=True)
+
+
+def _config(root, **kwargs):
+    return CodeConCatConfig(
+        target_path=str(root), disable_progress_bar=True, include_paths=["**/*.py"], **kwargs
+    )
+
+
+def _collected(root, config):
+    return sorted(
+        os.path.relpath(f.file_path, os.path.realpath(root)).replace(os.sep, "/")
+        for f in collect_local_files(str(root), config)
+    )
+
+
+class TestFindSubmodules:
+    """Test reading .gitmodules and selecting submodules."""
+
+    def test_read_gitmodules(self, tmp_path):
+        """Test sections, quoted values and comments are read."""
+        _write(
+            tmp_path,
+            {
+                ".gitmodules": (
+                    "# shared code\n"
+                    '[submodule "core lib"]\n'
+                    '\tpath = "libs/core"\n'
+                    "\turl = git@example.com:acme/core.git\n"
+                    '[submodule "no-path"]\n'
+                    "\turl = https://example.com/x.git\n"
+                ),
+            },
+        )
+
+        submodules = read_gitmodules(str(tmp_path))
+
+        assert [(s.name, s.path, s.url) for s in submodules] == [
+            ("core lib", "libs/core", "git@example.com:acme/core.git")
+        ]
+
+    def test_initialized_and_nested(self, tmp_path):
+        """Test only checked-out submodules are found, nested ones relative to the root."""
+        _repo(tmp_path)
+
+        assert [s.path for s in find_submodules(str(tmp_path))] == [
+            "libs/core",
+            "libs/core/ext/parser",
+        ]
+
+    def test_exclude_skips_nested(self, tmp_path):
+        """Test excluding a submodule by name also skips the submodules inside it."""
+        _repo(tmp_path)
+
+        selected = select_submodules(find_submodules(str(tmp_path)), ["core"])
+
+        assert selected == []
+
+
+class TestCollectSubmodules:
+    """Test collecting files from submodules."""
+
+    def test_submodules_are_skipped_by_default(self, tmp_path):
+        """Test a submodule under libs/ is left out without include_submodules."""
+        _repo(tmp_path)
+
+        assert _collected(tmp_path, _config(tmp_path)) == ["app.py"]
+
+    def test_include_submodules(self, tmp_path):
+        """Test submodules are walked as their own roots with their own .gitignore."""
+        _repo(tmp_path)
+
+        collected = _collected(tmp_path, _config(tmp_path, include_submodules=True))
+
+        assert collected == [
+            "app.py",
+            "libs/core/core.py",
+            "libs/core/ext/parser/parse.py",
+            "libs/core/tests/test_core.py",
+        ]
+
+    def test_per_submodule_filters(self, tmp_path):
+        """Test filters apply relative to the submodule and excluded submodules are skipped."""
+        _repo(tmp_path)
+        config = _config(
+            tmp_path,
+            include_submodules=True,
+            submodule_exclude=["libs/core/ext/*"],
+            submodule_filters={"libs/core": SubmoduleFilter(exclude_paths=["tests/**"])},
+        )
+
+        assert _collected(tmp_path, config) == ["app.py", "libs/core/core.py"]
@@ -0,0 +1,148 @@
+"""Tests for the symbolic link policies."""
+
+import os
+
+import pytest
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.local_collector import collect_local_files
+from codeconcat.collector.symlinks import collect_symlink_stubs, symlink_policy
+
+
+def _tree(root):
+    """src/ with two modules, a link to src/ under mirror/, a file link and a loop."""
+    (root / "src").mkdir()
+    (root / "src" / "app.py").write_text("x = 1\n")
+    (root / "src" / "util.py").write_text("y = 2\n")
+    (root / "external").mkdir()
+    (root / "external" / "lib.py").write_text("z = 3\n")
+    (root / "mirror").mkdir()
+    os.symlink(root / "src", root / "mirror" / "src")
+    os.symlink(root / "external" / "lib.py", root / "lib.py")
+    os.symlink(root, root / "src" / "loop")
+
+
+def _config(root, **kwargs):
+    return CodeConCatConfig(target_path=str(root), disable_progress_bar=True, **kwargs)
+
+
+def _collected(root, **kwargs):
+    real_root = os.path.realpath(root)
+    return sorted(
+        os.path.relpath(f.file_path, real_root).replace(os.sep, "/")
+        for f in collect_local_files(str(root), _config(root, **kwargs))
+    )
+
+
+class TestSymlinkPolicy:
+    """Test choosing the policy of a link."""
+
+    def test_rules_override_the_default(self):
+        config = CodeConCatConfig(
+            symlink_policy="follow", symlink_rules={"build/*": "skip", "*.so": "stub"}
+        )
+        assert symlink_policy("build/src", config) == "skip"
+        assert symlink_policy("lib/native.so", config) == "stub"
+        assert symlink_policy("vendor/lib", config) == "follow"
+
+    def test_unknown_policies_are_rejected(self):
+        with pytest.raises(ValueError):
+            CodeConCatConfig(symlink_policy="chase")
+        with pytest.raises(ValueError):
+            CodeConCatConfig(symlink_rules={"build/*": "chase"})
+
+
+class TestCollectWithSymlinks:
+    """Test walking trees that contain links."""
+
+    def test_skip_by_default(self, tmp_path):
+        _tree(tmp_path)
+        assert _collected(tmp_path) == ["external/lib.py", "src/app.py", "src/util.py"]
+
+    def test_follow_collects_each_file_once(self, tmp_path):
+        """Test the link to src/ and the loop back to the root add no duplicates."""
+        _tree(tmp_path)
+        assert _collected(tmp_path, symlink_policy="follow") == [
+            "external/lib.py",
+            "src/app.py",
+            "src/util.py",
+        ]
+
+    def test_follow_reaches_targets_outside_the_root(self, tmp_path):
+        (tmp_path / "shared").mkdir()
+        (tmp_path / "shared" / "money.py").write_text("CENTS = 100\n")
+        root = tmp_path / "service"
+        root.mkdir()
+        (root / "main.py").write_text("import money\n")
+        os.symlink(tmp_path / "shared", root / "shared")
+
+        assert _collected(root, symlink_policy="follow") == ["main.py", "shared/money.py"]
+        assert _collected(root) == ["main.py"]
+
+    def test_follow_keeps_the_link_path_of_files(self, tmp_path):
+        (tmp_path / "shared.py").write_text("SHARED = True\n")
+        root = tmp_path / "root"
+        root.mkdir()
+        os.symlink(tmp_path / "shared.py", root / "linked.py")
+
+        assert _collected(root, symlink_policy="follow") == ["linked.py"]
+
+    def test_rule_follows_one_link(self, tmp_path):
+        (tmp_path / "real").mkdir()
+        (tmp_path / "real" / "a.py").write_text("a = 1\n")
+        root = tmp_path / "root"
+        root.mkdir()
+        os.symlink(tmp_path / "real", root / "linked")
+        os.symlink(tmp_path / "real", root / "other")
+
+        collected = collect_local_files(
+            str(root), _config(root, symlink_rules={"linked": "follow"})
+        )
+
+        assert [os.path.basename(f.file_path) for f in collected] == ["a.py"]
+
+    def test_stubs_list_links_without_targets(self, tmp_path):
+        _tree(tmp_path)
+        config = _config(tmp_path, symlink_policy="stub")
+
+        stubs = collect_symlink_stubs(str(tmp_path), config)
+
+        assert _collected(tmp_path, symlink_policy="stub") == [
+            "external/lib.py",
+            "src/app.py",
+            "src/util.py",
+        ]
+        contents = sorted(stub.content.splitlines()[0] for stub in stubs)
+        assert contents == [
+            f"Symbolic link: lib.py -> {tmp_path / 'external' / 'lib.py'} (file)",
+            f"Symbolic link: mirror/src -> {tmp_path / 'src'} (directory)",
+            f"Symbolic link: src/loop -> {tmp_path} (directory)",
+        ]
+        assert {stub.doc_type for stub in stubs} == {"symlink_stub"}
+
+    def test_stubs_do_not_reveal_absolute_paths(self, tmp_path):
+        """Test targets inside the root are shown relative to it, others as linked."""
+        (tmp_path / "outside.py").write_text("x = 1\n")
+        root = tmp_path / "root"
+        (root / "src").mkdir(parents=True)
+        os.symlink(root / "src", root / "alias")
+        os.symlink(os.path.join("..", "outside.py"), root / "outside.py")
+
+        stubs = collect_symlink_stubs(str(root), _config(root, symlink_policy="stub"))
+
+        resolved = sorted(stub.content.splitlines()[1] for stub in stubs)
+        assert resolved == ["Resolves to: ../outside.py", "Resolves to: src"]
+
+    def test_stubs_use_the_collector_path_filters(self, tmp_path):
+        """Test exclude patterns match like gitignore and dot-named links are listed."""
+        _tree(tmp_path)
+        os.symlink(tmp_path / "src", tmp_path / ".alias")
+        config = _config(tmp_path, symlink_policy="stub", exclude_paths=["loop"])
+
+        stubs = collect_symlink_stubs(str(tmp_path), config)
+
+        assert sorted(os.path.relpath(stub.file_path, tmp_path) for stub in stubs) == [
+            ".alias",
+            "lib.py",
+            os.path.join("mirror", "src"),
+        ]
@@ -0,0 +1,184 @@
+"""Tests for monorepo workspace detection and per-package runs."""
+
+import json
+from unittest.mock import patch
+
+import pytest
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.collector.workspaces import detect_workspace
+from codeconcat.errors import ConfigurationError
+from codeconcat.workspace import output_file_name, run_workspace
+
+
+def _write(root, files):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content if isinstance(content, str) else json.dumps(content))
+
+
+def _packages(workspace):
+    return [(p.name, p.path, p.manager, p.depends_on) for p in workspace.packages]
+
+
+class TestDetectWorkspace:
+    """Test reading the packages of each workspace manifest."""
+
+    def test_pnpm(self, tmp_path):
+        """Test pnpm globs, exclusions and dependencies between packages."""
+        _write(
+            tmp_path,
+            {
+                "pnpm-workspace.yaml": "packages:\n  - 'packages/*'\n  - '!packages/legacy'\n",
+                "packages/core/package.json": {"name": "@acme/core"},
+                "packages/ui/package.json": {
+                    "name": "@acme/ui",
+                    "dependencies": {"@acme/core": "workspace:*", "react": "^18"},
+                },
+                "packages/legacy/package.json": {"name": "legacy"},
+                "packages/notes/README.md": "not a package",
+            },
+        )
+
+        workspace = detect_workspace(str(tmp_path))
+
+        assert workspace.managers == ["pnpm"]
+        assert _packages(workspace) == [
+            ("@acme/core", "packages/core", "pnpm", []),
+            ("@acme/ui", "packages/ui", "pnpm", ["@acme/core"]),
+        ]
+
+    def test_npm_workspaces_and_nx_projects(self, tmp_path):
+        """Test package.json workspaces and Nx project.json files are merged by path."""
+        _write(
+            tmp_path,
+            {
+                "package.json": {"workspaces": {"packages": ["libs/*"]}},
+                "yarn.lock": "",
+                "libs/auth/package.json": {"name": "auth"},
+                "nx.json": {},
+                "apps/web/project.json": {"name": "web", "implicitDependencies": ["auth"]},
+            },
+        )
+
+        workspace = detect_workspace(str(tmp_path))
+
+        assert workspace.managers == ["nx", "yarn"]
+        assert _packages(workspace) == [
+            ("web", "apps/web", "nx", ["auth"]),
+            ("auth", "libs/auth", "yarn", []),
+        ]
+
+    def test_lerna_default_packages(self, tmp_path):
+        """Test Lerna falls back to packages/* when no patterns are given."""
+        _write(tmp_path, {"lerna.json": {}, "packages/cli/package.json": {"name": "cli"}})
+
+        assert _packages(detect_workspace(str(tmp_path))) == [("cli", "packages/cli", "lerna", [])]
+
+    def test_cargo(self, tmp_path):
+        """Test Cargo members, excludes, a root crate and path dependencies."""
+        _write(
+            tmp_path,
+            {
+                "Cargo.toml": (
+                    '[package]\nname = "app"\n\n'
+                    '[workspace]\nmembers = ["crates/*"]\nexclude = ["crates/scratch"]\n'
+                ),
+                "crates/parser/Cargo.toml": '[package]\nname = "parser"\n',
+                "crates/cli/Cargo.toml": (
+                    '[package]\nname = "cli"\n\n[dependencies]\n'
+                    'parser = { path = "../parser" }\nclap = "4"\n'
+                ),
+                "crates/scratch/Cargo.toml": '[package]\nname = "scratch"\n',
+            },
+        )
+
+        workspace = detect_workspace(str(tmp_path))
+
+        assert _packages(workspace) == [
+            ("app", ".", "cargo", []),
+            ("cli", "crates/cli", "cargo", ["parser"]),
+            ("parser", "crates/parser", "cargo", []),
+        ]
+        assert workspace.nested_paths(workspace.packages[0]) == ["crates/cli", "crates/parser"]
+
+    def test_go_work(self, tmp_path):
+        """Test go.work use directives name modules by their module path."""
+        _write(
+            tmp_path,
+            {
+                "go.work": "go 1.22\n\nuse (\n\t./api // service\n\t./shared\n)\nuse ./tools\n",
+                "api/go.mod": (
+                    "module example.com/api\n\nrequire (\n\texample.com/shared v0.0.0\n"
+                    "\tgithub.com/gin-gonic/gin v1.9.1\n)\n"
+                ),
+                "shared/go.mod": "module example.com/shared\n",
+                "tools/go.mod": "module example.com/tools\n",
+            },
+        )
+
+        assert _packages(detect_workspace(str(tmp_path))) == [
+            ("example.com/api", "api", "go", ["example.com/shared"]),
+            ("example.com/shared", "shared", "go", []),
+            ("example.com/tools", "tools", "go", []),
+        ]
+
+    def test_no_workspace(self, tmp_path):
+        """Test plain repositories and invalid manifests are not workspaces."""
+        _write(tmp_path, {"package.json": {"name": "single"}, "pnpm-workspace.yaml": ": ["})
+
+        assert detect_workspace(str(tmp_path)) is None
+
+
+class TestRunWorkspace:
+    """Test writing one output per package and the index."""
+
+    def test_outputs_and_index(self, tmp_path):
+        """Test each package runs on its own directory and the index links the outputs."""
+        repo = tmp_path / "repo"
+        _write(
+            repo,
+            {
+                "pnpm-workspace.yaml": "packages: ['packages/*']\n",
+                "packages/core/package.json": {"name": "@acme/core"},
+                "packages/ui/package.json": {
+                    "name": "@acme/ui",
+                    "dependencies": {"@acme/core": "1"},
+                },
+            },
+        )
+        out = tmp_path / "out"
+        config = CodeConCatConfig(
+            target_path=str(repo), per_package=True, workspace_output_dir=str(out)
+        )
+        targets = []
+
+        def fake_run(package_config):
+            targets.append(package_config.target_path)
+            object.__setattr__(package_config, "_run_stats", {"files_parsed": 3})
+            return f"# {package_config.target_path}\n"
+
+        with patch("codeconcat.main.run_codeconcat", side_effect=fake_run):
+            run = run_workspace(config)
+
+        assert targets == [str(repo / "packages/core"), str(repo / "packages/ui")]
+        assert (out / "acme-core.md").read_text() == f"# {repo / 'packages/core'}\n"
+        index = (out / "index.md").read_text()
+        assert "| @acme/ui | `packages/ui` | 3 | [acme-ui.md](acme-ui.md) | @acme/core |" in index
+        assert run.index_path == str(out / "index.md")
+
+    def test_not_a_workspace(self, tmp_path):
+        """Test per-package runs fail clearly outside a workspace."""
+        config = CodeConCatConfig(target_path=str(tmp_path), per_package=True)
+
+        with pytest.raises(ConfigurationError, match="No workspace manifest"):
+            run_workspace(config)
+
+    def test_output_names(self):
+        """Test output names are file-system safe and unique."""
+        taken = {"index.md"}
+
+        names = [output_file_name(n, "md", taken) for n in ("@acme/ui", "acme-ui", "index")]
+
+        assert names == ["acme-ui.md", "acme-ui-2.md", "index-2.md"]
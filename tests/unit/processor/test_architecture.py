@@ -0,0 +1,142 @@
+"""Tests for architecture diagrams built from the import graph."""
+
+from unittest.mock import AsyncMock, patch
+
+import pytest
+
+from codeconcat.ai.base import AIProviderConfig, AIProviderType, SummarizationResult
+from codeconcat.ai.factory import get_ai_provider
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.processor.architecture import (
+    build_component_graph,
+    build_diagram_prompt,
+    component_of,
+    extract_mermaid,
+    render_mermaid,
+)
+from codeconcat.processor.summarization_processor import SummarizationProcessor
+
+IMPORTS = {
+    "app/cli/main.py": ["app.core.engine", "argparse", "Path"],
+    "app/cli/__init__.py": [],
+    "app/core/engine.py": ["..storage", ".models", "os"],
+    "app/core/models.py": ["dataclasses"],
+    "app/storage/__init__.py": ["app.core.models.Record"],
+    "web/src/index.ts": ["./views/home", "react"],
+    "web/src/views/home.ts": ["../api"],
+    "web/src/api.ts": [],
+}
+
+
+class TestComponentGraph:
+    """Test grouping files into components and resolving imports."""
+
+    def test_component_of(self):
+        """Test files are grouped by their directory cut to the depth."""
+        assert component_of("app/core/engine.py", 2) == "app/core"
+        assert component_of("web/src/views/home.ts", 2) == "web/src"
+        assert component_of("setup.py", 2) == "."
+
+    def test_internal_imports_become_edges(self):
+        """Test absolute, relative and path imports resolve; external ones are ignored."""
+        graph = build_component_graph(IMPORTS, depth=2)
+
+        assert sorted(graph.components) == ["app/cli", "app/core", "app/storage", "web/src"]
+        assert graph.edges == {
+            ("app/cli", "app/core"): 1,
+            ("app/core", "app/storage"): 1,
+            ("app/storage", "app/core"): 1,
+        }
+
+    def test_summaries_prefer_directory_summaries(self):
+        """Test a directory summary is used over the first sentences of file summaries."""
+        graph = build_component_graph(
+            IMPORTS,
+            file_summaries={
+                "app/core/engine.py": "Runs jobs. Retries on failure.",
+                "app/core/models.py": "Defines records.",
+                "app/cli/main.py": "Parses arguments.",
+            },
+            directory_summaries={"app/cli": "Command line entry points."},
+        )
+
+        assert graph.components["app/core"].summary == "Runs jobs. Defines records."
+        assert graph.components["app/cli"].summary == "Command line entry points."
+
+    def test_prompt_lists_components_and_edges(self):
+        """Test the prompt names every component by node id and lists the imports."""
+        prompt = build_diagram_prompt(build_component_graph(IMPORTS))
+
+        assert "- `c1` **app/cli** (2 files: __init__.py, main.py)" in prompt
+        assert "- c1 --> c2 (1 imports)" in prompt
+        assert "flowchart TD" in prompt
+
+
+class TestMermaid:
+    """Test extracting and rendering Mermaid diagrams."""
+
+    def test_extract_from_fenced_reply(self):
+        """Test the flowchart is taken from a fenced block and prose is rejected."""
+        reply = 'Here it is:\n```mermaid\nflowchart TD\n    c1["api"] --> c2["db"]\n```\n'
+
+        assert extract_mermaid(reply) == 'flowchart TD\n    c1["api"] --> c2["db"]'
+        assert extract_mermaid("graph LR\n  a --> b") == "graph LR\n  a --> b"
+        assert extract_mermaid("The system has an API and a database.") is None
+
+    def test_render_graph(self):
+        """Test the plain import graph renders nodes and weighted edges."""
+        diagram = render_mermaid(build_component_graph(IMPORTS))
+
+        assert diagram.splitlines()[:2] == ["flowchart TD", '    c1["app/cli"]']
+        assert "    c1 -->|1| c2" in diagram
+
+
+class TestDiagramGeneration:
+    """Test the AI diagram pass of the summarization processor."""
+
+    @staticmethod
+    def _processor():
+        provider = get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA, model="llama3.2", cache_enabled=False
+            )
+        )
+        config = CodeConCatConfig(
+            enable_ai_summary=True, ai_provider="ollama", ai_architecture_diagram=True
+        )
+        with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+            factory.return_value = provider
+            return SummarizationProcessor(config)
+
+    @staticmethod
+    def _files():
+        files = []
+        for path, imports in IMPORTS.items():
+            parsed_file = ParsedFileData(f"/repo/{path}", "x = 1", "python")
+            parsed_file.imports = imports
+            files.append(parsed_file)
+        return files
+
+    @pytest.mark.asyncio
+    async def test_ai_diagram(self):
+        """Test the provider's flowchart is used when it replies with Mermaid."""
+        processor = self._processor()
+        reply = '```mermaid\nflowchart TD\n    c1["app/cli: entry points"] --> c2\n```'
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary=reply)
+            diagram, source = await processor.generate_architecture_diagram(self._files())
+
+        assert source == "ai"
+        assert diagram == 'flowchart TD\n    c1["app/cli: entry points"] --> c2'
+        assert "**web/src**" in call.await_args.args[0]
+
+    @pytest.mark.asyncio
+    async def test_falls_back_to_import_graph(self):
+        """Test a reply without Mermaid or a failed request renders the import graph."""
+        processor = self._processor()
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary="", error="Ollama API error (500)")
+            processed = await processor.process_batch(self._files())
+
+        assert processed[0].ai_metadata["architecture_diagram_source"] == "graph"
+        assert processed[0].ai_metadata["architecture_diagram"].startswith("flowchart TD")
@@ -0,0 +1,155 @@
+"""Tests for the AI change summary of diff mode."""
+
+from unittest.mock import AsyncMock, patch
+
+import pytest
+
+from codeconcat.ai.base import AIProviderConfig, AIProviderType, SummarizationResult
+from codeconcat.ai.factory import get_ai_provider
+from codeconcat.base_types import (
+    AnnotatedFileData,
+    CodeConCatConfig,
+    Declaration,
+    DiffMetadata,
+    ParsedFileData,
+)
+from codeconcat.processor.change_summary import (
+    CHANGE_SUMMARY_MAX_TOKENS,
+    build_change_summary_prompt,
+    changed_declarations,
+    changed_line_ranges,
+)
+from codeconcat.processor.summarization_processor import SummarizationProcessor
+from codeconcat.writer.json_writer import write_json
+from codeconcat.writer.markdown_writer import write_markdown
+
+DIFF = """@@ -10,3 +10,4 @@ class Client:
+     def connect(self):
+-        return open_socket(self.host)
++        sock = open_socket(self.host, timeout=5)
++        return sock
+@@ -30 +31,0 @@ def helper():
+-    unused = 1
+"""
+
+
+def _diff_file():
+    client = Declaration("class", "Client", 1, 40, signature="class Client:")
+    client.children = [
+        Declaration("method", "connect", 10, 13, signature="def connect(self):"),
+        Declaration("method", "close", 15, 20, signature="def close(self):"),
+    ]
+    parsed_file = ParsedFileData(
+        "/repo/app/client.py",
+        "",
+        "python",
+        declarations=[client, Declaration("function", "helper", 28, 35)],
+        diff_content=DIFF,
+        diff_metadata=DiffMetadata("abc1234", "def5678", "modified", 2, 2, False),
+    )
+    deleted = ParsedFileData(
+        "/repo/app/legacy.py",
+        "",
+        "python",
+        diff_content="@@ -1,2 +0,0 @@\n-def old():\n-    pass\n",
+        diff_metadata=DiffMetadata("abc1234", "def5678", "deleted", 0, 2, False),
+    )
+    return parsed_file, deleted
+
+
+class TestChangedDeclarations:
+    """Test mapping diff hunks to declarations."""
+
+    def test_hunk_ranges(self):
+        """Test new-side ranges, with deletion-only hunks covering one line."""
+        assert changed_line_ranges(DIFF) == [(10, 13), (31, 31)]
+
+    def test_innermost_declarations(self):
+        """Test changes inside a method list the method, not its class."""
+        parsed_file, _deleted = _diff_file()
+
+        changed = changed_declarations(parsed_file.declarations, changed_line_ranges(DIFF))
+
+        assert [decl.name for decl in changed] == ["connect", "helper"]
+
+    def test_prompt_lists_files_and_declarations(self):
+        """Test the prompt names each file, its changed declarations and its diff."""
+        files = _diff_file()
+        paths = {"/repo/app/client.py": "client.py", "/repo/app/legacy.py": "legacy.py"}
+
+        prompt = build_change_summary_prompt(list(files), paths, "main", "feature")
+
+        assert "from `main` to `feature`" in prompt
+        assert "### client.py (modified, +2/-2)" in prompt
+        assert "- method `def connect(self):` (lines 10-13)" in prompt
+        assert "def close" not in prompt
+        assert "### legacy.py (deleted, +0/-2)" in prompt
+        assert "-def old():" in prompt
+
+
+class TestChangeSummaryPass:
+    """Test the change summary pass of the summarization processor."""
+
+    @staticmethod
+    def _processor(**overrides):
+        provider = get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA, model="llama3.2", cache_enabled=False
+            )
+        )
+        config = CodeConCatConfig(
+            enable_ai_summary=True,
+            ai_provider="ollama",
+            diff_from="main",
+            diff_to="feature",
+            **overrides,
+        )
+        with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+            factory.return_value = provider
+            return SummarizationProcessor(config), config
+
+    @pytest.mark.asyncio
+    async def test_change_summary_leads_the_output(self):
+        """Test the changelog is generated from the diff and written first."""
+        processor, config = self._processor()
+        files = list(_diff_file())
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary="Connections now time out.\n")
+            summary = await processor.generate_change_summary(files)
+
+        assert summary == "Connections now time out."
+        assert "from `main` to `feature`" in call.call_args.args[0]
+        item = AnnotatedFileData(
+            files[0].file_path,
+            "python",
+            "",
+            "",
+            ai_metadata={"change_summary": summary},
+            diff_metadata=files[0].diff_metadata,
+        )
+        markdown = write_markdown([item], config)
+        assert markdown.index("## Change Summary") < markdown.index("## Table of Contents")
+        assert '"change_summary": "Connections now time out."' in write_json([item], config)
+
+    @pytest.mark.asyncio
+    async def test_no_summary_outside_diff_mode(self):
+        """Test files without diff metadata send no change summary request."""
+        processor, _config = self._processor()
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            summary = await processor.generate_change_summary(
+                [ParsedFileData("/repo/app.py", "x = 1", "python")]
+            )
+
+        assert summary is None
+        call.assert_not_called()
+
+    def test_cost_estimate_counts_the_request(self):
+        """Test the change summary request is included in the cost estimate."""
+        with_summary, _config = self._processor()
+        without_summary, _config = self._processor(ai_change_summary=False)
+        files = list(_diff_file())
+
+        estimate = with_summary.estimate_cost(files)
+        baseline = without_summary.estimate_cost(files)
+        assert estimate.requests == baseline.requests + 1
+        assert estimate.output_tokens == baseline.output_tokens + CHANGE_SUMMARY_MAX_TOKENS
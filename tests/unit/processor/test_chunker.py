@@ -0,0 +1,118 @@
+"""Tests for declaration-aware chunking."""
+
+import json
+
+import pytest
+
+from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, Declaration
+from codeconcat.processor.chunker import DeclarationChunker, chunk_items, write_chunks_jsonl
+
+
+def word_count(text: str) -> int:
+    """Deterministic token counter for tests."""
+    return len(text.split())
+
+
+def make_lines(prefix: str, count: int, words: int = 4) -> list[str]:
+    return [" ".join(f"{prefix}{i}w{j}" for j in range(words)) for i in range(count)]
+
+
+class TestDeclarationChunker:
+    """Test chunk boundaries, overlap and parent context."""
+
+    def test_small_file_is_single_chunk(self):
+        """Test content under the budget yields one chunk."""
+        chunker = DeclarationChunker(max_tokens=100, overlap_tokens=0, token_counter=word_count)
+        content = "def a():\n    return 1\n"
+
+        chunks = chunker.chunk_file("a.py", content, [Declaration("function", "a", 1, 2)], "python")
+
+        assert len(chunks) == 1
+        assert chunks[0].start_line == 1
+        assert chunks[0].declarations == ["a"]
+        assert chunks[0].chunk_id == "a.py#0"
+
+    def test_boundaries_align_on_declarations(self):
+        """Test chunks split between functions rather than inside them."""
+        lines = make_lines("a", 5) + make_lines("b", 5)
+        decls = [Declaration("function", "a", 1, 5), Declaration("function", "b", 6, 10)]
+        chunker = DeclarationChunker(max_tokens=30, overlap_tokens=0, token_counter=word_count)
+
+        chunks = chunker.chunk_file("m.py", "\n".join(lines), decls)
+
+        assert [(c.start_line, c.end_line) for c in chunks] == [(1, 5), (6, 10)]
+        assert [c.declarations for c in chunks] == [["a"], ["b"]]
+
+    def test_large_class_split_on_methods_with_parent_context(self):
+        """Test oversized classes are split along methods and carry the class as context."""
+        lines = ["class Service:"] + make_lines("m", 4) + make_lines("n", 4)
+        cls = Declaration(
+            "class",
+            "Service",
+            1,
+            9,
+            children=[Declaration("method", "m", 2, 5), Declaration("method", "n", 6, 9)],
+        )
+        chunker = DeclarationChunker(max_tokens=25, overlap_tokens=0, token_counter=word_count)
+
+        chunks = chunker.chunk_file("svc.py", "\n".join(lines), [cls])
+
+        assert len(chunks) == 2
+        assert all(c.parent_context == ["class Service"] for c in chunks)
+        assert chunks[1].declarations == ["n"]
+        assert chunks[1].context_header == "svc.py > class Service (lines 6-9)"
+
+    def test_overlap_repeats_trailing_lines(self):
+        """Test the next chunk starts with trailing lines of the previous one."""
+        lines = make_lines("x", 12)
+        chunker = DeclarationChunker(max_tokens=30, overlap_tokens=10, token_counter=word_count)
+
+        chunks = chunker.chunk_file("data.txt", "\n".join(lines))
+
+        assert len(chunks) > 1
+        for prev, nxt in zip(chunks, chunks[1:], strict=False):
+            assert nxt.start_line <= prev.end_line
+            assert nxt.start_line > prev.start_line
+        assert chunks[-1].end_line == 12
+        assert all(c.token_count <= 30 for c in chunks)
+
+    def test_invalid_limits(self):
+        """Test overlap must be smaller than the chunk size."""
+        with pytest.raises(ValueError):
+            DeclarationChunker(max_tokens=10, overlap_tokens=10)
+
+    def test_negative_config_limits_rejected(self):
+        """Test negative chunk sizes fail when the config is loaded."""
+        with pytest.raises(ValueError):
+            CodeConCatConfig(chunk_max_tokens=-1)
+        with pytest.raises(ValueError):
+            CodeConCatConfig(chunk_overlap_tokens=-1)
+
+    def test_empty_content(self):
+        """Test whitespace-only content yields no chunks."""
+        assert DeclarationChunker(token_counter=word_count).chunk_file("e.py", "  \n") == []
+
+
+class TestChunkOutput:
+    """Test item chunking and JSON Lines output."""
+
+    def test_chunk_items_relative_paths_and_jsonl(self, tmp_path):
+        """Test paths are made relative to the root and chunks serialize to JSONL."""
+        source = tmp_path / "pkg" / "mod.py"
+        item = AnnotatedFileData(
+            file_path=str(source),
+            language="python",
+            content="def f():\n    pass\n",
+            annotated_content="",
+            declarations=[Declaration("function", "f", 1, 2)],
+        )
+
+        chunks = chunk_items([item], max_tokens=64, overlap_tokens=8, root_path=str(tmp_path))
+        out = tmp_path / "out" / "chunks.jsonl"
+        count = write_chunks_jsonl(chunks, str(out))
+
+        assert count == 1
+        record = json.loads(out.read_text().splitlines()[0])
+        assert record["file_path"] == "pkg/mod.py"
+        assert record["language"] == "python"
+        assert record["context_header"].startswith("pkg/mod.py (lines 1-")
@@ -0,0 +1,82 @@
+"""Tests for the compression dry-run report."""
+
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.processor.compression_report import (
+    STRATEGIES,
+    CompressionReport,
+    build_compression_report,
+    estimate_file_tokens,
+)
+
+PYTHON_SOURCE = '''def handler(request):
+    """Handle an incoming request."""
+    # validate the input first
+    value = request.get("value")
+    total = 0
+    for i in range(10):
+        total += i
+    return total + value
+'''
+
+
+def line_count(text: str) -> int:
+    """Deterministic token counter for tests."""
+    return len([line for line in text.split("\n") if line.strip()])
+
+
+class TestEstimateFileTokens:
+    """Test per-file estimates."""
+
+    def test_all_strategies_reported(self):
+        """Test every strategy is estimated and stripping never adds tokens."""
+        config = CodeConCatConfig(target_path="/repo")
+        file_data = ParsedFileData(file_path="/repo/app.py", language="python", content=PYTHON_SOURCE)
+
+        tokens = estimate_file_tokens(file_data, config, token_counter=line_count)
+
+        assert set(tokens) == set(STRATEGIES)
+        assert tokens["original"] == 8
+        assert tokens["no-comments"] == 7
+        assert tokens["no-docstrings"] < tokens["original"]
+
+    def test_does_not_mutate_config(self):
+        """Test strategies apply overrides to copies only."""
+        config = CodeConCatConfig(target_path="/repo", compression_level="low")
+        file_data = ParsedFileData(file_path="/repo/app.py", language="python", content=PYTHON_SOURCE)
+
+        estimate_file_tokens(file_data, config, token_counter=line_count)
+
+        assert config.enable_compression is False
+        assert config.compression_level == "low"
+        assert config.remove_comments is False
+
+
+class TestCompressionReport:
+    """Test aggregation and rendering."""
+
+    def test_groups_by_directory(self, tmp_path):
+        """Test files are grouped by top-level directory with root files under '.'."""
+        (tmp_path / "src" / "pkg").mkdir(parents=True)
+        config = CodeConCatConfig(target_path=str(tmp_path))
+        files = [
+            ParsedFileData(str(tmp_path / "src" / "pkg" / "a.py"), "x = 1\n", "python"),
+            ParsedFileData(str(tmp_path / "src" / "b.py"), "y = 2\n", "python"),
+            ParsedFileData(str(tmp_path / "setup.py"), "z = 3\n", "python"),
+            ParsedFileData(str(tmp_path / "empty.py"), "", "python"),
+        ]
+
+        report = build_compression_report(files, config, token_counter=line_count)
+
+        assert report.file_counts == {"src": 2, ".": 1}
+        assert report.totals()["original"] == 3
+
+    def test_savings_and_text(self):
+        """Test savings ratio and plain-text rendering."""
+        report = CompressionReport()
+        report.add("src", {s: 100 for s in STRATEGIES} | {"essential": 40})
+
+        assert report.savings("essential") == 0.6
+        assert report.savings("original") == 0.0
+        text = report.to_text()
+        assert text.splitlines()[0].startswith("directory")
+        assert "TOTAL" in text
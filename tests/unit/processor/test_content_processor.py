@@ -19,6 +19,8 @@ from codeconcat.processor.content_processor import (
     generate_file_summary,
     process_file_content,
     remove_docstrings,
+    truncate_long_lines,
+    truncate_string_literals,
 )
 
 
@@ -1041,6 +1043,88 @@ def function():
         self.assertIn("`CRITICAL` (Line 42): Enum severity test", summary)
         self.assertIn("`HIGH` (Line 43): Mock severity test", summary)
 
+    def test_truncate_long_lines_adds_marker(self):
+        """Test overlong lines are cut with a continuation marker."""
+        content = "short\n" + "x" * 120 + "\nend"
+
+        result, lines, chars = truncate_long_lines(content, 100)
+
+        self.assertEqual(lines, 1)
+        self.assertEqual(chars, 20)
+        self.assertEqual(result.split("\n")[1], "x" * 100 + "…[truncated 20 chars]")
+        self.assertEqual(len(result.split("\n")), 3)
+
+    def test_truncate_long_lines_formats_large_counts(self):
+        """Test the removed character count uses thousands separators."""
+        result, _, chars = truncate_long_lines("a" * 14_213, 10)
+
+        self.assertEqual(chars, 14_203)
+        self.assertTrue(result.endswith("…[truncated 14,203 chars]"))
+
+    def test_truncate_long_lines_disabled(self):
+        """Test zero length and short content leave content untouched."""
+        content = "y" * 500
+        self.assertEqual(truncate_long_lines(content, 0), (content, 0, 0))
+        self.assertEqual(truncate_long_lines("ok\nfine", 10), ("ok\nfine", 0, 0))
+
+    def test_negative_max_line_length_rejected(self):
+        """Test a negative limit is a config error rather than disabling truncation."""
+        with self.assertRaises(ValueError):
+            CodeConCatConfig(max_line_length=-1)
+
+    def test_truncate_string_literals_keeps_hash_and_length(self):
+        """Test oversized literals keep quotes, a preview, length and a stable hash."""
+        blob = "QUJD" * 500
+        content = f'LOGO = "{blob}"\nOTHER = "{blob}"\nname = "short"\n'
+
+        result, literals, chars = truncate_string_literals(content, 100)
+
+        self.assertEqual(literals, 2)
+        self.assertEqual(chars, 2 * (2000 - 40))
+        first, second, third = result.split("\n")[:3]
+        self.assertTrue(first.startswith('LOGO = "' + blob[:40] + "…[string truncated: 2,000 chars, sha256:"))
+        self.assertTrue(first.endswith(']"'))
+        self.assertEqual(first.split("sha256:")[1], second.split("sha256:")[1])
+        self.assertEqual(third, 'name = "short"')
+
+    def test_truncate_string_literals_preserves_line_count(self):
+        """Test multi-line literals keep their newlines so line numbers stay valid."""
+        sql = "\n".join(f"INSERT INTO t VALUES ({i});" for i in range(50))
+        content = f'DUMP = """{sql}"""\n\ndef after():\n    pass\n'
+
+        result, literals, _ = truncate_string_literals(content, 200)
+
+        self.assertEqual(literals, 1)
+        self.assertEqual(result.count("\n"), content.count("\n"))
+        self.assertIn("def after():", result)
+        self.assertIn('"""', result.split("\n")[0])
+
+    def test_truncate_string_literals_ignores_apostrophes_and_disabled(self):
+        """Test stray apostrophes do not span lines and zero disables the transform."""
+        content = "# don't touch\nx = 1\n# it's fine\n" + "y = 2\n" * 100
+        self.assertEqual(truncate_string_literals(content, 10), (content, 0, 0))
+        blob = f'"{"z" * 50}"'
+        self.assertEqual(truncate_string_literals(blob, 0), (blob, 0, 0))
+        with self.assertRaises(ValueError):
+            CodeConCatConfig(max_string_literal_length=-1)
+
+    def test_truncate_string_literals_unpaired_backtick_in_comment(self):
+        """Test a backtick in a Python comment does not pair with a later one."""
+        body = "".join(f"value_{i} = compute({i})\n" for i in range(30))
+        content = f"# Use `foo here\n{body}# then `bar\n"
+
+        self.assertEqual(truncate_string_literals(content, 50, "python"), (content, 0, 0))
+
+    def test_truncate_string_literals_backticks_by_language(self):
+        """Test backticks delimit strings in JavaScript and Go only, outside comments."""
+        blob = "x" * 300
+        js = f"// a `quoted` word\nconst t = `{blob}`;\n"
+        result, literals, _ = truncate_string_literals(js, 100, "javascript")
+        self.assertEqual(literals, 1)
+        self.assertTrue(result.startswith("// a `quoted` word\nconst t = `xxx"))
+        ruby = f"cmd = `{blob}`\n"
+        self.assertEqual(truncate_string_literals(ruby, 100, "ruby"), (ruby, 0, 0))
+
 
 if __name__ == "__main__":
     unittest.main()
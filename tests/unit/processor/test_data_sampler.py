@@ -0,0 +1,103 @@
+"""Tests for data file sampling and schema summaries."""
+
+import json
+
+from codeconcat.processor.data_sampler import (
+    infer_scalar_type,
+    sample_data_content,
+    should_sample,
+)
+
+CSV_CONTENT = """id,name,price,active,created
+1,apple,1.5,true,2024-01-02
+2,banana,,false,2024-01-03
+3,cherry,3,yes,2024-01-04
+4,date,4.25,no,2024-01-05
+"""
+
+
+class TestShouldSample:
+    """Test which files are sampled."""
+
+    def test_delimited_and_ndjson_always_sampled(self):
+        """Test CSV, TSV and NDJSON are sampled regardless of size."""
+        assert should_sample("data/rows.csv", "a,b\n")
+        assert should_sample("data/rows.TSV", "a\tb\n")
+        assert should_sample("logs/events.jsonl", "{}\n")
+
+    def test_small_json_kept(self):
+        """Test small JSON (usually configuration) is not sampled."""
+        assert not should_sample("package.json", '{"name": "x"}')
+        assert should_sample("fixtures.json", "[" + "1," * 20000 + "1]")
+
+    def test_non_data_file(self):
+        """Test source files are never sampled."""
+        assert not should_sample("main.py", "x = 1")
+
+
+class TestInferScalarType:
+    """Test CSV cell type inference."""
+
+    def test_types(self):
+        """Test each supported scalar type."""
+        assert infer_scalar_type("42") == "integer"
+        assert infer_scalar_type("-1.5e3") == "float"
+        assert infer_scalar_type("TRUE") == "boolean"
+        assert infer_scalar_type("2024-01-02") == "date"
+        assert infer_scalar_type("2024-01-02T10:00:00Z") == "datetime"
+        assert infer_scalar_type("hello") == "string"
+        assert infer_scalar_type("  ") is None
+
+
+class TestSampleDataContent:
+    """Test sampled output per format."""
+
+    def test_csv_schema_and_sample(self):
+        """Test CSV summary reports columns, types, row count and sample rows."""
+        result = sample_data_content(CSV_CONTENT, "prices.csv", sample_rows=2)
+
+        assert result.startswith("CSV file: 4 rows, 5 columns")
+        assert "  id: integer" in result
+        assert "  price: float (1 empty)" in result
+        assert "  active: boolean" in result
+        assert "  created: date" in result
+        assert "id,name,price,active,created\n1,apple" in result
+        assert "3,cherry" not in result
+        assert "... 2 more rows omitted" in result
+
+    def test_tsv(self):
+        """Test tab-delimited files use the tab delimiter."""
+        result = sample_data_content("a\tb\n1\tx\n", "t.tsv")
+        assert result.startswith("TSV file: 1 rows, 2 columns")
+        assert "  b: string" in result
+
+    def test_json_array_of_records(self):
+        """Test arrays of objects are summarized by field."""
+        records = [{"id": i, "tags": ["a"], "score": None if i == 0 else 0.5} for i in range(10)]
+        result = sample_data_content(json.dumps(records), "records.json", sample_rows=3)
+
+        assert result.startswith("JSON array file: 10 records")
+        assert "  id: integer" in result
+        assert "  score: float (1 empty)" in result
+        assert "... 7 more records omitted" in result
+
+    def test_json_object_truncates_arrays(self):
+        """Test nested arrays in an object are limited to the sample size."""
+        data = {"version": 1, "items": list(range(100))}
+        result = sample_data_content(json.dumps(data), "big.json", sample_rows=2)
+
+        assert "  items: array (100 items)" in result
+        assert '"... 98 more items"' in result
+
+    def test_ndjson(self):
+        """Test NDJSON records are parsed line by line."""
+        content = "\n".join(json.dumps({"event": "click", "n": i}) for i in range(4)) + "\n"
+        result = sample_data_content(content, "events.ndjson", sample_rows=1)
+
+        assert result.startswith("NDJSON file: 4 records")
+        assert "  event: string" in result
+
+    def test_invalid_json_returns_none(self):
+        """Test unparseable content falls back to normal handling."""
+        assert sample_data_content("{not json", "broken.json") is None
+        assert sample_data_content("x", "main.py") is None
@@ -0,0 +1,157 @@
+"""Tests for embedding generation and vector store export."""
+
+import ast
+import json
+import struct
+import sys
+from unittest.mock import patch
+
+import pytest
+
+from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, Declaration
+from codeconcat.processor.embeddings import (
+    EmbeddingError,
+    HashingEmbedder,
+    cosine_similarity,
+    create_embedder,
+    declaration_records,
+    export_embeddings,
+    run_embedding_stage,
+)
+
+SOURCE = """class UserStore:
+    def load_user(self, user_id):
+        return self.db.get(user_id)
+
+def parse_config(path):
+    return open(path).read()
+"""
+
+
+def read_npy(path):
+    """Minimal .npy reader for float32 matrices (avoids a NumPy test dependency)."""
+    data = path.read_bytes()
+    assert data[:8] == b"\x93NUMPY\x01\x00"
+    header_len = int.from_bytes(data[8:10], "little")
+    header = ast.literal_eval(data[10 : 10 + header_len].decode("latin1"))
+    assert (10 + header_len) % 64 == 0
+    rows, cols = header["shape"]
+    values = struct.unpack(f"<{rows * cols}f", data[10 + header_len :])
+    return [list(values[i * cols : (i + 1) * cols]) for i in range(rows)]
+
+
+def make_item(tmp_path):
+    return AnnotatedFileData(
+        file_path=str(tmp_path / "app" / "store.py"),
+        language="python",
+        content=SOURCE,
+        annotated_content=SOURCE,
+        declarations=[
+            Declaration(
+                "class", "UserStore", 1, 3, children=[Declaration("method", "load_user", 2, 3)]
+            ),
+            Declaration("function", "parse_config", 5, 6),
+        ],
+    )
+
+
+class TestHashingEmbedder:
+    """Test the dependency-free backend."""
+
+    def test_deterministic_and_normalized(self):
+        """Test vectors are stable, unit length and sized as configured."""
+        embedder = HashingEmbedder(dimensions=64)
+        first, second = embedder.embed(["loadUser user_id", "loadUser user_id"])
+
+        assert first == second
+        assert len(first) == 64
+        assert sum(v * v for v in first) == pytest.approx(1.0)
+
+    def test_subwords_make_related_text_similar(self):
+        """Test identifiers sharing subwords score higher than unrelated text."""
+        embedder = HashingEmbedder()
+        query, related, unrelated = embedder.embed(
+            ["load user", "def loadUser(self, user_id)", "render chart axis labels"]
+        )
+
+        assert cosine_similarity(query, related) > cosine_similarity(query, unrelated)
+
+    def test_cosine_similarity(self):
+        assert cosine_similarity([1.0, 0.0], [2.0, 0.0]) == pytest.approx(1.0)
+        assert cosine_similarity([1.0, 0.0], [0.0, 3.0]) == 0.0
+        assert cosine_similarity([0.0, 0.0], [1.0, 1.0]) == 0.0
+
+
+class TestDeclarationRecords:
+    """Test per-declaration records."""
+
+    def test_nested_declarations_get_qualified_ids(self, tmp_path):
+        """Test each declaration becomes a record with its enclosing context."""
+        records = declaration_records([make_item(tmp_path)], str(tmp_path))
+
+        assert [r.chunk_id for r in records] == [
+            "app/store.py#UserStore",
+            "app/store.py#UserStore.load_user",
+            "app/store.py#parse_config",
+        ]
+        assert records[1].parent_context == ["class UserStore"]
+        assert records[2].content.startswith("def parse_config")
+
+
+class TestExport:
+    """Test vector store export."""
+
+    def test_run_stage_writes_npy_store(self, tmp_path):
+        """Test the npy store contains one row per record plus metadata and manifest."""
+        config = CodeConCatConfig(
+            target_path=str(tmp_path),
+            output=str(tmp_path / "out.md"),
+            embed=True,
+            embed_backend="hashing",
+            embed_units="declarations",
+        )
+
+        result = run_embedding_stage([make_item(tmp_path)], config)
+
+        store = tmp_path / "out.embeddings"
+        assert result.count == 3 and result.location == str(store)
+        vectors = read_npy(store / "embeddings.npy")
+        assert len(vectors) == 3 and len(vectors[0]) == 384
+        metadata = [json.loads(line) for line in (store / "metadata.jsonl").read_text().splitlines()]
+        assert metadata[0]["chunk_id"] == "app/store.py#UserStore"
+        manifest = json.loads((store / "manifest.json").read_text())
+        assert manifest["backend"] == "hashing" and manifest["dimensions"] == 384
+
+    def test_missing_store_package(self, tmp_path):
+        """Test a missing optional store package raises a helpful error."""
+        records = declaration_records([make_item(tmp_path)], str(tmp_path))
+        embedder = HashingEmbedder(8)
+        with patch.dict(sys.modules, {"chromadb": None}):
+            with pytest.raises(EmbeddingError, match="pip install chromadb"):
+                export_embeddings(
+                    records,
+                    embedder.embed([r.content for r in records]),
+                    "chroma",
+                    str(tmp_path / "db"),
+                    embedder,
+                )
+
+
+class TestCreateEmbedder:
+    """Test backend selection."""
+
+    def test_local_only_refuses_remote_endpoint(self):
+        """Test the openai backend respects local-only mode."""
+        config = CodeConCatConfig(embed_backend="openai", ai_api_key="sk-test", ai_local_only=True)
+        with pytest.raises(EmbeddingError, match="Local-only"):
+            create_embedder(config)
+
+    def test_local_only_allows_private_server(self):
+        """Test an OpenAI-compatible server on a private address is accepted."""
+        config = CodeConCatConfig(
+            embed_backend="openai",
+            embed_api_base="http://127.0.0.1:8000/v1",
+            ai_local_only=True,
+        )
+        embedder = create_embedder(config)
+        assert embedder.api_base == "http://127.0.0.1:8000/v1"
@@ -0,0 +1,64 @@
+"""Tests for the heuristic project overview."""
+
+from codeconcat.base_types import Declaration, ParsedFileData
+from codeconcat.processor.project_overview import build_heuristic_overview
+
+
+def _files(root):
+    return [
+        ParsedFileData(
+            str(root / "src" / "app" / "cli.py"),
+            "import typer\n\napp = typer.Typer()\n",
+            "python",
+            declarations=[Declaration("function", "run", 3, 3)],
+            imports=["typer"],
+        ),
+        ParsedFileData(
+            str(root / "src" / "app" / "models.py"),
+            "class User:\n    pass\n\nclass Team:\n    pass\n",
+            "python",
+            declarations=[Declaration("class", "User", 1, 2), Declaration("class", "Team", 4, 5)],
+            imports=["pydantic.BaseModel"],
+            ai_summary="Defines the user and team models. Used by the API layer.",
+        ),
+        ParsedFileData(str(root / "web" / "index.ts"), "export {}\n", "typescript"),
+        ParsedFileData(str(root / "setup.py"), "", "python"),
+    ]
+
+
+class TestBuildHeuristicOverview:
+    """Test overview sections built without AI."""
+
+    def test_sections_from_repository(self, tmp_path):
+        """Test purpose, layout, key modules and tech stack are derived from the tree."""
+        (tmp_path / "README.md").write_text(
+            "# Demo\n\n[![CI](badge.svg)](ci)\n\nDemo manages users and teams over a CLI.\n"
+        )
+        (tmp_path / "pyproject.toml").write_text('[project]\nname = "demo"\n')
+
+        overview = build_heuristic_overview(_files(tmp_path), str(tmp_path))
+
+        assert "### Purpose\n\nDemo manages users and teams over a CLI." in overview
+        assert "- `src/`: 2 files (python)" in overview
+        assert "- `(root)`: 1 file (python)" in overview
+        assert "- `src/app/cli.py`: entry point; defines `run`" in overview
+        assert "- `src/app/models.py`: Defines the user and team models." in overview
+        assert "Python (pyproject)" in overview
+        assert "Frameworks & libraries: Typer, Pydantic" in overview
+
+    def test_manifest_description_fallback(self, tmp_path):
+        """Test the package description is used when there is no README."""
+        (tmp_path / "package.json").write_text('{"description": "A tiny web dashboard"}')
+
+        overview = build_heuristic_overview(_files(tmp_path), str(tmp_path))
+
+        assert "A tiny web dashboard" in overview
+        assert "Node.js (npm)" in overview
+
+    def test_without_root(self, tmp_path):
+        """Test paths are made relative to the common directory when no root is given."""
+        overview = build_heuristic_overview(_files(tmp_path), None)
+
+        assert "No README or package description found." in overview
+        assert "`src/app/cli.py`" in overview
+        assert str(tmp_path) not in overview
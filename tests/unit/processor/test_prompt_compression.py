@@ -0,0 +1,106 @@
+"""Tests for comment/docstring prompt compression."""
+
+import pytest
+
+from codeconcat.base_types import ParsedFileData
+from codeconcat.processor.prompt_compression import (
+    PromptCompressor,
+    SelfInformationPruner,
+    find_prose_spans,
+)
+
+PYTHON_SOURCE = '''def load(path):
+    """Load the configuration file from the given path and return it.
+
+    This is called by the main entry point at startup.
+    """
+    # Read the file and then parse it as YAML for the caller
+    return parse(open(path).read())  # trailing comment stays
+'''
+
+
+class TestFindProseSpans:
+    """Test locating comment and docstring prose."""
+
+    def test_python_docstring_and_comments(self):
+        """Test docstring lines and full-line comments are found, code lines are not."""
+        spans = find_prose_spans(PYTHON_SOURCE, "python")
+        lines = PYTHON_SOURCE.split("\n")
+
+        assert [s.line for s in spans] == [1, 3, 5]
+        assert lines[1][spans[0].start : spans[0].end].startswith("Load the configuration")
+        assert lines[5][spans[2].start :].startswith(" Read the file")
+
+    def test_python_string_assignment_is_not_a_docstring(self):
+        """Test code after a triple-quoted assignment is not mistaken for prose."""
+        source = (
+            '"""Queries for the billing report."""\n\n'
+            'SQL = """\nSELECT total FROM items\n"""\n\n\n'
+            "def compute_total(items, discount):\n"
+            "    total = sum(items)\n"
+            "    return total - discount\n"
+        )
+        spans = find_prose_spans(source, "python")
+
+        assert [s.line for s in spans] == [0]
+
+        file_data = ParsedFileData("/repo/report.py", source, "python")
+        PromptCompressor(keep_ratio=0.2).compress([file_data])
+        assert file_data.content.split("\n")[1:] == source.split("\n")[1:]
+
+    def test_javascript_block_comment(self):
+        """Test JSDoc-style block comments strip leading stars."""
+        source = "/**\n * Compute the total price.\n */\nfunction total() {}\n"
+        spans = find_prose_spans(source, "javascript")
+        lines = source.split("\n")
+
+        assert len(spans) == 1
+        assert lines[1][spans[0].start : spans[0].end] == "Compute the total price."
+
+
+class TestSelfInformationPruner:
+    """Test word-level pruning."""
+
+    def test_prunes_stopwords_first_and_keeps_identifiers(self):
+        """Test boilerplate words go first while identifiers survive."""
+        pruner = SelfInformationPruner(["the the the of of a"])
+        text = " Returns the parsed value of `config_path` for the caller"
+
+        result = pruner.prune(text, 0.5)
+
+        assert result.startswith(" ")
+        assert "`config_path`" in result
+        assert "the" not in result.split()
+        assert len(result.split()) <= 5
+
+    def test_full_ratio_is_noop(self):
+        """Test keep ratio of 1 leaves text unchanged."""
+        assert SelfInformationPruner().prune("keep every word here", 1.0) == "keep every word here"
+
+
+class TestPromptCompressor:
+    """Test the file-level pass."""
+
+    def test_code_is_untouched(self):
+        """Test only prose changes and code lines are byte-identical."""
+        file_data = ParsedFileData("/repo/a.py", PYTHON_SOURCE, "python")
+
+        result = PromptCompressor(keep_ratio=0.4).compress([file_data])
+
+        assert result.files_changed == 1
+        assert result.words_after < result.words_before
+        before, after = PYTHON_SOURCE.split("\n"), file_data.content.split("\n")
+        assert len(before) == len(after)
+        for idx in (0, 6):
+            assert before[idx] == after[idx]
+
+    def test_invalid_ratio(self):
+        """Test out-of-range keep ratios are rejected."""
+        with pytest.raises(ValueError):
+            PromptCompressor(keep_ratio=0)
+
+    def test_missing_llmlingua_falls_back(self):
+        """Test the llmlingua backend falls back when the package is absent."""
+        file_data = ParsedFileData("/repo/a.py", PYTHON_SOURCE, "python")
+        result = PromptCompressor(keep_ratio=0.5, backend="llmlingua").compress([file_data])
+        assert result.files_changed == 1
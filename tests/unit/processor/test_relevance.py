@@ -0,0 +1,127 @@
+"""Tests for task-aware relevance ranking."""
+
+from unittest.mock import patch
+
+from codeconcat.base_types import AnnotatedFileData, CodeConCatConfig, Declaration
+from codeconcat.processor.embeddings import EmbeddingError, HashingEmbedder
+from codeconcat.processor.relevance import (
+    fit_to_budget,
+    order_by_relevance,
+    rank_by_task,
+)
+
+GATEWAY = """class RateLimiter:
+    def allow_request(self, client_id):
+        return self.bucket.take(client_id)
+
+def route_request(request):
+    return forward(request)
+"""
+CHARTS = "def render_axis_labels(axis):\n    draw(axis.labels)\n"
+README = "Project notes about deployment environments."
+
+
+def make_item(path, content, declarations=None):
+    return AnnotatedFileData(
+        file_path=path,
+        language="python",
+        content=content,
+        annotated_content=content,
+        declarations=declarations or [],
+    )
+
+
+def items():
+    return [
+        make_item("charts/axis.py", CHARTS, [Declaration("function", "render_axis_labels", 1, 2)]),
+        make_item("notes.md", README),
+        make_item(
+            "gateway/limits.py",
+            GATEWAY,
+            [
+                Declaration(
+                    "class",
+                    "RateLimiter",
+                    1,
+                    3,
+                    children=[Declaration("method", "allow_request", 2, 3)],
+                ),
+                Declaration("function", "route_request", 5, 6),
+            ],
+        ),
+    ]
+
+
+CONFIG = CodeConCatConfig(embed_backend="hashing")
+
+
+class TestRanking:
+    """Test scoring files against the task."""
+
+    def test_relevant_file_ranks_first(self):
+        """Test the file whose declaration matches the task ranks first and names it."""
+        ranking = rank_by_task("add a rate limiter to the gateway", items(), CONFIG)
+
+        assert ranking[0].file_path == "gateway/limits.py"
+        assert ranking[0].best_match == "RateLimiter"
+        assert ranking[0].score > ranking[-1].score
+        assert {r.file_path for r in ranking} == {"charts/axis.py", "notes.md", "gateway/limits.py"}
+
+    def test_order_by_relevance(self):
+        """Test items are reordered by rank."""
+        files = items()
+        order_by_relevance(files, rank_by_task("axis labels in charts", files, CONFIG))
+
+        assert files[0].file_path == "charts/axis.py"
+
+    def test_unavailable_backend_falls_back_to_hashing(self):
+        """Test ranking still works when the configured embedder cannot load."""
+        with patch(
+            "codeconcat.processor.relevance.create_embedder",
+            side_effect=EmbeddingError("sentence-transformers is not installed"),
+        ):
+            ranking = rank_by_task("rate limiting", items(), CodeConCatConfig())
+
+        assert ranking[0].file_path == "gateway/limits.py"
+
+    def test_custom_embedder(self):
+        """Test an explicit embedder is used instead of the configured one."""
+        ranking = rank_by_task("deployment notes", items(), CONFIG, embedder=HashingEmbedder(64))
+
+        assert ranking[0].file_path == "notes.md"
+
+
+class TestBudget:
+    """Test fitting ranked items into the token budget."""
+
+    @staticmethod
+    def _tokens(text, model):
+        return len(text.split())
+
+    def test_tail_is_compressed_or_dropped(self):
+        """Test files past the budget are compressed when that fits and left out otherwise."""
+        files = items()
+        order_by_relevance(files, rank_by_task("rate limiter", files, CONFIG))
+        with (
+            patch("codeconcat.processor.relevance.count_tokens", side_effect=self._tokens),
+            patch(
+                "codeconcat.processor.relevance.CompressionProcessor.apply_compression",
+                side_effect=lambda item: "..." if item.declarations else item.content,
+            ),
+        ):
+            fitted, result = fit_to_budget(files, budget=12, config=CONFIG)
+
+        assert result.kept == ["gateway/limits.py"]
+        assert result.compressed == ["charts/axis.py"]
+        assert result.dropped == ["notes.md"]
+        assert [f.file_path for f in fitted] == ["gateway/limits.py", "charts/axis.py"]
+        assert fitted[1].content == "..."
+        assert result.tokens == 12
+
+    def test_everything_fits(self):
+        """Test nothing changes when the budget covers all files."""
+        files = items()
+        fitted, result = fit_to_budget(files, budget=100_000, config=CONFIG)
+
+        assert fitted == files
+        assert result.compressed == [] and result.dropped == []
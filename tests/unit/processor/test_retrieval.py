@@ -0,0 +1,127 @@
+"""Tests for retrieval and question answering over generated output."""
+
+from unittest.mock import AsyncMock
+
+import pytest
+
+from codeconcat.ai.base import SummarizationResult
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.processor.chunker import Chunk
+from codeconcat.processor.embeddings import HashingEmbedder, export_embeddings
+from codeconcat.processor.retrieval import (
+    QA_SYSTEM_PROMPT,
+    EmbeddingIndex,
+    RetrievedChunk,
+    answer_question,
+    build_question_prompt,
+    chunks_from_output,
+    search_chunks,
+)
+
+
+def make_chunk(file_path, content, parent_context=None):
+    lines = content.count("\n") + 1
+    return Chunk(
+        chunk_id=f"{file_path}#0",
+        file_path=file_path,
+        language="python",
+        start_line=1,
+        end_line=lines,
+        content=content,
+        token_count=0,
+        parent_context=parent_context or [],
+    )
+
+
+CHUNKS = [
+    make_chunk("auth/login.py", "def verify_password(user, password):\n    return check(user)"),
+    make_chunk("charts/axis.py", "def render_axis_labels(axis):\n    draw(axis.labels)"),
+    make_chunk(
+        "net/retry.py",
+        "def retry_with_backoff(func, max_retries=3):\n    sleep(2 ** attempt)",
+        parent_context=["class HttpClient"],
+    ),
+]
+
+
+class TestEmbeddingIndex:
+    """Test querying an exported embedding directory."""
+
+    def test_npy_index_round_trip(self, tmp_path):
+        """Test the npy store is searched with the embedder named in the manifest."""
+        embedder = HashingEmbedder()
+        vectors = embedder.embed([f"{c.context_header}\n{c.content}" for c in CHUNKS])
+        export_embeddings(CHUNKS, vectors, "npy", str(tmp_path), embedder)
+
+        index = EmbeddingIndex(tmp_path, CodeConCatConfig(embed_backend="sentence-transformers"))
+        results = index.search("where is the password verified", top_k=2)
+
+        assert index.embedder.name == "hashing"
+        assert len(results) == 2
+        assert results[0].chunk.file_path == "auth/login.py"
+        assert results[0].score >= results[1].score
+
+    def test_empty_index(self, tmp_path):
+        """Test an index with no records returns no results."""
+        export_embeddings([], [], "npy", str(tmp_path), HashingEmbedder())
+        assert EmbeddingIndex(tmp_path, CodeConCatConfig()).search("anything") == []
+
+
+class TestOutputSearch:
+    """Test searching an output file without an index."""
+
+    def test_search_chunks_ranks_by_similarity(self):
+        """Test identifier subwords match natural-language questions."""
+        results = search_chunks("how does retry backoff work", CHUNKS, top_k=1)
+
+        assert [r.chunk.file_path for r in results] == ["net/retry.py"]
+
+    def test_chunks_from_markdown_output(self, tmp_path):
+        """Test files are recovered from a markdown output and chunked."""
+        output = tmp_path / "output.md"
+        output.write_text(
+            "# Output\n\n### 1. src/app.py {#src-app-py}\n```python\ndef main():\n    run()\n```\n",
+            encoding="utf-8",
+        )
+
+        chunks = chunks_from_output(str(output))
+
+        assert [c.file_path for c in chunks] == ["src/app.py"]
+        assert chunks[0].language == "python"
+        assert "def main()" in chunks[0].content
+
+
+class TestQuestionPrompt:
+    """Test prompt construction and the provider call."""
+
+    def test_sources_are_numbered(self):
+        """Test each chunk is labelled with its number and location."""
+        results = [RetrievedChunk(CHUNKS[0], 0.9), RetrievedChunk(CHUNKS[2], 0.5)]
+
+        prompt = build_question_prompt("How do logins work?", results)
+
+        assert "[1] auth/login.py (lines 1-2)" in prompt
+        assert "[2] net/retry.py > class HttpClient (lines 1-2)" in prompt
+        assert prompt.rstrip().endswith("How do logins work?\n\n### Answer")
+
+    def test_context_is_capped(self):
+        """Test later sources are dropped once the character budget is used."""
+        results = [RetrievedChunk(c, 1.0) for c in CHUNKS]
+
+        prompt = build_question_prompt("q", results, max_chars=120)
+
+        assert "[1]" in prompt
+        assert "[3]" not in prompt
+
+    @pytest.mark.asyncio
+    async def test_answer_question_uses_qa_system_prompt(self):
+        """Test the provider receives the built prompt and the QA system prompt."""
+        provider = AsyncMock()
+        provider.complete.return_value = SummarizationResult(summary="See [1].")
+
+        result = await answer_question(provider, "Q?", [RetrievedChunk(CHUNKS[0], 1.0)], 200)
+
+        assert result.summary == "See [1]."
+        args, kwargs = provider.complete.call_args
+        assert "[1] auth/login.py" in args[0]
+        assert kwargs == {"system_prompt": QA_SYSTEM_PROMPT, "max_tokens": 200}
@@ -0,0 +1,169 @@
+"""Tests for the AI triage of security findings."""
+
+from unittest.mock import AsyncMock, patch
+
+import pytest
+
+from codeconcat.ai.base import AIProviderConfig, AIProviderType, SummarizationResult
+from codeconcat.ai.factory import get_ai_provider
+from codeconcat.base_types import (
+    AnnotatedFileData,
+    CodeConCatConfig,
+    ParsedFileData,
+    SecurityIssue,
+    SecuritySeverity,
+    SecurityTriage,
+)
+from codeconcat.processor.security_triage import build_triage_prompt, parse_triage
+from codeconcat.processor.summarization_processor import SummarizationProcessor
+from codeconcat.writer.json_writer import write_json
+from codeconcat.writer.markdown_writer import write_markdown
+
+CONTENT = "\n".join(
+    [
+        "import os",
+        "",
+        "def connect():",
+        '    api_key = "sk-live-abcdef123456"',
+        "    return api_key",
+        "",
+        "def test_connect():",
+        '    password = "changeme"',
+    ]
+)
+
+
+def _issues():
+    return [
+        SecurityIssue(
+            "api_key",
+            "Potential api_key detected.",
+            "/repo/app/db.py",
+            4,
+            SecuritySeverity.HIGH,
+            '    api_key = "sk-****456"',
+        ),
+        SecurityIssue(
+            "password",
+            "Potential password detected.",
+            "/repo/app/db.py",
+            8,
+            SecuritySeverity.MEDIUM,
+            '    password = "ch****me"',
+        ),
+    ]
+
+
+REPLY = """```json
+[
+  {"id": 1, "likelihood": 0.9, "severity": "critical", "rationale": "A live key in code."},
+  {"id": 2, "likelihood": 0.1, "severity": "LOW", "rationale": "Placeholder in a test."}
+]
+```"""
+
+
+class TestTriagePrompt:
+    """Test building triage prompts and parsing the replies."""
+
+    def test_prompt_shows_masked_flagged_lines(self):
+        """Test findings are numbered and flagged lines are sent as the scanner masked them."""
+        issues = _issues()
+        prompt = build_triage_prompt("app/db.py", "python", CONTENT, issues, issues)
+
+        assert "#### Finding 1: api_key (scanner severity HIGH), line 4" in prompt
+        assert "#### Finding 2: password (scanner severity MEDIUM), line 8" in prompt
+        assert '> 4 |     api_key = "sk-****456"' in prompt
+        assert "sk-live-abcdef123456" not in prompt
+        assert "changeme" not in prompt
+
+    def test_parse_reply(self):
+        """Test a fenced JSON reply is mapped to the findings by id."""
+        triage = parse_triage(REPLY, _issues())
+
+        assert triage[0] == SecurityTriage(0.9, SecuritySeverity.CRITICAL, "A live key in code.")
+        assert triage[1].verdict == "likely false positive"
+
+    def test_parse_skips_malformed_entries(self):
+        """Test unknown ids, missing likelihoods and non-JSON replies are ignored."""
+        reply = (
+            '[{"id": 7, "likelihood": 0.5}, {"id": 1}, '
+            '{"id": 2, "likelihood": 2, "severity": "?"}]'
+        )
+        triage = parse_triage(reply, _issues())
+
+        assert list(triage) == [1]
+        assert triage[1].likelihood == 1.0
+        assert triage[1].severity == SecuritySeverity.MEDIUM
+        assert parse_triage("I cannot help with that.", _issues()) == {}
+
+
+class TestTriagePass:
+    """Test the triage pass of the summarization processor."""
+
+    @staticmethod
+    def _processor(**overrides):
+        provider = get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA, model="llama3.2", cache_enabled=False
+            )
+        )
+        config = CodeConCatConfig(
+            enable_ai_summary=True,
+            ai_provider="ollama",
+            ai_security_triage=True,
+            **overrides,
+        )
+        with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+            factory.return_value = provider
+            return SummarizationProcessor(config), config
+
+    @staticmethod
+    def _file():
+        parsed_file = ParsedFileData("/repo/app/db.py", CONTENT, "python")
+        parsed_file.security_issues = _issues()
+        return parsed_file
+
+    @pytest.mark.asyncio
+    async def test_triage_is_written_with_findings(self):
+        """Test the triage is attached to each finding and rendered by the writers."""
+        processor, config = self._processor()
+        parsed_file = self._file()
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary=REPLY)
+            assert await processor.triage_security_findings([parsed_file]) == 2
+
+        assert parsed_file.security_issues[1].triage.likelihood == 0.1
+        item = AnnotatedFileData(
+            parsed_file.file_path,
+            "python",
+            CONTENT,
+            CONTENT,
+            security_issues=parsed_file.security_issues,
+        )
+        markdown = write_markdown([item], config)
+        assert "AI triage: likely false positive (10%, LOW): Placeholder in a test." in markdown
+        assert '"verdict": "likely true positive"' in write_json([item], config)
+
+    @pytest.mark.asyncio
+    async def test_min_likelihood_leaves_out_findings(self):
+        """Test findings rated below ai_triage_min_likelihood are removed and counted."""
+        processor, _config = self._processor(ai_triage_min_likelihood=0.5)
+        parsed_file = self._file()
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary=REPLY)
+            await processor.triage_security_findings([parsed_file])
+
+        assert [issue.rule_id for issue in parsed_file.security_issues] == ["api_key"]
+        assert processor.get_statistics()["security_triage"]["hidden_findings"] == 1
+
+    @pytest.mark.asyncio
+    async def test_failed_request_keeps_findings(self):
+        """Test a failed request leaves the findings untriaged and counts them."""
+        processor, _config = self._processor(ai_triage_min_likelihood=0.5)
+        parsed_file = self._file()
+        with patch.object(processor.ai_provider, "complete", new_callable=AsyncMock) as call:
+            call.return_value = SummarizationResult(summary="", error="Ollama API error (500)")
+            assert await processor.triage_security_findings([parsed_file]) == 0
+
+        assert len(parsed_file.security_issues) == 2
+        assert processor.get_statistics()["failures"]["security_findings"] == 2
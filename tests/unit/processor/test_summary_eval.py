@@ -0,0 +1,110 @@
+"""Tests for the summarization quality evaluation harness."""
+
+import json
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from codeconcat.ai.base import AIProviderConfig, AIProviderType, SummarizationResult
+from codeconcat.ai.factory import get_ai_provider
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.processor.embeddings import HashingEmbedder
+from codeconcat.processor.summary_eval import (
+    GoldenCase,
+    evaluate_model,
+    load_golden,
+    rouge_l,
+    rouge_n,
+    score_cases,
+)
+
+CORPUS = Path(__file__).parents[2] / "parser_test_corpus"
+
+
+class TestMetrics:
+    """Test the ROUGE scores and case scoring."""
+
+    def test_rouge_scores(self):
+        """Test ROUGE-N and ROUGE-L F1 on hand-checked examples."""
+        assert rouge_n("the cat sat on the mat", "the cat is on the mat") == pytest.approx(5 / 6)
+        assert rouge_n("the cat sat", "a dog ran", 2) == 0.0
+        assert rouge_l("a b c d", "a c d") == pytest.approx(6 / 7)
+        assert rouge_l("", "anything") == 0.0
+
+    def test_score_cases(self):
+        """Test missing summaries are failures and identical texts score 1."""
+        cases = [GoldenCase("a.py", "Parses config files."), GoldenCase("b.py", "Writes logs.")]
+        scores = score_cases({"a.py": "Parses config files."}, cases, HashingEmbedder())
+
+        assert scores[0].rougeL == 1.0
+        assert scores[0].embedding == pytest.approx(1.0)
+        assert scores[1].error == "no summary generated"
+        assert scores[1].rouge1 is None
+
+
+class TestGoldenFile:
+    """Test loading reference summaries."""
+
+    def test_corpus_golden_file(self):
+        """Test every reference in the parser corpus names an existing file."""
+        cases = load_golden(CORPUS)
+
+        assert "python/basic.py" in [case.path for case in cases]
+        assert all(case.reference for case in cases)
+
+    def test_missing_file_is_rejected(self, tmp_path):
+        """Test a reference to a file outside the corpus is an error."""
+        golden = tmp_path / "golden.json"
+        golden.write_text(json.dumps({"summaries": {"nope.py": "Missing."}}))
+
+        with pytest.raises(ValueError, match="nope.py"):
+            load_golden(tmp_path, golden)
+
+
+class TestEvaluateModel:
+    """Test summarizing and scoring a corpus with one model."""
+
+    @pytest.mark.asyncio
+    async def test_report_scores_each_file(self):
+        """Test the report holds a score per file, failures and token usage."""
+        cases = [c for c in load_golden(CORPUS) if c.path in ("c/basic.c", "python/basic.py")]
+        references = {str(CORPUS / c.path): c.reference for c in cases}
+        provider = get_ai_provider(
+            AIProviderConfig(
+                provider_type=AIProviderType.OLLAMA, model="llama3.2", cache_enabled=False
+            )
+        )
+        config = CodeConCatConfig(
+            enable_ai_summary=True,
+            ai_provider="ollama",
+            ai_model="llama3.2",
+            ai_min_file_lines=0,
+            enable_security_scanning=False,
+        )
+
+        async def summarize(code, language, context):
+            if context["file_path"].endswith("basic.c"):
+                return SummarizationResult(summary="", error="Ollama API error (500): boom")
+            return SummarizationResult(
+                summary=references[context["file_path"]],
+                model_used="llama3.2",
+                metadata={"input_tokens": 400, "output_tokens": 60},
+            )
+
+        with (
+            patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory,
+            patch.object(provider, "summarize_code", side_effect=summarize),
+        ):
+            factory.return_value = provider
+            report = await evaluate_model(config, CORPUS, cases, HashingEmbedder())
+
+        scores = {case.path: case for case in report.cases}
+        assert report.provider == "ollama"
+        assert report.model == "llama3.2"
+        assert report.failures == 1
+        assert scores["c/basic.c"].error == "Ollama API error (500): boom"
+        assert scores["python/basic.py"].rougeL == 1.0
+        assert report.mean("rouge1") == 1.0
+        assert report.input_tokens == 400
+        assert report.to_dict()["scores"]["embedding"] == pytest.approx(1.0)
@@ -2,7 +2,15 @@
 
 from unittest.mock import patch
 
-from codeconcat.language_map import GUESSLANG_AVAILABLE, ext_map, get_language_guesslang
+import pytest
+
+from codeconcat.language_map import (
+    GUESSLANG_AVAILABLE,
+    detect_language_from_content,
+    ext_map,
+    get_language_by_filename,
+    get_language_guesslang,
+)
 
 
 class TestLanguageMap:
@@ -85,3 +93,54 @@ class TestLanguageMap:
     def test_guesslang_available_is_boolean(self):
         """Test that GUESSLANG_AVAILABLE is a boolean."""
         assert isinstance(GUESSLANG_AVAILABLE, bool)
+
+
+class TestContentDetection:
+    """Test detecting the language of files without a telling extension."""
+
+    @pytest.mark.parametrize(
+        "name, expected",
+        [
+            ("Dockerfile.dev", "dockerfile"),
+            ("api.Dockerfile", "dockerfile"),
+            ("Containerfile", "dockerfile"),
+            ("Jenkinsfile", "groovy"),
+            ("Vagrantfile", "ruby"),
+            ("GNUmakefile", "makefile"),
+            (".bashrc", "bash"),
+            ("README", None),
+        ],
+    )
+    def test_get_language_by_filename(self, name, expected):
+        """Test well-known file names map to their language."""
+        assert get_language_by_filename(name) == expected
+
+    @pytest.mark.parametrize(
+        "content, expected",
+        [
+            ("#!/usr/bin/env python3\nprint('hi')\n", "python"),
+            ("#!/bin/sh\nset -e\n", "bash"),
+            ("#!/usr/bin/env -S node --no-warnings\nconsole.log(1)\n", "javascript"),
+            ("#!/usr/local/bin/ruby2.7\nputs 1\n", "ruby"),
+            ("#!/usr/bin/env unknown-tool\n", None),
+        ],
+    )
+    def test_shebang(self, content, expected):
+        """Test the interpreter on a shebang line decides the language."""
+        assert detect_language_from_content(content) == expected
+
+    def test_modelines(self):
+        """Test vim and Emacs modelines at the start or end of the file."""
+        assert detect_language_from_content("# -*- mode: perl -*-\nprint 1;\n") == "perl"
+        assert detect_language_from_content("echo hi\n" * 20 + "# vim: set ft=sh:\n") == "bash"
+        # A coding declaration is not a mode
+        assert detect_language_from_content("# -*- coding: utf-8 -*-\nx = 1\n") is None
+
+    def test_openings(self):
+        """Test PHP, XML and Dockerfile content is recognized without a shebang."""
+        assert detect_language_from_content("<?php\necho 1;\n") == "php"
+        assert detect_language_from_content('<?xml version="1.0"?>\n<a/>\n') == "xml"
+        dockerfile = "# build\nFROM python:3.12\nRUN pip install \\\n    httpx\nCOPY . /app\n"
+        assert detect_language_from_content(dockerfile) == "dockerfile"
+        assert detect_language_from_content("From the authors of this project\n") is None
+        assert detect_language_from_content("From here\nRun the tests\n") is None
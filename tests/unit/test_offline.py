@@ -0,0 +1,191 @@
+"""Tests for strict offline mode."""
+
+import hashlib
+import os
+from unittest.mock import patch
+
+import pytest
+
+from codeconcat.base_types import CodeConCatConfig
+from codeconcat.utils.offline import (
+    OFFLINE_ENV,
+    OfflineError,
+    apply_offline_mode,
+    is_offline,
+    offline_violations,
+    require_network,
+    require_tiktoken_encoding,
+)
+
+
+def _config(**overrides):
+    return CodeConCatConfig(offline=True, enable_external_semgrep=False, **overrides)
+
+
+@patch.dict(os.environ, {OFFLINE_ENV: ""})
+class TestApplyOfflineMode:
+    """Test enabling offline mode for a run."""
+
+    def test_disabled_by_default(self):
+        """Test nothing is checked or enabled without the offline setting."""
+        apply_offline_mode(CodeConCatConfig(source_url="https://github.com/owner/repo"))
+
+        assert not is_offline()
+
+    def test_enables_offline_environment(self):
+        """Test offline mode is exported for worker processes and Hugging Face."""
+        apply_offline_mode(_config())
+
+        assert is_offline()
+        assert os.environ["HF_HUB_OFFLINE"] == "1"
+
+    def test_environment_variable_enables_offline(self):
+        """Test CODECONCAT_OFFLINE turns offline mode on for a config without it."""
+        os.environ[OFFLINE_ENV] = "1"
+        config = CodeConCatConfig(source_url="https://github.com/owner/repo")
+
+        with pytest.raises(OfflineError):
+            apply_offline_mode(config)
+        assert config.offline
+
+    def test_lists_every_network_feature(self):
+        """Test the error names each enabled feature that needs the network."""
+        config = _config(
+            source_url="https://github.com/owner/repo",
+            archive="https://example.com/release.tar.gz",
+            enable_ai_summary=True,
+            ai_provider="openai",
+            install_semgrep=True,
+        )
+
+        with pytest.raises(OfflineError) as excinfo:
+            apply_offline_mode(config)
+
+        message = str(excinfo.value)
+        assert "remote collection of https://github.com/owner/repo" in message
+        assert "archive https://example.com/release.tar.gz" in message
+        assert "'openai' provider" in message
+        assert "--install-semgrep" in message
+
+    def test_external_semgrep_registry_ruleset_disabled(self):
+        """Test the default external semgrep scan is skipped instead of failing."""
+        config = CodeConCatConfig(offline=True, semgrep_ruleset="p/ci")
+
+        apply_offline_mode(config)
+
+        assert not config.enable_external_semgrep
+
+
+class TestOfflineViolations:
+    """Test which configurations need the network."""
+
+    def test_local_configuration_allowed(self, tmp_path):
+        """Test local AI, local embeddings and a local ruleset are accepted."""
+        config = _config(
+            enable_ai_summary=True,
+            ai_provider="ollama",
+            embed=True,
+            embed_backend="hashing",
+            enable_semgrep=True,
+            semgrep_ruleset=str(tmp_path),
+        )
+
+        assert offline_violations(config) == []
+
+    def test_remote_sources_rejected(self, tmp_path):
+        """Test remote repositories listed in sources are reported, local paths are not."""
+        config = _config(sources=[str(tmp_path), "lib=acme/shared", "https://gitlab.com/g/p"])
+
+        assert offline_violations(config) == [
+            "remote collection of acme/shared (pass a local path instead)",
+            "remote collection of https://gitlab.com/g/p (pass a local path instead)",
+        ]
+
+    def test_remote_endpoints_rejected(self):
+        """Test local providers and embedders on public hosts are rejected."""
+        config = _config(
+            enable_ai_summary=True,
+            ai_provider="local_server",
+            ai_api_base="https://inference.example.com/v1",
+            task="fix the parser",
+            embed_backend="openai",
+        )
+
+        violations = offline_violations(config)
+
+        assert "https://inference.example.com/v1" in violations[0]
+        assert "the OpenAI API" in violations[1]
+
+    def test_allowed_hosts_accepted(self):
+        """Test self-hosted servers listed in ai_allowed_hosts are accepted."""
+        config = _config(
+            enable_ai_summary=True,
+            ai_provider="vllm",
+            ai_api_base="http://gpu.corp.example:8000/v1",
+            ai_allowed_hosts=["gpu.corp.example"],
+        )
+
+        assert offline_violations(config) == []
+
+    def test_registry_ruleset_rejected(self):
+        """Test explicit semgrep scanning with a registry ruleset is rejected."""
+        violations = offline_violations(_config(enable_semgrep=True, semgrep_ruleset="p/ci"))
+
+        assert violations == [
+            "semgrep scanning with the registry ruleset 'p/ci' (set semgrep_ruleset to a local "
+            "rules path)"
+        ]
+
+
+@patch.dict(os.environ, {OFFLINE_ENV: "1"})
+class TestNetworkGuards:
+    """Test components refuse downloads in offline mode."""
+
+    def test_clone_refused(self):
+        """Test cloning a repository refuses to start instead of being logged as a failure."""
+        from codeconcat.collector.github_collector import collect_git_repo
+
+        with pytest.raises(OfflineError, match="Cloning a remote repository"):
+            collect_git_repo("acme/service", _config())
+
+    def test_require_network(self):
+        """Test network features raise with the feature name."""
+        with pytest.raises(OfflineError, match="Downloading the grammar needs network access"):
+            require_network("Downloading the grammar")
+
+    def test_tiktoken_uses_local_cache_only(self, tmp_path):
+        """Test tiktoken encodings load only when already in the cache directory."""
+        os.environ["TIKTOKEN_CACHE_DIR"] = str(tmp_path)
+        with pytest.raises(OfflineError):
+            require_tiktoken_encoding("p50k_edit")
+
+        url = "https://openaipublic.blob.core.windows.net/encodings/p50k_edit.tiktoken"
+        (tmp_path / hashlib.sha1(url.encode()).hexdigest()).write_text("cached")
+        require_tiktoken_encoding("p50k_edit")
+
+    def test_token_count_falls_back_to_estimate(self, tmp_path):
+        """Test token counting estimates instead of downloading an encoding."""
+        from codeconcat.processor import token_counter
+
+        os.environ["TIKTOKEN_CACHE_DIR"] = str(tmp_path)
+        with (
+            patch.dict(token_counter._ENCODER_CACHE, clear=True),
+            patch.object(token_counter.tiktoken, "encoding_for_model") as load,
+            patch.object(token_counter.tiktoken.model, "encoding_name_for_model") as name,
+        ):
+            name.return_value = "cl100k_base"
+            count = token_counter.count_tokens("two words", "gpt-4")
+
+        load.assert_not_called()
+        assert count == 2
+
+    def test_hosted_ai_provider_refused(self):
+        """Test summarization never initializes a hosted provider offline."""
+        from codeconcat.processor.summarization_processor import SummarizationProcessor
+
+        config = CodeConCatConfig(enable_ai_summary=True, ai_provider="openai", ai_api_key="test")
+        with patch("codeconcat.processor.summarization_processor.get_ai_provider") as factory:
+            processor = SummarizationProcessor(config)
+
+        factory.assert_not_called()
+        assert processor.ai_provider is None
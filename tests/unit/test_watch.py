@@ -0,0 +1,131 @@
+"""Tests for watch mode and the parse cache it reuses between runs."""
+
+import os
+from unittest.mock import patch
+
+from codeconcat.base_types import CodeConCatConfig, ParsedFileData
+from codeconcat.parser.parse_cache import ParseCache
+from codeconcat.parser.unified_pipeline import UnifiedPipeline
+from codeconcat.watch import Watcher, changed_paths, is_output_path, take_snapshot
+
+
+def _file(path, content):
+    return ParsedFileData(file_path=path, content=content, language="python")
+
+
+class TestParseCache:
+    """Test parse results are reused for unchanged files."""
+
+    def test_unchanged_files_are_not_parsed_again(self):
+        """Test only new and edited files reach the parser, in input order."""
+        config = CodeConCatConfig()
+        cache = ParseCache()
+        object.__setattr__(config, "_parse_cache", cache)
+        parsed = []
+
+        def process(self, file_data):
+            parsed.append(file_data.file_path)
+            return ParsedFileData(
+                file_path=file_data.file_path,
+                content=file_data.content,
+                language="python",
+                imports=[file_data.content],
+            )
+
+        with patch.object(UnifiedPipeline, "_process_file", process):
+            UnifiedPipeline(config).parse([_file("a.py", "one"), _file("b.py", "two")])
+            results, errors = UnifiedPipeline(config).parse(
+                [_file("a.py", "one"), _file("b.py", "edited"), _file("c.py", "new")]
+            )
+
+        assert parsed == ["a.py", "b.py", "b.py", "c.py"]
+        assert [r.imports for r in results] == [["one"], ["edited"], ["new"]]
+        assert errors == []
+        assert (cache.hits, len(cache)) == (1, 3)
+
+    def test_cached_results_are_copies(self):
+        """Test later stages editing a result do not change the cached entry."""
+        cache = ParseCache()
+        source = _file("a.py", "x = 1")
+        cache.put(source, _file("a.py", "x = 1"))
+
+        cache.get(source).content = "changed"
+
+        assert cache.get(source).content == "x = 1"
+
+    def test_removed_files_are_dropped(self):
+        """Test files no longer collected leave the cache."""
+        cache = ParseCache()
+        for path in ("a.py", "b.py"):
+            cache.put(_file(path, ""), _file(path, ""))
+
+        cache.retain({"a.py"})
+
+        assert len(cache) == 1
+        assert cache.get(_file("b.py", "")) is None
+
+
+class TestSnapshots:
+    """Test detecting changes under the watched directory."""
+
+    def test_changes_and_outputs(self, tmp_path):
+        """Test edits, additions and removals are seen; outputs are not watched."""
+        config = CodeConCatConfig(
+            target_path=str(tmp_path), output=str(tmp_path / "context.md"), use_gitignore=False
+        )
+        (tmp_path / "app.py").write_text("a = 1\n")
+        (tmp_path / "old.py").write_text("b = 1\n")
+        before = take_snapshot(str(tmp_path), config)
+
+        (tmp_path / "app.py").write_text("a = 22\n")
+        (tmp_path / "old.py").unlink()
+        (tmp_path / "new.py").write_text("c = 1\n")
+        for output in ("context.md", "context.part2.md", "ccc_codeconcat_app_101426.md"):
+            (tmp_path / output).write_text("output")
+        after = take_snapshot(str(tmp_path), config)
+
+        assert changed_paths(before, after) == [
+            str(tmp_path / name) for name in ("app.py", "new.py", "old.py")
+        ]
+        assert is_output_path(str(tmp_path / ".codeconcat_unsupported_files.json"), config)
+        assert not is_output_path(str(tmp_path / "app.py"), config)
+
+
+class TestWatcher:
+    """Test the regeneration loop."""
+
+    def test_regenerates_after_change(self, tmp_path):
+        """Test the output is written on start and again after a file changes."""
+        source = tmp_path / "app.py"
+        source.write_text("a = 1\n")
+        config = CodeConCatConfig(target_path=str(tmp_path), use_gitignore=False)
+        written = []
+        runs = []
+
+        def fake_run(run_config):
+            assert getattr(run_config, "_parse_cache") is watcher.parse_cache
+            run_config.output = os.path.join(tmp_path, "ccc_codeconcat_out.md")
+            if not runs:
+                source.write_text("a = 2\n")
+            return f"output {len(runs)}"
+
+        watcher = Watcher(
+            config, interval=0.01, debounce=0, write=lambda text, cfg: written.append(text)
+        )
+        with patch("codeconcat.main.run_codeconcat", side_effect=fake_run):
+            watcher.run(on_run=runs.append, should_stop=lambda: len(runs) >= 2)
+
+        assert written == ["output 0", "output 1"]
+        assert runs[0].changed == []
+        assert runs[1].changed == [str(source)]
+        assert config.output.endswith("ccc_codeconcat_out.md")
+
+    def test_failed_run_keeps_watching(self, tmp_path):
+        """Test a failing run is reported to on_error instead of ending the watch."""
+        config = CodeConCatConfig(target_path=str(tmp_path), use_gitignore=False)
+        errors = []
+
+        with patch("codeconcat.main.run_codeconcat", side_effect=ValueError("broken")):
+            Watcher(config, interval=0.01).run(on_error=errors.append, should_stop=lambda: True)
+
+        assert [str(e) for e in errors] == ["broken"]
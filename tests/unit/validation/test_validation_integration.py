@@ -5,10 +5,11 @@ from unittest.mock import MagicMock, patch
 import pytest
 
 from codeconcat.base_types import CodeConCatConfig, ParsedFileData
-from codeconcat.errors import ConfigurationError
+from codeconcat.errors import ConfigurationError, ValidationError
 from codeconcat.validation.integration import (
     sanitize_output,
     setup_semgrep,
+    validate_archive_members,
     validate_config_values,
     validate_input_files,
     verify_file_signatures,
@@ -55,6 +56,22 @@ class TestValidationIntegration:
         validated_files = validate_input_files(files_to_process, config)
         assert len(validated_files) == 2  # Should still pass
 
+    def test_validate_archive_members(self):
+        """Test archive members are scanned in memory and rejected in strict mode."""
+        files = [
+            ParsedFileData(file_path="src/app.py", content="x = 1\n", language="python"),
+            ParsedFileData(
+                file_path="src/run.py", content="import os\nos.system(cmd)\n", language="python"
+            ),
+        ]
+        config = CodeConCatConfig(enable_security_scanning=True, strict_security=False)
+
+        assert validate_archive_members(files, config) == files
+
+        config.strict_security = True
+        with pytest.raises(ValidationError, match="1 archive files"):
+            validate_archive_members(files, config)
+
     def test_validate_config_values(self):
         """Test validating configuration values."""
         # Valid configuration
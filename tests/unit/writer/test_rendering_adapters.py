@@ -41,6 +41,7 @@ from codeconcat.writer.rendering_adapters import (
     MarkdownRenderAdapter,
     TextRenderAdapter,
     XmlRenderAdapter,
+    declaration_gloss,
 )
 
 # Configure logging for tests
@@ -614,6 +615,34 @@ class TestRenderingAdapters:
         assert method["kind"] == "method"
         assert "abstract" in method["modifiers"]
 
+    def test_declaration_gloss_single_line(self):
+        """Test declaration summaries collapse to one line and are shortened."""
+        decl = Declaration("function", "f", 1, 5)
+        assert declaration_gloss(decl) == ""
+
+        decl.ai_summary = "Parses the config.\n\nReturns a dict."
+        assert declaration_gloss(decl) == "Parses the config. Returns a dict."
+
+        decl.ai_summary = "First sentence is short. " + "word " * 60
+        assert declaration_gloss(decl) == "First sentence is short."
+
+        decl.ai_summary = "x" * 300
+        gloss = declaration_gloss(decl, max_chars=50)
+        assert len(gloss) == 50 and gloss.endswith("…")
+
+    def test_declaration_gloss_rendered(self, config):
+        """Test markdown, JSON and XML output carry the declaration gloss."""
+        decl = Declaration("class", "Worker", 1, 30, ai_summary="Runs queued\njobs.")
+
+        markdown = MarkdownRenderAdapter.render_declarations([decl], "w.py", config)
+        assert "`Worker` (lines 1-30) — Runs queued jobs." in markdown
+
+        assert JsonRenderAdapter.declaration_to_dict(decl)["ai_summary"] == "Runs queued jobs."
+
+        root = ET.Element("declarations")
+        XmlRenderAdapter.add_declaration_to_element(root, decl)
+        assert root.find("declaration").get("summary") == "Runs queued jobs."
+
     def test_json_security_issue_to_dict_no_context(self):
         """Test converting security issue without context."""
         issue = SecurityIssue(